@@ -0,0 +1,117 @@
+package groth16_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// commitCircuit checks that {X, Y} and {A, B} are equal as multisets by
+// evaluating both products at a random point derived from the commitment.
+type commitCircuit struct {
+	X    frontend.Variable `gnark:",public"`
+	Y    frontend.Variable
+	A, B frontend.Variable
+}
+
+func (c *commitCircuit) Define(api frontend.API) error {
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		panic("compiler doesn't implement frontend.Committer")
+	}
+	r := committer.Commit(c.X, c.Y, c.A, c.B)
+	left := api.Mul(api.Sub(r, c.X), api.Sub(r, c.Y))
+	right := api.Mul(api.Sub(r, c.A), api.Sub(r, c.B))
+	api.AssertIsEqual(left, right)
+	return nil
+}
+
+func TestCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	assert.ProverSucceeded(&commitCircuit{}, &commitCircuit{X: 1, Y: 2, A: 2, B: 1}, test.WithBackends(backend.GROTH16))
+	assert.ProverFailed(&commitCircuit{}, &commitCircuit{X: 1, Y: 2, A: 3, B: 0}, test.WithBackends(backend.GROTH16))
+}
+
+func TestCommitmentSoundness(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &commitCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	w, err := frontend.NewWitness(&commitCircuit{X: 1, Y: 2, A: 2, B: 1}, ecc.BN254)
+	assert.NoError(err)
+	publicW, err := w.Public()
+	assert.NoError(err)
+
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicW))
+
+	// the verifying key and the proof keep the commitment through serialization
+	var buf bytes.Buffer
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vkRead := groth16.NewVerifyingKey(ecc.BN254)
+	_, err = vkRead.ReadFrom(&buf)
+	assert.NoError(err)
+	buf.Reset()
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	proofRead := groth16.NewProof(ecc.BN254)
+	_, err = proofRead.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proofRead, vkRead, publicW))
+
+	// doubling the commitment keeps a valid proof of knowledge but changes the
+	// challenge, the proof must not verify anymore
+	_proof := proof.(*groth16_bn254.Proof)
+	_proof.Commitment.Add(&_proof.Commitment, &_proof.Commitment)
+	_proof.CommitmentPok.Add(&_proof.CommitmentPok, &_proof.CommitmentPok)
+	assert.Error(groth16.Verify(_proof, vk, publicW))
+
+	// a commitment without matching proof of knowledge is rejected
+	_proof.CommitmentPok.Add(&_proof.CommitmentPok, &_proof.Commitment)
+	assert.Error(groth16.Verify(_proof, vk, publicW))
+
+	buf.Reset()
+	assert.NoError(vk.ExportSolidity(&buf))
+	assert.Contains(buf.String(), "uint256[2] memory commitment")
+}
+
+func TestCommitmentHiding(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &commitCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+
+	w, err := frontend.NewWitness(&commitCircuit{X: 1, Y: 2, A: 2, B: 1}, ecc.BN254)
+	assert.NoError(err)
+	publicW, err := w.Public()
+	assert.NoError(err)
+
+	// the commitment is blinded, two proofs of the same witness commit
+	// differently and both verify
+	proof1, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	proof2, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof1, vk, publicW))
+	assert.NoError(groth16.Verify(proof2, vk, publicW))
+
+	c1 := proof1.(*groth16_bn254.Proof).Commitment
+	c2 := proof2.(*groth16_bn254.Proof).Commitment
+	assert.False(c1.Equal(&c2))
+}
@@ -1,6 +1,7 @@
 package hint
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -9,6 +10,7 @@ import (
 func init() {
 	Register(IsZero)
 	Register(CheckZero)
+	Register(DivRem)
 }
 
 // IsZero computes the value 1 - a^(modulus-1) for the single input a. This
@@ -47,3 +49,17 @@ func CheckZero(curveID ecc.ID, inputs []*big.Int, results []*big.Int) error {
 
 	return nil
 }
+
+// DivRem computes the quotient and the remainder of the integer division of
+// the first input by the second input. It returns an error if the second input
+// is zero.
+func DivRem(_ ecc.ID, inputs []*big.Int, results []*big.Int) error {
+	if len(inputs) != 2 || len(results) != 2 {
+		return errors.New("expected two inputs and two results")
+	}
+	if inputs[1].Sign() == 0 {
+		return errors.New("division by zero")
+	}
+	results[0].QuoRem(inputs[0], inputs[1], results[1])
+	return nil
+}
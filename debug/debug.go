@@ -16,6 +16,7 @@ func Stack() string {
 func WriteStack(sbb *strings.Builder, forceClean ...bool) {
 	// derived from: https://golang.org/pkg/runtime/#example_Frames
 	// we stop when func name == Define as it is where the gnark circuit code should start
+	// (or at the compiler calling the callbacks deferred by the circuit)
 
 	// Ask runtime.Callers for up to 10 pcs
 	pc := make([]uintptr, 10)
@@ -35,6 +36,11 @@ func WriteStack(sbb *strings.Builder, forceClean ...bool) {
 		function := fe[len(fe)-1]
 		file := frame.File
 
+		if strings.HasSuffix(function, "callDeferred") {
+			// deferred callbacks are called by the compiler after Define
+			break
+		}
+
 		if !Debug || (len(forceClean) > 1 && forceClean[0]) {
 			if strings.Contains(function, "runtime.gopanic") {
 				continue
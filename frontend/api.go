@@ -50,6 +50,15 @@ type API interface {
 	// Inverse returns res = 1 / i1
 	Inverse(i1 Variable) Variable
 
+	// DivRem returns the quotient and the remainder of the integer division of
+	// a by b. It asserts that a and b fit in nbBits bits and that b is not
+	// zero. nbBits must be at most half the bit size of the field.
+	DivRem(a, b Variable, nbBits int) (quotient, remainder Variable)
+
+	// Mod returns a mod m. It asserts that a and m fit in nbBits bits and that
+	// m is not zero. nbBits must be at most half the bit size of the field.
+	Mod(a, m Variable, nbBits int) Variable
+
 	// ---------------------------------------------------------------------------------------------
 	// Bit operations
 	// TODO @gbotrel move bit operations in std/math/bits
@@ -95,6 +104,18 @@ type API interface {
 	// Cmp returns 1 if i1>i2, 0 if i1=i2, -1 if i1<i2
 	Cmp(i1, i2 Variable) Variable
 
+	// IsLess returns 1 if a < b, 0 otherwise. It asserts that a and b fit in
+	// nbBits bits, which is cheaper than the full width comparison of Cmp.
+	IsLess(a, b Variable, nbBits int) Variable
+
+	// Min returns the minimum of a and b. It asserts that a and b fit in
+	// nbBits bits.
+	Min(a, b Variable, nbBits int) Variable
+
+	// Max returns the maximum of a and b. It asserts that a and b fit in
+	// nbBits bits.
+	Max(a, b Variable, nbBits int) Variable
+
 	// ---------------------------------------------------------------------------------------------
 	// Assertions
 
@@ -110,6 +131,9 @@ type API interface {
 	// AssertIsLessOrEqual fails if  v > bound
 	AssertIsLessOrEqual(v Variable, bound Variable)
 
+	// AssertIsLess fails if a >= b or if a or b do not fit in nbBits bits
+	AssertIsLess(a, b Variable, nbBits int)
+
 	// Println behaves like fmt.Println but accepts cd.Variable as parameter
 	// whose value will be resolved at runtime when computed by the solver
 	Println(a ...Variable)
@@ -7,6 +7,7 @@ import (
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/kvstore"
 )
 
 type NewBuilder func(ecc.ID, CompileConfig) (Builder, error)
@@ -58,6 +59,16 @@ type Compiler interface {
 
 	// Backend returns the backend.ID injected by the compiler
 	Backend() backend.ID
+
+	// Defer registers a callback which is called after circuit.Define() and
+	// before Compile(), in the order of registration. Gadgets use it to
+	// finalize batched operations once all of them are known. Callbacks may
+	// register further callbacks.
+	Defer(cb func(api API) error)
+
+	// Store allows the gadgets to share state, such as batching singletons,
+	// during the circuit definition.
+	kvstore.Store
 }
 
 // Builder represents a constraint system builder
@@ -79,3 +90,21 @@ type Builder interface {
 	// called inside circuit.Define()
 	AddSecretVariable(name string) Variable
 }
+
+// Rangechecker allows to range-check variables to be of the specified bit
+// width. Not all compilers implement this interface. Circuits should instead use
+// the [github.com/consensys/gnark/std/rangecheck] package which chooses the
+// best method for the given compiler.
+type Rangechecker interface {
+	// Check asserts that v fits in nbBits bits.
+	Check(v Variable, nbBits int)
+}
+
+// Committer allows to commit to the variables and returns the commitment. The
+// commitment can be used as a challenge in the circuit, as the prover can not
+// choose the committed values after seeing it. Not all compilers implement
+// this interface.
+type Committer interface {
+	// Commit commits to the variables and returns the commitment.
+	Commit(toCommit ...Variable) (commitment Variable)
+}
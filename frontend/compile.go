@@ -8,6 +8,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend/schema"
+	"github.com/consensys/gnark/internal/circuitdefer"
 	"github.com/consensys/gnark/logger"
 )
 
@@ -116,10 +117,24 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	if err = circuit.Define(builder); err != nil {
 		return fmt.Errorf("define circuit: %w", err)
 	}
+	if err = callDeferred(builder); err != nil {
+		return fmt.Errorf("deferred: %w", err)
+	}
 	log.Info().Msg("parsed circuit")
 	return
 }
 
+// callDeferred calls the callbacks registered with Compiler.Defer, including
+// the ones registered by the callbacks themselves.
+func callDeferred(builder Builder) error {
+	for i := 0; i < len(circuitdefer.GetAll[func(API) error](builder)); i++ {
+		if err := circuitdefer.GetAll[func(API) error](builder)[i](builder); err != nil {
+			return fmt.Errorf("defer fn %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // CompileOption defines option for altering the behaviour of the Compile
 // method. See the descriptions of the functions returning instances of this
 // type for available options.
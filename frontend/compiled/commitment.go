@@ -0,0 +1,33 @@
+package compiled
+
+import "github.com/consensys/gnark/backend/hint"
+
+// CommitmentInfo describes the commitment to a set of wires made through
+// frontend.Committer. The backend computes the commitment by overriding the
+// hint with HintID at proving time.
+type CommitmentInfo struct {
+	Committed          []int   // sorted IDs of the committed wires, public wires first
+	NbPrivateCommitted int     // number of secret and internal wires in Committed
+	CommitmentIndex    int     // ID of the wire holding the commitment
+	HintID             hint.ID // ID of the hint computing the commitment
+}
+
+// Is returns true if the constraint system commits to some wires.
+func (i *CommitmentInfo) Is() bool {
+	return i.Committed != nil
+}
+
+// NbPublicCommitted returns the number of public wires in Committed.
+func (i *CommitmentInfo) NbPublicCommitted() int {
+	return len(i.Committed) - i.NbPrivateCommitted
+}
+
+// PublicCommitted returns the IDs of the committed public wires.
+func (i *CommitmentInfo) PublicCommitted() []int {
+	return i.Committed[:i.NbPublicCommitted()]
+}
+
+// PrivateCommitted returns the IDs of the committed secret and internal wires.
+func (i *CommitmentInfo) PrivateCommitted() []int {
+	return i.Committed[i.NbPublicCommitted():]
+}
@@ -22,7 +22,8 @@ import (
 // R1CS decsribes a set of R1C constraint
 type R1CS struct {
 	ConstraintSystem
-	Constraints []R1C
+	Constraints    []R1C
+	CommitmentInfo CommitmentInfo
 }
 
 // GetNbConstraints returns the number of constraints
@@ -525,6 +525,92 @@ func (system *r1cs) Cmp(i1, i2 frontend.Variable) frontend.Variable {
 	return res
 }
 
+// IsLess returns 1 if a < b, 0 otherwise. It asserts that a and b fit in
+// nbBits bits.
+func (system *r1cs) IsLess(a, b frontend.Variable, nbBits int) frontend.Variable {
+	ca, aConstant := system.mustFitBits(a, nbBits)
+	cb, bConstant := system.mustFitBits(b, nbBits)
+	if aConstant && bConstant {
+		if ca.Cmp(cb) < 0 {
+			return 1
+		}
+		return 0
+	}
+
+	// a - b + 2ⁿ is in [1, 2ⁿ⁺¹) and its top bit is set if and only if a >= b
+	shift := new(big.Int).Lsh(big.NewInt(1), uint(nbBits))
+	cBits := system.ToBinary(system.Add(system.Sub(a, b), shift), nbBits+1)
+	return system.Sub(1, cBits[nbBits])
+}
+
+// Min returns the minimum of a and b. It asserts that a and b fit in nbBits
+// bits.
+func (system *r1cs) Min(a, b frontend.Variable, nbBits int) frontend.Variable {
+	return system.Select(system.IsLess(a, b, nbBits), a, b)
+}
+
+// Max returns the maximum of a and b. It asserts that a and b fit in nbBits
+// bits.
+func (system *r1cs) Max(a, b frontend.Variable, nbBits int) frontend.Variable {
+	return system.Select(system.IsLess(a, b, nbBits), b, a)
+}
+
+// DivRem returns the quotient and the remainder of the integer division of a
+// by b. It asserts that a and b fit in nbBits bits and that b is not zero.
+func (system *r1cs) DivRem(a, b frontend.Variable, nbBits int) (quotient, remainder frontend.Variable) {
+	// q·b + r must not wrap around
+	if nbBits > (system.BitLen()-2)/2 {
+		panic(fmt.Sprintf("number of bits %d out of range [1, %d]", nbBits, (system.BitLen()-2)/2))
+	}
+	ca, aConstant := system.mustFitBits(a, nbBits)
+	if cb, bConstant := system.ConstantValue(b); bConstant {
+		if cb.Sign() == 0 {
+			panic("divRem: division by zero")
+		}
+		if aConstant {
+			system.mustFitBits(cb, nbBits)
+			q, r := new(big.Int).QuoRem(ca, cb, new(big.Int))
+			return q, r
+		}
+	}
+
+	res, err := system.NewHint(hint.DivRem, 2, a, b)
+	if err != nil {
+		// the function errs only if the number of inputs is invalid.
+		panic(err)
+	}
+	quotient, remainder = res[0], res[1]
+	system.mustFitBits(quotient, nbBits)
+	// r < b also asserts that b fits in nbBits bits and is not zero
+	system.AssertIsLess(remainder, b, nbBits)
+	system.AssertIsEqual(system.Add(system.Mul(quotient, b), remainder), a)
+	return quotient, remainder
+}
+
+// Mod returns a mod m. It asserts that a and m fit in nbBits bits and that m
+// is not zero.
+func (system *r1cs) Mod(a, m frontend.Variable, nbBits int) frontend.Variable {
+	_, r := system.DivRem(a, m, nbBits)
+	return r
+}
+
+// mustFitBits asserts that v fits in nbBits bits. It returns the value of v and
+// true if v is a constant.
+func (system *r1cs) mustFitBits(v frontend.Variable, nbBits int) (*big.Int, bool) {
+	// the comparisons compute a - b + 2ⁿ which must not wrap around
+	if nbBits <= 0 || nbBits > system.BitLen()-2 {
+		panic(fmt.Sprintf("number of bits %d out of range [1, %d]", nbBits, system.BitLen()-2))
+	}
+	if c, ok := system.ConstantValue(v); ok {
+		if c.BitLen() > nbBits {
+			panic(fmt.Sprintf("constant %s does not fit in %d bits", c, nbBits))
+		}
+		return c, true
+	}
+	system.ToBinary(v, nbBits)
+	return nil, false
+}
+
 // Println enables circuit debugging and behaves almost like fmt.Println()
 //
 // the print will be done once the R1CS.Solve() method is executed
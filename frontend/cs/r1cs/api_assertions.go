@@ -88,6 +88,21 @@ func (system *r1cs) AssertIsLessOrEqual(_v frontend.Variable, bound frontend.Var
 
 }
 
+// AssertIsLess fails if a >= b or if a or b do not fit in nbBits bits
+func (system *r1cs) AssertIsLess(a, b frontend.Variable, nbBits int) {
+	ca, aConstant := system.mustFitBits(a, nbBits)
+	cb, bConstant := system.mustFitBits(b, nbBits)
+	if aConstant && bConstant {
+		if ca.Cmp(cb) >= 0 {
+			panic(fmt.Sprintf("assertIsLess failed: constant(%s) >= constant(%s)", ca, cb))
+		}
+		return
+	}
+
+	// b - a - 1 is in [0, 2ⁿ) if and only if a < b
+	system.ToBinary(system.Sub(b, a, 1), nbBits)
+}
+
 func (system *r1cs) mustBeLessOrEqVar(a, bound compiled.LinearExpression) {
 	debug := system.AddDebugInfo("mustBeLessOrEq", a, " <= ", bound)
 
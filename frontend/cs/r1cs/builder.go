@@ -17,6 +17,7 @@ limitations under the License.
 package r1cs
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"math/big"
@@ -40,6 +41,8 @@ import (
 	bn254r1cs "github.com/consensys/gnark/internal/backend/bn254/cs"
 	bw6633r1cs "github.com/consensys/gnark/internal/backend/bw6-633/cs"
 	bw6761r1cs "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/internal/kvstore"
 	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
 )
@@ -51,11 +54,14 @@ func NewBuilder(curve ecc.ID, config frontend.CompileConfig) (frontend.Builder,
 
 type r1cs struct {
 	compiled.ConstraintSystem
-	Constraints []compiled.R1C
+	Constraints    []compiled.R1C
+	CommitmentInfo compiled.CommitmentInfo
 
 	st     cs.CoeffTable
 	config frontend.CompileConfig
 
+	kvstore.Store
+
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[uint64][]compiled.LinearExpression
 }
@@ -71,6 +77,7 @@ func newBuilder(curveID ecc.ID, config frontend.CompileConfig) *r1cs {
 		},
 		Constraints: make([]compiled.R1C, 0, config.Capacity),
 		st:          cs.NewCoeffTable(),
+		Store:       kvstore.New(),
 		mtBooleans:  make(map[uint64][]compiled.LinearExpression),
 		config:      config,
 	}
@@ -356,6 +363,7 @@ var tVariable reflect.Type
 
 func init() {
 	tVariable = reflect.ValueOf(struct{ A frontend.Variable }{}).FieldByName("A").Type()
+	hint.Register(commitmentPlaceholder)
 }
 
 // Compile constructs a rank-1 constraint sytem
@@ -380,6 +388,7 @@ func (cs *r1cs) Compile() (frontend.CompiledConstraintSystem, error) {
 	res := compiled.R1CS{
 		ConstraintSystem: cs.ConstraintSystem,
 		Constraints:      cs.Constraints,
+		CommitmentInfo:   cs.CommitmentInfo,
 	}
 
 	// sanity check
@@ -537,6 +546,87 @@ func (system *r1cs) Backend() backend.ID {
 	return backend.GROTH16
 }
 
+// Defer registers cb to be called after circuit.Define() and before Compile()
+func (system *r1cs) Defer(cb func(frontend.API) error) {
+	circuitdefer.Put(system, cb)
+}
+
+// Commit commits to the variables and returns the commitment. The Groth16
+// backend replaces the commitment by a hash of a Pedersen commitment to the
+// private committed values and of the public committed values. At most one
+// commitment is supported per circuit.
+func (system *r1cs) Commit(v ...frontend.Variable) frontend.Variable {
+	if system.CommitmentInfo.Is() {
+		panic("multiple commitments are not supported")
+	}
+
+	// collect the IDs of the committed wires, constants do not need to be committed
+	seen := make(map[int]struct{})
+	committed := make([]int, 0, len(v))
+	for _, vv := range v {
+		l := system.toVariable(vv).(compiled.LinearExpression)
+		if _, isConstant := system.ConstantValue(l); isConstant {
+			continue
+		}
+		if len(l) != 1 {
+			// commit to a single wire holding the value of the linear expression
+			res := system.newInternalVariable()
+			system.addConstraint(newR1C(l, system.one(), res))
+			l = res
+		}
+		if _, ok := seen[l[0].WireID()]; !ok {
+			seen[l[0].WireID()] = struct{}{}
+			committed = append(committed, l[0].WireID())
+		}
+	}
+	sort.Ints(committed)
+
+	inputs := make([]frontend.Variable, len(committed))
+	nbPrivate := 0
+	for i, wID := range committed {
+		visibility := schema.Internal
+		switch {
+		case wID < system.NbPublicVariables:
+			visibility = schema.Public
+		case wID < system.NbPublicVariables+system.NbSecretVariables:
+			visibility = schema.Secret
+			nbPrivate++
+		default:
+			nbPrivate++
+		}
+		inputs[i] = compiled.LinearExpression{compiled.Pack(wID, compiled.CoeffIdOne, visibility)}
+	}
+
+	res, err := system.NewHint(commitmentPlaceholder, 1, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	commitment := res[0].(compiled.LinearExpression)
+
+	system.CommitmentInfo = compiled.CommitmentInfo{
+		Committed:          committed,
+		NbPrivateCommitted: nbPrivate,
+		CommitmentIndex:    commitment[0].WireID(),
+		HintID:             hint.UUID(commitmentPlaceholder),
+	}
+
+	return commitment
+}
+
+// commitmentPlaceholder is the hint computing the commitment when solving the
+// constraint system outside of the Groth16 prover. It hashes the committed
+// values, the prover replaces it with the actual commitment.
+func commitmentPlaceholder(curveID ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	h := sha256.New()
+	buf := make([]byte, (curveID.Info().Fr.Bits+7)/8)
+	for _, in := range inputs {
+		h.Write(in.FillBytes(buf)) // #nosec G104 -- does not err
+	}
+	outputs[0].SetBytes(h.Sum(nil))
+	outputs[0].Mod(outputs[0], curveID.Info().Fr.Modulus())
+	return nil
+}
+
 // toVariable will return (and allocate if neccesary) a compiled.LinearExpression from given value
 //
 // if input is already a compiled.LinearExpression, does nothing
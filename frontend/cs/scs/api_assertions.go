@@ -99,6 +99,21 @@ func (system *scs) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Varia
 	}
 }
 
+// AssertIsLess fails if a >= b or if a or b do not fit in nbBits bits
+func (system *scs) AssertIsLess(a, b frontend.Variable, nbBits int) {
+	ca, aConstant := system.mustFitBits(a, nbBits)
+	cb, bConstant := system.mustFitBits(b, nbBits)
+	if aConstant && bConstant {
+		if ca.Cmp(cb) >= 0 {
+			panic(fmt.Sprintf("assertIsLess failed: constant(%s) >= constant(%s)", ca, cb))
+		}
+		return
+	}
+
+	// b - a - 1 is in [0, 2ⁿ) if and only if a < b
+	system.ToBinary(system.Sub(b, a, 1), nbBits)
+}
+
 func (system *scs) mustBeLessOrEqVar(a compiled.Term, bound compiled.Term) {
 
 	debug := system.AddDebugInfo("mustBeLessOrEq", a, " <= ", bound)
@@ -40,6 +40,8 @@ import (
 	bn254r1cs "github.com/consensys/gnark/internal/backend/bn254/cs"
 	bw6633r1cs "github.com/consensys/gnark/internal/backend/bw6-633/cs"
 	bw6761r1cs "github.com/consensys/gnark/internal/backend/bw6-761/cs"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/internal/kvstore"
 	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
 )
@@ -55,6 +57,8 @@ type scs struct {
 	st     cs.CoeffTable
 	config frontend.CompileConfig
 
+	kvstore.Store
+
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[int]struct{}
 }
@@ -71,6 +75,7 @@ func newBuilder(curveID ecc.ID, config frontend.CompileConfig) *scs {
 		mtBooleans:  make(map[int]struct{}),
 		Constraints: make([]compiled.SparseR1C, 0, config.Capacity),
 		st:          cs.NewCoeffTable(),
+		Store:       kvstore.New(),
 		config:      config,
 	}
 
@@ -465,6 +470,11 @@ func (system *scs) Backend() backend.ID {
 	return backend.PLONK
 }
 
+// Defer registers cb to be called after circuit.Define() and before Compile()
+func (system *scs) Defer(cb func(frontend.API) error) {
+	circuitdefer.Put(system, cb)
+}
+
 // Tag creates a tag at a given place in a circuit. The state of the tag may contain informations needed to
 // measure constraints, variables and coefficients creations through AddCounter
 func (system *scs) Tag(name string) frontend.Tag {
@@ -1,6 +1,6 @@
 module github.com/consensys/gnark
 
-go 1.17
+go 1.18
 
 require (
 	github.com/consensys/bavard v0.1.13
@@ -9,6 +9,7 @@ require (
 	github.com/leanovate/gopter v0.2.9
 	github.com/rs/zerolog v1.26.1
 	github.com/stretchr/testify v1.8.0
+	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa
 )
 
 replace github.com/fxamacker/cbor/v2 v2.2.0 => github.com/overeality-zkbridge/cbor/v2 v2.0.0-20220804005221-6dcd031a976c
@@ -19,7 +20,6 @@ require (
 	github.com/mmcloughlin/addchain v0.4.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/x448/float16 v0.8.4 // indirect
-	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa // indirect
 	golang.org/x/sys v0.0.0-20220727055044-e65921a090b8 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
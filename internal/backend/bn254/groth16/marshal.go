@@ -18,10 +18,13 @@ package groth16
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	curve "github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend/compiled"
 	"io"
 	"sync"
 	"io/ioutil"
@@ -30,14 +33,14 @@ import (
 )
 
 // WriteTo writes binary encoding of the Proof elements to writer
-// points are stored in compressed form Ar | Krs | Bs
+// points are stored in compressed form Ar | Bs | Krs | Commitment | CommitmentPok
 // use WriteRawTo(...) to encode the proof without point compression
 func (proof *Proof) WriteTo(w io.Writer) (n int64, err error) {
 	return proof.writeTo(w, false)
 }
 
 // WriteRawTo writes binary encoding of the Proof elements to writer
-// points are stored in uncompressed form Ar | Krs | Bs
+// points are stored in uncompressed form Ar | Bs | Krs | Commitment | CommitmentPok
 // use WriteTo(...) to encode the proof with point compression
 func (proof *Proof) WriteRawTo(w io.Writer) (n int64, err error) {
 	return proof.writeTo(w, true)
@@ -60,6 +63,12 @@ func (proof *Proof) writeTo(w io.Writer, raw bool) (int64, error) {
 	if err := enc.Encode(&proof.Krs); err != nil {
 		return enc.BytesWritten(), err
 	}
+	if err := enc.Encode(&proof.Commitment); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(&proof.CommitmentPok); err != nil {
+		return enc.BytesWritten(), err
+	}
 	return enc.BytesWritten(), nil
 }
 
@@ -78,6 +87,16 @@ func (proof *Proof) ReadFrom(r io.Reader) (n int64, err error) {
 	if err := dec.Decode(&proof.Krs); err != nil {
 		return dec.BytesRead(), err
 	}
+	// proofs of circuits without commitment may end here
+	if err := dec.Decode(&proof.Commitment); err != nil {
+		if errors.Is(err, io.EOF) {
+			return dec.BytesRead(), nil
+		}
+		return dec.BytesRead(), err
+	}
+	if err := dec.Decode(&proof.CommitmentPok); err != nil {
+		return dec.BytesRead(), err
+	}
 
 	return dec.BytesRead(), nil
 }
@@ -100,6 +119,7 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
 // follows bellman format:
 // https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs#L143
 // [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2,uint32(len(Kvk)),[Kvk]1
+// followed by the commitment key and info if the circuit commits to wires, see writeCommitmentTo
 func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	var enc *curve.Encoder
 	if raw {
@@ -132,14 +152,45 @@ func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	if err := enc.Encode(vk.G1.K); err != nil {
 		return enc.BytesWritten(), err
 	}
+
+	if vk.CommitmentInfo.Is() {
+		if err := vk.writeCommitmentTo(enc); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
 	return enc.BytesWritten(), nil
 }
 
+// writeCommitmentTo serialization format:
+// [1]2,-[σ]2,uint64(len(Committed)),uint64(NbPrivateCommitted),uint64(CommitmentIndex),uint64(HintID),[uint64(Committed)]
+func (vk *VerifyingKey) writeCommitmentTo(enc *curve.Encoder) error {
+	info := &vk.CommitmentInfo
+	toEncode := []interface{}{
+		&vk.CommitmentKey.G,
+		&vk.CommitmentKey.GSigmaNeg,
+		uint64(len(info.Committed)),
+		uint64(info.NbPrivateCommitted),
+		uint64(info.CommitmentIndex),
+		uint64(info.HintID),
+	}
+	for _, wID := range info.Committed {
+		toEncode = append(toEncode, uint64(wID))
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ReadFrom attempts to decode a VerifyingKey from reader
 // VerifyingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 // serialization format:
 // https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs#L143
 // [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2,uint32(len(Kvk)),[Kvk]1
+// followed by the commitment key and info if the circuit commits to wires, see writeCommitmentTo
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	return vk.readFrom(r)
 }
@@ -178,6 +229,10 @@ func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)
 		return dec.BytesRead(), err
 	}
 
+	if err := vk.readCommitmentFrom(dec); err != nil {
+		return dec.BytesRead(), err
+	}
+
 	// recompute vk.e (e(α, β)) and  -[δ]2, -[γ]2
 	var err error
 	vk.e, err = curve.Pair([]curve.G1Affine{vk.G1.Alpha}, []curve.G2Affine{vk.G2.Beta})
@@ -190,6 +245,50 @@ func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)
 	return dec.BytesRead(), nil
 }
 
+// readCommitmentFrom decodes the commitment key and info written by writeCommitmentTo.
+// Keys of circuits without commitment end before, in which case it does nothing.
+func (vk *VerifyingKey) readCommitmentFrom(dec *curve.Decoder) error {
+	if err := dec.Decode(&vk.CommitmentKey.G); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	decode := func(v interface{}) error {
+		if err := dec.Decode(v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		return nil
+	}
+	if err := decode(&vk.CommitmentKey.GSigmaNeg); err != nil {
+		return err
+	}
+	var nbCommitted, nbPrivateCommitted, commitmentIndex, hintID uint64
+	for _, v := range []interface{}{&nbCommitted, &nbPrivateCommitted, &commitmentIndex, &hintID} {
+		if err := decode(v); err != nil {
+			return err
+		}
+	}
+	info := compiled.CommitmentInfo{
+		Committed:          make([]int, nbCommitted),
+		NbPrivateCommitted: int(nbPrivateCommitted),
+		CommitmentIndex:    int(commitmentIndex),
+		HintID:             hint.ID(hintID),
+	}
+	for i := range info.Committed {
+		var wID uint64
+		if err := decode(&wID); err != nil {
+			return err
+		}
+		info.Committed[i] = int(wID)
+	}
+	vk.CommitmentInfo = info
+	return nil
+}
+
 // WriteTo writes binary encoding of the key elements to writer
 // points are compressed
 // use WriteRawTo(...) to encode the key without point compression
@@ -494,6 +593,13 @@ func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	//infinityB
 	w.Write(boolArrayToByte(pk.InfinityB))
 
+	//commitment key
+	w.Write(G1AffineArrayToBytes(pk.CommitmentKey.Basis))
+	w.Write(G1AffineArrayToBytes(pk.CommitmentKey.BasisExpSigma))
+	w.Write(G1AffineToBytes(pk.CommitmentKey.Blinding))
+	w.Write(G1AffineToBytes(pk.CommitmentKey.BlindingExpSigma))
+	w.Write(G1AffineToBytes(pk.CommitmentKey.BlindingDelta))
+
 	return 0, nil
 }
 
@@ -767,9 +873,57 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 		panic("pk.InfinityB length is not equal to nbWires")
 	}
 
+	//commitment key
+	if err := pk.readCommitmentKey(r); err != nil {
+		return 0, err
+	}
+
 	return 0, nil
 }
 
+// readCommitmentKey reads the commitment key written by writeTo. Keys
+// serialized before the commitments were supported end before, in which case
+// the commitment key is left empty.
+func (pk *ProvingKey) readCommitmentKey(r io.Reader) error {
+	read := func(n int) ([]byte, error) {
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	bases := []*[]curve.G1Affine{&pk.CommitmentKey.Basis, &pk.CommitmentKey.BasisExpSigma}
+	for i, basis := range bases {
+		lengthBytes, err := read(8)
+		if err != nil {
+			if i == 0 && err == io.EOF {
+				return nil
+			}
+			return io.ErrUnexpectedEOF
+		}
+		length := byte_to_int(lengthBytes)
+		if length < 0 {
+			return errors.New("invalid commitment key length")
+		}
+		*basis = make([]curve.G1Affine, 0)
+		for j := 0; j < length; j++ {
+			b, err := read(64)
+			if err != nil {
+				return io.ErrUnexpectedEOF
+			}
+			*basis = append(*basis, byte_to_G1Affine(b))
+		}
+	}
+	for _, p := range []*curve.G1Affine{&pk.CommitmentKey.Blinding, &pk.CommitmentKey.BlindingExpSigma, &pk.CommitmentKey.BlindingDelta} {
+		b, err := read(64)
+		if err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		*p = byte_to_G1Affine(b)
+	}
+	return nil
+}
+
 
 
 func readG2AffineArrayParallel(buf []byte, offset *int, maxConcurrency int) []curve.G2Affine {
@@ -1001,5 +1155,40 @@ func ReadFromBytes(pk *ProvingKey, buf []byte, maxConcurrency int) (int64, error
 		panic("pk.InfinityB length is not equal to nbWires")
 	}
 
+	//commitment key
+	if err := pk.readCommitmentKeyBytes(buf, &offset, maxConcurrency); err != nil {
+		return 0, err
+	}
+
 	return 0, nil
 }
+
+// readCommitmentKeyBytes reads the commitment key at the offset of buf, see
+// readCommitmentKey.
+func (pk *ProvingKey) readCommitmentKeyBytes(buf []byte, offset *int, maxConcurrency int) error {
+	if *offset == len(buf) {
+		return nil
+	}
+	bases := []*[]curve.G1Affine{&pk.CommitmentKey.Basis, &pk.CommitmentKey.BasisExpSigma}
+	for _, basis := range bases {
+		if len(buf)-*offset < 8 {
+			return io.ErrUnexpectedEOF
+		}
+		length := byte_to_int(buf[*offset : *offset+8])
+		if length < 0 {
+			return errors.New("invalid commitment key length")
+		}
+		if (len(buf)-*offset-8)/64 < length {
+			return io.ErrUnexpectedEOF
+		}
+		*basis = readG1AffineArrayParallel(buf, offset, maxConcurrency)
+	}
+	if len(buf)-*offset < 3*64 {
+		return io.ErrUnexpectedEOF
+	}
+	pk.CommitmentKey.Blinding = byte_to_G1Affine(buf[*offset : *offset+64])
+	pk.CommitmentKey.BlindingExpSigma = byte_to_G1Affine(buf[*offset+64 : *offset+128])
+	pk.CommitmentKey.BlindingDelta = byte_to_G1Affine(buf[*offset+128 : *offset+192])
+	*offset += 3 * 64
+	return nil
+}
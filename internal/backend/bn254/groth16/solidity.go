@@ -5,6 +5,9 @@ package groth16
 // this is an experimental feature and gnark solidity generator as not been thoroughly tested
 const solidityTemplate = `
 {{- $lenK := len .G1.K }}
+{{- $commit := .CommitmentInfo.Is }}
+{{- $lenInput := sub $lenK 1 }}
+{{- if $commit }}{{ $lenInput = sub $lenK 2 }}{{ end }}
 // SPDX-License-Identifier: AML
 // 
 // Copyright 2017 Christian Reitwiessner
@@ -147,6 +150,46 @@ library Pairing {
 
         return out[0] != 0;
     }
+
+    /* @return The result of computing the pairing check
+     *         e(a1, a2) * e(b1, b2) == 1
+     */
+    function pairing2(
+        G1Point memory a1,
+        G2Point memory a2,
+        G1Point memory b1,
+        G2Point memory b2
+    ) internal view returns (bool) {
+
+        G1Point[2] memory p1 = [a1, b1];
+        G2Point[2] memory p2 = [a2, b2];
+        uint256 inputSize = 12;
+        uint256[] memory input = new uint256[](inputSize);
+
+        for (uint256 i = 0; i < 2; i++) {
+            uint256 j = i * 6;
+            input[j + 0] = p1[i].X;
+            input[j + 1] = p1[i].Y;
+            input[j + 2] = p2[i].X[0];
+            input[j + 3] = p2[i].X[1];
+            input[j + 4] = p2[i].Y[0];
+            input[j + 5] = p2[i].Y[1];
+        }
+
+        uint256[1] memory out;
+        bool success;
+
+        // solium-disable-next-line security/no-inline-assembly
+        assembly {
+            success := staticcall(sub(gas(), 2000), 8, add(input, 0x20), mul(inputSize, 0x20), out, 0x20)
+            // Use "invalid" to make gas estimation work
+            switch success case 0 { invalid() }
+        }
+
+        require(success,"pairing-opcode-failed");
+
+        return out[0] != 0;
+    }
 }
 
 contract Verifier {
@@ -162,6 +205,10 @@ contract Verifier {
         Pairing.G2Point gamma2;
         Pairing.G2Point delta2;
         Pairing.G1Point[{{$lenK}}] IC;
+        {{- if $commit }}
+        Pairing.G2Point commitmentG;
+        Pairing.G2Point commitmentGSigmaNeg;
+        {{- end }}
     }
 
     struct Proof {
@@ -178,17 +225,29 @@ contract Verifier {
         {{- range $i, $ki := .G1.K }}   
         vk.IC[{{$i}}] = Pairing.G1Point(uint256({{$ki.X.String}}), uint256({{$ki.Y.String}}));
         {{- end}}
+        {{- if $commit }}
+        vk.commitmentG = Pairing.G2Point([uint256({{.CommitmentKey.G.X.A1.String}}), uint256({{.CommitmentKey.G.X.A0.String}})], [uint256({{.CommitmentKey.G.Y.A1.String}}), uint256({{.CommitmentKey.G.Y.A0.String}})]);
+        vk.commitmentGSigmaNeg = Pairing.G2Point([uint256({{.CommitmentKey.GSigmaNeg.X.A1.String}}), uint256({{.CommitmentKey.GSigmaNeg.X.A0.String}})], [uint256({{.CommitmentKey.GSigmaNeg.Y.A1.String}}), uint256({{.CommitmentKey.GSigmaNeg.Y.A0.String}})]);
+        {{- end }}
     }
     
     /*
      * @returns Whether the proof is valid given the hardcoded verifying key
      *          above and the public inputs
+     {{- if $commit }}
+     *          The commitment and its proof of knowledge are Proof.Commitment
+     *          and Proof.CommitmentPok
+     {{- end }}
      */
     function verifyProof(
         uint256[2] memory a,
         uint256[2][2] memory b,
         uint256[2] memory c,
-        uint256[{{sub $lenK 1}}] memory input
+        {{- if $commit }}
+        uint256[2] memory commitment,
+        uint256[2] memory commitmentPok,
+        {{- end }}
+        uint256[{{$lenInput}}] memory input
     ) public view returns (bool r) {
 
         Proof memory proof;
@@ -219,6 +278,21 @@ contract Verifier {
             require(input[i] < SNARK_SCALAR_FIELD,"verifier-gte-snark-scalar-field");
             vk_x = Pairing.plus(vk_x, Pairing.scalar_mul(vk.IC[i + 1], input[i]));
         }
+        {{- if $commit }}
+
+        // Check the knowledge of the committed values and add the commitment
+        Pairing.G1Point memory d = Pairing.G1Point(commitment[0], commitment[1]);
+        Pairing.G1Point memory pok = Pairing.G1Point(commitmentPok[0], commitmentPok[1]);
+        require(d.X < PRIME_Q && d.Y < PRIME_Q, "verifier-commitment-gte-prime-q");
+        require(pok.X < PRIME_Q && pok.Y < PRIME_Q, "verifier-commitment-pok-gte-prime-q");
+        require(Pairing.pairing2(d, vk.commitmentGSigmaNeg, pok, vk.commitmentG), "verifier-commitment-pok-failed");
+
+        // The commitment value in the circuit hashes the commitment and the committed public inputs
+        uint256 h = uint256(sha256(abi.encodePacked(commitment[0], commitment[1]
+            {{- range $w := .CommitmentInfo.PublicCommitted }}, input[{{sub $w 1}}]{{ end }}))) % SNARK_SCALAR_FIELD;
+        vk_x = Pairing.plus(vk_x, Pairing.scalar_mul(vk.IC[{{sub $lenK 1}}], h));
+        vk_x = Pairing.plus(vk_x, d);
+        {{- end }}
 
         vk_x = Pairing.plus(vk_x, vk.IC[0]);
 
@@ -17,19 +17,22 @@
 package groth16
 
 import (
+	"errors"
 	curve "github.com/consensys/gnark-crypto/ecc/bw6-633"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend/compiled"
 	"io"
 )
 
 // WriteTo writes binary encoding of the Proof elements to writer
-// points are stored in compressed form Ar | Krs | Bs
+// points are stored in compressed form Ar | Bs | Krs | Commitment | CommitmentPok
 // use WriteRawTo(...) to encode the proof without point compression
 func (proof *Proof) WriteTo(w io.Writer) (n int64, err error) {
 	return proof.writeTo(w, false)
 }
 
 // WriteRawTo writes binary encoding of the Proof elements to writer
-// points are stored in uncompressed form Ar | Krs | Bs
+// points are stored in uncompressed form Ar | Bs | Krs | Commitment | CommitmentPok
 // use WriteTo(...) to encode the proof with point compression
 func (proof *Proof) WriteRawTo(w io.Writer) (n int64, err error) {
 	return proof.writeTo(w, true)
@@ -52,6 +55,12 @@ func (proof *Proof) writeTo(w io.Writer, raw bool) (int64, error) {
 	if err := enc.Encode(&proof.Krs); err != nil {
 		return enc.BytesWritten(), err
 	}
+	if err := enc.Encode(&proof.Commitment); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(&proof.CommitmentPok); err != nil {
+		return enc.BytesWritten(), err
+	}
 	return enc.BytesWritten(), nil
 }
 
@@ -70,6 +79,16 @@ func (proof *Proof) ReadFrom(r io.Reader) (n int64, err error) {
 	if err := dec.Decode(&proof.Krs); err != nil {
 		return dec.BytesRead(), err
 	}
+	// proofs of circuits without commitment may end here
+	if err := dec.Decode(&proof.Commitment); err != nil {
+		if errors.Is(err, io.EOF) {
+			return dec.BytesRead(), nil
+		}
+		return dec.BytesRead(), err
+	}
+	if err := dec.Decode(&proof.CommitmentPok); err != nil {
+		return dec.BytesRead(), err
+	}
 
 	return dec.BytesRead(), nil
 }
@@ -92,6 +111,7 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
 // follows bellman format:
 // https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs#L143
 // [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2,uint32(len(Kvk)),[Kvk]1
+// followed by the commitment key and info if the circuit commits to wires, see writeCommitmentTo
 func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	var enc *curve.Encoder
 	if raw {
@@ -124,14 +144,45 @@ func (vk *VerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 	if err := enc.Encode(vk.G1.K); err != nil {
 		return enc.BytesWritten(), err
 	}
+
+	if vk.CommitmentInfo.Is() {
+		if err := vk.writeCommitmentTo(enc); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
 	return enc.BytesWritten(), nil
 }
 
+// writeCommitmentTo serialization format:
+// [1]2,-[σ]2,uint64(len(Committed)),uint64(NbPrivateCommitted),uint64(CommitmentIndex),uint64(HintID),[uint64(Committed)]
+func (vk *VerifyingKey) writeCommitmentTo(enc *curve.Encoder) error {
+	info := &vk.CommitmentInfo
+	toEncode := []interface{}{
+		&vk.CommitmentKey.G,
+		&vk.CommitmentKey.GSigmaNeg,
+		uint64(len(info.Committed)),
+		uint64(info.NbPrivateCommitted),
+		uint64(info.CommitmentIndex),
+		uint64(info.HintID),
+	}
+	for _, wID := range info.Committed {
+		toEncode = append(toEncode, uint64(wID))
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ReadFrom attempts to decode a VerifyingKey from reader
 // VerifyingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
 // serialization format:
 // https://github.com/zkcrypto/bellman/blob/fa9be45588227a8c6ec34957de3f68705f07bd92/src/groth16/mod.rs#L143
 // [α]1,[β]1,[β]2,[γ]2,[δ]1,[δ]2,uint32(len(Kvk)),[Kvk]1
+// followed by the commitment key and info if the circuit commits to wires, see writeCommitmentTo
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	return vk.readFrom(r)
 }
@@ -170,6 +221,10 @@ func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)
 		return dec.BytesRead(), err
 	}
 
+	if err := vk.readCommitmentFrom(dec); err != nil {
+		return dec.BytesRead(), err
+	}
+
 	// recompute vk.e (e(α, β)) and  -[δ]2, -[γ]2
 	var err error
 	vk.e, err = curve.Pair([]curve.G1Affine{vk.G1.Alpha}, []curve.G2Affine{vk.G2.Beta})
@@ -182,6 +237,50 @@ func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)
 	return dec.BytesRead(), nil
 }
 
+// readCommitmentFrom decodes the commitment key and info written by writeCommitmentTo.
+// Keys of circuits without commitment end before, in which case it does nothing.
+func (vk *VerifyingKey) readCommitmentFrom(dec *curve.Decoder) error {
+	if err := dec.Decode(&vk.CommitmentKey.G); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+	decode := func(v interface{}) error {
+		if err := dec.Decode(v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		return nil
+	}
+	if err := decode(&vk.CommitmentKey.GSigmaNeg); err != nil {
+		return err
+	}
+	var nbCommitted, nbPrivateCommitted, commitmentIndex, hintID uint64
+	for _, v := range []interface{}{&nbCommitted, &nbPrivateCommitted, &commitmentIndex, &hintID} {
+		if err := decode(v); err != nil {
+			return err
+		}
+	}
+	info := compiled.CommitmentInfo{
+		Committed:          make([]int, nbCommitted),
+		NbPrivateCommitted: int(nbPrivateCommitted),
+		CommitmentIndex:    int(commitmentIndex),
+		HintID:             hint.ID(hintID),
+	}
+	for i := range info.Committed {
+		var wID uint64
+		if err := decode(&wID); err != nil {
+			return err
+		}
+		info.Committed[i] = int(wID)
+	}
+	vk.CommitmentInfo = info
+	return nil
+}
+
 // WriteTo writes binary encoding of the key elements to writer
 // points are compressed
 // use WriteRawTo(...) to encode the key without point compression
@@ -226,6 +325,11 @@ func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 		pk.NbInfinityB,
 		pk.InfinityA,
 		pk.InfinityB,
+		pk.CommitmentKey.Basis,
+		pk.CommitmentKey.BasisExpSigma,
+		&pk.CommitmentKey.Blinding,
+		&pk.CommitmentKey.BlindingExpSigma,
+		&pk.CommitmentKey.BlindingDelta,
 	}
 
 	for _, v := range toEncode {
@@ -291,6 +395,27 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 	if err := dec.Decode(&pk.InfinityB); err != nil {
 		return n + dec.BytesRead(), err
 	}
+	// keys serialized before the commitments were supported end here
+	if err := dec.Decode(&pk.CommitmentKey.Basis); err != nil {
+		if errors.Is(err, io.EOF) {
+			return n + dec.BytesRead(), nil
+		}
+		return n + dec.BytesRead(), err
+	}
+	toDecode = []interface{}{
+		&pk.CommitmentKey.BasisExpSigma,
+		&pk.CommitmentKey.Blinding,
+		&pk.CommitmentKey.BlindingExpSigma,
+		&pk.CommitmentKey.BlindingDelta,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return n + dec.BytesRead(), err
+		}
+	}
 
 	return n + dec.BytesRead(), nil
 }
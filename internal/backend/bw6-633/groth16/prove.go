@@ -28,6 +28,7 @@ import (
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/hint"
 	bw6_633witness "github.com/consensys/gnark/internal/backend/bw6-633/witness"
 	"github.com/consensys/gnark/internal/utils"
 	"github.com/consensys/gnark/logger"
@@ -40,13 +41,15 @@ import (
 // with a valid statement and a VerifyingKey
 // Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
 type Proof struct {
-	Ar, Krs curve.G1Affine
-	Bs      curve.G2Affine
+	Ar, Krs                   curve.G1Affine
+	Bs                        curve.G2Affine
+	Commitment, CommitmentPok curve.G1Affine // commitment to the committed private wires and its proof of knowledge, see frontend.Committer
 }
 
 // isValid ensures proof elements are in the correct subgroup
 func (proof *Proof) isValid() bool {
-	return proof.Ar.IsInSubGroup() && proof.Krs.IsInSubGroup() && proof.Bs.IsInSubGroup()
+	return proof.Ar.IsInSubGroup() && proof.Krs.IsInSubGroup() && proof.Bs.IsInSubGroup() &&
+		proof.Commitment.IsInSubGroup() && proof.CommitmentPok.IsInSubGroup()
 }
 
 // CurveID returns the curveID
@@ -62,6 +65,33 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bw6_633witness.Witness, opt ba
 
 	log := logger.Logger().With().Str("curve", r1cs.CurveID().String()).Int("nbConstraints", len(r1cs.Constraints)).Str("backend", "groth16").Logger()
 
+	proof := &Proof{}
+	var commitmentBlinding fr.Element
+
+	// the solver computes the commitment when it needs it; we replace the placeholder hint
+	// by one computing the commitment and its hash
+	if r1cs.CommitmentInfo.Is() {
+		hintFunctions := make(map[hint.ID]hint.Function, len(opt.HintFunctions)+1)
+		for id, f := range opt.HintFunctions {
+			hintFunctions[id] = f
+		}
+		hintFunctions[r1cs.CommitmentInfo.HintID] = func(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+			values := make([]fr.Element, len(inputs))
+			for i := range inputs {
+				values[i].SetBigInt(inputs[i])
+			}
+			nbPublicCommitted := r1cs.CommitmentInfo.NbPublicCommitted()
+			var err error
+			if proof.Commitment, proof.CommitmentPok, commitmentBlinding, err = pk.commit(values[nbPublicCommitted:]); err != nil {
+				return err
+			}
+			res := commitmentHash(&proof.Commitment, values[:nbPublicCommitted])
+			res.ToBigIntRegular(outputs[0])
+			return nil
+		}
+		opt.HintFunctions = hintFunctions
+	}
+
 	// solve the R1CS and compute the a, b, c vectors
 	a := make([]fr.Element, len(r1cs.Constraints), pk.Domain.Cardinality)
 	b := make([]fr.Element, len(r1cs.Constraints), pk.Domain.Cardinality)
@@ -90,6 +120,23 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bw6_633witness.Witness, opt ba
 		}
 	})
 
+	// the committed private wires and the commitment are not in pk.G1.K
+	wireValuesK := wireValues[r1cs.NbPublicVariables:]
+	if info := &r1cs.CommitmentInfo; info.Is() {
+		committed := info.PrivateCommitted()
+		wireValuesK = make([]fr.Element, 0, len(pk.G1.K))
+		for i, j := r1cs.NbPublicVariables, 0; i < len(wireValues); i++ {
+			if j < len(committed) && committed[j] == i {
+				j++
+				continue
+			}
+			if i == info.CommitmentIndex {
+				continue
+			}
+			wireValuesK = append(wireValuesK, wireValues[i])
+		}
+	}
+
 	// H (witness reduction / FFT part)
 	var h []fr.Element
 	chHDone := make(chan struct{}, 1)
@@ -149,7 +196,6 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bw6_633witness.Witness, opt ba
 	// computes r[δ], s[δ], kr[δ]
 	deltas := curve.BatchScalarMultiplicationG1(&pk.G1.Delta, []fr.Element{_r, _s, _kr})
 
-	proof := &Proof{}
 	var bs1, ar curve.G1Jac
 
 	n := runtime.NumCPU()
@@ -192,11 +238,20 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bw6_633witness.Witness, opt ba
 			_, err := krs2.MultiExp(pk.G1.Z, h, ecc.MultiExpConfig{NbTasks: n / 2})
 			chKrs2Done <- err
 		}()
-		if _, err := krs.MultiExp(pk.G1.K, wireValues[r1cs.NbPublicVariables:], ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
+		if _, err := krs.MultiExp(pk.G1.K, wireValuesK, ecc.MultiExpConfig{NbTasks: n / 2}); err != nil {
 			chKrsDone <- err
 			return
 		}
 		krs.AddMixed(&deltas[2])
+		if r1cs.CommitmentInfo.Is() {
+			// the verifier adds the commitment, blinded by [η/γ]1, to the public inputs
+			// remove the blinding, e([η/γ]1, [γ]2) == e([η/δ]1, [δ]2)
+			var blinding big.Int
+			commitmentBlinding.ToBigIntRegular(&blinding)
+			p1.FromAffine(&pk.CommitmentKey.BlindingDelta)
+			p1.ScalarMultiplication(&p1, &blinding)
+			krs.SubAssign(&p1)
+		}
 		n := 3
 		for n != 0 {
 			select {
@@ -272,6 +327,31 @@ func Prove(r1cs *cs.R1CS, pk *ProvingKey, witness bw6_633witness.Witness, opt ba
 	return proof, nil
 }
 
+// commit computes the Pedersen commitment to the committed private values and
+// its proof of knowledge. The commitment is blinded by a random multiple of
+// [η/γ]1 so that it does not reveal the values, the blinding factor is
+// returned to be removed from Krs. The values are in Montgomery form.
+func (pk *ProvingKey) commit(values []fr.Element) (commitment, pok curve.G1Affine, blinding fr.Element, err error) {
+	nbCommitted := len(pk.CommitmentKey.Basis)
+	if len(values) != nbCommitted {
+		err = fmt.Errorf("invalid number of committed values, got %d, expected %d", len(values), nbCommitted)
+		return
+	}
+	if _, err = blinding.SetRandom(); err != nil {
+		return
+	}
+	basis := append(pk.CommitmentKey.Basis[:nbCommitted:nbCommitted], pk.CommitmentKey.Blinding)
+	basisExpSigma := append(pk.CommitmentKey.BasisExpSigma[:nbCommitted:nbCommitted], pk.CommitmentKey.BlindingExpSigma)
+	scalars := append(values[:nbCommitted:nbCommitted], blinding)
+
+	config := ecc.MultiExpConfig{ScalarsMont: true}
+	if _, err = commitment.MultiExp(basis, scalars, config); err != nil {
+		return
+	}
+	_, err = pok.MultiExp(basisExpSigma, scalars, config)
+	return
+}
+
 func computeH(a, b, c []fr.Element, domain *fft.Domain) []fr.Element {
 	// H part of Krs
 	// Compute H (hz=ab-c, where z=-2 on ker X^n+1 (z(x)=x^n-1))
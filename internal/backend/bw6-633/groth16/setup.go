@@ -41,7 +41,7 @@ type ProvingKey struct {
 	G1 struct {
 		Alpha, Beta, Delta curve.G1Affine
 		A, B, Z            []curve.G1Affine
-		K                  []curve.G1Affine // the indexes correspond to the private wires
+		K                  []curve.G1Affine // the indexes correspond to the private wires, except the committed ones and the commitment
 	}
 
 	// [β]2, [δ]2, [B(t)]2
@@ -53,6 +53,13 @@ type ProvingKey struct {
 	// if InfinityA[i] == true, the point G1.A[i] == infinity
 	InfinityA, InfinityB     []bool
 	NbInfinityA, NbInfinityB uint64
+
+	// [Kpk(t)/γ]1 and [σKpk(t)/γ]1 for the committed private wires, see frontend.Committer
+	// [η/γ]1 and [ση/γ]1 blind the commitment and [η/δ]1 removes the blinding from Krs
+	CommitmentKey struct {
+		Basis, BasisExpSigma                      []curve.G1Affine
+		Blinding, BlindingExpSigma, BlindingDelta curve.G1Affine
+	}
 }
 
 // VerifyingKey is used by a Groth16 verifier to verify the validity of a proof and a statement
@@ -62,7 +69,7 @@ type VerifyingKey struct {
 	G1 struct {
 		Alpha       curve.G1Affine
 		Beta, Delta curve.G1Affine   // unused, here for compatibility purposes
-		K           []curve.G1Affine // The indexes correspond to the public wires
+		K           []curve.G1Affine // The indexes correspond to the public wires, followed by the commitment if any
 	}
 
 	// [β]2, [δ]2, [γ]2,
@@ -74,6 +81,12 @@ type VerifyingKey struct {
 
 	// e(α, β)
 	e curve.GT // not serialized
+
+	// [1]2, -[σ]2 to verify the knowledge of the committed values
+	CommitmentKey struct {
+		G, GSigmaNeg curve.G2Affine
+	}
+	CommitmentInfo compiled.CommitmentInfo // only the public committed wires are needed
 }
 
 // Setup constructs the SRS
@@ -93,6 +106,15 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	nbPublicWires := int(r1cs.NbPublicVariables)
 	nbPrivateWires := r1cs.NbSecretVariables + r1cs.NbInternalVariables
 
+	// the committed private wires and the commitment are moved from the private to the public part
+	// the verifier gets them through the commitment and the commitment value respectively
+	commitmentInfo := &r1cs.CommitmentInfo
+	nbPrivateCommitted := commitmentInfo.NbPrivateCommitted
+	if commitmentInfo.Is() {
+		nbPublicWires++
+		nbPrivateWires -= nbPrivateCommitted + 1
+	}
+
 	// Setting group for fft
 	domain := fft.NewDomain(uint64(len(r1cs.Constraints)))
 
@@ -119,34 +141,49 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 
 	// the G1 scalars are ordered (arbitrary) as follow:
 	//
-	// [[α], [β], [δ], [A(i)], [B(i)], [pk.K(i)], [Z(i)], [vk.K(i)]]
+	// [[α], [β], [δ], [A(i)], [B(i)], [pk.K(i)], [Z(i)], [vk.K(i)], [ck(i)], [σck(i)], [η/γ], [ση/γ], [η/δ]]
 	// len(A) == len(B) == nbWires
 	// len(pk.K) == nbPrivateWires
 	// len(vk.K) == nbPublicWires
 	// len(Z) == domain.Cardinality
+	// len(ck) == nbPrivateCommitted
+	// the blinding bases [η/γ], [ση/γ], [η/δ] are present only if the circuit commits to wires
 
-	// compute scalars for pkK and vkK
+	// compute scalars for pkK, vkK and the commitment key ck
 	pkK := make([]fr.Element, nbPrivateWires)
 	vkK := make([]fr.Element, nbPublicWires)
+	ckK := make([]fr.Element, nbPrivateCommitted)
+	ckKSigma := make([]fr.Element, nbPrivateCommitted)
 
 	var t0, t1 fr.Element
 
-	for i := 0; i < nbPublicWires; i++ {
+	computeK := func(i int, coeff *fr.Element) fr.Element {
 		t1.Mul(&A[i], &toxicWaste.beta)
 		t0.Mul(&B[i], &toxicWaste.alpha)
 		t1.Add(&t1, &t0).
 			Add(&t1, &C[i]).
-			Mul(&t1, &toxicWaste.gammaInv)
-		vkK[i] = t1.ToRegular()
-	}
-
-	for i := 0; i < nbPrivateWires; i++ {
-		t1.Mul(&A[i+nbPublicWires], &toxicWaste.beta)
-		t0.Mul(&B[i+nbPublicWires], &toxicWaste.alpha)
-		t1.Add(&t1, &t0).
-			Add(&t1, &C[i+nbPublicWires]).
-			Mul(&t1, &toxicWaste.deltaInv)
-		pkK[i] = t1.ToRegular()
+			Mul(&t1, coeff)
+		return t1
+	}
+
+	for i := 0; i < int(r1cs.NbPublicVariables); i++ {
+		vkK[i] = computeK(i, &toxicWaste.gammaInv).ToRegular()
+	}
+
+	committed := commitmentInfo.PrivateCommitted()
+	for i, nbCommitted, j := int(r1cs.NbPublicVariables), 0, 0; i < nbWires; i++ {
+		if nbCommitted < len(committed) && committed[nbCommitted] == i {
+			k := computeK(i, &toxicWaste.gammaInv)
+			ckK[nbCommitted] = k.ToRegular()
+			k.Mul(&k, &toxicWaste.sigma)
+			ckKSigma[nbCommitted] = k.ToRegular()
+			nbCommitted++
+		} else if commitmentInfo.Is() && i == commitmentInfo.CommitmentIndex {
+			vkK[nbPublicWires-1] = computeK(i, &toxicWaste.gammaInv).ToRegular()
+		} else {
+			pkK[j] = computeK(i, &toxicWaste.deltaInv).ToRegular()
+			j++
+		}
 	}
 
 	// convert A and B to regular form
@@ -199,13 +236,22 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	pk.NbInfinityB = uint64(nbWires - n)
 
 	// compute our batch scalar multiplication with g1 elements
-	g1Scalars := make([]fr.Element, 0, (nbWires*3)+int(domain.Cardinality)+3)
+	g1Scalars := make([]fr.Element, 0, (nbWires*3)+int(domain.Cardinality)+6+2*nbPrivateCommitted)
 	g1Scalars = append(g1Scalars, toxicWaste.alphaReg, toxicWaste.betaReg, toxicWaste.deltaReg)
 	g1Scalars = append(g1Scalars, A...)
 	g1Scalars = append(g1Scalars, B...)
 	g1Scalars = append(g1Scalars, pkK...)
 	g1Scalars = append(g1Scalars, Z...)
 	g1Scalars = append(g1Scalars, vkK...)
+	g1Scalars = append(g1Scalars, ckK...)
+	g1Scalars = append(g1Scalars, ckKSigma...)
+	if commitmentInfo.Is() {
+		var etaGamma, etaGammaSigma, etaDelta fr.Element
+		etaGamma.Mul(&toxicWaste.eta, &toxicWaste.gammaInv)
+		etaGammaSigma.Mul(&etaGamma, &toxicWaste.sigma)
+		etaDelta.Mul(&toxicWaste.eta, &toxicWaste.deltaInv)
+		g1Scalars = append(g1Scalars, etaGamma.ToRegular(), etaGammaSigma.ToRegular(), etaDelta.ToRegular())
+	}
 
 	g1PointsAff := curve.BatchScalarMultiplicationG1(&g1, g1Scalars)
 
@@ -229,18 +275,31 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 
 	offset += int(domain.Cardinality)
 
-	vk.G1.K = g1PointsAff[offset:]
+	vk.G1.K = g1PointsAff[offset : offset+nbPublicWires]
+	offset += nbPublicWires
+
+	pk.CommitmentKey.Basis = g1PointsAff[offset : offset+nbPrivateCommitted]
+	offset += nbPrivateCommitted
+
+	pk.CommitmentKey.BasisExpSigma = g1PointsAff[offset : offset+nbPrivateCommitted]
+	offset += nbPrivateCommitted
+
+	if commitmentInfo.Is() {
+		pk.CommitmentKey.Blinding = g1PointsAff[offset]
+		pk.CommitmentKey.BlindingExpSigma = g1PointsAff[offset+1]
+		pk.CommitmentKey.BlindingDelta = g1PointsAff[offset+2]
+	}
 
 	// ---------------------------------------------------------------------------------------------
 	// G2 scalars
 
 	// the G2 scalars are ordered as follow:
 	//
-	// [[B(i)], [β], [δ], [γ]]
+	// [[B(i)], [β], [δ], [γ], [σ]]
 	// len(B) == nbWires
 
 	// compute our batch scalar multiplication with g2 elements
-	g2Scalars := append(B, toxicWaste.betaReg, toxicWaste.deltaReg, toxicWaste.gammaReg)
+	g2Scalars := append(B, toxicWaste.betaReg, toxicWaste.deltaReg, toxicWaste.gammaReg, toxicWaste.sigmaReg)
 
 	g2PointsAff := curve.BatchScalarMultiplicationG2(&g2, g2Scalars)
 
@@ -256,6 +315,13 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	vk.G2.deltaNeg.Neg(&vk.G2.Delta)
 	vk.G2.gammaNeg.Neg(&vk.G2.Gamma)
 
+	// sets vk: [1]2, -[σ]2
+	if commitmentInfo.Is() {
+		vk.CommitmentKey.G = g2
+		vk.CommitmentKey.GSigmaNeg.Neg(&g2PointsAff[len(B)+3])
+		vk.CommitmentInfo = *commitmentInfo
+	}
+
 	// ---------------------------------------------------------------------------------------------
 	// Pairing: vk.e
 	vk.G1.Alpha = pk.G1.Alpha
@@ -358,11 +424,11 @@ func setupABC(r1cs *cs.R1CS, domain *fft.Domain, toxicWaste toxicWaste) (A []fr.
 type toxicWaste struct {
 
 	// Montgomery form of params
-	t, alpha, beta, gamma, delta fr.Element
-	gammaInv, deltaInv           fr.Element
+	t, alpha, beta, gamma, delta, sigma, eta fr.Element
+	gammaInv, deltaInv                       fr.Element
 
 	// Non Montgomery form of params
-	alphaReg, betaReg, gammaReg, deltaReg fr.Element
+	alphaReg, betaReg, gammaReg, deltaReg, sigmaReg fr.Element
 }
 
 func sampleToxicWaste() (toxicWaste, error) {
@@ -394,6 +460,16 @@ func sampleToxicWaste() (toxicWaste, error) {
 			return res, err
 		}
 	}
+	for res.sigma.IsZero() {
+		if _, err := res.sigma.SetRandom(); err != nil {
+			return res, err
+		}
+	}
+	for res.eta.IsZero() {
+		if _, err := res.eta.SetRandom(); err != nil {
+			return res, err
+		}
+	}
 
 	res.gammaInv.Inverse(&res.gamma)
 	res.deltaInv.Inverse(&res.delta)
@@ -402,6 +478,7 @@ func sampleToxicWaste() (toxicWaste, error) {
 	res.betaReg = res.beta.ToRegular()
 	res.gammaReg = res.gamma.ToRegular()
 	res.deltaReg = res.delta.ToRegular()
+	res.sigmaReg = res.sigma.ToRegular()
 
 	return res, nil
 }
@@ -420,12 +497,21 @@ func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	// in pk.G1.A, pk.G1.B, and pk.G2.B
 	nbZeroesA, nbZeroesB := dummyInfinityCount(r1cs)
 
+	// the committed private wires and the commitment are not in pk.G1.K
+	nbPrivateCommitted := r1cs.CommitmentInfo.NbPrivateCommitted
+	nbPrivateWires := nbWires - r1cs.NbPublicVariables
+	if r1cs.CommitmentInfo.Is() {
+		nbPrivateWires -= nbPrivateCommitted + 1
+	}
+
 	// initialize proving key
 	pk.G1.A = make([]curve.G1Affine, nbWires-nbZeroesA)
 	pk.G1.B = make([]curve.G1Affine, nbWires-nbZeroesB)
-	pk.G1.K = make([]curve.G1Affine, nbWires-r1cs.NbPublicVariables)
+	pk.G1.K = make([]curve.G1Affine, nbPrivateWires)
 	pk.G1.Z = make([]curve.G1Affine, domain.Cardinality)
 	pk.G2.B = make([]curve.G2Affine, nbWires-nbZeroesB)
+	pk.CommitmentKey.Basis = make([]curve.G1Affine, nbPrivateCommitted)
+	pk.CommitmentKey.BasisExpSigma = make([]curve.G1Affine, nbPrivateCommitted)
 
 	// set infinity markers
 	pk.InfinityA = make([]bool, nbWires)
@@ -470,6 +556,15 @@ func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	for i := 0; i < len(pk.G1.K); i++ {
 		pk.G1.K[i] = r1Aff
 	}
+	for i := 0; i < nbPrivateCommitted; i++ {
+		pk.CommitmentKey.Basis[i] = r1Aff
+		pk.CommitmentKey.BasisExpSigma[i] = r1Aff
+	}
+	if r1cs.CommitmentInfo.Is() {
+		pk.CommitmentKey.Blinding = r1Aff
+		pk.CommitmentKey.BlindingExpSigma = r1Aff
+		pk.CommitmentKey.BlindingDelta = r1Aff
+	}
 	pk.G1.Alpha = r1Aff
 	pk.G1.Beta = r1Aff
 	pk.G1.Delta = r1Aff
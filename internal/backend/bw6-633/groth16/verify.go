@@ -19,8 +19,11 @@ package groth16
 import (
 	"github.com/consensys/gnark-crypto/ecc"
 
+	"github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+
 	curve "github.com/consensys/gnark-crypto/ecc/bw6-633"
 
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	bw6_633witness "github.com/consensys/gnark/internal/backend/bw6-633/witness"
@@ -33,13 +36,18 @@ import (
 var (
 	errPairingCheckFailed         = errors.New("pairing doesn't match")
 	errCorrectSubgroupCheckFailed = errors.New("points in the proof are not in the correct subgroup")
+	errCommitmentPokFailed        = errors.New("proof of knowledge of the committed values doesn't match")
 )
 
 // Verify verifies a proof with given VerifyingKey and publicWitness
 func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness) error {
 
-	if len(publicWitness) != (len(vk.G1.K) - 1) {
-		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), len(vk.G1.K)-1)
+	nbPublicVars := len(vk.G1.K)
+	if vk.CommitmentInfo.Is() {
+		nbPublicVars-- // the commitment is not part of the public witness
+	}
+	if len(publicWitness) != (nbPublicVars - 1) {
+		return fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitness), nbPublicVars-1)
 	}
 	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Logger()
 	start := time.Now()
@@ -60,12 +68,33 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness
 		close(chDone)
 	}()
 
+	// the commitment is a public input derived from the committed values
+	if vk.CommitmentInfo.Is() {
+		// check e(D, -[σ]2) e(Π, [1]2) == 1, that is, the prover knows the committed values
+		ok, err := curve.PairingCheck([]curve.G1Affine{proof.Commitment, proof.CommitmentPok}, []curve.G2Affine{vk.CommitmentKey.GSigmaNeg, vk.CommitmentKey.G})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errCommitmentPokFailed
+		}
+
+		publicCommitted := make([]fr.Element, vk.CommitmentInfo.NbPublicCommitted())
+		for i, wID := range vk.CommitmentInfo.PublicCommitted() {
+			publicCommitted[i] = publicWitness[wID-1]
+		}
+		publicWitness = append(publicWitness[:len(publicWitness):len(publicWitness)], commitmentHash(&proof.Commitment, publicCommitted))
+	}
+
 	// compute e(Σx.[Kvk(t)]1, -[γ]2)
 	var kSum curve.G1Jac
 	if _, err := kSum.MultiExp(vk.G1.K[1:], publicWitness, ecc.MultiExpConfig{ScalarsMont: true}); err != nil {
 		return err
 	}
 	kSum.AddMixed(&vk.G1.K[0])
+	if vk.CommitmentInfo.Is() {
+		kSum.AddMixed(&proof.Commitment)
+	}
 	var kSumAff curve.G1Affine
 	kSumAff.FromJacobian(&kSum)
 
@@ -88,6 +117,23 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness bw6_633witness.Witness
 	return nil
 }
 
+// commitmentHash returns the commitment value in the circuit, that is the hash
+// of the Pedersen commitment to the committed private values and of the
+// committed public values.
+func commitmentHash(commitment *curve.G1Affine, publicCommitted []fr.Element) fr.Element {
+	h := sha256.New()
+	x, y := commitment.X.Bytes(), commitment.Y.Bytes()
+	h.Write(x[:]) // #nosec G104 -- does not err
+	h.Write(y[:]) // #nosec G104 -- does not err
+	for i := range publicCommitted {
+		b := publicCommitted[i].Bytes()
+		h.Write(b[:]) // #nosec G104 -- does not err
+	}
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
+
 // ExportSolidity not implemented for BW6-633
 func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
 	return errors.New("not implemented")
@@ -25,6 +25,7 @@ import (
 	"math/big"
 	"reflect"
 
+	"github.com/consensys/gnark/frontend/compiled"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
 
@@ -45,6 +46,8 @@ func TestProofSerialization(t *testing.T) {
 			proof.Ar = ar
 			proof.Krs = krs
 			proof.Bs = bs
+			proof.Commitment = ar
+			proof.CommitmentPok = krs
 
 			var bufCompressed bytes.Buffer
 			written, err := proof.WriteTo(&bufCompressed)
@@ -121,6 +124,15 @@ func TestVerifyingKeySerialization(t *testing.T) {
 				vk.G1.K[i] = p1
 			}
 
+			vk.CommitmentKey.G = p2
+			vk.CommitmentKey.GSigmaNeg = p2
+			vk.CommitmentInfo = compiled.CommitmentInfo{
+				Committed:          []int{1, 3, 4},
+				NbPrivateCommitted: 2,
+				CommitmentIndex:    5,
+				HintID:             42,
+			}
+
 			var bufCompressed bytes.Buffer
 			written, err := vk.WriteTo(&bufCompressed)
 			if err != nil {
@@ -202,6 +214,12 @@ func TestProvingKeySerialization(t *testing.T) {
 			pk.InfinityB = make([]bool, nbWires)
 			pk.InfinityA[2] = true
 
+			// commitment key
+			pk.CommitmentKey.Basis = []curve.G1Affine{p1, p1}
+			pk.CommitmentKey.BasisExpSigma = []curve.G1Affine{p1, p1}
+			pk.CommitmentKey.Blinding = p1
+			pk.CommitmentKey.BlindingDelta = p1
+
 			var bufCompressed bytes.Buffer
 			written, err := pk.WriteTo(&bufCompressed)
 			if err != nil {
@@ -247,6 +265,57 @@ func TestProvingKeySerialization(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestProvingKeySerializationWithoutCommitment(t *testing.T) {
+	var pk ProvingKey
+	domain := fft.NewDomain(8)
+	pk.Domain = *domain
+
+	nbWires := 6
+	pk.G1.A = make([]curve.G1Affine, nbWires)
+	pk.G1.B = make([]curve.G1Affine, nbWires)
+	pk.G1.K = make([]curve.G1Affine, 4)
+	pk.G1.Z = make([]curve.G1Affine, pk.Domain.Cardinality)
+	pk.G2.B = make([]curve.G2Affine, nbWires)
+	pk.InfinityA = make([]bool, nbWires)
+	pk.InfinityB = make([]bool, nbWires)
+
+	var buf bytes.Buffer
+	if _, err := pk.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// the commitment key is written last, the keys serialized before the
+	// commitments were supported end before it
+	var ck bytes.Buffer
+	enc := curve.NewEncoder(&ck)
+	toEncode := []interface{}{
+		pk.CommitmentKey.Basis,
+		pk.CommitmentKey.BasisExpSigma,
+		&pk.CommitmentKey.Blinding,
+		&pk.CommitmentKey.BlindingExpSigma,
+		&pk.CommitmentKey.BlindingDelta,
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	withoutCommitment := buf.Bytes()[:buf.Len()-ck.Len()]
+
+	var read ProvingKey
+	if _, err := read.ReadFrom(bytes.NewReader(withoutCommitment)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&pk, &read) {
+		t.Fatal("reading a proving key without commitment key failed")
+	}
+
+	// a truncated commitment key is an error
+	if _, err := read.ReadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Fatal("reading a truncated proving key should fail")
+	}
+}
+
 func GenG1() gopter.Gen {
 	_, _, g1GenAff, _ := curve.Generators()
 	return func(genParams *gopter.GenParameters) *gopter.GenResult {
@@ -0,0 +1,56 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/frontend"
+)
+
+type divRemCircuit struct {
+	A frontend.Variable
+	B frontend.Variable `gnark:",public"`
+	Q frontend.Variable
+	R frontend.Variable
+}
+
+func (circuit *divRemCircuit) Define(api frontend.API) error {
+	q, r := api.DivRem(circuit.A, circuit.B, 64)
+	api.AssertIsEqual(q, circuit.Q)
+	api.AssertIsEqual(r, circuit.R)
+	api.AssertIsEqual(api.Mod(circuit.A, circuit.B, 64), circuit.R)
+	return nil
+}
+
+func init() {
+
+	good := []frontend.Circuit{
+		&divRemCircuit{
+			A: 1000000007,
+			B: 12345,
+			Q: 81004,
+			R: 5627,
+		},
+		&divRemCircuit{
+			A: 12344,
+			B: 12345,
+			Q: 0,
+			R: 12344,
+		},
+	}
+
+	bad := []frontend.Circuit{
+		&divRemCircuit{
+			A: 1000000007,
+			B: 12345,
+			Q: 81003,
+			R: 17972,
+		},
+		&divRemCircuit{
+			A: 12344,
+			B: 0,
+			Q: 0,
+			R: 12344,
+		},
+	}
+
+	addNewEntry("divrem", &divRemCircuit{}, good, bad, gnark.Curves())
+}
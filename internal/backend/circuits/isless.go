@@ -0,0 +1,67 @@
+package circuits
+
+import (
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/frontend"
+)
+
+type isLessCircuit struct {
+	A   frontend.Variable
+	B   frontend.Variable `gnark:",public"`
+	R   frontend.Variable
+	Min frontend.Variable
+}
+
+func (circuit *isLessCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.IsLess(circuit.A, circuit.B, 32), circuit.R)
+	api.AssertIsEqual(api.Min(circuit.A, circuit.B, 32), circuit.Min)
+	api.AssertIsLess(circuit.Min, api.Add(api.Max(circuit.A, circuit.B, 32), 1), 32)
+	return nil
+}
+
+func init() {
+
+	good := []frontend.Circuit{
+		&isLessCircuit{
+			A:   12345,
+			B:   12346,
+			R:   1,
+			Min: 12345,
+		},
+		&isLessCircuit{
+			A:   12346,
+			B:   12345,
+			R:   0,
+			Min: 12345,
+		},
+		&isLessCircuit{
+			A:   12345,
+			B:   12345,
+			R:   0,
+			Min: 12345,
+		},
+	}
+
+	bad := []frontend.Circuit{
+		&isLessCircuit{
+			A:   12345,
+			B:   12346,
+			R:   0,
+			Min: 12345,
+		},
+		&isLessCircuit{
+			A:   12346,
+			B:   12345,
+			R:   0,
+			Min: 12346,
+		},
+		&isLessCircuit{
+			A:   1 << 32,
+			B:   12345,
+			R:   0,
+			Min: 12345,
+		},
+	}
+
+	addNewEntry("isless", &isLessCircuit{}, good, bad, gnark.Curves())
+}
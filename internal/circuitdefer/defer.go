@@ -0,0 +1,52 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package circuitdefer stores the callbacks deferred by the circuit to the end
+// of circuit definition in the key-value store of the builder.
+package circuitdefer
+
+import (
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+type deferKey struct{}
+
+// Put appends the callback cb to the deferred callbacks of builder. It panics
+// if builder does not implement kvstore.Store.
+func Put[T any](builder any, cb T) {
+	kv, ok := builder.(kvstore.Store)
+	if !ok {
+		panic("builder does not implement kvstore.Store")
+	}
+	var deferred []T
+	if val := kv.GetKeyValue(deferKey{}); val != nil {
+		deferred = val.([]T)
+	}
+	kv.SetKeyValue(deferKey{}, append(deferred, cb))
+}
+
+// GetAll returns the deferred callbacks of builder in the order they were
+// registered.
+func GetAll[T any](builder any) []T {
+	kv, ok := builder.(kvstore.Store)
+	if !ok {
+		panic("builder does not implement kvstore.Store")
+	}
+	if val := kv.GetKeyValue(deferKey{}); val != nil {
+		return val.([]T)
+	}
+	return nil
+}
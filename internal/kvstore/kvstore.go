@@ -0,0 +1,49 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kvstore implements a simple key-value store.
+//
+// The store is not synchronized and accepts any comparable keys. It is used
+// by the circuit builders to share singletons, such as batching gadgets,
+// between the components of a circuit.
+package kvstore
+
+// Store is a key-value store.
+type Store interface {
+	// SetKeyValue stores value under key, replacing any previous value.
+	SetKeyValue(key, value interface{})
+	// GetKeyValue returns the value stored under key or nil.
+	GetKeyValue(key interface{}) (value interface{})
+}
+
+type impl struct {
+	db map[interface{}]interface{}
+}
+
+// New returns a new empty store.
+func New() Store {
+	return &impl{
+		db: make(map[interface{}]interface{}),
+	}
+}
+
+func (c *impl) SetKeyValue(key, value interface{}) {
+	c.db[key] = value
+}
+
+func (c *impl) GetKeyValue(key interface{}) interface{} {
+	return c.db[key]
+}
@@ -6,11 +6,15 @@ import (
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
 	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/eddsa"
 )
 
 var (
@@ -79,6 +83,51 @@ func initSnippets() {
 		_ = mimc.Sum()
 	})
 
+	// compare the verification of 16 signatures one by one and in a batch
+	newSignatures := func(newVariable func() frontend.Variable) ([]eddsa.Signature, []frontend.Variable, []eddsa.PublicKey) {
+		sigs := make([]eddsa.Signature, 16)
+		msgs := make([]frontend.Variable, len(sigs))
+		pubKeys := make([]eddsa.PublicKey, len(sigs))
+		for i := range sigs {
+			sigs[i].R.X, sigs[i].R.Y, sigs[i].S = newVariable(), newVariable(), newVariable()
+			pubKeys[i].A.X, pubKeys[i].A.Y = newVariable(), newVariable()
+			msgs[i] = newVariable()
+		}
+		return sigs, msgs, pubKeys
+	}
+	registerSnippet("signature/eddsa.Verify/16", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, tedwards.BN254)
+		mimc, _ := mimc.NewMiMC(api)
+		sigs, msgs, pubKeys := newSignatures(newVariable)
+		for i := range sigs {
+			mimc.Reset()
+			_ = eddsa.Verify(curve, sigs[i], msgs[i], pubKeys[i], &mimc)
+		}
+	}, ecc.BN254)
+	registerSnippet("signature/eddsa.BatchVerify/16", func(api frontend.API, newVariable func() frontend.Variable) {
+		curve, _ := twistededwards.NewEdCurve(api, tedwards.BN254)
+		mimc, _ := mimc.NewMiMC(api)
+		sigs, msgs, pubKeys := newSignatures(newVariable)
+		_ = eddsa.BatchVerify(curve, sigs, msgs, pubKeys, &mimc)
+	}, ecc.BN254)
+
+	registerSnippet("math/emulated/secp256k1_64.Mul", func(api frontend.API, newVariable func() frontend.Variable) {
+		secp256k1, _ := emulated.NewField[emulated.Secp256k1Fp](api)
+
+		newElement := func() *emulated.Element[emulated.Secp256k1Fp] {
+			limbs := make([]frontend.Variable, emulated.Secp256k1Fp{}.NbLimbs())
+			for i := 0; i < len(limbs); i++ {
+				limbs[i] = newVariable()
+			}
+			return &emulated.Element[emulated.Secp256k1Fp]{Limbs: limbs}
+		}
+
+		x := newElement()
+		y := newElement()
+		z := secp256k1.Mul(x, y)
+		secp256k1.AssertIsEqual(z, newElement())
+	}, ecc.BN254)
+
 	registerSnippet("pairing_bls12377", func(api frontend.API, newVariable func() frontend.Variable) {
 
 		var dummyG1 sw_bls12377.G1Affine
@@ -0,0 +1,132 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// VerifyMultiProof asserts that leaves are the data of the leaves at the
+// positions indices in the complete tree of the given depth with root
+// merkleRoot. The indices are constants of the circuit and must be strictly
+// increasing. proof contains the siblings which are not computed from the
+// leaves, in the order given by [Tree.MultiProof]. The internal nodes shared
+// by the paths of several leaves are computed once.
+//
+// It returns an error if the indices are invalid or if the numbers of leaves
+// or siblings do not match the indices.
+func VerifyMultiProof(api frontend.API, h hash.Hash, merkleRoot frontend.Variable, depth int, indices []uint64, leaves, proof []frontend.Variable) error {
+	if len(leaves) != len(indices) {
+		return fmt.Errorf("got %d leaves for %d indices", len(leaves), len(indices))
+	}
+	size, err := MultiProofSize(depth, indices)
+	if err != nil {
+		return err
+	}
+	if len(proof) != size {
+		return fmt.Errorf("got %d siblings, expected %d", len(proof), size)
+	}
+
+	nodes := make([]frontend.Variable, len(leaves))
+	for i := range leaves {
+		nodes[i] = leafSum(api, h, leaves[i])
+	}
+	k := 0
+	root := walkMultiProof(depth, indices, nodes,
+		func(int, uint64) frontend.Variable {
+			k++
+			return proof[k-1]
+		},
+		func(left, right frontend.Variable) frontend.Variable {
+			return nodeSum(api, h, left, right)
+		})
+
+	api.AssertIsEqual(root, merkleRoot)
+	return nil
+}
+
+// MultiProofSize returns the number of siblings in a multiproof of the
+// leaves at the positions indices in a complete tree of the given depth. It
+// is used to allocate the proof when defining the circuit.
+func MultiProofSize(depth int, indices []uint64) (int, error) {
+	if err := checkIndices(depth, indices); err != nil {
+		return 0, err
+	}
+	size := 0
+	walkMultiProof(depth, indices, make([]struct{}, len(indices)),
+		func(int, uint64) struct{} {
+			size++
+			return struct{}{}
+		},
+		func(struct{}, struct{}) struct{} { return struct{}{} })
+	return size, nil
+}
+
+// checkIndices returns an error if the indices are not strictly increasing
+// positions of a tree of the given depth.
+func checkIndices(depth int, indices []uint64) error {
+	if depth < 0 || depth >= 64 {
+		return fmt.Errorf("invalid depth %d", depth)
+	}
+	if len(indices) == 0 {
+		return errors.New("no leaf to verify")
+	}
+	for i := range indices {
+		if indices[i] >= 1<<depth {
+			return fmt.Errorf("index %d out of range for depth %d", indices[i], depth)
+		}
+		if i > 0 && indices[i] <= indices[i-1] {
+			return errors.New("indices must be strictly increasing")
+		}
+	}
+	return nil
+}
+
+// walkMultiProof computes the root from the nodes at the positions indices
+// of the leaf level, level by level. At each level, the nodes are combined
+// with their siblings in increasing order of position. If the sibling of a
+// node is not known, then it is given by sibling. It is shared by the
+// circuit and the native builder so that the siblings are in the same order.
+func walkMultiProof[T any](depth int, indices []uint64, nodes []T, sibling func(level int, index uint64) T, parent func(left, right T) T) T {
+	idx := make([]uint64, len(indices))
+	copy(idx, indices)
+	cur := make([]T, len(nodes))
+	copy(cur, nodes)
+	for level := 0; level < depth; level++ {
+		nextIdx := make([]uint64, 0, len(idx))
+		next := make([]T, 0, len(cur))
+		for i := 0; i < len(idx); i++ {
+			var left, right T
+			if idx[i]&1 == 1 {
+				left, right = sibling(level, idx[i]-1), cur[i]
+			} else if i+1 < len(idx) && idx[i+1] == idx[i]+1 {
+				left, right = cur[i], cur[i+1]
+				i++
+			} else {
+				left, right = cur[i], sibling(level, idx[i]+1)
+			}
+			nextIdx = append(nextIdx, idx[i]>>1)
+			next = append(next, parent(left, right))
+		}
+		idx, cur = nextIdx, next
+	}
+	return cur[0]
+}
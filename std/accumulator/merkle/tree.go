@@ -0,0 +1,110 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// Tree is a complete binary Merkle tree built natively. It hashes the leaves
+// and the internal nodes as the circuit functions of this package do and
+// builds the witnesses for [VerifyProofIndex] and [VerifyMultiProof].
+type Tree struct {
+	h      hash.Hash
+	leaves [][]byte
+	// nodes[0] are the hashes of the leaves and nodes[Depth()][0] is the root
+	nodes [][][]byte
+}
+
+// NewTree builds the tree of the leaves using h. The number of leaves must
+// be a power of two.
+func NewTree(h hash.Hash, leaves [][]byte) (*Tree, error) {
+	n := len(leaves)
+	if n == 0 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("the number of leaves %d is not a power of two", n)
+	}
+	t := &Tree{h: h, leaves: leaves}
+	level := make([][]byte, n)
+	for i := range leaves {
+		level[i] = t.sum(leaves[i])
+	}
+	t.nodes = append(t.nodes, level)
+	for len(level) > 1 {
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			next[i] = t.sum(level[2*i], level[2*i+1])
+		}
+		t.nodes = append(t.nodes, next)
+		level = next
+	}
+	return t, nil
+}
+
+// sum returns H(data[0] ∥ data[1] ∥ ...).
+func (t *Tree) sum(data ...[]byte) []byte {
+	t.h.Reset()
+	for _, d := range data {
+		t.h.Write(d)
+	}
+	return t.h.Sum(nil)
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() []byte {
+	return t.nodes[len(t.nodes)-1][0]
+}
+
+// Depth returns the number of levels above the leaves.
+func (t *Tree) Depth() int {
+	return len(t.nodes) - 1
+}
+
+// Proof returns the proof set of the leaf at position index for
+// [VerifyProofIndex]: the data of the leaf followed by the siblings from the
+// leaf level to the root.
+func (t *Tree) Proof(index uint64) ([][]byte, error) {
+	if index >= uint64(len(t.leaves)) {
+		return nil, errors.New("leaf index out of range")
+	}
+	proofSet := [][]byte{t.leaves[index]}
+	for level := 0; level < t.Depth(); level++ {
+		proofSet = append(proofSet, t.nodes[level][index^1])
+		index >>= 1
+	}
+	return proofSet, nil
+}
+
+// MultiProof returns the data of the leaves at the positions indices and the
+// siblings for [VerifyMultiProof]. The indices must be strictly increasing.
+func (t *Tree) MultiProof(indices []uint64) (leaves, proof [][]byte, err error) {
+	if err := checkIndices(t.Depth(), indices); err != nil {
+		return nil, nil, err
+	}
+	leaves = make([][]byte, len(indices))
+	for i, idx := range indices {
+		leaves[i] = t.leaves[idx]
+	}
+	walkMultiProof(t.Depth(), indices, make([]struct{}, len(indices)),
+		func(level int, index uint64) struct{} {
+			proof = append(proof, t.nodes[level][index])
+			return struct{}{}
+		},
+		func(struct{}, struct{}) struct{} { return struct{}{} })
+	return leaves, proof, nil
+}
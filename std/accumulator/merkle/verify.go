@@ -42,33 +42,38 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package merkle provides a ZKP-circuit function to verify merkle proofs.
+// Package merkle provides ZKP-circuit functions to verify Merkle proofs.
+//
+// The leaves are hashed as H(data) and the internal nodes as H(left, right)
+// without domain separation, using any hash function implementing
+// [hash.Hash]. The proofs may be given in the format of
+// gnark-crypto/accumulator/merkletree with a helper slice (see
+// [VerifyProof]), with the path directions given by the bits of the leaf
+// index (see [VerifyProofIndex]) or as a multiproof for several leaves of
+// the same tree (see [VerifyMultiProof]). The matching witnesses are built
+// natively with [Tree].
 package merkle
 
 import (
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
 )
 
 // leafSum returns the hash created from data inserted to form a leaf.
 // Without domain separation.
-func leafSum(api frontend.API, h mimc.MiMC, data frontend.Variable) frontend.Variable {
-
+func leafSum(api frontend.API, h hash.Hash, data frontend.Variable) frontend.Variable {
+	h.Reset()
 	h.Write(data)
-	res := h.Sum()
-
-	return res
+	return h.Sum()
 }
 
-// nodeSum returns the hash created from data inserted to form a leaf.
-// Without domain separation.
-func nodeSum(api frontend.API, h mimc.MiMC, a, b frontend.Variable) frontend.Variable {
-
+// nodeSum returns the hash created from two sibling nodes being combined
+// into a parent node. Without domain separation.
+func nodeSum(api frontend.API, h hash.Hash, a, b frontend.Variable) frontend.Variable {
+	h.Reset()
 	h.Write(a, b)
-	//res := h.Sum(a, b)
-	res := h.Sum()
-
-	return res
+	return h.Sum()
 }
 
 // GenerateProofHelper generates an array of 1 or 0 telling if during the proof verification
@@ -132,7 +137,7 @@ func GenerateProofHelper(proofSet [][]byte, proofIndex, numLeaves uint64) []int
 // true if the first element of the proof set is a leaf of data in the Merkle
 // root. False is returned if the proof set or Merkle root is nil, and if
 // 'numLeaves' equals 0.
-func VerifyProof(api frontend.API, h mimc.MiMC, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable) {
+func VerifyProof(api frontend.API, h hash.Hash, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable) {
 
 	sum := leafSum(api, h, proofSet[0])
 
@@ -147,3 +152,25 @@ func VerifyProof(api frontend.API, h mimc.MiMC, merkleRoot frontend.Variable, pr
 	api.AssertIsEqual(sum, merkleRoot)
 
 }
+
+// VerifyProofIndex asserts that the first element of proofSet is the data
+// of the leaf at position leafIndex in the tree with root merkleRoot. The
+// remaining elements of proofSet are the siblings from the leaf level to
+// the root, as returned by [Tree.Proof].
+//
+// The path directions are given by the bits of leafIndex: if the i-th bit is
+// 0, then the i-th node of the path is a left child. leafIndex is asserted to
+// be less than 2^(len(proofSet)-1). Contrary to [VerifyProof], the tree must
+// be complete, that is the number of leaves must be a power of two.
+func VerifyProofIndex(api frontend.API, h hash.Hash, merkleRoot frontend.Variable, proofSet []frontend.Variable, leafIndex frontend.Variable) {
+	path := bits.ToBinary(api, leafIndex, bits.WithNbDigits(len(proofSet)-1))
+
+	sum := leafSum(api, h, proofSet[0])
+	for i := 1; i < len(proofSet); i++ {
+		d1 := api.Select(path[i-1], proofSet[i], sum)
+		d2 := api.Select(path[i-1], sum, proofSet[i])
+		sum = nodeSum(api, h, d1, d2)
+	}
+
+	api.AssertIsEqual(sum, merkleRoot)
+}
@@ -40,7 +40,7 @@ func (circuit *merkleCircuit) Define(api frontend.API) error {
 	if err != nil {
 		return err
 	}
-	VerifyProof(api, hFunc, circuit.RootHash, circuit.Path, circuit.Helper)
+	VerifyProof(api, &hFunc, circuit.RootHash, circuit.Path, circuit.Helper)
 	return nil
 }
 
@@ -96,3 +96,149 @@ func TestVerify(t *testing.T) {
 	assert := test.NewAssert(t)
 	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
 }
+
+// randomLeaves returns n random leaves of 32 bytes which are valid scalars of
+// BN254.
+func randomLeaves(t *testing.T, n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		var leaf fr.Element
+		if _, err := leaf.SetRandom(); err != nil {
+			t.Fatal(err)
+		}
+		b := leaf.Bytes()
+		leaves[i] = b[:]
+	}
+	return leaves
+}
+
+func TestTree(t *testing.T) {
+	leaves := randomLeaves(t, 8)
+	tree, err := NewTree(bn254.NewMiMC(), leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the tree matches gnark-crypto's merkletree for complete trees
+	var buf bytes.Buffer
+	for i := range leaves {
+		buf.Write(leaves[i])
+	}
+	merkleRoot, proof, _, err := merkletree.BuildReaderProof(&buf, bn254.NewMiMC(), 32, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(merkleRoot, tree.Root()) {
+		t.Fatal("roots differ")
+	}
+	proofSet, err := tree.Proof(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proofSet) != len(proof) {
+		t.Fatal("proof sizes differ")
+	}
+	for i := range proof {
+		if !bytes.Equal(proof[i], proofSet[i]) {
+			t.Fatal("proofs differ")
+		}
+	}
+
+	if _, err := NewTree(bn254.NewMiMC(), leaves[:6]); err == nil {
+		t.Fatal("expected error for incomplete tree")
+	}
+}
+
+type merkleIndexCircuit struct {
+	RootHash  frontend.Variable `gnark:",public"`
+	Path      []frontend.Variable
+	LeafIndex frontend.Variable
+}
+
+func (circuit *merkleIndexCircuit) Define(api frontend.API) error {
+	hFunc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifyProofIndex(api, &hFunc, circuit.RootHash, circuit.Path, circuit.LeafIndex)
+	return nil
+}
+
+func TestVerifyIndex(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree, err := NewTree(bn254.NewMiMC(), randomLeaves(t, 16))
+	assert.NoError(err)
+
+	proofIndex := uint64(11)
+	proof, err := tree.Proof(proofIndex)
+	assert.NoError(err)
+
+	circuit := merkleIndexCircuit{Path: make([]frontend.Variable, len(proof))}
+	witness := merkleIndexCircuit{
+		RootHash:  tree.Root(),
+		Path:      make([]frontend.Variable, len(proof)),
+		LeafIndex: proofIndex,
+	}
+	for i := range proof {
+		witness.Path[i] = proof[i]
+	}
+	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	witness.LeafIndex = proofIndex ^ 1
+	assert.ProverFailed(&circuit, &witness, test.WithCurves(ecc.BN254))
+}
+
+type merkleMultiCircuit struct {
+	RootHash      frontend.Variable `gnark:",public"`
+	Leaves, Proof []frontend.Variable
+	depth         int
+	indices       []uint64
+}
+
+func (circuit *merkleMultiCircuit) Define(api frontend.API) error {
+	hFunc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return VerifyMultiProof(api, &hFunc, circuit.RootHash, circuit.depth, circuit.indices, circuit.Leaves, circuit.Proof)
+}
+
+func TestVerifyMultiProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree, err := NewTree(bn254.NewMiMC(), randomLeaves(t, 16))
+	assert.NoError(err)
+
+	indices := []uint64{0, 1, 6, 11}
+	leaves, proof, err := tree.MultiProof(indices)
+	assert.NoError(err)
+	size, err := MultiProofSize(tree.Depth(), indices)
+	assert.NoError(err)
+	// the siblings of the leaves 0 and 1 are shared
+	assert.Equal(size, len(proof))
+	assert.Less(size, 4*tree.Depth())
+
+	circuit := merkleMultiCircuit{
+		Leaves:  make([]frontend.Variable, len(indices)),
+		Proof:   make([]frontend.Variable, size),
+		depth:   tree.Depth(),
+		indices: indices,
+	}
+	witness := merkleMultiCircuit{
+		RootHash: tree.Root(),
+		Leaves:   make([]frontend.Variable, len(indices)),
+		Proof:    make([]frontend.Variable, size),
+	}
+	for i := range leaves {
+		witness.Leaves[i] = leaves[i]
+	}
+	for i := range proof {
+		witness.Proof[i] = proof[i]
+	}
+	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	witness.Leaves[0], witness.Leaves[1] = leaves[1], leaves[0]
+	assert.ProverFailed(&circuit, &witness, test.WithCurves(ecc.BN254))
+
+	_, _, err = tree.MultiProof([]uint64{3, 2})
+	assert.Error(err)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package smt provides ZKP-circuit functions to verify proofs on a sparse
+// Merkle tree of fixed depth keyed by field elements.
+//
+// The leaf of key k is at position k, so the keys must be less than 2^depth.
+// A tree of depth at least the bit length of the field modulus holds every
+// field element: the path of a key is then its canonical decomposition, so
+// that the keys k and k+p do not share a leaf.
+// A leaf storing the value v is hashed as H(k, v) and an empty leaf is 0. The
+// internal nodes are hashed as H(left, right), so that the root of an empty
+// subtree of height i is the constant Eᵢ with E₀ = 0 and Eᵢ₊₁ = H(Eᵢ, Eᵢ).
+//
+// A proof is the list of the siblings on the path from the leaf to the root.
+// The same proof is used for the membership and the non-membership of a key
+// and for the update of its value. The proofs are built natively with
+// [Tree].
+package smt
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// VerifyMembership asserts that the key is set to value in the tree with the
+// given root. The depth of the tree is len(siblings).
+func VerifyMembership(api frontend.API, h hash.Hash, root, key, value frontend.Variable, siblings []frontend.Variable) {
+	path := keyPath(api, key, len(siblings))
+	api.AssertIsEqual(computeRoot(api, h, path, leafSum(h, key, value), siblings), root)
+}
+
+// VerifyNonMembership asserts that the key is not set in the tree with the
+// given root. The depth of the tree is len(siblings).
+func VerifyNonMembership(api frontend.API, h hash.Hash, root, key frontend.Variable, siblings []frontend.Variable) {
+	path := keyPath(api, key, len(siblings))
+	api.AssertIsEqual(computeRoot(api, h, path, 0, siblings), root)
+}
+
+// VerifyInsert asserts that the tree with root newRoot is obtained by
+// setting the key to value in the tree with root oldRoot, where the key is
+// not set. The depth of the tree is len(siblings).
+func VerifyInsert(api frontend.API, h hash.Hash, oldRoot, newRoot, key, value frontend.Variable, siblings []frontend.Variable) {
+	path := keyPath(api, key, len(siblings))
+	api.AssertIsEqual(computeRoot(api, h, path, 0, siblings), oldRoot)
+	api.AssertIsEqual(computeRoot(api, h, path, leafSum(h, key, value), siblings), newRoot)
+}
+
+// VerifyUpdate asserts that the tree with root newRoot is obtained by
+// setting the key from oldValue to newValue in the tree with root oldRoot.
+// The depth of the tree is len(siblings).
+func VerifyUpdate(api frontend.API, h hash.Hash, oldRoot, newRoot, key, oldValue, newValue frontend.Variable, siblings []frontend.Variable) {
+	path := keyPath(api, key, len(siblings))
+	api.AssertIsEqual(computeRoot(api, h, path, leafSum(h, key, oldValue), siblings), oldRoot)
+	api.AssertIsEqual(computeRoot(api, h, path, leafSum(h, key, newValue), siblings), newRoot)
+}
+
+// keyPath returns the depth bits of the key in little-endian order. It
+// asserts that the key is less than 2^depth.
+func keyPath(api frontend.API, key frontend.Variable, depth int) []frontend.Variable {
+	path, err := api.Compiler().NewHint(bits.NBits, depth, key)
+	if err != nil {
+		panic(err)
+	}
+	assertKeyPath(api, key, path)
+	return path
+}
+
+// assertKeyPath asserts that path is the little-endian bit decomposition of
+// the key. If the path is at least as long as the field elements, then the
+// decomposition is also asserted to be less than the modulus, otherwise the
+// key k could take the path of k+p.
+func assertKeyPath(api frontend.API, key frontend.Variable, path []frontend.Variable) {
+	for i := range path {
+		api.AssertIsBoolean(path[i])
+	}
+	api.AssertIsEqual(bits.FromBinary(api, path, bits.WithUnconstrainedInputs()), key)
+	modulus := api.Compiler().Curve().Info().Fr.Modulus()
+	if len(path) >= modulus.BitLen() {
+		assertBitsLessOrEqual(api, path, new(big.Int).Sub(modulus, big.NewInt(1)))
+	}
+}
+
+// assertBitsLessOrEqual asserts that the value of the boolean little-endian
+// bits b is at most the constant bound.
+func assertBitsLessOrEqual(api frontend.API, b []frontend.Variable, bound *big.Int) {
+	nbBits := len(b)
+	// t trailing ones in the bound
+	t := 0
+	for t < nbBits && bound.Bit(t) == 1 {
+		t++
+	}
+	// p[i] == 1 → bits[j] == bound[j] for all j ⩾ i
+	p := make([]frontend.Variable, nbBits+1)
+	p[nbBits] = 1
+	for i := nbBits - 1; i >= t; i-- {
+		if bound.Bit(i) == 0 {
+			p[i] = p[i+1]
+		} else {
+			p[i] = api.Mul(p[i+1], b[i])
+		}
+	}
+	for i := nbBits - 1; i >= 0; i-- {
+		if bound.Bit(i) == 0 {
+			// the bit must be 0 when the higher bits equal those of the bound
+			api.AssertIsEqual(api.Mul(api.Sub(1, p[i+1], b[i]), b[i]), 0)
+		}
+	}
+}
+
+// leafSum returns H(key, value).
+func leafSum(h hash.Hash, key, value frontend.Variable) frontend.Variable {
+	h.Reset()
+	h.Write(key, value)
+	return h.Sum()
+}
+
+// computeRoot returns the root of the tree from the leaf and its siblings.
+// If path[i] is 0, then the node at level i is a left child.
+func computeRoot(api frontend.API, h hash.Hash, path []frontend.Variable, leaf frontend.Variable, siblings []frontend.Variable) frontend.Variable {
+	node := leaf
+	for i := range siblings {
+		left := api.Select(path[i], siblings[i], node)
+		right := api.Select(path[i], node, siblings[i])
+		h.Reset()
+		h.Write(left, right)
+		node = h.Sum()
+	}
+	return node
+}
@@ -0,0 +1,194 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const testDepth = 10
+
+type membershipCircuit struct {
+	Root       frontend.Variable `gnark:",public"`
+	Key, Value frontend.Variable
+	Siblings   [testDepth]frontend.Variable
+}
+
+func (c *membershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifyMembership(api, &h, c.Root, c.Key, c.Value, c.Siblings[:])
+	return nil
+}
+
+type nonMembershipCircuit struct {
+	Root     frontend.Variable `gnark:",public"`
+	Key      frontend.Variable
+	Siblings [testDepth]frontend.Variable
+}
+
+func (c *nonMembershipCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifyNonMembership(api, &h, c.Root, c.Key, c.Siblings[:])
+	return nil
+}
+
+type insertCircuit struct {
+	OldRoot, NewRoot frontend.Variable `gnark:",public"`
+	Key, Value       frontend.Variable
+	Siblings         [testDepth]frontend.Variable
+}
+
+func (c *insertCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifyInsert(api, &h, c.OldRoot, c.NewRoot, c.Key, c.Value, c.Siblings[:])
+	return nil
+}
+
+type updateCircuit struct {
+	OldRoot, NewRoot   frontend.Variable `gnark:",public"`
+	Key                frontend.Variable
+	OldValue, NewValue frontend.Variable
+	Siblings           [testDepth]frontend.Variable
+}
+
+func (c *updateCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	VerifyUpdate(api, &h, c.OldRoot, c.NewRoot, c.Key, c.OldValue, c.NewValue, c.Siblings[:])
+	return nil
+}
+
+func proof(t *testing.T, tree *Tree, key int64) (siblings [testDepth]frontend.Variable) {
+	p, err := tree.Proof(big.NewInt(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range p {
+		siblings[i] = p[i]
+	}
+	return siblings
+}
+
+func TestSMT(t *testing.T) {
+	assert := test.NewAssert(t)
+	tree := NewTree(bn254.NewMiMC(), ecc.BN254.Info().Fr.Modulus(), testDepth)
+	for k := int64(1); k < 5; k++ {
+		assert.NoError(tree.Set(big.NewInt(k*200), big.NewInt(k)))
+	}
+	assert.Error(tree.Set(big.NewInt(1<<testDepth), big.NewInt(1)))
+
+	// membership
+	m := membershipCircuit{Root: tree.Root(), Key: 400, Value: 2, Siblings: proof(t, tree, 400)}
+	assert.ProverSucceeded(&membershipCircuit{}, &m, test.WithCurves(ecc.BN254))
+	m.Value = 3
+	assert.ProverFailed(&membershipCircuit{}, &m, test.WithCurves(ecc.BN254))
+
+	// non-membership
+	nm := nonMembershipCircuit{Root: tree.Root(), Key: 401, Siblings: proof(t, tree, 401)}
+	assert.ProverSucceeded(&nonMembershipCircuit{}, &nm, test.WithCurves(ecc.BN254))
+	nm = nonMembershipCircuit{Root: tree.Root(), Key: 400, Siblings: proof(t, tree, 400)}
+	assert.ProverFailed(&nonMembershipCircuit{}, &nm, test.WithCurves(ecc.BN254))
+
+	// insert
+	ins := insertCircuit{OldRoot: tree.Root(), Key: 401, Value: 42, Siblings: proof(t, tree, 401)}
+	assert.NoError(tree.Set(big.NewInt(401), big.NewInt(42)))
+	ins.NewRoot = tree.Root()
+	assert.ProverSucceeded(&insertCircuit{}, &ins, test.WithCurves(ecc.BN254))
+	ins.Value = 43
+	assert.ProverFailed(&insertCircuit{}, &ins, test.WithCurves(ecc.BN254))
+
+	// update
+	up := updateCircuit{OldRoot: tree.Root(), Key: 401, OldValue: 42, NewValue: 7, Siblings: proof(t, tree, 401)}
+	assert.NoError(tree.Set(big.NewInt(401), big.NewInt(7)))
+	up.NewRoot = tree.Root()
+	assert.ProverSucceeded(&updateCircuit{}, &up, test.WithCurves(ecc.BN254))
+	up.OldValue = 41
+	assert.ProverFailed(&updateCircuit{}, &up, test.WithCurves(ecc.BN254))
+
+	v, ok := tree.Get(big.NewInt(401))
+	assert.True(ok)
+	assert.Equal(int64(7), v.Int64())
+	_, ok = tree.Get(big.NewInt(402))
+	assert.False(ok)
+}
+
+// fullDepth is the depth of a tree holding every element of the BN254 scalar
+// field.
+const fullDepth = 254
+
+// aliasCircuit verifies the non-membership of a key as VerifyNonMembership
+// does, but with the path of the key given in the witness.
+type aliasCircuit struct {
+	Root     frontend.Variable `gnark:",public"`
+	Key      frontend.Variable
+	Path     [fullDepth]frontend.Variable
+	Siblings [fullDepth]frontend.Variable
+}
+
+func (c *aliasCircuit) Define(api frontend.API) error {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	assertKeyPath(api, c.Key, c.Path[:])
+	api.AssertIsEqual(computeRoot(api, &h, c.Path[:], 0, c.Siblings[:]), c.Root)
+	return nil
+}
+
+func TestAliasedPath(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.Info().Fr.Modulus()
+	tree := NewTree(bn254.NewMiMC(), modulus, fullDepth)
+	key := big.NewInt(5)
+	assert.NoError(tree.Set(key, big.NewInt(1)))
+	assert.NoError(tree.Set(new(big.Int).Sub(modulus, big.NewInt(1)), big.NewInt(2)))
+	assert.Error(tree.Set(modulus, big.NewInt(3)))
+
+	assignment := func(key, pos *big.Int) *aliasCircuit {
+		c := aliasCircuit{Root: tree.Root(), Key: key}
+		for i := 0; i < fullDepth; i++ {
+			c.Path[i] = pos.Bit(i)
+			c.Siblings[i] = tree.node(i, new(big.Int).Xor(new(big.Int).Rsh(pos, uint(i)), big.NewInt(1)))
+		}
+		return &c
+	}
+	unset := big.NewInt(6)
+	assert.SolvingSucceeded(&aliasCircuit{}, assignment(unset, unset), test.WithCurves(ecc.BN254))
+
+	// the leaf at position k+p is empty and its path packs to k, but it does
+	// not prove that k is not set
+	aliased := new(big.Int).Add(key, modulus)
+	assert.SolvingFailed(&aliasCircuit{}, assignment(key, aliased), test.WithCurves(ecc.BN254))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+)
+
+// Tree is a sparse Merkle tree built natively. It hashes the leaves and the
+// internal nodes as the circuit functions of this package do and builds the
+// witnesses for them. Only the non-empty nodes are stored.
+//
+// The keys and the values are encoded as big-endian integers on
+// h.BlockSize() bytes when hashed, so that they are read as field elements
+// by hash functions such as MiMC. The keys are less than 2^depth and than the
+// modulus of the field.
+type Tree struct {
+	h       hash.Hash
+	modulus *big.Int
+	depth   int
+	// empty[i] is the root of an empty subtree of height i
+	empty [][]byte
+	// nodes[i] are the non-empty nodes at height i indexed by position
+	nodes []map[string][]byte
+	// values are the values of the set keys
+	values map[string]*big.Int
+}
+
+// NewTree returns an empty tree of the given depth using h, which hashes
+// elements of the field of the given modulus.
+func NewTree(h hash.Hash, modulus *big.Int, depth int) *Tree {
+	t := &Tree{
+		h:       h,
+		modulus: new(big.Int).Set(modulus),
+		depth:   depth,
+		empty:   make([][]byte, depth+1),
+		nodes:   make([]map[string][]byte, depth+1),
+		values:  make(map[string]*big.Int),
+	}
+	t.empty[0] = make([]byte, h.Size())
+	for i := 0; i < depth; i++ {
+		t.empty[i+1] = t.sum(t.empty[i], t.empty[i])
+	}
+	for i := range t.nodes {
+		t.nodes[i] = make(map[string][]byte)
+	}
+	return t
+}
+
+// sum returns H(data[0] ∥ data[1] ∥ ...).
+func (t *Tree) sum(data ...[]byte) []byte {
+	t.h.Reset()
+	for _, d := range data {
+		t.h.Write(d)
+	}
+	return t.h.Sum(nil)
+}
+
+// encode returns the big-endian encoding of v on h.BlockSize() bytes.
+func (t *Tree) encode(v *big.Int) []byte {
+	return v.FillBytes(make([]byte, t.h.BlockSize()))
+}
+
+// node returns the node at height i and the given position.
+func (t *Tree) node(i int, pos *big.Int) []byte {
+	if n, ok := t.nodes[i][string(pos.Bytes())]; ok {
+		return n
+	}
+	return t.empty[i]
+}
+
+// checkKey returns an error if the key is not a position of the tree.
+func (t *Tree) checkKey(key *big.Int) error {
+	if key.Sign() < 0 || key.BitLen() > t.depth || key.Cmp(t.modulus) >= 0 {
+		return errors.New("key out of range")
+	}
+	return nil
+}
+
+// Depth returns the depth of the tree.
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() []byte {
+	return t.node(t.depth, new(big.Int))
+}
+
+// Get returns the value of the key and whether it is set.
+func (t *Tree) Get(key *big.Int) (*big.Int, bool) {
+	v, ok := t.values[string(key.Bytes())]
+	if !ok {
+		return nil, false
+	}
+	return new(big.Int).Set(v), true
+}
+
+// Set sets the key to value and updates the nodes on its path.
+func (t *Tree) Set(key, value *big.Int) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	t.values[string(key.Bytes())] = new(big.Int).Set(value)
+	pos := new(big.Int).Set(key)
+	node := t.sum(t.encode(key), t.encode(value))
+	for i := 0; i <= t.depth; i++ {
+		t.nodes[i][string(pos.Bytes())] = node
+		if i == t.depth {
+			break
+		}
+		sibling := t.node(i, new(big.Int).Xor(pos, big.NewInt(1)))
+		if pos.Bit(0) == 0 {
+			node = t.sum(node, sibling)
+		} else {
+			node = t.sum(sibling, node)
+		}
+		pos.Rsh(pos, 1)
+	}
+	return nil
+}
+
+// Proof returns the siblings on the path of the key from the leaf to the
+// root. The key may or may not be set.
+func (t *Tree) Proof(key *big.Int) ([][]byte, error) {
+	if err := t.checkKey(key); err != nil {
+		return nil, err
+	}
+	siblings := make([][]byte, t.depth)
+	pos := new(big.Int).Set(key)
+	for i := range siblings {
+		siblings[i] = t.node(i, new(big.Int).Xor(pos, big.NewInt(1)))
+		pos.Rsh(pos, 1)
+	}
+	return siblings, nil
+}
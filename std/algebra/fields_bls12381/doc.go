@@ -0,0 +1,36 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package fields_bls12381 implements the extension fields of the BLS12-381
+curve over the emulated base field.
+
+The tower is the same as in gnark-crypto:
+
+	Fp2 = Fp[u]/(u²+1)
+	Fp6 = Fp2[v]/(v³-(1+u))
+	Fp12 = Fp6[w]/(w²-v)
+
+The base field is emulated using the package
+[github.com/consensys/gnark/std/math/emulated], so that the arithmetic can be
+used in circuits defined over a different native field, for example BN254.
+The operations are provided by the types [Ext2], [Ext6] and [Ext12], which do
+not modify their inputs and return newly allocated elements.
+
+Inversions and divisions are computed in hints and constrained with a
+multiplication.
+*/
+package fields_bls12381
@@ -0,0 +1,154 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bls12381
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// xHalfAbs is |x|/2 where x = -0xd201000000010000 is the seed of BLS12-381.
+const xHalfAbs uint64 = 0x6900800000008000
+
+var (
+	// frobeniusCoeffs[k-1] = (1+u)^(k(p-1)/6) for k = 1..5
+	frobeniusCoeffs [5][2]*big.Int
+	// frobeniusSquareCoeffs[k-1] = (1+u)^(k(p²-1)/6) for k = 1..5. The values
+	// are in the base field.
+	frobeniusSquareCoeffs [5]*big.Int
+)
+
+func init() {
+	var fp emulated.BLS12381Fp
+	p := fp.Modulus()
+	// e1 = (p-1)/6, e2 = (p²-1)/6
+	e1 := new(big.Int).Sub(p, big.NewInt(1))
+	e1.Div(e1, big.NewInt(6))
+	e2 := new(big.Int).Mul(p, p)
+	e2.Sub(e2, big.NewInt(1))
+	e2.Div(e2, big.NewInt(6))
+	for k := int64(1); k <= 5; k++ {
+		frobeniusCoeffs[k-1][0], frobeniusCoeffs[k-1][1] = expE2(p, big.NewInt(1), big.NewInt(1), new(big.Int).Mul(e1, big.NewInt(k)))
+		c0, c1 := expE2(p, big.NewInt(1), big.NewInt(1), new(big.Int).Mul(e2, big.NewInt(k)))
+		if c1.Sign() != 0 {
+			panic("frobenius square coefficient not in base field")
+		}
+		frobeniusSquareCoeffs[k-1] = c0
+	}
+}
+
+// expE2 returns (a0 + a1*u)^e modulo p.
+func expE2(p, a0, a1, e *big.Int) (*big.Int, *big.Int) {
+	r0, r1 := big.NewInt(1), big.NewInt(0)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r0, r1 = mulE2(p, r0, r1, r0, r1)
+		if e.Bit(i) == 1 {
+			r0, r1 = mulE2(p, r0, r1, a0, a1)
+		}
+	}
+	return r0, r1
+}
+
+// mulE2 returns (x0 + x1*u)(y0 + y1*u) modulo p.
+func mulE2(p, x0, x1, y0, y1 *big.Int) (*big.Int, *big.Int) {
+	r0 := new(big.Int).Sub(new(big.Int).Mul(x0, y0), new(big.Int).Mul(x1, y1))
+	r1 := new(big.Int).Add(new(big.Int).Mul(x0, y1), new(big.Int).Mul(x1, y0))
+	return r0.Mod(r0, p), r1.Mod(r1, p)
+}
+
+// CyclotomicSquare returns x² for x in the cyclotomic subgroup. The result
+// is undefined for other elements.
+func (e *Ext12) CyclotomicSquare(x *E12) *E12 {
+	// Granger-Scott's cyclotomic square
+	// https://eprint.iacr.org/2009/565.pdf, 3.2
+	t0 := e.Ext2.Square(&x.C1.B1)
+	t1 := e.Ext2.Square(&x.C0.B0)
+	t6 := e.Ext2.Square(e.Ext2.Add(&x.C1.B1, &x.C0.B0))
+	t6 = e.Ext2.Sub(e.Ext2.Sub(t6, t0), t1)
+	t2 := e.Ext2.Square(&x.C0.B2)
+	t3 := e.Ext2.Square(&x.C1.B0)
+	t7 := e.Ext2.Square(e.Ext2.Add(&x.C0.B2, &x.C1.B0))
+	t7 = e.Ext2.Sub(e.Ext2.Sub(t7, t2), t3)
+	t4 := e.Ext2.Square(&x.C1.B2)
+	t5 := e.Ext2.Square(&x.C0.B1)
+	t8 := e.Ext2.Square(e.Ext2.Add(&x.C1.B2, &x.C0.B1))
+	t8 = e.Ext2.MulByNonResidue(e.Ext2.Sub(e.Ext2.Sub(t8, t4), t5))
+
+	t0 = e.Ext2.Add(e.Ext2.MulByNonResidue(t0), t1)
+	t2 = e.Ext2.Add(e.Ext2.MulByNonResidue(t2), t3)
+	t4 = e.Ext2.Add(e.Ext2.MulByNonResidue(t4), t5)
+
+	// 3t - 2x for the coefficients of C0 and 3t + 2x for C1
+	sub := func(t, x *E2) *E2 { return e.Ext2.Add(e.Ext2.Double(e.Ext2.Sub(t, x)), t) }
+	add := func(t, x *E2) *E2 { return e.Ext2.Add(e.Ext2.Double(e.Ext2.Add(t, x)), t) }
+	return &E12{
+		C0: E6{B0: *sub(t0, &x.C0.B0), B1: *sub(t2, &x.C0.B1), B2: *sub(t4, &x.C0.B2)},
+		C1: E6{B0: *add(t8, &x.C1.B0), B1: *add(t6, &x.C1.B1), B2: *add(t7, &x.C1.B2)},
+	}
+}
+
+// Frobenius returns x^p.
+func (e *Ext12) Frobenius(x *E12) *E12 {
+	// the coefficient of w^k is conjugated and multiplied by (1+u)^(k(p-1)/6)
+	coeff := func(c *E2, k int) *E2 {
+		cc := e.Ext2.Conjugate(c)
+		if k == 0 {
+			return cc
+		}
+		return e.Ext2.Mul(cc, e.Ext2.FromConstant(frobeniusCoeffs[k-1][0], frobeniusCoeffs[k-1][1]))
+	}
+	return &E12{
+		C0: E6{B0: *coeff(&x.C0.B0, 0), B1: *coeff(&x.C0.B1, 2), B2: *coeff(&x.C0.B2, 4)},
+		C1: E6{B0: *coeff(&x.C1.B0, 1), B1: *coeff(&x.C1.B1, 3), B2: *coeff(&x.C1.B2, 5)},
+	}
+}
+
+// FrobeniusSquare returns x^(p²).
+func (e *Ext12) FrobeniusSquare(x *E12) *E12 {
+	// the coefficient of w^k is multiplied by (1+u)^(k(p²-1)/6)
+	coeff := func(c *E2, k int) *E2 {
+		if k == 0 {
+			return c
+		}
+		return e.Ext2.MulByConstElement(c, frobeniusSquareCoeffs[k-1])
+	}
+	return &E12{
+		C0: E6{B0: *coeff(&x.C0.B0, 0), B1: *coeff(&x.C0.B1, 2), B2: *coeff(&x.C0.B2, 4)},
+		C1: E6{B0: *coeff(&x.C1.B0, 1), B1: *coeff(&x.C1.B1, 3), B2: *coeff(&x.C1.B2, 5)},
+	}
+}
+
+// ExptHalf returns x^(t/2) for x in the cyclotomic subgroup, where t =
+// -0xd201000000010000 is the seed of BLS12-381.
+func (e *Ext12) ExptHalf(x *E12) *E12 {
+	res := x
+	for i := 61; i >= 0; i-- {
+		res = e.CyclotomicSquare(res)
+		if (xHalfAbs>>i)&1 == 1 {
+			res = e.Mul(res, x)
+		}
+	}
+	// the seed is negative
+	return e.Conjugate(res)
+}
+
+// Expt returns x^t for x in the cyclotomic subgroup, where t =
+// -0xd201000000010000 is the seed of BLS12-381.
+func (e *Ext12) Expt(x *E12) *E12 {
+	return e.CyclotomicSquare(e.ExptHalf(x))
+}
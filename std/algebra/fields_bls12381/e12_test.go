@@ -0,0 +1,156 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type e12Op int
+
+const (
+	e12Mul e12Op = iota
+	e12Square
+	e12Inverse
+	e12MulBy014
+	e12Frobenius
+	e12FrobeniusSquare
+	e12CyclotomicSquare
+	e12Expt
+)
+
+type e12Circuit struct {
+	A, B, C E12
+	op      e12Op
+}
+
+func (c *e12Circuit) Define(api frontend.API) error {
+	e, err := NewExt12(api)
+	if err != nil {
+		return err
+	}
+	var res *E12
+	switch c.op {
+	case e12Mul:
+		res = e.Mul(&c.A, &c.B)
+	case e12Square:
+		res = e.Square(&c.A)
+	case e12Inverse:
+		res = e.Inverse(&c.A)
+	case e12MulBy014:
+		res = e.MulBy014(&c.A, &c.B.C0.B0, &c.B.C0.B1, &c.B.C1.B1)
+	case e12Frobenius:
+		res = e.Frobenius(&c.A)
+	case e12FrobeniusSquare:
+		res = e.FrobeniusSquare(&c.A)
+	case e12CyclotomicSquare:
+		res = e.CyclotomicSquare(&c.A)
+	case e12Expt:
+		res = e.Expt(&c.A)
+	}
+	e.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+// checkE12 checks in the test engine that op applied to a and b returns c.
+func checkE12(assert *test.Assert, op e12Op, a, b, c *bls12381.GT) {
+	circuit := e12Circuit{A: NewE12(), B: NewE12(), C: NewE12(), op: op}
+	var witness e12Circuit
+	witness.A.Assign(a)
+	witness.B.Assign(b)
+	witness.C.Assign(c)
+	err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+}
+
+func randomE12(assert *test.Assert) *bls12381.GT {
+	var a bls12381.GT
+	_, err := a.SetRandom()
+	assert.NoError(err)
+	return &a
+}
+
+// randomCyclotomic returns a random element of the cyclotomic subgroup.
+func randomCyclotomic(assert *test.Assert) *bls12381.GT {
+	a := randomE12(assert)
+	// a^((p⁶-1)(p²+1))
+	var t bls12381.GT
+	t.Conjugate(a)
+	a.Inverse(a)
+	t.Mul(&t, a)
+	a.FrobeniusSquare(&t).Mul(a, &t)
+	return a
+}
+
+func TestMulFp12(t *testing.T) {
+	assert := test.NewAssert(t)
+	a, b := randomE12(assert), randomE12(assert)
+	var c bls12381.GT
+	c.Mul(a, b)
+	checkE12(assert, e12Mul, a, b, &c)
+}
+
+func TestSquareFp12(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := randomE12(assert)
+	var c bls12381.GT
+	c.Square(a)
+	checkE12(assert, e12Square, a, a, &c)
+}
+
+func TestInverseFp12(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := randomE12(assert)
+	var c bls12381.GT
+	c.Inverse(a)
+	checkE12(assert, e12Inverse, a, a, &c)
+}
+
+func TestMulBy014Fp12(t *testing.T) {
+	assert := test.NewAssert(t)
+	a, b := randomE12(assert), randomE12(assert)
+	// the sparse element is given by b.C0.B0, b.C0.B1 and b.C1.B1
+	c := *a
+	c.MulBy014(&b.C0.B0, &b.C0.B1, &b.C1.B1)
+	checkE12(assert, e12MulBy014, a, b, &c)
+}
+
+func TestFrobeniusFp12(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := randomE12(assert)
+	var c, d bls12381.GT
+	c.Frobenius(a)
+	d.FrobeniusSquare(a)
+	checkE12(assert, e12Frobenius, a, a, &c)
+	checkE12(assert, e12FrobeniusSquare, a, a, &d)
+}
+
+func TestCyclotomicFp12(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := randomCyclotomic(assert)
+	var c, d bls12381.GT
+	c.CyclotomicSquare(a)
+	d.Expt(a)
+	checkE12(assert, e12CyclotomicSquare, a, a, &c)
+	checkE12(assert, e12Expt, a, a, &d)
+}
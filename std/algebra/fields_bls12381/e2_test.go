@@ -0,0 +1,119 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type e2Op int
+
+const (
+	e2Mul e2Op = iota
+	e2Div
+	e2SquareInverse
+)
+
+type e2Circuit struct {
+	A, B, C E2
+	op      e2Op
+}
+
+func (c *e2Circuit) Define(api frontend.API) error {
+	e, err := NewExt2(api)
+	if err != nil {
+		return err
+	}
+	var res *E2
+	switch c.op {
+	case e2Mul:
+		res = e.Mul(&c.A, &c.B)
+	case e2Div:
+		res = e.Div(&c.A, &c.B)
+	case e2SquareInverse:
+		res = e.Inverse(e.Square(&c.A))
+	}
+	e.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func newE2Circuit(op e2Op) *e2Circuit {
+	return &e2Circuit{A: NewE2(), B: NewE2(), C: NewE2(), op: op}
+}
+
+// randomE2 returns a random element of Fp2 as the coordinates of a G2 point
+// as the internal tower type cannot be named.
+func randomE2(assert *test.Assert) bls12381.G2Affine {
+	var v bls12381.G2Affine
+	_, err := v.X.SetRandom()
+	assert.NoError(err)
+	return v
+}
+
+func TestMulFp2(t *testing.T) {
+	assert := test.NewAssert(t)
+	a, b := randomE2(assert), randomE2(assert)
+	var c bls12381.G2Affine
+	c.X.Mul(&a.X, &b.X)
+
+	var witness e2Circuit
+	witness.A.Assign(&a.X.A0, &a.X.A1)
+	witness.B.Assign(&b.X.A0, &b.X.A1)
+	witness.C.Assign(&c.X.A0, &c.X.A1)
+	circuit := newE2Circuit(e2Mul)
+	assert.ProverSucceeded(circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+
+	witness.C.Assign(&a.X.A0, &a.X.A1)
+	assert.ProverFailed(circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
+
+func TestDivFp2(t *testing.T) {
+	assert := test.NewAssert(t)
+	a, b := randomE2(assert), randomE2(assert)
+	var c bls12381.G2Affine
+	c.X.Inverse(&b.X)
+	c.X.Mul(&a.X, &c.X)
+
+	var witness e2Circuit
+	witness.A.Assign(&a.X.A0, &a.X.A1)
+	witness.B.Assign(&b.X.A0, &b.X.A1)
+	witness.C.Assign(&c.X.A0, &c.X.A1)
+	circuit := newE2Circuit(e2Div)
+	assert.SolvingSucceeded(circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
+
+func TestSquareInverseFp2(t *testing.T) {
+	assert := test.NewAssert(t)
+	a := randomE2(assert)
+	var c bls12381.G2Affine
+	c.X.Square(&a.X)
+	c.X.Inverse(&c.X)
+
+	var witness e2Circuit
+	witness.A.Assign(&a.X.A0, &a.X.A1)
+	witness.B.Assign(&a.X.A0, &a.X.A1)
+	witness.C.Assign(&c.X.A0, &c.X.A1)
+	circuit := newE2Circuit(e2SquareInverse)
+	err := test.IsSolved(circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bls12381
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	for _, h := range GetHints() {
+		hint.Register(h)
+	}
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []hint.Function {
+	return []hint.Function{
+		InverseE2Hint,
+		DivE2Hint,
+		InverseE12Hint,
+	}
+}
+
+// InverseE2Hint computes the inverse of the Fp2 element given by the emulated
+// inputs a0 and a1. It is called with [emulated.Field.NewHint].
+func InverseE2Hint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(p *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 2 || len(outputs) != 2 {
+			return errors.New("expecting two inputs and two outputs")
+		}
+		inv0, inv1, err := inverseE2(p, inputs[0], inputs[1])
+		if err != nil {
+			return err
+		}
+		outputs[0].Set(inv0)
+		outputs[1].Set(inv1)
+		return nil
+	})
+}
+
+// DivE2Hint computes x/y for the Fp2 elements given by the emulated inputs
+// x0, x1, y0 and y1. It is called with [emulated.Field.NewHint].
+func DivE2Hint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(p *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 4 || len(outputs) != 2 {
+			return errors.New("expecting four inputs and two outputs")
+		}
+		inv0, inv1, err := inverseE2(p, inputs[2], inputs[3])
+		if err != nil {
+			return err
+		}
+		// (x0 + x1*u)(i0 + i1*u) = x0*i0 - x1*i1 + (x0*i1 + x1*i0)*u
+		outputs[0].Sub(new(big.Int).Mul(inputs[0], inv0), new(big.Int).Mul(inputs[1], inv1))
+		outputs[1].Add(new(big.Int).Mul(inputs[0], inv1), new(big.Int).Mul(inputs[1], inv0))
+		return nil
+	})
+}
+
+// InverseE12Hint computes the inverse of the Fp12 element given by the twelve
+// emulated inputs in the order C0.B0.A0, C0.B0.A1, C0.B1.A0, ..., C1.B2.A1. It
+// is called with [emulated.Field.NewHint].
+func InverseE12Hint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(p *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 12 || len(outputs) != 12 {
+			return errors.New("expecting twelve inputs and twelve outputs")
+		}
+		var x bls12381.GT
+		coords := e12Coordinates(&x)
+		for i := range coords {
+			coords[i].SetBigInt(inputs[i])
+		}
+		var zero bls12381.GT
+		if x.Equal(&zero) {
+			return errors.New("no inverse")
+		}
+		x.Inverse(&x)
+		for i := range coords {
+			coords[i].ToBigIntRegular(outputs[i])
+		}
+		return nil
+	})
+}
+
+// inverseE2 returns the inverse of a0 + a1*u modulo p.
+func inverseE2(p, a0, a1 *big.Int) (*big.Int, *big.Int, error) {
+	// 1/(a0 + a1*u) = (a0 - a1*u) / (a0² + a1²)
+	norm := new(big.Int).Mul(a0, a0)
+	norm.Add(norm, new(big.Int).Mul(a1, a1))
+	norm.Mod(norm, p)
+	if norm.ModInverse(norm, p) == nil {
+		return nil, nil, errors.New("no inverse")
+	}
+	inv0 := new(big.Int).Mul(a0, norm)
+	inv0.Mod(inv0, p)
+	inv1 := new(big.Int).Mul(a1, norm)
+	inv1.Neg(inv1).Mod(inv1, p)
+	return inv0, inv1, nil
+}
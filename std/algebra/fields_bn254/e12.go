@@ -0,0 +1,186 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bn254
+
+import (
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// E12 is an element of Fp12 given as C0 + C1*w.
+type E12 struct {
+	C0, C1 E6
+}
+
+// NewE12 returns an element with allocated limbs. It is used to define the
+// element in a circuit structure.
+func NewE12() E12 {
+	return E12{C0: NewE6(), C1: NewE6()}
+}
+
+// Assign is a helper to assign the native element v into the coordinates.
+func (e *E12) Assign(v *bn254.GT) {
+	c := *v
+	coords := e12Coordinates(&c)
+	limbs := make([]emulated.Element[emulated.BN254Fp], len(coords))
+	for i := range coords {
+		limbs[i] = emulated.NewElement[emulated.BN254Fp](coords[i])
+	}
+	e.C0.B0.A0, e.C0.B0.A1 = limbs[0], limbs[1]
+	e.C0.B1.A0, e.C0.B1.A1 = limbs[2], limbs[3]
+	e.C0.B2.A0, e.C0.B2.A1 = limbs[4], limbs[5]
+	e.C1.B0.A0, e.C1.B0.A1 = limbs[6], limbs[7]
+	e.C1.B1.A0, e.C1.B1.A1 = limbs[8], limbs[9]
+	e.C1.B2.A0, e.C1.B2.A1 = limbs[10], limbs[11]
+}
+
+// e12Coordinates returns pointers to the base field coordinates of the native
+// element v in the order C0.B0.A0, C0.B0.A1, C0.B1.A0, ..., C1.B2.A1.
+func e12Coordinates(v *bn254.GT) []*fp.Element {
+	return []*fp.Element{
+		&v.C0.B0.A0, &v.C0.B0.A1, &v.C0.B1.A0, &v.C0.B1.A1, &v.C0.B2.A0, &v.C0.B2.A1,
+		&v.C1.B0.A0, &v.C1.B0.A1, &v.C1.B1.A0, &v.C1.B1.A1, &v.C1.B2.A0, &v.C1.B2.A1,
+	}
+}
+
+// coordinates returns pointers to the base field coordinates of e in the same
+// order as e12Coordinates.
+func (e *E12) coordinates() []*baseElement {
+	return []*baseElement{
+		&e.C0.B0.A0, &e.C0.B0.A1, &e.C0.B1.A0, &e.C0.B1.A1, &e.C0.B2.A0, &e.C0.B2.A1,
+		&e.C1.B0.A0, &e.C1.B0.A1, &e.C1.B1.A0, &e.C1.B1.A1, &e.C1.B2.A0, &e.C1.B2.A1,
+	}
+}
+
+// Ext12 performs the arithmetic in Fp12. It embeds [Ext6] for the arithmetic
+// of the coefficients.
+type Ext12 struct {
+	*Ext6
+}
+
+// NewExt12 returns a new [Ext12] instance. It returns an error if initialising
+// the emulated base field fails.
+func NewExt12(api frontend.API) (*Ext12, error) {
+	e6, err := NewExt6(api)
+	if err != nil {
+		return nil, err
+	}
+	return &Ext12{Ext6: e6}, nil
+}
+
+// Zero returns the constant zero element.
+func (e *Ext12) Zero() *E12 {
+	z := e.Ext6.Zero()
+	return &E12{C0: *z, C1: *z}
+}
+
+// One returns the constant one element.
+func (e *Ext12) One() *E12 {
+	return &E12{C0: *e.Ext6.One(), C1: *e.Ext6.Zero()}
+}
+
+// Add returns x+y.
+func (e *Ext12) Add(x, y *E12) *E12 {
+	return &E12{
+		C0: *e.Ext6.Add(&x.C0, &y.C0),
+		C1: *e.Ext6.Add(&x.C1, &y.C1),
+	}
+}
+
+// Sub returns x-y.
+func (e *Ext12) Sub(x, y *E12) *E12 {
+	return &E12{
+		C0: *e.Ext6.Sub(&x.C0, &y.C0),
+		C1: *e.Ext6.Sub(&x.C1, &y.C1),
+	}
+}
+
+// Conjugate returns C0 - C1*w, which is also x^(p⁶). For the elements of the
+// cyclotomic subgroup it is the inverse.
+func (e *Ext12) Conjugate(x *E12) *E12 {
+	return &E12{
+		C0: x.C0,
+		C1: *e.Ext6.Neg(&x.C1),
+	}
+}
+
+// Mul returns x*y.
+func (e *Ext12) Mul(x, y *E12) *E12 {
+	a := e.Ext6.Mul(e.Ext6.Add(&x.C0, &x.C1), e.Ext6.Add(&y.C0, &y.C1))
+	b := e.Ext6.Mul(&x.C0, &y.C0)
+	c := e.Ext6.Mul(&x.C1, &y.C1)
+	return &E12{
+		C0: *e.Ext6.Add(e.Ext6.MulByNonResidue(c), b),
+		C1: *e.Ext6.Sub(e.Ext6.Sub(a, b), c),
+	}
+}
+
+// Square returns x².
+func (e *Ext12) Square(x *E12) *E12 {
+	// Algorithm 22 from https://eprint.iacr.org/2010/354.pdf
+	c0 := e.Ext6.Sub(&x.C0, &x.C1)
+	c3 := e.Ext6.Sub(&x.C0, e.Ext6.MulByNonResidue(&x.C1))
+	c2 := e.Ext6.Mul(&x.C0, &x.C1)
+	c0 = e.Ext6.Add(e.Ext6.Mul(c0, c3), c2)
+	return &E12{
+		C0: *e.Ext6.Add(c0, e.Ext6.MulByNonResidue(c2)),
+		C1: *e.Ext6.Double(c2),
+	}
+}
+
+// Inverse returns 1/x. The inverse is computed in a hint and constrained by
+// checking x * (1/x) = 1. If x is zero, then the constraints are not
+// satisfiable.
+func (e *Ext12) Inverse(x *E12) *E12 {
+	res := e.fp.NewHint(InverseE12Hint, 12, x.coordinates()...)
+	var inv E12
+	for i, c := range inv.coordinates() {
+		*c = *res[i]
+	}
+	e.AssertIsEqual(e.Mul(x, &inv), e.One())
+	return &inv
+}
+
+// MulBy034 returns x*(c0 + c3*w + c4*v*w), which is the form of the line
+// evaluations in the Miller loop.
+func (e *Ext12) MulBy034(x *E12, c0, c3, c4 *E2) *E12 {
+	a := e.Ext6.MulByE2(&x.C0, c0)
+	b := e.Ext6.MulBy01(&x.C1, c3, c4)
+
+	d := e.Ext6.MulBy01(e.Ext6.Add(&x.C0, &x.C1), e.Ext2.Add(c0, c3), c4)
+	return &E12{
+		C0: *e.Ext6.Add(e.Ext6.MulByNonResidue(b), a),
+		C1: *e.Ext6.Sub(e.Ext6.Sub(d, a), b),
+	}
+}
+
+// Select returns x if selector is 1 and y otherwise. The selector is assumed
+// to be boolean.
+func (e *Ext12) Select(selector frontend.Variable, x, y *E12) *E12 {
+	return &E12{
+		C0: *e.Ext6.Select(selector, &x.C0, &y.C0),
+		C1: *e.Ext6.Select(selector, &x.C1, &y.C1),
+	}
+}
+
+// AssertIsEqual asserts that x and y are equal.
+func (e *Ext12) AssertIsEqual(x, y *E12) {
+	e.Ext6.AssertIsEqual(&x.C0, &y.C0)
+	e.Ext6.AssertIsEqual(&x.C1, &y.C1)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bn254
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// BaseField is the emulated base field of BN254.
+type BaseField = emulated.Field[emulated.BN254Fp]
+
+// baseElement is an element of the emulated base field.
+type baseElement = emulated.Element[emulated.BN254Fp]
+
+// E2 is an element of Fp2 given as A0 + A1*u.
+type E2 struct {
+	A0, A1 emulated.Element[emulated.BN254Fp]
+}
+
+// NewE2 returns an element with allocated limbs. It is used to define the
+// element in a circuit structure.
+func NewE2() E2 {
+	return E2{
+		A0: emulated.NewElement[emulated.BN254Fp](nil),
+		A1: emulated.NewElement[emulated.BN254Fp](nil),
+	}
+}
+
+// Assign is a helper to assign the native element a0 + a1*u into the
+// coordinates.
+func (e *E2) Assign(a0, a1 *fp.Element) {
+	e.A0 = emulated.NewElement[emulated.BN254Fp](a0)
+	e.A1 = emulated.NewElement[emulated.BN254Fp](a1)
+}
+
+// Ext2 performs the arithmetic in Fp2.
+type Ext2 struct {
+	api frontend.API
+	fp  *BaseField
+}
+
+// NewExt2 returns a new [Ext2] instance. It returns an error if initialising
+// the emulated base field fails.
+func NewExt2(api frontend.API) (*Ext2, error) {
+	fp, err := emulated.NewField[emulated.BN254Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base api: %w", err)
+	}
+	return &Ext2{api: api, fp: fp}, nil
+}
+
+// BaseField returns the emulated base field.
+func (e *Ext2) BaseField() *BaseField {
+	return e.fp
+}
+
+// API returns the native API.
+func (e *Ext2) API() frontend.API {
+	return e.api
+}
+
+// FromConstant returns the constant element a0 + a1*u.
+func (e *Ext2) FromConstant(a0, a1 *big.Int) *E2 {
+	return &E2{A0: *e.fp.NewElement(a0), A1: *e.fp.NewElement(a1)}
+}
+
+// Zero returns the constant zero element.
+func (e *Ext2) Zero() *E2 {
+	return &E2{A0: *e.fp.Zero(), A1: *e.fp.Zero()}
+}
+
+// One returns the constant one element.
+func (e *Ext2) One() *E2 {
+	return &E2{A0: *e.fp.One(), A1: *e.fp.Zero()}
+}
+
+// Add returns x+y.
+func (e *Ext2) Add(x, y *E2) *E2 {
+	return &E2{
+		A0: *e.fp.Add(&x.A0, &y.A0),
+		A1: *e.fp.Add(&x.A1, &y.A1),
+	}
+}
+
+// Sub returns x-y.
+func (e *Ext2) Sub(x, y *E2) *E2 {
+	return &E2{
+		A0: *e.fp.Sub(&x.A0, &y.A0),
+		A1: *e.fp.Sub(&x.A1, &y.A1),
+	}
+}
+
+// Neg returns -x.
+func (e *Ext2) Neg(x *E2) *E2 {
+	return &E2{
+		A0: *e.fp.Neg(&x.A0),
+		A1: *e.fp.Neg(&x.A1),
+	}
+}
+
+// Double returns 2x.
+func (e *Ext2) Double(x *E2) *E2 {
+	return e.Add(x, x)
+}
+
+// Mul returns x*y.
+func (e *Ext2) Mul(x, y *E2) *E2 {
+	// Karatsuba: (x0+x1)(y0+y1) - x0y0 - x1y1 = x0y1 + x1y0
+	a := e.fp.Mul(e.fp.Add(&x.A0, &x.A1), e.fp.Add(&y.A0, &y.A1))
+	b := e.fp.Mul(&x.A0, &y.A0)
+	c := e.fp.Mul(&x.A1, &y.A1)
+	return &E2{
+		A0: *e.fp.Sub(b, c),
+		A1: *e.fp.Sub(e.fp.Sub(a, b), c),
+	}
+}
+
+// Square returns x².
+func (e *Ext2) Square(x *E2) *E2 {
+	// (x0+x1)(x0-x1) + 2x0x1*u
+	a := e.fp.Mul(e.fp.Add(&x.A0, &x.A1), e.fp.Sub(&x.A0, &x.A1))
+	b := e.fp.Mul(&x.A0, &x.A1)
+	return &E2{
+		A0: *a,
+		A1: *e.fp.Add(b, b),
+	}
+}
+
+// MulByElement returns x*y for y in the base field.
+func (e *Ext2) MulByElement(x *E2, y *baseElement) *E2 {
+	return &E2{
+		A0: *e.fp.Mul(&x.A0, y),
+		A1: *e.fp.Mul(&x.A1, y),
+	}
+}
+
+// MulByConstElement returns x*c for the constant c in the base field.
+func (e *Ext2) MulByConstElement(x *E2, c *big.Int) *E2 {
+	return &E2{
+		A0: *e.fp.MulConst(&x.A0, c),
+		A1: *e.fp.MulConst(&x.A1, c),
+	}
+}
+
+// MulByNonResidue returns x*(9+u).
+func (e *Ext2) MulByNonResidue(x *E2) *E2 {
+	return &E2{
+		A0: *e.fp.Sub(e.fp.MulConst(&x.A0, big.NewInt(9)), &x.A1),
+		A1: *e.fp.Add(&x.A0, e.fp.MulConst(&x.A1, big.NewInt(9))),
+	}
+}
+
+// Conjugate returns the conjugate x0 - x1*u of x, which is also x^p.
+func (e *Ext2) Conjugate(x *E2) *E2 {
+	return &E2{
+		A0: x.A0,
+		A1: *e.fp.Neg(&x.A1),
+	}
+}
+
+// Inverse returns 1/x. The inverse is computed in a hint and constrained by
+// checking x * (1/x) = 1. If x is zero, then the constraints are not
+// satisfiable.
+func (e *Ext2) Inverse(x *E2) *E2 {
+	res := e.fp.NewHint(InverseE2Hint, 2, &x.A0, &x.A1)
+	inv := &E2{A0: *res[0], A1: *res[1]}
+	e.AssertIsEqual(e.Mul(x, inv), e.One())
+	return inv
+}
+
+// Div returns x/y. The quotient is computed in a hint and constrained by
+// checking (x/y) * y = x. If y is zero, then the constraints are not
+// satisfiable.
+func (e *Ext2) Div(x, y *E2) *E2 {
+	res := e.fp.NewHint(DivE2Hint, 2, &x.A0, &x.A1, &y.A0, &y.A1)
+	div := &E2{A0: *res[0], A1: *res[1]}
+	e.AssertIsEqual(e.Mul(div, y), x)
+	return div
+}
+
+// Select returns x if selector is 1 and y otherwise. The selector is assumed
+// to be boolean.
+func (e *Ext2) Select(selector frontend.Variable, x, y *E2) *E2 {
+	return &E2{
+		A0: *e.fp.Select(selector, &x.A0, &y.A0),
+		A1: *e.fp.Select(selector, &x.A1, &y.A1),
+	}
+}
+
+// AssertIsEqual asserts that x and y are equal.
+func (e *Ext2) AssertIsEqual(x, y *E2) {
+	e.fp.AssertIsEqual(&x.A0, &y.A0)
+	e.fp.AssertIsEqual(&x.A1, &y.A1)
+}
+
+// IsZero returns a boolean indicating if x is zero.
+func (e *Ext2) IsZero(x *E2) frontend.Variable {
+	return e.api.And(e.fp.IsZero(&x.A0), e.fp.IsZero(&x.A1))
+}
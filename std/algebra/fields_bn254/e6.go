@@ -0,0 +1,181 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fields_bn254
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// E6 is an element of Fp6 given as B0 + B1*v + B2*v².
+type E6 struct {
+	B0, B1, B2 E2
+}
+
+// NewE6 returns an element with allocated limbs. It is used to define the
+// element in a circuit structure.
+func NewE6() E6 {
+	return E6{B0: NewE2(), B1: NewE2(), B2: NewE2()}
+}
+
+// Ext6 performs the arithmetic in Fp6. It embeds [Ext2] for the arithmetic
+// of the coefficients.
+type Ext6 struct {
+	*Ext2
+}
+
+// NewExt6 returns a new [Ext6] instance. It returns an error if initialising
+// the emulated base field fails.
+func NewExt6(api frontend.API) (*Ext6, error) {
+	e2, err := NewExt2(api)
+	if err != nil {
+		return nil, err
+	}
+	return &Ext6{Ext2: e2}, nil
+}
+
+// Zero returns the constant zero element.
+func (e *Ext6) Zero() *E6 {
+	z := e.Ext2.Zero()
+	return &E6{B0: *z, B1: *z, B2: *z}
+}
+
+// One returns the constant one element.
+func (e *Ext6) One() *E6 {
+	z := e.Ext2.Zero()
+	return &E6{B0: *e.Ext2.One(), B1: *z, B2: *z}
+}
+
+// Add returns x+y.
+func (e *Ext6) Add(x, y *E6) *E6 {
+	return &E6{
+		B0: *e.Ext2.Add(&x.B0, &y.B0),
+		B1: *e.Ext2.Add(&x.B1, &y.B1),
+		B2: *e.Ext2.Add(&x.B2, &y.B2),
+	}
+}
+
+// Sub returns x-y.
+func (e *Ext6) Sub(x, y *E6) *E6 {
+	return &E6{
+		B0: *e.Ext2.Sub(&x.B0, &y.B0),
+		B1: *e.Ext2.Sub(&x.B1, &y.B1),
+		B2: *e.Ext2.Sub(&x.B2, &y.B2),
+	}
+}
+
+// Neg returns -x.
+func (e *Ext6) Neg(x *E6) *E6 {
+	return &E6{
+		B0: *e.Ext2.Neg(&x.B0),
+		B1: *e.Ext2.Neg(&x.B1),
+		B2: *e.Ext2.Neg(&x.B2),
+	}
+}
+
+// Double returns 2x.
+func (e *Ext6) Double(x *E6) *E6 {
+	return e.Add(x, x)
+}
+
+// Mul returns x*y.
+func (e *Ext6) Mul(x, y *E6) *E6 {
+	// Algorithm 13 from https://eprint.iacr.org/2010/354.pdf
+	t0 := e.Ext2.Mul(&x.B0, &y.B0)
+	t1 := e.Ext2.Mul(&x.B1, &y.B1)
+	t2 := e.Ext2.Mul(&x.B2, &y.B2)
+
+	c0 := e.Ext2.Mul(e.Ext2.Add(&x.B1, &x.B2), e.Ext2.Add(&y.B1, &y.B2))
+	c0 = e.Ext2.Sub(e.Ext2.Sub(c0, t1), t2)
+	c0 = e.Ext2.Add(e.Ext2.MulByNonResidue(c0), t0)
+
+	c1 := e.Ext2.Mul(e.Ext2.Add(&x.B0, &x.B1), e.Ext2.Add(&y.B0, &y.B1))
+	c1 = e.Ext2.Sub(e.Ext2.Sub(c1, t0), t1)
+	c1 = e.Ext2.Add(c1, e.Ext2.MulByNonResidue(t2))
+
+	c2 := e.Ext2.Mul(e.Ext2.Add(&x.B0, &x.B2), e.Ext2.Add(&y.B0, &y.B2))
+	c2 = e.Ext2.Add(e.Ext2.Sub(e.Ext2.Sub(c2, t0), t2), t1)
+
+	return &E6{B0: *c0, B1: *c1, B2: *c2}
+}
+
+// Square returns x².
+func (e *Ext6) Square(x *E6) *E6 {
+	// Algorithm 16 from https://eprint.iacr.org/2010/354.pdf
+	c4 := e.Ext2.Double(e.Ext2.Mul(&x.B0, &x.B1))
+	c5 := e.Ext2.Square(&x.B2)
+	c1 := e.Ext2.Add(e.Ext2.MulByNonResidue(c5), c4)
+	c2 := e.Ext2.Sub(c4, c5)
+	c3 := e.Ext2.Square(&x.B0)
+	c4 = e.Ext2.Add(e.Ext2.Sub(&x.B0, &x.B1), &x.B2)
+	c5 = e.Ext2.Double(e.Ext2.Mul(&x.B1, &x.B2))
+	c4 = e.Ext2.Square(c4)
+	c0 := e.Ext2.Add(e.Ext2.MulByNonResidue(c5), c3)
+	b2 := e.Ext2.Sub(e.Ext2.Add(e.Ext2.Add(c2, c4), c5), c3)
+	return &E6{B0: *c0, B1: *c1, B2: *b2}
+}
+
+// MulByNonResidue returns x*v.
+func (e *Ext6) MulByNonResidue(x *E6) *E6 {
+	return &E6{
+		B0: *e.Ext2.MulByNonResidue(&x.B2),
+		B1: x.B0,
+		B2: x.B1,
+	}
+}
+
+// MulByE2 returns x*y for y in Fp2.
+func (e *Ext6) MulByE2(x *E6, y *E2) *E6 {
+	return &E6{
+		B0: *e.Ext2.Mul(&x.B0, y),
+		B1: *e.Ext2.Mul(&x.B1, y),
+		B2: *e.Ext2.Mul(&x.B2, y),
+	}
+}
+
+// MulBy01 returns x*(c0 + c1*v).
+func (e *Ext6) MulBy01(x *E6, c0, c1 *E2) *E6 {
+	a := e.Ext2.Mul(&x.B0, c0)
+	b := e.Ext2.Mul(&x.B1, c1)
+
+	t0 := e.Ext2.Mul(c1, e.Ext2.Add(&x.B1, &x.B2))
+	t0 = e.Ext2.Add(e.Ext2.MulByNonResidue(e.Ext2.Sub(t0, b)), a)
+
+	t2 := e.Ext2.Mul(c0, e.Ext2.Add(&x.B0, &x.B2))
+	t2 = e.Ext2.Add(e.Ext2.Sub(t2, a), b)
+
+	t1 := e.Ext2.Mul(e.Ext2.Add(c0, c1), e.Ext2.Add(&x.B0, &x.B1))
+	t1 = e.Ext2.Sub(e.Ext2.Sub(t1, a), b)
+
+	return &E6{B0: *t0, B1: *t1, B2: *t2}
+}
+
+// Select returns x if selector is 1 and y otherwise. The selector is assumed
+// to be boolean.
+func (e *Ext6) Select(selector frontend.Variable, x, y *E6) *E6 {
+	return &E6{
+		B0: *e.Ext2.Select(selector, &x.B0, &y.B0),
+		B1: *e.Ext2.Select(selector, &x.B1, &y.B1),
+		B2: *e.Ext2.Select(selector, &x.B2, &y.B2),
+	}
+}
+
+// AssertIsEqual asserts that x and y are equal.
+func (e *Ext6) AssertIsEqual(x, y *E6) {
+	e.Ext2.AssertIsEqual(&x.B0, &y.B0)
+	e.Ext2.AssertIsEqual(&x.B1, &y.B1)
+	e.Ext2.AssertIsEqual(&x.B2, &y.B2)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package sw_bls12381 implements the groups G1, G2 and GT of the BLS12-381
+curve and the optimal ate pairing over emulated fields.
+
+The base field of BLS12-381 is emulated using the package
+[github.com/consensys/gnark/std/math/emulated], so that the pairing can be
+computed in circuits over a different native field, for example BN254. The
+group G1 uses the package [github.com/consensys/gnark/std/algebra/weierstrass]
+and the extension fields use the package
+[github.com/consensys/gnark/std/algebra/fields_bls12381].
+
+The points are represented in affine coordinates and the group operations
+use incomplete formulas. The point at infinity is not representable.
+
+The package also implements the hashing of byte strings to G2 as specified in
+RFC 9380 for the suite BLS12381G2_XMD:SHA-256_SSWU_RO_.
+*/
+package sw_bls12381
@@ -0,0 +1,48 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12381
+
+import (
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// G1Affine is a point of G1 in affine coordinates.
+type G1Affine weierstrass.AffinePoint[emulated.BLS12381Fp]
+
+// G1 is the curve for the operations in G1. The scalars are elements of the
+// emulated scalar field of BLS12-381.
+type G1 = weierstrass.Curve[emulated.BLS12381Fp, emulated.BLS12381Fr]
+
+// NewG1 returns a new [G1] instance for the operations in G1.
+func NewG1(api frontend.API) (*G1, error) {
+	return weierstrass.New[emulated.BLS12381Fp, emulated.BLS12381Fr](api, weierstrass.GetBLS12381Params())
+}
+
+// NewG1Affine returns a point with allocated limbs. It is used to define the
+// point in a circuit structure.
+func NewG1Affine() G1Affine {
+	return G1Affine(weierstrass.NewAffinePoint[emulated.BLS12381Fp]())
+}
+
+// Assign is a helper to assign the native point v into the coordinates.
+func (p *G1Affine) Assign(v *bls12381.G1Affine) {
+	p.X = emulated.NewElement[emulated.BLS12381Fp](&v.X)
+	p.Y = emulated.NewElement[emulated.BLS12381Fp](&v.Y)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12381
+
+import (
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12381"
+)
+
+// seedAbs is |x| where x = -0xd201000000010000 is the seed of BLS12-381.
+const seedAbs uint64 = 0xd201000000010000
+
+var (
+	// psiX and psiY are the coefficients of the endomorphism ψ.
+	psiX = [2]string{"0", "4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939437"}
+	psiY = [2]string{
+		"2973677408986561043442465346520108879172042883009249989176415018091420807192182638567116318576472649347015917690530",
+		"1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257",
+	}
+	// thirdRootOne is a primitive third root of unity in the base field, so
+	// that ψ²(x, y) = (thirdRootOne*x, -y).
+	thirdRootOne, _ = new(big.Int).SetString("4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939436", 10)
+)
+
+// G2Affine is a point of G2 in affine coordinates. The coordinates are
+// elements of Fp2.
+type G2Affine struct {
+	X, Y fields_bls12381.E2
+}
+
+// NewG2Affine returns a point with allocated limbs. It is used to define the
+// point in a circuit structure.
+func NewG2Affine() G2Affine {
+	return G2Affine{X: fields_bls12381.NewE2(), Y: fields_bls12381.NewE2()}
+}
+
+// Assign is a helper to assign the native point v into the coordinates.
+func (p *G2Affine) Assign(v *bls12381.G2Affine) {
+	p.X.Assign(&v.X.A0, &v.X.A1)
+	p.Y.Assign(&v.Y.A0, &v.Y.A1)
+}
+
+// G2 allows to perform operations on the points of the twist E'(Fp2): Y² =
+// X³ + 4(1+u) containing G2.
+type G2 struct {
+	api  frontend.API
+	ext2 *fields_bls12381.Ext2
+	b    *fields_bls12381.E2
+	psiX *fields_bls12381.E2
+	psiY *fields_bls12381.E2
+}
+
+// NewG2 returns a new [G2] instance for the operations in G2. It returns an
+// error if initialising the emulated field fails.
+func NewG2(api frontend.API) (*G2, error) {
+	ext2, err := fields_bls12381.NewExt2(api)
+	if err != nil {
+		return nil, err
+	}
+	return newG2(api, ext2), nil
+}
+
+func newG2(api frontend.API, ext2 *fields_bls12381.Ext2) *G2 {
+	return &G2{
+		api:  api,
+		ext2: ext2,
+		b:    ext2.FromConstant(big.NewInt(4), big.NewInt(4)),
+		psiX: ext2.FromConstant(bigFromString(psiX[0]), bigFromString(psiX[1])),
+		psiY: ext2.FromConstant(bigFromString(psiY[0]), bigFromString(psiY[1])),
+	}
+}
+
+func bigFromString(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid constant " + s)
+	}
+	return v
+}
+
+// Ext2 returns the arithmetic of the coordinates.
+func (g *G2) Ext2() *fields_bls12381.Ext2 {
+	return g.ext2
+}
+
+// Neg returns -p. It doesn't modify p.
+func (g *G2) Neg(p *G2Affine) *G2Affine {
+	return &G2Affine{X: p.X, Y: *g.ext2.Neg(&p.Y)}
+}
+
+// AssertIsEqual asserts that p and q are the same point.
+func (g *G2) AssertIsEqual(p, q *G2Affine) {
+	g.ext2.AssertIsEqual(&p.X, &q.X)
+	g.ext2.AssertIsEqual(&p.Y, &q.Y)
+}
+
+// AssertIsOnCurve asserts that p satisfies the equation of the twist.
+func (g *G2) AssertIsOnCurve(p *G2Affine) {
+	// y² = x³ + 4(1+u)
+	left := g.ext2.Square(&p.Y)
+	right := g.ext2.Add(g.ext2.Mul(g.ext2.Square(&p.X), &p.X), g.b)
+	g.ext2.AssertIsEqual(left, right)
+}
+
+// Select returns p if b is 1 and q otherwise. The selector is assumed to be
+// boolean.
+func (g *G2) Select(b frontend.Variable, p, q *G2Affine) *G2Affine {
+	return &G2Affine{
+		X: *g.ext2.Select(b, &p.X, &q.X),
+		Y: *g.ext2.Select(b, &p.Y, &q.Y),
+	}
+}
+
+// Add returns p+q. It doesn't modify p nor q. It uses incomplete formulas in
+// affine coordinates: if p and q have the same x-coordinate (p = ±q), then
+// the constraints are not satisfiable. The formulas do not depend on the
+// curve coefficients and can be used for any curve in short Weierstrass form.
+func (g *G2) Add(p, q *G2Affine) *G2Affine {
+	res, _ := g.addStep(p, q)
+	return res
+}
+
+// addStep returns p+q and the slope of the line through p and q.
+func (g *G2) addStep(p, q *G2Affine) (*G2Affine, *fields_bls12381.E2) {
+	// λ = (q.y-p.y)/(q.x-p.x). We compute the inverse of the denominator
+	// instead of the quotient so that the constraints are not satisfiable if
+	// p equals q.
+	λ := g.ext2.Mul(g.ext2.Sub(&q.Y, &p.Y), g.ext2.Inverse(g.ext2.Sub(&q.X, &p.X)))
+	return g.line(p, q, λ), λ
+}
+
+// Double returns 2p. It doesn't modify p. The point p must be on a curve with
+// a = 0 and must not be a point of order 2.
+func (g *G2) Double(p *G2Affine) *G2Affine {
+	res, _ := g.doubleStep(p)
+	return res
+}
+
+// doubleStep returns 2p and the slope of the tangent at p.
+func (g *G2) doubleStep(p *G2Affine) (*G2Affine, *fields_bls12381.E2) {
+	// λ = 3x²/2y
+	xx3 := g.ext2.MulByConstElement(g.ext2.Square(&p.X), big.NewInt(3))
+	λ := g.ext2.Div(xx3, g.ext2.Double(&p.Y))
+	return g.line(p, p, λ), λ
+}
+
+// line returns the third intersection of the line with slope λ through p
+// and q with the curve, negated.
+func (g *G2) line(p, q *G2Affine, λ *fields_bls12381.E2) *G2Affine {
+	// xr = λ²-p.x-q.x
+	xr := g.ext2.Sub(g.ext2.Sub(g.ext2.Square(λ), &p.X), &q.X)
+	// yr = λ(p.x-xr) - p.y
+	yr := g.ext2.Sub(g.ext2.Mul(λ, g.ext2.Sub(&p.X, xr)), &p.Y)
+	return &G2Affine{X: *xr, Y: *yr}
+}
+
+// psi returns ψ(p) where ψ is the untwist-Frobenius-twist endomorphism.
+func (g *G2) psi(p *G2Affine) *G2Affine {
+	return &G2Affine{
+		X: *g.ext2.Mul(g.ext2.Conjugate(&p.X), g.psiX),
+		Y: *g.ext2.Mul(g.ext2.Conjugate(&p.Y), g.psiY),
+	}
+}
+
+// ScalarMulBySeed returns [x]p where x = -0xd201000000010000 is the seed of
+// BLS12-381. The point p must not be of small order, otherwise the
+// constraints are not satisfiable.
+func (g *G2) ScalarMulBySeed(p *G2Affine) *G2Affine {
+	res := p
+	for i := 62; i >= 0; i-- {
+		res = g.Double(res)
+		if (seedAbs>>i)&1 == 1 {
+			res = g.Add(res, p)
+		}
+	}
+	return g.Neg(res)
+}
+
+// AssertIsInSubgroup asserts that p is in G2 by checking that ψ(p) = [x]p,
+// where x is the seed of the curve (see https://eprint.iacr.org/2021/1130).
+// The point p must be on the twist.
+func (g *G2) AssertIsInSubgroup(p *G2Affine) {
+	g.AssertIsEqual(g.psi(p), g.ScalarMulBySeed(p))
+}
+
+// ClearCofactor returns the projection [h_eff]p of a point p of the twist to
+// G2. It uses the endomorphism ψ as in https://eprint.iacr.org/2017/419.pdf,
+// section 4.1. The result is the same as in gnark-crypto.
+func (g *G2) ClearCofactor(p *G2Affine) *G2Affine {
+	// [x²-x-1]p + ψ([x-1]p) + ψ²([2]p)
+	xp := g.ScalarMulBySeed(p)
+	xxp := g.ScalarMulBySeed(xp)
+	negP := g.Neg(p)
+	res := g.Add(g.Add(xxp, g.Neg(xp)), negP)
+	res = g.Add(res, g.psi(g.Add(xp, negP)))
+	p2 := g.Double(p)
+	// ψ²(x, y) = (ωx, -y)
+	psi2 := &G2Affine{
+		X: *g.ext2.MulByConstElement(&p2.X, thirdRootOne),
+		Y: *g.ext2.Neg(&p2.Y),
+	}
+	return g.Add(res, psi2)
+}
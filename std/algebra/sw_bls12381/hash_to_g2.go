@@ -0,0 +1,257 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12381
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12381"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+var (
+	// sswuZ is the non-square Z = -(2+u) of the SSWU map.
+	sswuZ = [2]*big.Int{big.NewInt(-2), big.NewInt(-1)}
+	// sswuA and sswuB are the coefficients of the curve E2': y² = x³ +
+	// 240u*x + 1012(1+u), which is 3-isogenous to the twist.
+	sswuA = [2]*big.Int{big.NewInt(0), big.NewInt(240)}
+	sswuB = [2]*big.Int{big.NewInt(1012), big.NewInt(1012)}
+
+	// isogeny coefficients from RFC 9380, appendix E.3, in increasing
+	// degree. The denominators are monic and the leading coefficient is
+	// omitted.
+	isoXNum = [4][2]string{
+		{"0x5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97d6", "0x5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97d6"},
+		{"0x0", "0x11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71a"},
+		{"0x11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71e", "0x8ab05f8bdd54cde190937e76bc3e447cc27c3d6fbd7063fcd104635a790520c0a395554e5c6aaaa9354ffffffffe38d"},
+		{"0x171d6541fa38ccfaed6dea691f5fb614cb14b4e7f4e810aa22d6108f142b85757098e38d0f671c7188e2aaaaaaaa5ed1", "0x0"},
+	}
+	isoXDen = [2][2]string{
+		{"0x0", "0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa63"},
+		{"0xc", "0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa9f"},
+	}
+	isoYNum = [4][2]string{
+		{"0x1530477c7ab4113b59a4c18b076d11930f7da5d4a07f649bf54439d87d27e500fc8c25ebf8c92f6812cfc71c71c6d706", "0x1530477c7ab4113b59a4c18b076d11930f7da5d4a07f649bf54439d87d27e500fc8c25ebf8c92f6812cfc71c71c6d706"},
+		{"0x0", "0x5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97be"},
+		{"0x11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71c", "0x8ab05f8bdd54cde190937e76bc3e447cc27c3d6fbd7063fcd104635a790520c0a395554e5c6aaaa9354ffffffffe38f"},
+		{"0x124c9ad43b6cf79bfbf7043de3811ad0761b0f37a1e26286b0e977c69aa274524e79097a56dc4bd9e1b371c71c718b10", "0x0"},
+	}
+	isoYDen = [3][2]string{
+		{"0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa8fb", "0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa8fb"},
+		{"0x0", "0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa9d3"},
+		{"0x12", "0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa99"},
+	}
+)
+
+// HashToG2 hashes the message msg to a point of G2 using the domain
+// separation tag dst as specified in RFC 9380 for the suite
+// BLS12381G2_XMD:SHA-256_SSWU_RO_. The message is given as bytes and the
+// result is the same as the one of gnark-crypto HashToCurveG2SSWU. It
+// returns an error if the domain separation tag is longer than 255 bytes.
+//
+// The exceptional cases of the map to the curve and of the incomplete
+// additions happen with negligible probability and make the constraints not
+// satisfiable.
+func (g *G2) HashToG2(msg []frontend.Variable, dst []byte) (*G2Affine, error) {
+	u, err := g.hashToField(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	// the isogeny is a group homomorphism so that we add the points on E2'
+	// and apply the isogeny only once.
+	q0 := g.mapToIsogenousCurve(u[0])
+	q1 := g.mapToIsogenousCurve(u[1])
+	q := g.isogeny(g.Add(q0, q1))
+	return g.ClearCofactor(q), nil
+}
+
+// hashToField hashes msg to two elements of Fp2.
+func (g *G2) hashToField(msg []frontend.Variable, dst []byte) ([2]*fields_bls12381.E2, error) {
+	// L = ceil((ceil(log2(p)) + k) / 8) = 64 bytes per base field element
+	// for the security level k = 128.
+	const L = 64
+	uniform, err := expandMsgXMD(g.api, msg, dst, 4*L)
+	if err != nil {
+		return [2]*fields_bls12381.E2{}, err
+	}
+	fp := g.ext2.BaseField()
+	var els [4]*emulated.Element[emulated.BLS12381Fp]
+	// 2^384 mod p for combining the high and low bits
+	var fpParams emulated.BLS12381Fp
+	shift := new(big.Int).Lsh(big.NewInt(1), 384)
+	shift.Mod(shift, fpParams.Modulus())
+	for i := range els {
+		// the big-endian bytes as little-endian bits
+		chunk := uniform[i*L : (i+1)*L]
+		elBits := make([]frontend.Variable, 0, 8*L)
+		for j := L - 1; j >= 0; j-- {
+			elBits = append(elBits, bits.ToBinary(g.api, chunk[j], bits.WithNbDigits(8))...)
+		}
+		lo := fp.FromBits(elBits[:384]...)
+		hi := fp.FromBits(elBits[384:]...)
+		els[i] = fp.Add(lo, fp.MulConst(hi, shift))
+	}
+	return [2]*fields_bls12381.E2{
+		{A0: *els[0], A1: *els[1]},
+		{A0: *els[2], A1: *els[3]},
+	}, nil
+}
+
+// expandMsgXMD expands msg to lenInBytes bytes using SHA-256 as specified in
+// RFC 9380, section 5.3.1.
+func expandMsgXMD(api frontend.API, msg []frontend.Variable, dst []byte, lenInBytes int) ([]frontend.Variable, error) {
+	const bInBytes, sInBytes = 32, 64
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if ell > 255 || lenInBytes > 65535 {
+		return nil, errors.New("requested output too long")
+	}
+	if len(dst) > 255 {
+		return nil, errors.New("domain separation tag too long")
+	}
+	dstPrime := make([]frontend.Variable, 0, len(dst)+1)
+	for _, b := range dst {
+		dstPrime = append(dstPrime, b)
+	}
+	dstPrime = append(dstPrime, len(dst))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || 0 || DST_prime)
+	msgPrime := make([]frontend.Variable, 0, sInBytes+len(msg)+3+len(dstPrime))
+	for i := 0; i < sInBytes; i++ {
+		msgPrime = append(msgPrime, 0)
+	}
+	msgPrime = append(msgPrime, msg...)
+	msgPrime = append(msgPrime, lenInBytes>>8, lenInBytes&0xff, 0)
+	msgPrime = append(msgPrime, dstPrime...)
+	b0 := sha2.Sum256(api, msgPrime)
+
+	// b_1 = H(b_0 || 1 || DST_prime)
+	// b_i = H(strxor(b_0, b_(i-1)) || i || DST_prime)
+	res := make([]frontend.Variable, 0, ell*bInBytes)
+	bi := b0
+	for i := 1; i <= ell; i++ {
+		var in []frontend.Variable
+		if i == 1 {
+			in = append(in, b0...)
+		} else {
+			in = append(in, xorBytes(api, b0, bi)...)
+		}
+		in = append(in, i)
+		in = append(in, dstPrime...)
+		bi = sha2.Sum256(api, in)
+		res = append(res, bi...)
+	}
+	return res[:lenInBytes], nil
+}
+
+// xorBytes returns the bytewise XOR of a and b.
+func xorBytes(api frontend.API, a, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, len(a))
+	for i := range a {
+		aBits := bits.ToBinary(api, a[i], bits.WithNbDigits(8))
+		bBits := bits.ToBinary(api, b[i], bits.WithNbDigits(8))
+		rBits := make([]frontend.Variable, 8)
+		for j := range rBits {
+			rBits[j] = api.Xor(aBits[j], bBits[j])
+		}
+		res[i] = bits.FromBinary(api, rBits, bits.WithUnconstrainedInputs())
+	}
+	return res
+}
+
+// mapToIsogenousCurve maps u to a point of E2' using the simplified SWU map
+// as specified in RFC 9380, section 6.6.2.
+func (g *G2) mapToIsogenousCurve(u *fields_bls12381.E2) *G2Affine {
+	e := g.ext2
+	z := e.FromConstant(sswuZ[0], sswuZ[1])
+	a := e.FromConstant(sswuA[0], sswuA[1])
+	b := e.FromConstant(sswuB[0], sswuB[1])
+	curve := func(x *fields_bls12381.E2) *fields_bls12381.E2 {
+		// x³ + A*x + B
+		return e.Add(e.Add(e.Mul(e.Square(x), x), e.Mul(a, x)), b)
+	}
+
+	// x1 = (-B/A) * (1 + 1/(Z²u⁴ + Zu²))
+	tv := e.Mul(z, e.Square(u))
+	den := e.Add(e.Square(tv), tv)
+	x1 := e.Div(e.Mul(g.sswuNegBOverA(), e.Add(den, e.One())), den)
+	gx1 := curve(x1)
+	// x2 = Zu² * x1, gx2 = (Zu²)³ * gx1
+	x2 := e.Mul(tv, x1)
+	gx2 := curve(x2)
+
+	// y is the square root of gx1 if it is a square and of gx2 otherwise.
+	// Exactly one of them is a square as Z is not a square.
+	res := e.BaseField().NewHint(SSWUSqrtHint, 2, &gx1.A0, &gx1.A1, &gx2.A0, &gx2.A1, &u.A0, &u.A1)
+	y := &fields_bls12381.E2{A0: *res[0], A1: *res[1]}
+	yy := e.Square(y)
+	isGx1 := e.IsZero(e.Sub(yy, gx1))
+	e.AssertIsEqual(yy, e.Select(isGx1, gx1, gx2))
+	g.api.AssertIsEqual(g.sgn0(u), g.sgn0(y))
+	return &G2Affine{X: *e.Select(isGx1, x1, x2), Y: *y}
+}
+
+// sswuNegBOverA returns the constant -B/A of the SSWU map.
+func (g *G2) sswuNegBOverA() *fields_bls12381.E2 {
+	// -1012(1+u)/(240u) = 1012(u-1)/240
+	var fpParams emulated.BLS12381Fp
+	p := fpParams.Modulus()
+	c := new(big.Int).ModInverse(big.NewInt(240), p)
+	c.Mul(c, big.NewInt(1012)).Mod(c, p)
+	return g.ext2.FromConstant(new(big.Int).Sub(p, c), c)
+}
+
+// sgn0 returns the sign of x as defined in RFC 9380, section 4.1.
+func (g *G2) sgn0(x *fields_bls12381.E2) frontend.Variable {
+	fp := g.ext2.BaseField()
+	// sign_0 OR (zero_0 AND sign_1). If zero_0 is set, then sign_0 is not.
+	sign0 := fp.ToBits(&x.A0)[0]
+	zero0 := fp.IsZero(&x.A0)
+	sign1 := fp.ToBits(&x.A1)[0]
+	return g.api.Add(sign0, g.api.Mul(zero0, sign1))
+}
+
+// isogeny maps the point p of E2' to the twist using the 3-isogeny.
+func (g *G2) isogeny(p *G2Affine) *G2Affine {
+	e := g.ext2
+	// eval returns the polynomial with the given coefficients evaluated at
+	// x using Horner's method. If monic is set, then the leading
+	// coefficient is one and omitted in coeffs.
+	eval := func(coeffs [][2]string, monic bool) *fields_bls12381.E2 {
+		constant := func(c [2]string) *fields_bls12381.E2 {
+			c0, _ := new(big.Int).SetString(c[0], 0)
+			c1, _ := new(big.Int).SetString(c[1], 0)
+			return e.FromConstant(c0, c1)
+		}
+		var res *fields_bls12381.E2
+		if monic {
+			res = e.Add(&p.X, constant(coeffs[len(coeffs)-1]))
+		} else {
+			res = constant(coeffs[len(coeffs)-1])
+		}
+		for i := len(coeffs) - 2; i >= 0; i-- {
+			res = e.Add(e.Mul(res, &p.X), constant(coeffs[i]))
+		}
+		return res
+	}
+	x := e.Div(eval(isoXNum[:], false), eval(isoXDen[:], true))
+	y := e.Mul(&p.Y, e.Div(eval(isoYNum[:], false), eval(isoYDen[:], true)))
+	return &G2Affine{X: *x, Y: *y}
+}
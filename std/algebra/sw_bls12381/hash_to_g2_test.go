@@ -0,0 +1,71 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12381
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const hashToG2DST = "QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_"
+
+type hashToG2Circuit struct {
+	Msg      []frontend.Variable
+	Expected G2Affine
+}
+
+func (c *hashToG2Circuit) Define(api frontend.API) error {
+	g2, err := NewG2(api)
+	if err != nil {
+		return err
+	}
+	res, err := g2.HashToG2(c.Msg, []byte(hashToG2DST))
+	if err != nil {
+		return err
+	}
+	g2.AssertIsEqual(res, &c.Expected)
+	return nil
+}
+
+func TestHashToG2(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, msg := range []string{"", "abc", "abcdef0123456789"} {
+		expected, err := bls12381.HashToCurveG2SSWU([]byte(msg), []byte(hashToG2DST))
+		assert.NoError(err)
+		circuit := hashToG2Circuit{Msg: make([]frontend.Variable, len(msg)), Expected: NewG2Affine()}
+		witness := hashToG2Circuit{Msg: make([]frontend.Variable, len(msg))}
+		for i := range msg {
+			witness.Msg[i] = msg[i]
+		}
+		witness.Expected.Assign(&expected)
+		err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+		assert.NoError(err, msg)
+
+		// a different message hashes to a different point
+		witness.Msg = append(witness.Msg[:0:0], witness.Msg...)
+		if len(msg) > 0 {
+			witness.Msg[0] = msg[0] ^ 1
+			err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+			assert.Error(err, msg)
+		}
+	}
+}
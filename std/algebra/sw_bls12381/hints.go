@@ -0,0 +1,88 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12381
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	for _, h := range GetHints() {
+		hint.Register(h)
+	}
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []hint.Function {
+	return []hint.Function{
+		SSWUSqrtHint,
+	}
+}
+
+// SSWUSqrtHint computes the square root y of gx1 if it is a square in Fp2
+// and of gx2 otherwise, such that the sign of y equals the sign of u. The
+// inputs are the emulated elements gx1, gx2 and u, each given by its two
+// coordinates. It is called with [emulated.Field.NewHint].
+func SSWUSqrtHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(p *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 6 || len(outputs) != 2 {
+			return errors.New("expecting six inputs and two outputs")
+		}
+		// the coordinates of a point are used as native Fp2 elements
+		var gx1, gx2 bls12381.G2Affine
+		gx1.X.A0.SetBigInt(inputs[0])
+		gx1.X.A1.SetBigInt(inputs[1])
+		gx2.X.A0.SetBigInt(inputs[2])
+		gx2.X.A1.SetBigInt(inputs[3])
+		gx := &gx1.X
+		if gx.Legendre() == -1 {
+			gx = &gx2.X
+		}
+		if gx.Legendre() == -1 {
+			return errors.New("no square root")
+		}
+		y := &gx1.Y
+		y.Sqrt(gx)
+		y0, y1 := new(big.Int), new(big.Int)
+		y.A0.ToBigIntRegular(y0)
+		y.A1.ToBigIntRegular(y1)
+		u0 := new(big.Int).Mod(inputs[4], p)
+		u1 := new(big.Int).Mod(inputs[5], p)
+		if sgn0(u0, u1) != sgn0(y0, y1) {
+			y0.Sub(p, y0).Mod(y0, p)
+			y1.Sub(p, y1).Mod(y1, p)
+		}
+		outputs[0].Set(y0)
+		outputs[1].Set(y1)
+		return nil
+	})
+}
+
+// sgn0 returns the sign of the reduced Fp2 element a0 + a1*u as defined in
+// RFC 9380, section 4.1.
+func sgn0(a0, a1 *big.Int) uint {
+	if a0.Sign() == 0 {
+		return a1.Bit(0)
+	}
+	return a0.Bit(0)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12381
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// GTEl is an element of the target group GT, a subgroup of Fp12.
+type GTEl = fields_bls12381.E12
+
+// NewGTEl returns an element with allocated limbs. It is used to define the
+// element in a circuit structure.
+func NewGTEl() GTEl {
+	return fields_bls12381.NewE12()
+}
+
+// Pairing computes the optimal ate pairing of BLS12-381 over the emulated
+// fields.
+type Pairing struct {
+	api  frontend.API
+	ext2 *fields_bls12381.Ext2
+	*fields_bls12381.Ext12
+	g2 *G2
+}
+
+// NewPairing returns a new [Pairing] instance. It returns an error if
+// initialising the emulated field fails.
+func NewPairing(api frontend.API) (*Pairing, error) {
+	ext12, err := fields_bls12381.NewExt12(api)
+	if err != nil {
+		return nil, err
+	}
+	return &Pairing{
+		api:   api,
+		ext2:  ext12.Ext2,
+		Ext12: ext12,
+		g2:    newG2(api, ext12.Ext2),
+	}, nil
+}
+
+// G2 returns the operations in G2 sharing the emulated field with the
+// pairing.
+func (pr *Pairing) G2() *G2 {
+	return pr.g2
+}
+
+// MillerLoop computes the product of the Miller loops of the pairs (P[i],
+// Q[i]). The points must not be the point at infinity, the points P[i] must
+// be in G1 and the points Q[i] must be in G2 (see
+// [G2.AssertIsInSubgroup]). It returns an error if the inputs are empty or
+// their lengths differ.
+//
+// The lines are evaluated in affine coordinates and are equal to the lines of
+// gnark-crypto up to a factor which is cancelled by the final exponentiation.
+func (pr *Pairing) MillerLoop(P []*G1Affine, Q []*G2Affine) (*GTEl, error) {
+	n := len(P)
+	if n == 0 || n != len(Q) {
+		return nil, errors.New("invalid inputs sizes")
+	}
+	fp := pr.ext2.BaseField()
+
+	// the lines are given by c0 + c1*v + c4*v*w where
+	//	c0 = λ*x_T - y_T, c1 = -λ*x_P and c4 = y_P
+	negXP := make([]*emulated.Element[emulated.BLS12381Fp], n)
+	yP := make([]*fields_bls12381.E2, n)
+	for k := 0; k < n; k++ {
+		negXP[k] = fp.Neg(&P[k].X)
+		yP[k] = &fields_bls12381.E2{A0: P[k].Y, A1: *fp.Zero()}
+	}
+	mulLine := func(f *GTEl, k int, t *G2Affine, λ *fields_bls12381.E2) *GTEl {
+		c0 := pr.ext2.Sub(pr.ext2.Mul(λ, &t.X), &t.Y)
+		c1 := pr.ext2.MulByElement(λ, negXP[k])
+		return pr.MulBy014(f, c0, c1, yP[k])
+	}
+
+	T := make([]*G2Affine, n)
+	copy(T, Q)
+	f := pr.One()
+	for i := 62; i >= 0; i-- {
+		if i != 62 {
+			f = pr.Square(f)
+		}
+		for k := 0; k < n; k++ {
+			tt, λ := pr.g2.doubleStep(T[k])
+			f = mulLine(f, k, T[k], λ)
+			T[k] = tt
+		}
+		if (seedAbs>>i)&1 == 0 {
+			continue
+		}
+		for k := 0; k < n; k++ {
+			tt, λ := pr.g2.addStep(T[k], Q[k])
+			f = mulLine(f, k, T[k], λ)
+			T[k] = tt
+		}
+	}
+	// the seed is negative
+	return pr.Conjugate(f), nil
+}
+
+// FinalExponentiation computes the final exponentiation
+// f^((p¹²-1)/r).
+func (pr *Pairing) FinalExponentiation(f *GTEl) *GTEl {
+	// easy part: f^((p⁶-1)(p²+1))
+	t0 := pr.Conjugate(f)
+	res := pr.Mul(t0, pr.Inverse(f))
+	res = pr.Mul(pr.FrobeniusSquare(res), res)
+
+	// hard part (up to permutation) as in gnark-crypto
+	// Daiki Hayashida and Kenichiro Hayasaka and Tadanori Teruya
+	// https://eprint.iacr.org/2020/875.pdf
+	t0 = pr.CyclotomicSquare(res)
+	t1 := pr.ExptHalf(t0)
+	t2 := pr.Conjugate(res)
+	t1 = pr.Mul(t1, t2)
+	t2 = pr.Expt(t1)
+	t1 = pr.Conjugate(t1)
+	t1 = pr.Mul(t1, t2)
+	t2 = pr.Expt(t1)
+	t1 = pr.Frobenius(t1)
+	t1 = pr.Mul(t1, t2)
+	res = pr.Mul(res, t0)
+	t0 = pr.Expt(t1)
+	t2 = pr.Expt(t0)
+	t0 = pr.FrobeniusSquare(t1)
+	t1 = pr.Conjugate(t1)
+	t1 = pr.Mul(t1, t2)
+	t1 = pr.Mul(t1, t0)
+	return pr.Mul(res, t1)
+}
+
+// Pair computes the product of the reduced pairings e(P[i], Q[i]). See
+// [Pairing.MillerLoop] for the requirements on the inputs.
+func (pr *Pairing) Pair(P []*G1Affine, Q []*G2Affine) (*GTEl, error) {
+	f, err := pr.MillerLoop(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	return pr.FinalExponentiation(f), nil
+}
+
+// PairingCheck asserts that the product of the reduced pairings e(P[i],
+// Q[i]) is one. See [Pairing.MillerLoop] for the requirements on the inputs.
+func (pr *Pairing) PairingCheck(P []*G1Affine, Q []*G2Affine) error {
+	f, err := pr.Pair(P, Q)
+	if err != nil {
+		return err
+	}
+	pr.AssertIsEqual(f, pr.One())
+	return nil
+}
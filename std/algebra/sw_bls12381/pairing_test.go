@@ -0,0 +1,110 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bls12381
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type pairCircuit struct {
+	P   G1Affine
+	Q   G2Affine
+	Res GTEl
+}
+
+func (c *pairCircuit) Define(api frontend.API) error {
+	pr, err := NewPairing(api)
+	if err != nil {
+		return err
+	}
+	res, err := pr.Pair([]*G1Affine{&c.P}, []*G2Affine{&c.Q})
+	if err != nil {
+		return err
+	}
+	pr.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func randomG1G2(assert *test.Assert) (bls12381.G1Affine, bls12381.G2Affine) {
+	_, _, g1, g2 := bls12381.Generators()
+	var s1, s2 fr.Element
+	_, err := s1.SetRandom()
+	assert.NoError(err)
+	_, err = s2.SetRandom()
+	assert.NoError(err)
+	var p bls12381.G1Affine
+	var q bls12381.G2Affine
+	p.ScalarMultiplication(&g1, s1.ToBigIntRegular(new(big.Int)))
+	q.ScalarMultiplication(&g2, s2.ToBigIntRegular(new(big.Int)))
+	return p, q
+}
+
+func TestPair(t *testing.T) {
+	assert := test.NewAssert(t)
+	p, q := randomG1G2(assert)
+	res, err := bls12381.Pair([]bls12381.G1Affine{p}, []bls12381.G2Affine{q})
+	assert.NoError(err)
+
+	circuit := pairCircuit{P: NewG1Affine(), Q: NewG2Affine(), Res: NewGTEl()}
+	var witness pairCircuit
+	witness.P.Assign(&p)
+	witness.Q.Assign(&q)
+	witness.Res.Assign(&res)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+}
+
+type pairingCheckCircuit struct {
+	P1, P2 G1Affine
+	Q1, Q2 G2Affine
+}
+
+func (c *pairingCheckCircuit) Define(api frontend.API) error {
+	pr, err := NewPairing(api)
+	if err != nil {
+		return err
+	}
+	return pr.PairingCheck([]*G1Affine{&c.P1, &c.P2}, []*G2Affine{&c.Q1, &c.Q2})
+}
+
+func TestPairingCheck(t *testing.T) {
+	assert := test.NewAssert(t)
+	p, q := randomG1G2(assert)
+	var negP bls12381.G1Affine
+	negP.Neg(&p)
+
+	circuit := pairingCheckCircuit{P1: NewG1Affine(), P2: NewG1Affine(), Q1: NewG2Affine(), Q2: NewG2Affine()}
+	var witness pairingCheckCircuit
+	witness.P1.Assign(&p)
+	witness.P2.Assign(&negP)
+	witness.Q1.Assign(&q)
+	witness.Q2.Assign(&q)
+	err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+
+	witness.P2.Assign(&p)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bn254
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bn254"
+)
+
+var (
+	// bTwist is the coefficient 3/(9+u) of the twist.
+	bTwist = [2]string{
+		"19485874751759354771024239261021720505790618469301721065564631296452457478373",
+		"266929791119991161246907387137283842545076965332900288569378510910307636690",
+	}
+	// psiX and psiY are the coefficients of the endomorphism ψ, that is
+	// (9+u)^((p-1)/3) and (9+u)^((p-1)/2).
+	psiX = [2]string{
+		"21575463638280843010398324269430826099269044274347216827212613867836435027261",
+		"10307601595873709700152284273816112264069230130616436755625194854815875713954",
+	}
+	psiY = [2]string{
+		"2821565182194536844548159561693502659359617185244120367078079554186484126554",
+		"3505843767911556378687030309984248845540243509899259641013678093033130930403",
+	}
+	// psi2X is the coefficient (9+u)^((p²-1)/3) of the endomorphism ψ², which
+	// is in the base field. The coefficient of the y-coordinate is -1.
+	psi2X = bigFromString("21888242871839275220042445260109153167277707414472061641714758635765020556616")
+	// fixedCoeff is 6x², where x = 4965661367192848881 is the seed of BN254.
+	fixedCoeff = bigFromString("147946756881789318990833708069417712966")
+)
+
+// G2Affine is a point of G2 in affine coordinates. The coordinates are
+// elements of Fp2.
+type G2Affine struct {
+	X, Y fields_bn254.E2
+}
+
+// NewG2Affine returns a point with allocated limbs. It is used to define the
+// point in a circuit structure.
+func NewG2Affine() G2Affine {
+	return G2Affine{X: fields_bn254.NewE2(), Y: fields_bn254.NewE2()}
+}
+
+// Assign is a helper to assign the native point v into the coordinates.
+func (p *G2Affine) Assign(v *bn254.G2Affine) {
+	p.X.Assign(&v.X.A0, &v.X.A1)
+	p.Y.Assign(&v.Y.A0, &v.Y.A1)
+}
+
+// G2 allows to perform operations on the points of the twist E'(Fp2): Y² =
+// X³ + 3/(9+u) containing G2.
+type G2 struct {
+	api  frontend.API
+	ext2 *fields_bn254.Ext2
+	b    *fields_bn254.E2
+	psiX *fields_bn254.E2
+	psiY *fields_bn254.E2
+}
+
+// NewG2 returns a new [G2] instance for the operations in G2. It returns an
+// error if initialising the emulated field fails.
+func NewG2(api frontend.API) (*G2, error) {
+	ext2, err := fields_bn254.NewExt2(api)
+	if err != nil {
+		return nil, err
+	}
+	return newG2(api, ext2), nil
+}
+
+func newG2(api frontend.API, ext2 *fields_bn254.Ext2) *G2 {
+	return &G2{
+		api:  api,
+		ext2: ext2,
+		b:    ext2.FromConstant(bigFromString(bTwist[0]), bigFromString(bTwist[1])),
+		psiX: ext2.FromConstant(bigFromString(psiX[0]), bigFromString(psiX[1])),
+		psiY: ext2.FromConstant(bigFromString(psiY[0]), bigFromString(psiY[1])),
+	}
+}
+
+func bigFromString(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid constant " + s)
+	}
+	return v
+}
+
+// Ext2 returns the arithmetic of the coordinates.
+func (g *G2) Ext2() *fields_bn254.Ext2 {
+	return g.ext2
+}
+
+// Neg returns -p. It doesn't modify p.
+func (g *G2) Neg(p *G2Affine) *G2Affine {
+	return &G2Affine{X: p.X, Y: *g.ext2.Neg(&p.Y)}
+}
+
+// AssertIsEqual asserts that p and q are the same point.
+func (g *G2) AssertIsEqual(p, q *G2Affine) {
+	g.ext2.AssertIsEqual(&p.X, &q.X)
+	g.ext2.AssertIsEqual(&p.Y, &q.Y)
+}
+
+// AssertIsOnCurve asserts that p satisfies the equation of the twist.
+func (g *G2) AssertIsOnCurve(p *G2Affine) {
+	// y² = x³ + 3/(9+u)
+	left := g.ext2.Square(&p.Y)
+	right := g.ext2.Add(g.ext2.Mul(g.ext2.Square(&p.X), &p.X), g.b)
+	g.ext2.AssertIsEqual(left, right)
+}
+
+// Select returns p if b is 1 and q otherwise. The selector is assumed to be
+// boolean.
+func (g *G2) Select(b frontend.Variable, p, q *G2Affine) *G2Affine {
+	return &G2Affine{
+		X: *g.ext2.Select(b, &p.X, &q.X),
+		Y: *g.ext2.Select(b, &p.Y, &q.Y),
+	}
+}
+
+// Add returns p+q. It doesn't modify p nor q. It uses incomplete formulas in
+// affine coordinates: if p and q have the same x-coordinate (p = ±q), then
+// the constraints are not satisfiable.
+func (g *G2) Add(p, q *G2Affine) *G2Affine {
+	res, _ := g.addStep(p, q)
+	return res
+}
+
+// addStep returns p+q and the slope of the line through p and q.
+func (g *G2) addStep(p, q *G2Affine) (*G2Affine, *fields_bn254.E2) {
+	// λ = (q.y-p.y)/(q.x-p.x). We compute the inverse of the denominator
+	// instead of the quotient so that the constraints are not satisfiable if
+	// p equals q.
+	λ := g.ext2.Mul(g.ext2.Sub(&q.Y, &p.Y), g.ext2.Inverse(g.ext2.Sub(&q.X, &p.X)))
+	return g.line(p, q, λ), λ
+}
+
+// Double returns 2p. It doesn't modify p.
+func (g *G2) Double(p *G2Affine) *G2Affine {
+	res, _ := g.doubleStep(p)
+	return res
+}
+
+// doubleStep returns 2p and the slope of the tangent at p.
+func (g *G2) doubleStep(p *G2Affine) (*G2Affine, *fields_bn254.E2) {
+	// λ = 3x²/2y
+	xx3 := g.ext2.MulByConstElement(g.ext2.Square(&p.X), big.NewInt(3))
+	λ := g.ext2.Div(xx3, g.ext2.Double(&p.Y))
+	return g.line(p, p, λ), λ
+}
+
+// line returns the third intersection of the line with slope λ through p
+// and q with the curve, negated.
+func (g *G2) line(p, q *G2Affine, λ *fields_bn254.E2) *G2Affine {
+	// xr = λ²-p.x-q.x
+	xr := g.ext2.Sub(g.ext2.Sub(g.ext2.Square(λ), &p.X), &q.X)
+	// yr = λ(p.x-xr) - p.y
+	yr := g.ext2.Sub(g.ext2.Mul(λ, g.ext2.Sub(&p.X, xr)), &p.Y)
+	return &G2Affine{X: *xr, Y: *yr}
+}
+
+// psi returns ψ(p) where ψ is the untwist-Frobenius-twist endomorphism.
+func (g *G2) psi(p *G2Affine) *G2Affine {
+	return &G2Affine{
+		X: *g.ext2.Mul(g.ext2.Conjugate(&p.X), g.psiX),
+		Y: *g.ext2.Mul(g.ext2.Conjugate(&p.Y), g.psiY),
+	}
+}
+
+// negPsi2 returns -ψ²(p).
+func (g *G2) negPsi2(p *G2Affine) *G2Affine {
+	return &G2Affine{
+		X: *g.ext2.MulByConstElement(&p.X, psi2X),
+		Y: p.Y,
+	}
+}
+
+// scalarMulConst returns [s]p for the constant s > 1 using the
+// double-and-add algorithm. The point p must not be of small order,
+// otherwise the constraints are not satisfiable.
+func (g *G2) scalarMulConst(p *G2Affine, s *big.Int) *G2Affine {
+	res := p
+	for i := s.BitLen() - 2; i >= 0; i-- {
+		res = g.Double(res)
+		if s.Bit(i) == 1 {
+			res = g.Add(res, p)
+		}
+	}
+	return res
+}
+
+// AssertIsInSubgroup asserts that p is in G2 by checking that ψ(p) = [6x²]p,
+// where x is the seed of the curve (see https://eprint.iacr.org/2022/352).
+// The point p must be on the twist.
+func (g *G2) AssertIsInSubgroup(p *G2Affine) {
+	g.AssertIsEqual(g.psi(p), g.scalarMulConst(p, fixedCoeff))
+}
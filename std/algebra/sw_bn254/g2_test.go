@@ -0,0 +1,108 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bn254
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type g2AddCircuit struct {
+	P, Q, Sum, Double G2Affine
+}
+
+func (c *g2AddCircuit) Define(api frontend.API) error {
+	g2, err := NewG2(api)
+	if err != nil {
+		return err
+	}
+	g2.AssertIsOnCurve(&c.P)
+	g2.AssertIsEqual(g2.Add(&c.P, &c.Q), &c.Sum)
+	g2.AssertIsEqual(g2.Double(&c.P), &c.Double)
+	return nil
+}
+
+func TestG2Add(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, p := randomG1G2(assert)
+	_, q := randomG1G2(assert)
+	var sum, double bn254.G2Affine
+	sum.Add(&p, &q)
+	double.Add(&p, &p)
+
+	circuit := g2AddCircuit{P: NewG2Affine(), Q: NewG2Affine(), Sum: NewG2Affine(), Double: NewG2Affine()}
+	var witness g2AddCircuit
+	witness.P.Assign(&p)
+	witness.Q.Assign(&q)
+	witness.Sum.Assign(&sum)
+	witness.Double.Assign(&double)
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+
+	witness.Sum.Assign(&double)
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
+
+type g2SubgroupCircuit struct {
+	P G2Affine
+}
+
+func (c *g2SubgroupCircuit) Define(api frontend.API) error {
+	g2, err := NewG2(api)
+	if err != nil {
+		return err
+	}
+	g2.AssertIsOnCurve(&c.P)
+	g2.AssertIsInSubgroup(&c.P)
+	return nil
+}
+
+// randomTwistPoint returns a random point on the twist which is not in G2
+// with overwhelming probability.
+func randomTwistPoint(assert *test.Assert) bn254.G2Affine {
+	var p, b bn254.G2Affine
+	b.X.SetString(bTwist[0], bTwist[1])
+	for {
+		_, err := p.X.SetRandom()
+		assert.NoError(err)
+		// y² = x³ + 3/(9+u)
+		p.Y.Square(&p.X).Mul(&p.Y, &p.X).Add(&p.Y, &b.X)
+		if p.Y.Legendre() == 1 {
+			p.Y.Sqrt(&p.Y)
+			return p
+		}
+	}
+}
+
+func TestG2Subgroup(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, q := randomG1G2(assert)
+	circuit := g2SubgroupCircuit{P: NewG2Affine()}
+	var witness g2SubgroupCircuit
+	witness.P.Assign(&q)
+	err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+
+	p := randomTwistPoint(assert)
+	witness.P.Assign(&p)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
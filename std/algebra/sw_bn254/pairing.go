@@ -0,0 +1,209 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw_bn254
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// loopCounter is the NAF decomposition of 6x+2, where x = 4965661367192848881
+// is the seed of BN254.
+var loopCounter [66]int8
+
+func init() {
+	ecc.NafDecomposition(bigFromString("29793968203157093288"), loopCounter[:])
+}
+
+// GTEl is an element of the target group GT, a subgroup of Fp12.
+type GTEl = fields_bn254.E12
+
+// NewGTEl returns an element with allocated limbs. It is used to define the
+// element in a circuit structure.
+func NewGTEl() GTEl {
+	return fields_bn254.NewE12()
+}
+
+// Pairing computes the optimal ate pairing of BN254 over the emulated fields.
+type Pairing struct {
+	api  frontend.API
+	ext2 *fields_bn254.Ext2
+	*fields_bn254.Ext12
+	g2 *G2
+}
+
+// NewPairing returns a new [Pairing] instance. It returns an error if
+// initialising the emulated field fails.
+func NewPairing(api frontend.API) (*Pairing, error) {
+	ext12, err := fields_bn254.NewExt12(api)
+	if err != nil {
+		return nil, err
+	}
+	return &Pairing{
+		api:   api,
+		ext2:  ext12.Ext2,
+		Ext12: ext12,
+		g2:    newG2(api, ext12.Ext2),
+	}, nil
+}
+
+// G2 returns the operations in G2 sharing the emulated field with the
+// pairing.
+func (pr *Pairing) G2() *G2 {
+	return pr.g2
+}
+
+// MillerLoop computes the product of the Miller loops of the pairs (P[i],
+// Q[i]). The points must not be the point at infinity, the points P[i] must
+// be in G1 and the points Q[i] must be in G2 (see
+// [G2.AssertIsInSubgroup]). It returns an error if the inputs are empty or
+// their lengths differ.
+//
+// The lines are evaluated in affine coordinates and are equal to the lines of
+// gnark-crypto up to a factor which is cancelled by the final exponentiation.
+func (pr *Pairing) MillerLoop(P []*G1Affine, Q []*G2Affine) (*GTEl, error) {
+	n := len(P)
+	if n == 0 || n != len(Q) {
+		return nil, errors.New("invalid inputs sizes")
+	}
+	fp := pr.ext2.BaseField()
+
+	// the lines are given by c0 + c3*w + c4*v*w where
+	//	c0 = y_P, c3 = -λ*x_P and c4 = λ*x_T - y_T
+	negXP := make([]*emulated.Element[emulated.BN254Fp], n)
+	yP := make([]*fields_bn254.E2, n)
+	negQ := make([]*G2Affine, n)
+	for k := 0; k < n; k++ {
+		negXP[k] = fp.Neg(&P[k].X)
+		yP[k] = &fields_bn254.E2{A0: P[k].Y, A1: *fp.Zero()}
+		negQ[k] = pr.g2.Neg(Q[k])
+	}
+	mulLine := func(f *GTEl, k int, t *G2Affine, λ *fields_bn254.E2) *GTEl {
+		c3 := pr.ext2.MulByElement(λ, negXP[k])
+		c4 := pr.ext2.Sub(pr.ext2.Mul(λ, &t.X), &t.Y)
+		return pr.MulBy034(f, yP[k], c3, c4)
+	}
+
+	T := make([]*G2Affine, n)
+	copy(T, Q)
+	f := pr.One()
+	for i := len(loopCounter) - 2; i >= 0; i-- {
+		if i != len(loopCounter)-2 {
+			f = pr.Square(f)
+		}
+		for k := 0; k < n; k++ {
+			tt, λ := pr.g2.doubleStep(T[k])
+			f = mulLine(f, k, T[k], λ)
+			T[k] = tt
+			if loopCounter[i] == 0 {
+				continue
+			}
+			q := Q[k]
+			if loopCounter[i] == -1 {
+				q = negQ[k]
+			}
+			tt, λ = pr.g2.addStep(T[k], q)
+			f = mulLine(f, k, T[k], λ)
+			T[k] = tt
+		}
+	}
+
+	// lines through [6x+2]Q and ψ(Q) and through [6x+2]Q + ψ(Q) and -ψ²(Q)
+	for k := 0; k < n; k++ {
+		tt, λ := pr.g2.addStep(T[k], pr.g2.psi(Q[k]))
+		f = mulLine(f, k, T[k], λ)
+		_, λ = pr.g2.addStep(tt, pr.g2.negPsi2(Q[k]))
+		f = mulLine(f, k, tt, λ)
+	}
+	return f, nil
+}
+
+// FinalExponentiation computes the final exponentiation
+// f^((p¹²-1)/r).
+func (pr *Pairing) FinalExponentiation(f *GTEl) *GTEl {
+	// easy part: f^((p⁶-1)(p²+1))
+	t := pr.Mul(pr.Conjugate(f), pr.Inverse(f))
+	var mt [4]*GTEl // mt[i] is m^(x^i)
+	mt[0] = pr.Mul(pr.FrobeniusSquare(t), t)
+
+	// hard part as in gnark-crypto
+	// https://eprint.iacr.org/2008/490.pdf
+	mt[1] = pr.Expt(mt[0])
+	mt[2] = pr.Expt(mt[1])
+	mt[3] = pr.Expt(mt[2])
+
+	var y [7]*GTEl
+	y[1] = pr.Conjugate(mt[0])
+	y[4] = mt[1]
+	y[5] = pr.Conjugate(mt[2])
+	y[6] = mt[3]
+
+	for i := range mt {
+		mt[i] = pr.Frobenius(mt[i])
+	}
+	y[0] = mt[0]
+	y[3] = pr.Conjugate(mt[1])
+	y[4] = pr.Conjugate(pr.Mul(y[4], mt[2]))
+	y[6] = pr.Conjugate(pr.Mul(y[6], mt[3]))
+
+	mt[0] = pr.Frobenius(mt[0])
+	mt[2] = pr.Frobenius(mt[2])
+	y[0] = pr.Mul(y[0], mt[0])
+	y[2] = mt[2]
+	mt[0] = pr.Frobenius(mt[0])
+	y[0] = pr.Mul(y[0], mt[0])
+
+	// addition chain
+	t0 := pr.CyclotomicSquare(y[6])
+	t0 = pr.Mul(t0, y[4])
+	t0 = pr.Mul(t0, y[5])
+	t1 := pr.Mul(y[3], y[5])
+	t1 = pr.Mul(t1, t0)
+	t0 = pr.Mul(t0, y[2])
+	t1 = pr.CyclotomicSquare(t1)
+	t1 = pr.Mul(t1, t0)
+	t1 = pr.CyclotomicSquare(t1)
+	t0 = pr.Mul(t1, y[1])
+	t1 = pr.Mul(t1, y[0])
+	t0 = pr.CyclotomicSquare(t0)
+	return pr.Mul(t0, t1)
+}
+
+// Pair computes the product of the reduced pairings e(P[i], Q[i]). See
+// [Pairing.MillerLoop] for the requirements on the inputs.
+func (pr *Pairing) Pair(P []*G1Affine, Q []*G2Affine) (*GTEl, error) {
+	f, err := pr.MillerLoop(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	return pr.FinalExponentiation(f), nil
+}
+
+// PairingCheck asserts that the product of the reduced pairings e(P[i],
+// Q[i]) is one. See [Pairing.MillerLoop] for the requirements on the inputs.
+func (pr *Pairing) PairingCheck(P []*G1Affine, Q []*G2Affine) error {
+	f, err := pr.Pair(P, Q)
+	if err != nil {
+		return err
+	}
+	pr.AssertIsEqual(f, pr.One())
+	return nil
+}
@@ -56,8 +56,19 @@ func (c *curve) ScalarMul(p1 Point, scalar frontend.Variable) Point {
 	}
 	return p
 }
+func (c *curve) FixedBaseScalarMul(p1 Point, scalar frontend.Variable) Point {
+	_, xConstant := c.api.Compiler().ConstantValue(p1.X)
+	_, yConstant := c.api.Compiler().ConstantValue(p1.Y)
+	if !xConstant || !yConstant {
+		return c.ScalarMul(p1, scalar)
+	}
+	var p Point
+	p.fixedBaseScalarMul(c.api, &p1, scalar, c.params)
+	return p
+}
 func (c *curve) DoubleBaseScalarMul(p1, p2 Point, s1, s2 frontend.Variable) Point {
 	var p Point
+	// the GLV variant is disabled for the same reason as in ScalarMul
 	p.doubleBaseScalarMul(c.api, &p1, &p2, s1, s2, c.params)
 	return p
 }
@@ -21,6 +21,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	tbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
 	tbls12381_bandersnatch "github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
 	tbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
@@ -31,6 +32,8 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/twistededwards"
 
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/test"
 )
 
@@ -145,6 +148,20 @@ func (circuit *addCircuit) Define(api frontend.API) error {
 		api.AssertIsEqual(res.Y, circuit.ScalarMulResult.Y)
 	}
 
+	{
+		// fixed base scalar mul
+		res := curve.FixedBaseScalarMul(circuit.fixedPoint, circuit.S2)
+		api.AssertIsEqual(res.X, circuit.ScalarMulResult.X)
+		api.AssertIsEqual(res.Y, circuit.ScalarMulResult.Y)
+	}
+
+	{
+		// fixed base scalar mul falling back to variable base
+		res := curve.FixedBaseScalarMul(circuit.P2, circuit.S2)
+		api.AssertIsEqual(res.X, circuit.ScalarMulResult.X)
+		api.AssertIsEqual(res.Y, circuit.ScalarMulResult.Y)
+	}
+
 	{
 		// double scalar mul
 		res := curve.DoubleBaseScalarMul(circuit.P1, circuit.P2, circuit.S1, circuit.S2)
@@ -382,3 +399,153 @@ func (p *CurveParams) randomScalar() *big.Int {
 	r, _ := rand.Int(rand.Reader, p.Order)
 	return r
 }
+
+type glvCircuit struct {
+	P1, P2 Point
+	S1, S2 frontend.Variable
+}
+
+func (circuit *glvCircuit) Define(api frontend.API) error {
+	c, err := NewEdCurve(api, twistededwards.BLS12_381_BANDERSNATCH)
+	if err != nil {
+		return err
+	}
+	params := c.Params()
+
+	endo := c.(*curve).endo
+
+	// compare with the scalar multiplications without endomorphism
+	var expected, res Point
+	expected.scalarMul(api, &circuit.P1, circuit.S1, params)
+	res.scalarMulGLV(api, &circuit.P1, circuit.S1, params, endo)
+	api.AssertIsEqual(res.X, expected.X)
+	api.AssertIsEqual(res.Y, expected.Y)
+
+	expected.doubleBaseScalarMul(api, &circuit.P1, &circuit.P2, circuit.S1, circuit.S2, params)
+	res.doubleBaseScalarMulGLV(api, &circuit.P1, &circuit.P2, circuit.S1, circuit.S2, params, endo)
+	api.AssertIsEqual(res.X, expected.X)
+	api.AssertIsEqual(res.Y, expected.Y)
+
+	res = c.FixedBaseScalarMul(Point{X: params.Base[0], Y: params.Base[1]}, circuit.S2)
+	expected.scalarMul(api, &Point{X: params.Base[0], Y: params.Base[1]}, circuit.S2, params)
+	api.AssertIsEqual(res.X, expected.X)
+	api.AssertIsEqual(res.Y, expected.Y)
+	return nil
+}
+
+func TestScalarMulGLV(t *testing.T) {
+	assert := test.NewAssert(t)
+	params, err := GetCurveParams(twistededwards.BLS12_381_BANDERSNATCH)
+	assert.NoError(err)
+	modulus := ecc.BLS12_381.Info().Fr.Modulus()
+	p1, p2, _, _, _, _, _, _, _ := testData(params, twistededwards.BLS12_381_BANDERSNATCH)
+
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(params.Order, big.NewInt(1)),
+		params.Order,
+		new(big.Int).Add(params.Order, big.NewInt(7)),
+		new(big.Int).Sub(modulus, big.NewInt(1)),
+		params.randomScalar(),
+	}
+	for _, s := range scalars {
+		witness := glvCircuit{P1: p1, P2: p2, S1: s, S2: params.randomScalar()}
+		assert.SolvingSucceeded(&glvCircuit{}, &witness, test.WithCurves(ecc.BLS12_381))
+
+		// the identity point
+		witness.P1 = Point{X: 0, Y: 1}
+		assert.SolvingSucceeded(&glvCircuit{}, &witness, test.WithCurves(ecc.BLS12_381))
+	}
+}
+
+type identityCircuit struct {
+	P Point
+	S frontend.Variable
+}
+
+func (circuit *identityCircuit) Define(api frontend.API) error {
+	c, err := NewEdCurve(api, twistededwards.BLS12_381_BANDERSNATCH)
+	if err != nil {
+		return err
+	}
+	res := c.ScalarMul(circuit.P, circuit.S)
+	api.AssertIsEqual(res.X, 0)
+	api.AssertIsEqual(res.Y, 1)
+	return nil
+}
+
+func TestScalarMulIdentity(t *testing.T) {
+	assert := test.NewAssert(t)
+	params, err := GetCurveParams(twistededwards.BLS12_381_BANDERSNATCH)
+	assert.NoError(err)
+	base := Point{X: params.Base[0], Y: params.Base[1]}
+
+	// [Order]P is the identity but [5]P is not, which a decomposition of the
+	// scalar unchecked over the integers would accept
+	assert.SolvingSucceeded(&identityCircuit{}, &identityCircuit{P: base, S: params.Order}, test.WithCurves(ecc.BLS12_381))
+	assert.SolvingFailed(&identityCircuit{}, &identityCircuit{P: base, S: 5}, test.WithCurves(ecc.BLS12_381))
+}
+
+type scalarMulCircuit struct {
+	curveID twistededwards.ID
+	fixed   bool
+	P       Point
+	S       frontend.Variable
+}
+
+func (circuit *scalarMulCircuit) Define(api frontend.API) error {
+	curve, err := NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	var res Point
+	if circuit.fixed {
+		res = curve.FixedBaseScalarMul(Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}, circuit.S)
+	} else {
+		res = curve.ScalarMul(circuit.P, circuit.S)
+	}
+	api.AssertIsEqual(res.X, res.Y)
+	return nil
+}
+
+func benchScalarMul(b *testing.B, circuit *scalarMulCircuit) {
+	snarkCurve, err := GetSnarkCurve(circuit.curveID)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var ccs frontend.CompiledConstraintSystem
+	b.Run("groth16", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ccs, err = frontend.Compile(snarkCurve, r1cs.NewBuilder, circuit, frontend.IgnoreUnconstrainedInputs())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Log("groth16", ccs.GetNbConstraints())
+	b.Run("plonk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ccs, err = frontend.Compile(snarkCurve, scs.NewBuilder, circuit, frontend.IgnoreUnconstrainedInputs())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Log("plonk", ccs.GetNbConstraints())
+}
+
+func BenchmarkScalarMul(b *testing.B) {
+	b.Run("variable base", func(b *testing.B) {
+		benchScalarMul(b, &scalarMulCircuit{curveID: twistededwards.BN254})
+	})
+	b.Run("fixed base", func(b *testing.B) {
+		benchScalarMul(b, &scalarMulCircuit{curveID: twistededwards.BN254, fixed: true})
+	})
+	b.Run("jubjub", func(b *testing.B) {
+		benchScalarMul(b, &scalarMulCircuit{curveID: twistededwards.BLS12_381})
+	})
+	b.Run("bandersnatch", func(b *testing.B) {
+		benchScalarMul(b, &scalarMulCircuit{curveID: twistededwards.BLS12_381_BANDERSNATCH})
+	})
+}
@@ -172,3 +172,62 @@ func (p *Point) doubleBaseScalarMul(api frontend.API, p1, p2 *Point, s1, s2 fron
 
 	return p
 }
+
+// fixedBaseScalarMul computes the scalar multiplication of a point with
+// constant coordinates on a twisted Edwards curve
+// p1: base point (with constant coordinates)
+// curve: parameters of the Edwards curve
+// scal: scalar as a SNARK constraint
+// The multiples [k*4^i]p1 for k in {0,1,2,3} are constants, so that each window
+// of two bits of the scalar selects a multiple and the selected multiples are
+// added without doubling. As the table is constant, the selection is a linear
+// combination of 1, b0, b1 and b0*b1, computed once for both coordinates.
+func (p *Point) fixedBaseScalarMul(api frontend.API, p1 *Point, scalar frontend.Variable, curve *CurveParams) *Point {
+
+	// first unpack the scalar
+	b := api.ToBinary(scalar)
+
+	res := Point{}
+	tmp := Point{}
+	base := *p1
+	double := Point{}
+	triple := Point{}
+
+	for i := 0; i < len(b); i += 2 {
+		// the table is computed on constants and adds no constraint
+		double.double(api, &base, curve)
+		triple.add(api, &double, &base, curve)
+
+		if i+1 < len(b) {
+			b0b1 := api.Mul(b[i], b[i+1])
+			tmp.X = lookup2Constant(api, b[i], b[i+1], b0b1, 0, base.X, double.X, triple.X)
+			tmp.Y = lookup2Constant(api, b[i], b[i+1], b0b1, 1, base.Y, double.Y, triple.Y)
+		} else {
+			tmp.X = api.Select(b[i], base.X, 0)
+			tmp.Y = api.Select(b[i], base.Y, 1)
+		}
+
+		if i == 0 {
+			res = tmp
+		} else {
+			res.add(api, &res, &tmp, curve)
+		}
+
+		base.double(api, &double, curve)
+	}
+
+	p.X = res.X
+	p.Y = res.Y
+
+	return p
+}
+
+// lookup2Constant returns i0, i1, i2 or i3 as Lookup2 for the constants i0,
+// i1, i2 and i3, given the product b0b1 of the bits.
+func lookup2Constant(api frontend.API, b0, b1, b0b1 frontend.Variable, i0, i1, i2, i3 frontend.Variable) frontend.Variable {
+	return api.Add(i0,
+		api.Mul(b0, api.Sub(i1, i0)),
+		api.Mul(b1, api.Sub(i2, i0)),
+		api.Mul(b0b1, api.Sub(api.Add(i3, i0), i1, i2)),
+	)
+}
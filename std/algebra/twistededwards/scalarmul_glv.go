@@ -85,15 +85,17 @@ func init() {
 	hint.Register(DecomposeScalar)
 }
 
-// ScalarMul computes the scalar multiplication of a point on a twisted Edwards curve
-// p1: base point (as snark point)
-// curve: parameters of the Edwards curve
-// scal: scalar as a SNARK constraint
-// Standard left to right double and add
-func (p *Point) scalarMulGLV(api frontend.API, p1 *Point, scalar frontend.Variable, curve *CurveParams, endo *EndoParams) *Point {
-	// the hints allow to decompose the scalar s into s1 and s2 such that
-	// s1 + λ * s2 == s mod Order,
-	// with λ s.t. λ² = -2 mod Order.
+// decomposeScalarGLV returns the bits of s1 and s2 such that
+// -s1 + λ * s2 == scalar mod Order,
+// with λ s.t. λ² = -2 mod Order.
+//
+// This is not sound yet (https://github.com/ConsenSys/gnark/issues/268): the
+// equality with the quotient k given by the hint only holds modulo the
+// native modulus r, and λ * s2 is much larger than r, so that a prover can
+// pick s1 and s2 for any scalar. Range checking s1, s2 and k is not enough,
+// the equality must also be checked over the integers. The GLV variants are
+// therefore not used by Curve.
+func decomposeScalarGLV(api frontend.API, scalar frontend.Variable, curve *CurveParams, endo *EndoParams) ([]frontend.Variable, []frontend.Variable) {
 	sd, err := api.NewHint(DecomposeScalar, 3, scalar)
 	if err != nil {
 		// err is non-nil only for invalid number of inputs
@@ -110,13 +112,32 @@ func (p *Point) scalarMulGLV(api frontend.API, p1 *Point, scalar frontend.Variab
 	// This changes the size bounds to 2*sqrt(Order) = 129.
 	n := 129
 
-	b1 := api.ToBinary(s1, n)
-	b2 := api.ToBinary(s2, n)
+	return api.ToBinary(s1, n), api.ToBinary(s2, n)
+}
 
-	var res, _p1, p2, p3, tmp Point
+// glvTable returns -p1, phi(p1) and -p1 + phi(p1), selected by the bits of
+// s1 and s2 of decomposeScalarGLV.
+func glvTable(api frontend.API, p1 *Point, curve *CurveParams, endo *EndoParams) (_p1, p2, p3 Point) {
 	_p1.neg(api, p1)
 	p2.phi(api, p1, curve, endo)
 	p3.add(api, &_p1, &p2, curve)
+	return
+}
+
+// ScalarMul computes the scalar multiplication of a point on a twisted Edwards curve
+// p1: base point (as snark point)
+// curve: parameters of the Edwards curve
+// scal: scalar as a SNARK constraint
+// Standard left to right double and add
+func (p *Point) scalarMulGLV(api frontend.API, p1 *Point, scalar frontend.Variable, curve *CurveParams, endo *EndoParams) *Point {
+	// the hints allow to decompose the scalar s into s1 and s2 such that
+	// s1 + λ * s2 == s mod Order,
+	// with λ s.t. λ² = -2 mod Order.
+	b1, b2 := decomposeScalarGLV(api, scalar, curve, endo)
+	n := len(b1)
+
+	var res, tmp Point
+	_p1, p2, p3 := glvTable(api, p1, curve, endo)
 
 	res.X = api.Lookup2(b1[n-1], b2[n-1], 0, _p1.X, p2.X, p3.X)
 	res.Y = api.Lookup2(b1[n-1], b2[n-1], 1, _p1.Y, p2.Y, p3.Y)
@@ -133,3 +154,39 @@ func (p *Point) scalarMulGLV(api frontend.API, p1 *Point, scalar frontend.Variab
 
 	return p
 }
+
+// doubleBaseScalarMulGLV computes s1*P1+s2*P2
+// where P1 and P2 are points on a twisted Edwards curve with an efficient
+// endomorphism and s1, s2 scalars. Both scalars are decomposed as in
+// scalarMulGLV, so that the loop runs over half as many bits as in
+// doubleBaseScalarMul with two additions per bit.
+func (p *Point) doubleBaseScalarMulGLV(api frontend.API, p1, p2 *Point, s1, s2 frontend.Variable, curve *CurveParams, endo *EndoParams) *Point {
+	b11, b12 := decomposeScalarGLV(api, s1, curve, endo)
+	b21, b22 := decomposeScalarGLV(api, s2, curve, endo)
+	n := len(b11)
+
+	var res, tmp Point
+	_p1, p1phi, p1sum := glvTable(api, p1, curve, endo)
+	_p2, p2phi, p2sum := glvTable(api, p2, curve, endo)
+
+	res.X = api.Lookup2(b11[n-1], b12[n-1], 0, _p1.X, p1phi.X, p1sum.X)
+	res.Y = api.Lookup2(b11[n-1], b12[n-1], 1, _p1.Y, p1phi.Y, p1sum.Y)
+	tmp.X = api.Lookup2(b21[n-1], b22[n-1], 0, _p2.X, p2phi.X, p2sum.X)
+	tmp.Y = api.Lookup2(b21[n-1], b22[n-1], 1, _p2.Y, p2phi.Y, p2sum.Y)
+	res.add(api, &res, &tmp, curve)
+
+	for i := n - 2; i >= 0; i-- {
+		res.double(api, &res, curve)
+		tmp.X = api.Lookup2(b11[i], b12[i], 0, _p1.X, p1phi.X, p1sum.X)
+		tmp.Y = api.Lookup2(b11[i], b12[i], 1, _p1.Y, p1phi.Y, p1sum.Y)
+		res.add(api, &res, &tmp, curve)
+		tmp.X = api.Lookup2(b21[i], b22[i], 0, _p2.X, p2phi.X, p2sum.X)
+		tmp.Y = api.Lookup2(b21[i], b22[i], 1, _p2.Y, p2phi.Y, p2sum.Y)
+		res.add(api, &res, &tmp, curve)
+	}
+
+	p.X = res.X
+	p.Y = res.Y
+
+	return p
+}
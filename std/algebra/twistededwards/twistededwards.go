@@ -41,6 +41,10 @@ type Curve interface {
 	Neg(p1 Point) Point
 	AssertIsOnCurve(p1 Point)
 	ScalarMul(p1 Point, scalar frontend.Variable) Point
+	// FixedBaseScalarMul computes [scalar]p1 for a point p1 with constant
+	// coordinates, such as the base point, using precomputed tables. It falls
+	// back to ScalarMul if the coordinates are not constant.
+	FixedBaseScalarMul(p1 Point, scalar frontend.Variable) Point
 	DoubleBaseScalarMul(p1, p2 Point, s1, s2 frontend.Variable) Point
 	API() frontend.API
 }
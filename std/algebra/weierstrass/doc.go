@@ -0,0 +1,35 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package weierstrass implements elliptic curve group operations in (short)
+Weierstrass form over emulated fields.
+
+The curve is given by the equation
+
+	Y² = X³ + aX + b
+
+over the base field of the curve, which is emulated using the package
+[github.com/consensys/gnark/std/math/emulated]. Thus the package allows to
+perform group operations on curves whose base field differs from the native
+field of the SNARK curve, for example secp256k1 or P-256 inside a BN254
+circuit.
+
+The points are represented in affine coordinates and the group operations use
+incomplete formulas. The point at infinity is not representable, see the
+documentation of the methods of [Curve] for the exceptional cases.
+*/
+package weierstrass
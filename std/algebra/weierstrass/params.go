@@ -0,0 +1,89 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weierstrass
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// CurveParams defines parameters of an elliptic curve in short Weierstrass
+// form given by the equation
+//
+//	Y² = X³ + aX + b
+//
+// The base point is defined by (Gx, Gy).
+type CurveParams struct {
+	A  *big.Int // a in curve equation
+	B  *big.Int // b in curve equation
+	Gx *big.Int // base point x
+	Gy *big.Int // base point y
+}
+
+// GetSecp256k1Params returns curve parameters for the curve secp256k1. When
+// initialising new curve, use the base field [emulated.Secp256k1Fp] and scalar
+// field [emulated.Secp256k1Fr].
+func GetSecp256k1Params() CurveParams {
+	gx, _ := new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
+	gy, _ := new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
+	return CurveParams{
+		A:  big.NewInt(0),
+		B:  big.NewInt(7),
+		Gx: gx,
+		Gy: gy,
+	}
+}
+
+// GetP256Params returns curve parameters for the curve NIST P-256. When
+// initialising new curve, use the base field [emulated.P256Fp] and scalar
+// field [emulated.P256Fr].
+func GetP256Params() CurveParams {
+	params := elliptic.P256().Params()
+	a := new(big.Int).Sub(params.P, big.NewInt(3))
+	return CurveParams{
+		A:  a,
+		B:  new(big.Int).Set(params.B),
+		Gx: new(big.Int).Set(params.Gx),
+		Gy: new(big.Int).Set(params.Gy),
+	}
+}
+
+// GetBN254Params returns the curve parameters for the curve BN254 (alt_bn128).
+// When initialising new curve, use the base field [emulated.BN254Fp] and
+// scalar field [emulated.BN254Fr].
+func GetBN254Params() CurveParams {
+	return CurveParams{
+		A:  big.NewInt(0),
+		B:  big.NewInt(3),
+		Gx: big.NewInt(1),
+		Gy: big.NewInt(2),
+	}
+}
+
+// GetBLS12381Params returns the curve parameters for the curve BLS12-381 (the
+// G1 group). When initialising new curve, use the base field
+// [emulated.BLS12381Fp] and scalar field [emulated.BLS12381Fr].
+func GetBLS12381Params() CurveParams {
+	gx, _ := new(big.Int).SetString("17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb", 16)
+	gy, _ := new(big.Int).SetString("08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1", 16)
+	return CurveParams{
+		A:  big.NewInt(0),
+		B:  big.NewInt(4),
+		Gx: gx,
+		Gy: gy,
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weierstrass
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+var (
+	big2 = big.NewInt(2)
+	big3 = big.NewInt(3)
+)
+
+// AffinePoint represents a point on the elliptic curve in affine coordinates.
+// We do not check that the point is actually on the curve. The coordinates
+// of the points returned by the methods of [Curve] are not necessarily
+// reduced.
+type AffinePoint[Base emulated.FieldParams] struct {
+	X, Y emulated.Element[Base]
+}
+
+// NewAffinePoint returns a point with the limbs of the coordinates allocated
+// and set to zero. It is used to define the point in a circuit structure.
+func NewAffinePoint[Base emulated.FieldParams]() AffinePoint[Base] {
+	return AffinePoint[Base]{
+		X: emulated.NewElement[Base](nil),
+		Y: emulated.NewElement[Base](nil),
+	}
+}
+
+// Curve allows to perform operations on the points of the elliptic curve
+// given by [CurveParams] in short Weierstrass form. The coordinates are
+// elements of the emulated base field Base and the scalars are elements of
+// the emulated scalar field Scalars.
+type Curve[Base, Scalars emulated.FieldParams] struct {
+	params    CurveParams
+	api       frontend.API
+	baseApi   *emulated.Field[Base]
+	scalarApi *emulated.Field[Scalars]
+	g         AffinePoint[Base]
+	a         *emulated.Element[Base]
+	b         *emulated.Element[Base]
+	addA      bool
+}
+
+// New returns a new [Curve] instance over the base field Base and scalar field
+// Scalars defined by the curve parameters params. It returns an error if
+// initialising the emulated fields fails.
+func New[Base, Scalars emulated.FieldParams](api frontend.API, params CurveParams) (*Curve[Base, Scalars], error) {
+	ba, err := emulated.NewField[Base](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base api: %w", err)
+	}
+	sa, err := emulated.NewField[Scalars](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar api: %w", err)
+	}
+	return &Curve[Base, Scalars]{
+		params:    params,
+		api:       api,
+		baseApi:   ba,
+		scalarApi: sa,
+		g: AffinePoint[Base]{
+			X: *ba.NewElement(params.Gx),
+			Y: *ba.NewElement(params.Gy),
+		},
+		a:    ba.NewElement(params.A),
+		b:    ba.NewElement(params.B),
+		addA: params.A.Sign() != 0,
+	}, nil
+}
+
+// Params returns the parameters of the curve.
+func (c *Curve[B, S]) Params() CurveParams {
+	return c.params
+}
+
+// API returns the native API.
+func (c *Curve[B, S]) API() frontend.API {
+	return c.api
+}
+
+// BaseField returns the emulated base field used for the coordinates.
+func (c *Curve[B, S]) BaseField() *emulated.Field[B] {
+	return c.baseApi
+}
+
+// ScalarField returns the emulated scalar field.
+func (c *Curve[B, S]) ScalarField() *emulated.Field[S] {
+	return c.scalarApi
+}
+
+// Generator returns the base point of the curve. The point is constant.
+func (c *Curve[B, S]) Generator() *AffinePoint[B] {
+	return &c.g
+}
+
+// Neg returns an inverse of p. It doesn't modify p.
+func (c *Curve[B, S]) Neg(p *AffinePoint[B]) *AffinePoint[B] {
+	return &AffinePoint[B]{
+		X: p.X,
+		Y: *c.baseApi.Neg(&p.Y),
+	}
+}
+
+// AssertIsEqual asserts that p and q are the same point.
+func (c *Curve[B, S]) AssertIsEqual(p, q *AffinePoint[B]) {
+	c.baseApi.AssertIsEqual(&p.X, &q.X)
+	c.baseApi.AssertIsEqual(&p.Y, &q.Y)
+}
+
+// AssertIsOnCurve asserts that p satisfies the curve equation.
+func (c *Curve[B, S]) AssertIsOnCurve(p *AffinePoint[B]) {
+	// y² = x³ + ax + b
+	left := c.baseApi.Mul(&p.Y, &p.Y)
+	right := c.baseApi.Mul(c.baseApi.Mul(&p.X, &p.X), &p.X)
+	if c.addA {
+		right = c.baseApi.Add(right, c.baseApi.Mul(c.a, &p.X))
+	}
+	right = c.baseApi.Add(right, c.b)
+	c.baseApi.AssertIsEqual(left, right)
+}
+
+// Add adds p and q and returns it. It doesn't modify p nor q. It uses
+// incomplete formulas in affine coordinates: p and q must be different and
+// must not be the point at infinity. If p is the inverse of q, then the
+// constraints are not satisfiable. If p equals q, then the result is not
+// constrained and [Curve.Double] must be used instead.
+func (c *Curve[B, S]) Add(p, q *AffinePoint[B]) *AffinePoint[B] {
+	// λ = (q.y-p.y)/(q.x-p.x)
+	qypy := c.baseApi.Sub(&q.Y, &p.Y)
+	qxpx := c.baseApi.Sub(&q.X, &p.X)
+	λ := c.baseApi.Div(qypy, qxpx)
+
+	// xr = λ²-p.x-q.x
+	λλ := c.baseApi.Mul(λ, λ)
+	xr := c.baseApi.Sub(c.baseApi.Sub(λλ, &p.X), &q.X)
+
+	// yr = λ(p.x-xr) - p.y
+	pxrx := c.baseApi.Sub(&p.X, xr)
+	λpxrx := c.baseApi.Mul(λ, pxrx)
+	yr := c.baseApi.Sub(λpxrx, &p.Y)
+
+	return &AffinePoint[B]{
+		X: *xr,
+		Y: *yr,
+	}
+}
+
+// Double doubles p and returns it. It doesn't modify p. The point p must not
+// be the point at infinity nor a point of order 2.
+func (c *Curve[B, S]) Double(p *AffinePoint[B]) *AffinePoint[B] {
+	// λ = (3x²+a)/2y
+	xx := c.baseApi.Mul(&p.X, &p.X)
+	xx3a := c.baseApi.MulConst(xx, big3)
+	if c.addA {
+		xx3a = c.baseApi.Add(xx3a, c.a)
+	}
+	y2 := c.baseApi.MulConst(&p.Y, big2)
+	λ := c.baseApi.Div(xx3a, y2)
+
+	// xr = λ²-2x
+	λλ := c.baseApi.Mul(λ, λ)
+	x2 := c.baseApi.MulConst(&p.X, big2)
+	xr := c.baseApi.Sub(λλ, x2)
+
+	// yr = λ(x-xr)-y
+	pxrx := c.baseApi.Sub(&p.X, xr)
+	λpxrx := c.baseApi.Mul(λ, pxrx)
+	yr := c.baseApi.Sub(λpxrx, &p.Y)
+
+	return &AffinePoint[B]{
+		X: *xr,
+		Y: *yr,
+	}
+}
+
+// Select selects between p and q given the selector b. If b == 1, then
+// returns p and q otherwise.
+func (c *Curve[B, S]) Select(b frontend.Variable, p, q *AffinePoint[B]) *AffinePoint[B] {
+	return &AffinePoint[B]{
+		X: *c.baseApi.Select(b, &p.X, &q.X),
+		Y: *c.baseApi.Select(b, &p.Y, &q.Y),
+	}
+}
+
+// Lookup2 performs a 2-bit lookup between i0, i1, i2, i3 based on bits b0 and
+// b1. Returns i0 if b0=b1=0, i1 if b0=1 and b1=0, i2 if b0=0 and b1=1 and i3
+// if b0=b1=1.
+func (c *Curve[B, S]) Lookup2(b0, b1 frontend.Variable, i0, i1, i2, i3 *AffinePoint[B]) *AffinePoint[B] {
+	return &AffinePoint[B]{
+		X: *c.baseApi.Lookup2(b0, b1, &i0.X, &i1.X, &i2.X, &i3.X),
+		Y: *c.baseApi.Lookup2(b0, b1, &i0.Y, &i1.Y, &i2.Y, &i3.Y),
+	}
+}
+
+// ScalarMul computes s * p and returns it. It doesn't modify p nor s. The
+// point p must be a point of the prime order subgroup different from the
+// point at infinity. The scalar is decomposed into its canonical bit
+// representation.
+//
+// The algorithm is the right-to-left double-and-add where the accumulator
+// is initialised with p and p is subtracted at the end if the least
+// significant bit of s is zero. With this initialisation the incomplete
+// addition formulas never get equal inputs. The constraints are not
+// satisfiable for a negligible number of scalars, including 0, 1 and -1. If p
+// is constant (for example the base point), then the doublings do not add
+// constraints.
+func (c *Curve[B, S]) ScalarMul(p *AffinePoint[B], s *emulated.Element[S]) *AffinePoint[B] {
+	sBits := c.scalarApi.ToBits(s)
+	res := p
+	acc := c.Double(p)
+	for i := 1; i < len(sBits); i++ {
+		tmp := c.Add(res, acc)
+		res = c.Select(sBits[i], tmp, res)
+		if i < len(sBits)-1 {
+			acc = c.Double(acc)
+		}
+	}
+	tmp := c.Add(res, c.Neg(p))
+	return c.Select(sBits[0], res, tmp)
+}
+
+// ScalarMulBase computes s * G where G is the base point of the curve and
+// returns it. See [Curve.ScalarMul] for the limitations.
+func (c *Curve[B, S]) ScalarMulBase(s *emulated.Element[S]) *AffinePoint[B] {
+	return c.ScalarMul(&c.g, s)
+}
@@ -0,0 +1,185 @@
+package weierstrass
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+var testCurve = ecc.BN254
+
+type addCircuit[B, S emulated.FieldParams] struct {
+	params CurveParams
+	P, Q   AffinePoint[B]
+	R      AffinePoint[B]
+}
+
+func (c *addCircuit[B, S]) Define(api frontend.API) error {
+	cr, err := New[B, S](api, c.params)
+	if err != nil {
+		return err
+	}
+	res := cr.Add(&c.P, &c.Q)
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestAdd(t *testing.T) {
+	assert := test.NewAssert(t)
+	p256 := elliptic.P256()
+	px, py := p256.ScalarBaseMult([]byte{0x05})
+	qx, qy := p256.ScalarBaseMult([]byte{0x0b})
+	rx, ry := p256.Add(px, py, qx, qy)
+
+	circuit := addCircuit[emulated.P256Fp, emulated.P256Fr]{
+		params: GetP256Params(),
+		P:      NewAffinePoint[emulated.P256Fp](),
+		Q:      NewAffinePoint[emulated.P256Fp](),
+		R:      NewAffinePoint[emulated.P256Fp](),
+	}
+	witness := addCircuit[emulated.P256Fp, emulated.P256Fr]{
+		P: AffinePoint[emulated.P256Fp]{X: emulated.NewElement[emulated.P256Fp](px), Y: emulated.NewElement[emulated.P256Fp](py)},
+		Q: AffinePoint[emulated.P256Fp]{X: emulated.NewElement[emulated.P256Fp](qx), Y: emulated.NewElement[emulated.P256Fp](qy)},
+		R: AffinePoint[emulated.P256Fp]{X: emulated.NewElement[emulated.P256Fp](rx), Y: emulated.NewElement[emulated.P256Fp](ry)},
+	}
+	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.WithBackends(backend.GROTH16), test.NoSerialization())
+
+	witness.R.Y = emulated.NewElement[emulated.P256Fp](new(big.Int).Sub(p256.Params().P, ry))
+	assert.ProverFailed(&circuit, &witness, test.WithCurves(testCurve), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
+
+type doubleCircuit[B, S emulated.FieldParams] struct {
+	params CurveParams
+	P, R   AffinePoint[B]
+}
+
+func (c *doubleCircuit[B, S]) Define(api frontend.API) error {
+	cr, err := New[B, S](api, c.params)
+	if err != nil {
+		return err
+	}
+	cr.AssertIsOnCurve(&c.P)
+	res := cr.Double(&c.P)
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestDouble(t *testing.T) {
+	assert := test.NewAssert(t)
+	var p, r bn254.G1Affine
+	_, _, p, _ = bn254.Generators()
+	var s fr.Element
+	s.SetRandom()
+	p.ScalarMultiplication(&p, s.ToBigIntRegular(new(big.Int)))
+	var pj bn254.G1Jac
+	pj.FromAffine(&p)
+	pj.DoubleAssign()
+	r.FromJacobian(&pj)
+
+	circuit := doubleCircuit[emulated.BN254Fp, emulated.BN254Fr]{
+		params: GetBN254Params(),
+		P:      NewAffinePoint[emulated.BN254Fp](),
+		R:      NewAffinePoint[emulated.BN254Fp](),
+	}
+	witness := doubleCircuit[emulated.BN254Fp, emulated.BN254Fr]{
+		P: AffinePoint[emulated.BN254Fp]{X: emulated.NewElement[emulated.BN254Fp](p.X), Y: emulated.NewElement[emulated.BN254Fp](p.Y)},
+		R: AffinePoint[emulated.BN254Fp]{X: emulated.NewElement[emulated.BN254Fp](r.X), Y: emulated.NewElement[emulated.BN254Fp](r.Y)},
+	}
+	assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.WithBackends(backend.GROTH16), test.NoSerialization())
+
+	witness.P.Y = emulated.NewElement[emulated.BN254Fp](p.X)
+	assert.ProverFailed(&circuit, &witness, test.WithCurves(testCurve), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
+
+type scalarMulCircuit[B, S emulated.FieldParams] struct {
+	params CurveParams
+	P, R   AffinePoint[B]
+	S      emulated.Element[S]
+}
+
+func (c *scalarMulCircuit[B, S]) Define(api frontend.API) error {
+	cr, err := New[B, S](api, c.params)
+	if err != nil {
+		return err
+	}
+	res := cr.ScalarMul(&c.P, &c.S)
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestScalarMul(t *testing.T) {
+	assert := test.NewAssert(t)
+	p256 := elliptic.P256()
+	params := p256.Params()
+	for _, s := range []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(6), new(big.Int).Sub(params.N, big.NewInt(2)), nil} {
+		if s == nil {
+			var err error
+			if s, err = rand.Int(rand.Reader, params.N); err != nil {
+				t.Fatal(err)
+			}
+		}
+		px, py := p256.ScalarBaseMult([]byte{0x2a})
+		rx, ry := p256.ScalarMult(px, py, s.Bytes())
+
+		circuit := scalarMulCircuit[emulated.P256Fp, emulated.P256Fr]{
+			params: GetP256Params(),
+			P:      NewAffinePoint[emulated.P256Fp](),
+			R:      NewAffinePoint[emulated.P256Fp](),
+			S:      emulated.NewElement[emulated.P256Fr](nil),
+		}
+		witness := scalarMulCircuit[emulated.P256Fp, emulated.P256Fr]{
+			P: AffinePoint[emulated.P256Fp]{X: emulated.NewElement[emulated.P256Fp](px), Y: emulated.NewElement[emulated.P256Fp](py)},
+			R: AffinePoint[emulated.P256Fp]{X: emulated.NewElement[emulated.P256Fp](rx), Y: emulated.NewElement[emulated.P256Fp](ry)},
+			S: emulated.NewElement[emulated.P256Fr](s),
+		}
+		err := test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN)
+		assert.NoError(err, s.String())
+	}
+}
+
+type scalarMulBaseCircuit[B, S emulated.FieldParams] struct {
+	params CurveParams
+	R      AffinePoint[B]
+	S      emulated.Element[S]
+}
+
+func (c *scalarMulBaseCircuit[B, S]) Define(api frontend.API) error {
+	cr, err := New[B, S](api, c.params)
+	if err != nil {
+		return err
+	}
+	res := cr.ScalarMulBase(&c.S)
+	cr.AssertIsEqual(res, &c.R)
+	return nil
+}
+
+func TestScalarMulBase(t *testing.T) {
+	assert := test.NewAssert(t)
+	var r bn254.G1Affine
+	var s fr.Element
+	s.SetRandom()
+	sBig := s.ToBigIntRegular(new(big.Int))
+	_, _, g, _ := bn254.Generators()
+	r.ScalarMultiplication(&g, sBig)
+
+	circuit := scalarMulBaseCircuit[emulated.BN254Fp, emulated.BN254Fr]{
+		params: GetBN254Params(),
+		R:      NewAffinePoint[emulated.BN254Fp](),
+		S:      emulated.NewElement[emulated.BN254Fr](nil),
+	}
+	witness := scalarMulBaseCircuit[emulated.BN254Fp, emulated.BN254Fr]{
+		R: AffinePoint[emulated.BN254Fp]{X: emulated.NewElement[emulated.BN254Fp](r.X), Y: emulated.NewElement[emulated.BN254Fp](r.Y)},
+		S: emulated.NewElement[emulated.BN254Fr](sBig),
+	}
+	err := test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN)
+	assert.NoError(err)
+}
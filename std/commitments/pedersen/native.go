@@ -0,0 +1,196 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+const (
+	generatorTag = "gnark/pedersen/generator"
+	blindingTag  = "gnark/pedersen/blinding"
+)
+
+// NativeCommitment is a Pedersen commitment computed natively.
+type NativeCommitment struct {
+	X, Y *big.Int
+}
+
+// Commitment returns the commitment to be assigned in a circuit.
+func (c NativeCommitment) Commitment() Commitment {
+	return Commitment{P: twistededwards.Point{X: c.X, Y: c.Y}}
+}
+
+// Committer computes Pedersen commitments natively, with the same generators
+// as the circuit gadget.
+type Committer struct {
+	curve      *nativeCurve
+	generators [][2]*big.Int
+	blinding   [2]*big.Int
+}
+
+// NewCommitter returns a committer to vectors of at most size elements on
+// the given curve.
+func NewCommitter(id tedwards.ID, size int) (*Committer, error) {
+	snarkCurve, err := twistededwards.GetSnarkCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	params, err := twistededwards.GetCurveParams(id)
+	if err != nil {
+		return nil, err
+	}
+	modulus := snarkCurve.Info().Fr.Modulus()
+	gens, blinding, err := generators(params, modulus, size)
+	if err != nil {
+		return nil, err
+	}
+	return &Committer{
+		curve:      &nativeCurve{params: params, modulus: modulus},
+		generators: gens,
+		blinding:   blinding,
+	}, nil
+}
+
+// Commit returns the commitment to values with the given randomness. The
+// missing values up to the size of the committer are zero.
+func (c *Committer) Commit(values []*big.Int, randomness *big.Int) (NativeCommitment, error) {
+	if len(values) > len(c.generators) {
+		return NativeCommitment{}, fmt.Errorf("got %d values, expected at most %d", len(values), len(c.generators))
+	}
+	x, y := c.curve.scalarMul(c.blinding[0], c.blinding[1], randomness)
+	for i := range values {
+		gx, gy := c.curve.scalarMul(c.generators[i][0], c.generators[i][1], values[i])
+		x, y = c.curve.add(x, y, gx, gy)
+	}
+	return NativeCommitment{X: x, Y: y}, nil
+}
+
+// Verify returns true if com is the commitment to values with the given
+// randomness.
+func (c *Committer) Verify(com NativeCommitment, values []*big.Int, randomness *big.Int) bool {
+	expected, err := c.Commit(values, randomness)
+	if err != nil {
+		return false
+	}
+	return expected.X.Cmp(com.X) == 0 && expected.Y.Cmp(com.Y) == 0
+}
+
+// Add returns the sum of the commitments c1 and c2.
+func (c *Committer) Add(c1, c2 NativeCommitment) NativeCommitment {
+	x, y := c.curve.add(c1.X, c1.Y, c2.X, c2.Y)
+	return NativeCommitment{X: x, Y: y}
+}
+
+// generators returns the generators of the vector elements and of the
+// randomness, derived by hashing to the curve.
+func generators(params *twistededwards.CurveParams, modulus *big.Int, size int) ([][2]*big.Int, [2]*big.Int, error) {
+	if size < 0 {
+		return nil, [2]*big.Int{}, errors.New("negative size")
+	}
+	curve := &nativeCurve{params: params, modulus: modulus}
+	gens := make([][2]*big.Int, size)
+	for i := range gens {
+		x, y := curve.hashToCurve(generatorTag, uint32(i))
+		gens[i] = [2]*big.Int{x, y}
+	}
+	x, y := curve.hashToCurve(blindingTag, 0)
+	return gens, [2]*big.Int{x, y}, nil
+}
+
+// nativeCurve implements the arithmetic of a twisted Edwards curve with big
+// integers.
+type nativeCurve struct {
+	params  *twistededwards.CurveParams
+	modulus *big.Int
+}
+
+// hashToCurve returns a point of the prime order subgroup derived from the
+// tag and the index by try-and-increment: y is SHA-256(tag || index ||
+// counter) for the first counter such that y is the ordinate of a point, x is
+// the even square root and the point is multiplied by the cofactor.
+func (c *nativeCurve) hashToCurve(tag string, index uint32) (*big.Int, *big.Int) {
+	p := c.modulus
+	one := big.NewInt(1)
+	for counter := uint32(0); ; counter++ {
+		var buf [8]byte
+		binary.BigEndian.PutUint32(buf[:4], index)
+		binary.BigEndian.PutUint32(buf[4:], counter)
+		h := sha256.New()
+		h.Write([]byte(tag))
+		h.Write(buf[:])
+		y := new(big.Int).SetBytes(h.Sum(nil))
+		y.Mod(y, p)
+
+		// x² = (1 - y²) / (a - d*y²)
+		y2 := new(big.Int).Mul(y, y)
+		num := new(big.Int).Sub(one, y2)
+		den := new(big.Int).Mul(c.params.D, y2)
+		den.Sub(c.params.A, den).Mod(den, p)
+		if den.Sign() == 0 {
+			continue
+		}
+		x2 := num.Mul(num, den.ModInverse(den, p)).Mod(num, p)
+		x := new(big.Int).ModSqrt(x2, p)
+		if x == nil {
+			continue
+		}
+		if x.Bit(0) == 1 {
+			x.Sub(p, x)
+		}
+
+		x, y = c.scalarMul(x, y, c.params.Cofactor)
+		if x.Sign() != 0 {
+			return x, y
+		}
+	}
+}
+
+func (c *nativeCurve) add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := c.modulus
+	// x3 = (x1*y2 + y1*x2) / (1 + d*x1*x2*y1*y2)
+	// y3 = (y1*y2 - a*x1*x2) / (1 - d*x1*x2*y1*y2)
+	x1x2 := new(big.Int).Mul(x1, x2)
+	y1y2 := new(big.Int).Mul(y1, y2)
+	t := new(big.Int).Mul(x1x2, y1y2)
+	t.Mul(t, c.params.D).Mod(t, p)
+	x3 := new(big.Int).Mul(x1, y2)
+	x3.Add(x3, new(big.Int).Mul(y1, x2))
+	x3.Mul(x3, new(big.Int).ModInverse(new(big.Int).Add(t, big.NewInt(1)), p)).Mod(x3, p)
+	y3 := new(big.Int).Mul(c.params.A, x1x2)
+	y3.Sub(y1y2, y3)
+	y3.Mul(y3, new(big.Int).ModInverse(new(big.Int).Sub(big.NewInt(1), t), p)).Mod(y3, p)
+	return x3, y3
+}
+
+func (c *nativeCurve) scalarMul(x, y, s *big.Int) (*big.Int, *big.Int) {
+	rx, ry := big.NewInt(0), big.NewInt(1)
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		rx, ry = c.add(rx, ry, rx, ry)
+		if s.Bit(i) == 1 {
+			rx, ry = c.add(rx, ry, x, y)
+		}
+	}
+	return rx, ry
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pedersen provides ZKP-circuit functions to compute and open
+// Pedersen vector commitments on the twisted Edwards curves defined over the
+// scalar fields of the SNARK curves, and their native counterpart.
+//
+// The commitment to the values v_0, ..., v_{n-1} with the randomness r is
+//
+//	C = [v_0]G_0 + ... + [v_{n-1}]G_{n-1} + [r]H
+//
+// where the generators G_i and H are derived by hashing to the curve, so that
+// their discrete logarithms are unknown.
+//
+// The values and the randomness are elements of the scalar field of the SNARK
+// curve, while the generators have the prime order l of the twisted Edwards
+// subgroup, which is smaller. The commitment therefore binds the values only
+// modulo l: a commitment to v also opens to v+l. Callers which need the values
+// themselves, such as balances, must range check them below l, for instance
+// with api.ToBinary(v, n) where 2^n <= l.
+//
+// The commitments are additively homomorphic: the sum of the commitments to
+// two vectors is the commitment to their sum, as long as the sums of the
+// values and of the randomness do not overflow the scalar field.
+package pedersen
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// Commitment stores a Pedersen commitment (to be used in gnark circuit)
+type Commitment struct {
+	P twistededwards.Point
+}
+
+// Pedersen computes Pedersen commitments to vectors of at most a given size
+// in a circuit.
+type Pedersen struct {
+	curve      twistededwards.Curve
+	generators []twistededwards.Point
+	blinding   twistededwards.Point
+}
+
+// New returns a gadget to commit to vectors of at most size elements on
+// the given curve.
+func New(curve twistededwards.Curve, size int) (*Pedersen, error) {
+	modulus := curve.API().Compiler().Curve().Info().Fr.Modulus()
+	gens, blinding, err := generators(curve.Params(), modulus, size)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pedersen{
+		curve:      curve,
+		generators: make([]twistededwards.Point, size),
+		blinding:   twistededwards.Point{X: blinding[0], Y: blinding[1]},
+	}
+	for i := range gens {
+		p.generators[i] = twistededwards.Point{X: gens[i][0], Y: gens[i][1]}
+	}
+	return p, nil
+}
+
+// Commit returns the commitment to values with the given randomness. The
+// missing values up to the size of the gadget are zero.
+func (p *Pedersen) Commit(values []frontend.Variable, randomness frontend.Variable) (Commitment, error) {
+	if len(values) > len(p.generators) {
+		return Commitment{}, fmt.Errorf("got %d values, expected at most %d", len(values), len(p.generators))
+	}
+	res := p.curve.FixedBaseScalarMul(p.blinding, randomness)
+	for i := range values {
+		res = p.curve.Add(res, p.curve.FixedBaseScalarMul(p.generators[i], values[i]))
+	}
+	return Commitment{P: res}, nil
+}
+
+// AssertOpening asserts that c is the commitment to values with the given
+// randomness. The values are only determined modulo the order of the
+// generators, see the package documentation.
+func (p *Pedersen) AssertOpening(c Commitment, values []frontend.Variable, randomness frontend.Variable) error {
+	expected, err := p.Commit(values, randomness)
+	if err != nil {
+		return err
+	}
+	p.curve.API().AssertIsEqual(c.P.X, expected.P.X)
+	p.curve.API().AssertIsEqual(c.P.Y, expected.P.Y)
+	return nil
+}
+
+// Add returns the sum of the commitments c1 and c2, which is the commitment
+// to the sum of the values with the sum of the randomness.
+func (p *Pedersen) Add(c1, c2 Commitment) Commitment {
+	return Commitment{P: p.curve.Add(c1.P, c2.P)}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/test"
+)
+
+var curves = []tedwards.ID{
+	tedwards.BN254,
+	tedwards.BLS12_377,
+	tedwards.BLS12_381,
+	tedwards.BLS12_381_BANDERSNATCH,
+	tedwards.BW6_761,
+	tedwards.BW6_633,
+	tedwards.BLS24_315,
+}
+
+type openingCircuit struct {
+	curveID    tedwards.ID
+	size       int
+	Commitment Commitment `gnark:",public"`
+	Values     []frontend.Variable
+	Randomness frontend.Variable
+}
+
+func (circuit *openingCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	pedersen, err := New(curve, circuit.size)
+	if err != nil {
+		return err
+	}
+	return pedersen.AssertOpening(circuit.Commitment, circuit.Values, circuit.Randomness)
+}
+
+type addCircuit struct {
+	curveID tedwards.ID
+	C1, C2  Commitment
+	Values1 []frontend.Variable
+	Values2 []frontend.Variable
+	R1, R2  frontend.Variable
+}
+
+func (circuit *addCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	pedersen, err := New(curve, len(circuit.Values1))
+	if err != nil {
+		return err
+	}
+	sum := make([]frontend.Variable, len(circuit.Values1))
+	for i := range sum {
+		sum[i] = api.Add(circuit.Values1[i], circuit.Values2[i])
+	}
+	return pedersen.AssertOpening(pedersen.Add(circuit.C1, circuit.C2), sum, api.Add(circuit.R1, circuit.R2))
+}
+
+func randomValues(r *rand.Rand, n int, bound *big.Int) []*big.Int {
+	res := make([]*big.Int, n)
+	for i := range res {
+		res[i] = new(big.Int).Rand(r, bound)
+	}
+	return res
+}
+
+func variables(values []*big.Int) []frontend.Variable {
+	res := make([]frontend.Variable, len(values))
+	for i := range values {
+		res[i] = values[i]
+	}
+	return res
+}
+
+func TestGenerators(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, id := range curves {
+		committer, err := NewCommitter(id, 4)
+		assert.NoError(err)
+		params := committer.curve.params
+		points := append(committer.generators, committer.blinding)
+		seen := make(map[string]bool)
+		for _, g := range points {
+			// in the prime order subgroup, not the identity
+			x, y := committer.curve.scalarMul(g[0], g[1], params.Order)
+			assert.Equal(0, x.Sign())
+			assert.Equal(0, y.Cmp(big.NewInt(1)))
+			assert.NotEqual(0, g[0].Sign())
+			assert.False(seen[g[0].String()], "generators must be distinct")
+			seen[g[0].String()] = true
+		}
+
+		// the generators do not depend on the size
+		other, err := NewCommitter(id, 2)
+		assert.NoError(err)
+		assert.Equal(committer.generators[:2], other.generators)
+		assert.Equal(committer.blinding, other.blinding)
+	}
+}
+
+func TestOpening(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, id := range curves {
+		// the compiled circuits are cached by address, use a fresh assert
+		// as the Jubjub and Bandersnatch circuits share their snark curve
+		assert := test.NewAssert(t)
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+		params, err := twistededwards.GetCurveParams(id)
+		assert.NoError(err)
+		committer, err := NewCommitter(id, 3)
+		assert.NoError(err)
+
+		values := randomValues(r, 2, snarkCurve.Info().Fr.Modulus())
+		randomness := new(big.Int).Rand(r, params.Order)
+		c, err := committer.Commit(values, randomness)
+		assert.NoError(err)
+		assert.True(committer.Verify(c, values, randomness))
+		assert.False(committer.Verify(c, values, new(big.Int).Add(randomness, big.NewInt(1))))
+		_, err = committer.Commit(make([]*big.Int, 4), randomness)
+		assert.Error(err)
+
+		circuit := openingCircuit{curveID: id, size: 3, Values: make([]frontend.Variable, 2)}
+		witness := openingCircuit{Commitment: c.Commitment(), Values: variables(values), Randomness: randomness}
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		witness.Values[1] = new(big.Int).Add(values[1], big.NewInt(1))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		witness.Values[1] = values[1]
+		witness.Randomness = new(big.Int).Add(randomness, big.NewInt(1))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		// the values are only bound modulo the order of the generators
+		witness.Randomness = randomness
+		witness.Values[1] = new(big.Int).Add(values[1], params.Order)
+		if witness.Values[1].(*big.Int).Cmp(snarkCurve.Info().Fr.Modulus()) >= 0 {
+			witness.Values[1] = new(big.Int).Sub(values[1], params.Order)
+		}
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestAdd(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, id := range curves {
+		// the compiled circuits are cached by address, use a fresh assert
+		// as the Jubjub and Bandersnatch circuits share their snark curve
+		assert := test.NewAssert(t)
+		snarkCurve, err := twistededwards.GetSnarkCurve(id)
+		assert.NoError(err)
+		committer, err := NewCommitter(id, 2)
+		assert.NoError(err)
+
+		// balances which do not overflow
+		bound := new(big.Int).Lsh(big.NewInt(1), 64)
+		v1, v2 := randomValues(r, 2, bound), randomValues(r, 2, bound)
+		r1, r2 := new(big.Int).Rand(r, bound), new(big.Int).Rand(r, bound)
+		c1, err := committer.Commit(v1, r1)
+		assert.NoError(err)
+		c2, err := committer.Commit(v2, r2)
+		assert.NoError(err)
+		sum := []*big.Int{new(big.Int).Add(v1[0], v2[0]), new(big.Int).Add(v1[1], v2[1])}
+		assert.True(committer.Verify(committer.Add(c1, c2), sum, new(big.Int).Add(r1, r2)))
+
+		circuit := addCircuit{
+			curveID: id,
+			Values1: make([]frontend.Variable, 2),
+			Values2: make([]frontend.Variable, 2),
+		}
+		witness := addCircuit{
+			C1: c1.Commitment(), C2: c2.Commitment(),
+			Values1: variables(v1), Values2: variables(v2),
+			R1: r1, R2: r2,
+		}
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		witness.R2 = new(big.Int).Add(r2, big.NewInt(1))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+	}
+}
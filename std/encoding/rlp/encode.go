@@ -0,0 +1,141 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rlp
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// EncodeString returns the RLP encoding of the string of the first length
+// bytes of payload, and the length of the encoding. It asserts that length is
+// at most len(payload).
+func EncodeString(api frontend.API, payload []frontend.Variable, length frontend.Variable) ([]frontend.Variable, frontend.Variable) {
+	payload = mask(api, payload, length)
+
+	// a single byte below 0x80 is its own encoding
+	var isSingle frontend.Variable = 0
+	if len(payload) > 0 {
+		first := bits.ToBinary(api, payload[0], bits.WithNbDigits(8))
+		isSingle = api.Mul(api.IsZero(api.Sub(length, 1)), api.Sub(1, first[7]))
+	}
+	header, headerLen := encodeHeader(api, 0x80, length, nbBytes(len(payload)))
+	isHeader := api.Sub(1, isSingle)
+	for i := range header {
+		header[i] = api.Mul(header[i], isHeader)
+	}
+
+	return concat(api, [][]frontend.Variable{header, payload}, []frontend.Variable{api.Mul(headerLen, isHeader), length})
+}
+
+// EncodeUint returns the RLP encoding of the unsigned integer v of at most
+// nbBytes bytes, and the length of the encoding.
+func EncodeUint(api frontend.API, v frontend.Variable, nbBytes int) ([]frontend.Variable, frontend.Variable) {
+	b := bits.ToBinary(api, v, bits.WithNbDigits(8*nbBytes))
+	be := make([]frontend.Variable, nbBytes)
+	for i := range be {
+		j := nbBytes - 1 - i
+		be[i] = bits.FromBinary(api, b[8*j:8*j+8])
+	}
+	payload, zeros := trimLeadingZeros(api, be)
+	return EncodeString(api, payload, api.Sub(nbBytes, zeros))
+}
+
+// EncodeList returns the RLP encoding of the list of the encoded items, of
+// the given lengths, and the length of the encoding. The bytes of each item
+// past its length must be zero, as returned by the other encoding functions.
+func EncodeList(api frontend.API, items [][]frontend.Variable, lengths []frontend.Variable) ([]frontend.Variable, frontend.Variable) {
+	if len(items) != len(lengths) {
+		panic("items and lengths must have the same length")
+	}
+	payload, length := concat(api, items, lengths)
+	header, headerLen := encodeHeader(api, 0xc0, length, nbBytes(len(payload)))
+	return concat(api, [][]frontend.Variable{header, payload}, []frontend.Variable{headerLen, length})
+}
+
+// encodeHeader returns the header of a string (base 0x80) or a list (base
+// 0xc0) with a payload of length n, written in at most lenOfLenMax bytes,
+// padded with zeroes, and its length.
+func encodeHeader(api frontend.API, base int, n frontend.Variable, lenOfLenMax int) ([]frontend.Variable, frontend.Variable) {
+	b := bits.ToBinary(api, n, bits.WithNbDigits(8*lenOfLenMax))
+	be := make([]frontend.Variable, lenOfLenMax)
+	for i := range be {
+		j := lenOfLenMax - 1 - i
+		be[i] = bits.FromBinary(api, b[8*j:8*j+8])
+	}
+
+	// n < 56 iff its bits from the 6-th are zero and its 6 lowest bits are
+	// not 111xxx
+	isShort := api.Mul(api.IsZero(sum(api, b[6:])), api.Sub(1, api.Mul(b[5], b[4], b[3])))
+	isLong := api.Sub(1, isShort)
+	lenBytes, zeros := trimLeadingZeros(api, be)
+	lenOfLen := api.Sub(lenOfLenMax, zeros)
+
+	header := make([]frontend.Variable, 1+lenOfLenMax)
+	header[0] = api.Add(
+		api.Mul(isShort, api.Add(base, n)),
+		api.Mul(isLong, api.Add(base+55, lenOfLen)),
+	)
+	for i := range lenBytes {
+		header[1+i] = api.Mul(isLong, lenBytes[i])
+	}
+	return header, api.Add(1, api.Mul(isLong, lenOfLen))
+}
+
+// trimLeadingZeros returns the big-endian bytes without their leading
+// zeroes, padded with zeroes, and the number of leading zeroes.
+func trimLeadingZeros(api frontend.API, be []frontend.Variable) ([]frontend.Variable, frontend.Variable) {
+	zeros := make([]frontend.Variable, len(be))
+	var allZero frontend.Variable = 1
+	for i := range be {
+		allZero = api.Mul(allZero, api.IsZero(be[i]))
+		zeros[i] = allZero
+	}
+	nbZeros := sum(api, zeros)
+	return window(api, be, indicators(api, nbZeros, len(be)), len(be)), nbZeros
+}
+
+// concat returns the concatenation of the first lengths[i] elements of the
+// parts, padded with zeroes, and its length. The elements of each part past
+// its length must be zero.
+func concat(api frontend.API, parts [][]frontend.Variable, lengths []frontend.Variable) ([]frontend.Variable, frontend.Variable) {
+	size := 0
+	for _, part := range parts {
+		size += len(part)
+	}
+	terms := make([][]frontend.Variable, size)
+
+	// the part i starts at a variable offset of at most maxOffset
+	var offset frontend.Variable = 0
+	maxOffset := 0
+	for i, part := range parts {
+		ind := indicators(api, offset, maxOffset+1)
+		for s := range ind {
+			for j := range part {
+				terms[s+j] = append(terms[s+j], api.Mul(ind[s], part[j]))
+			}
+		}
+		offset = api.Add(offset, lengths[i])
+		maxOffset += len(part)
+	}
+
+	res := make([]frontend.Variable, size)
+	for i := range res {
+		res[i] = sum(api, terms[i])
+	}
+	return res, offset
+}
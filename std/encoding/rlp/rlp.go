@@ -0,0 +1,216 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rlp provides ZKP-circuit functions to decode and encode data in the
+// Recursive Length Prefix (RLP) serialization of Ethereum.
+//
+// An encoding is given as a slice of byte variables, whose length is the
+// maximal length of the encoding, with its actual length as a variable. The
+// bytes past the actual length are ignored. The items are decoded at variable
+// offsets, so that a single circuit handles encodings of different shapes,
+// and the decoding asserts that the encoding is canonical, as Ethereum
+// requires. The elements of the encoding are assumed to be bytes, which is
+// asserted when they are hashed with the functions of std/hash/sha3.
+//
+// The encoding functions return byte variables padded with zeroes to the
+// maximal length of the result, with the actual length.
+//
+// Accessing data at a variable offset costs a constraint per byte of the
+// encoding and per byte read, so the maximal lengths should be kept tight.
+package rlp
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// Item is a decoded RLP item in an encoding.
+type Item struct {
+	Offset frontend.Variable // offset of the item
+	Start  frontend.Variable // offset of the payload
+	Len    frontend.Variable // length of the payload
+	End    frontend.Variable // offset past the item
+	IsList frontend.Variable // 1 for a list, 0 for a string
+}
+
+// Decode decodes the header of the item at the given offset in the first
+// length bytes of data. It asserts that the header is canonical and that the
+// item ends before length, which must be at most len(data).
+func Decode(api frontend.API, data []frontend.Variable, length, offset frontend.Variable) Item {
+	api.AssertIsLessOrEqual(length, len(data))
+
+	// the length of the payload of a long item is given in at most
+	// lenOfLenMax bytes
+	lenOfLenMax := nbBytes(len(data))
+	header := window(api, data, indicators(api, offset, len(data)), 1+lenOfLenMax)
+
+	b := bits.ToBinary(api, header[0], bits.WithNbDigits(8))
+	isSingle := api.Sub(1, b[7])
+	isList := api.Mul(b[7], b[6])
+	isString := api.Sub(b[7], isList)
+	isLong := api.Mul(b[5], b[4], b[3])
+	isLongString := api.Mul(isString, isLong)
+	isLongList := api.Mul(isList, isLong)
+	long := api.Add(isLongString, isLongList)
+
+	lenOfLen := api.Add(
+		api.Mul(isLongString, api.Sub(header[0], 0xb7)),
+		api.Mul(isLongList, api.Sub(header[0], 0xf7)),
+	)
+	lenOfLenInd := indicators(api, lenOfLen, lenOfLenMax+1)
+	api.AssertIsEqual(sum(api, lenOfLenInd), 1)
+	longLen := make([]frontend.Variable, 0, lenOfLenMax)
+	var acc frontend.Variable = 0
+	for k := 1; k <= lenOfLenMax; k++ {
+		acc = api.Add(api.Mul(acc, 256), header[k])
+		longLen = append(longLen, api.Mul(lenOfLenInd[k], acc))
+	}
+	payloadLen := api.Add(
+		isSingle,
+		api.Mul(api.Sub(isString, isLongString), api.Sub(header[0], 0x80)),
+		api.Mul(api.Sub(isList, isLongList), api.Sub(header[0], 0xc0)),
+		sum(api, longLen),
+	)
+
+	// the long form is only used for payloads of at least 56 bytes, and its
+	// length has no leading zero
+	api.AssertIsEqual(api.Mul(long, api.IsZero(header[1])), 0)
+	api.AssertIsLessOrEqual(api.Mul(long, 56), payloadLen)
+
+	// a single byte below 0x80 is its own encoding
+	first := bits.ToBinary(api, header[1], bits.WithNbDigits(8))
+	api.AssertIsEqual(api.Mul(api.IsZero(api.Sub(header[0], 0x81)), api.Sub(1, first[7])), 0)
+
+	start := api.Add(offset, api.Sub(1, isSingle), lenOfLen)
+	end := api.Add(start, payloadLen)
+	api.AssertIsLessOrEqual(end, length)
+
+	return Item{
+		Offset: offset,
+		Start:  start,
+		Len:    payloadLen,
+		End:    end,
+		IsList: isList,
+	}
+}
+
+// DecodeList decodes the list at the given offset in the first length bytes
+// of data and its items. It asserts that the list has exactly nbItems items.
+func DecodeList(api frontend.API, data []frontend.Variable, length, offset frontend.Variable, nbItems int) (Item, []Item) {
+	list := Decode(api, data, length, offset)
+	api.AssertIsEqual(list.IsList, 1)
+
+	items := make([]Item, nbItems)
+	offset = list.Start
+	for i := range items {
+		items[i] = Decode(api, data, length, offset)
+		offset = items[i].End
+	}
+	api.AssertIsEqual(offset, list.End)
+
+	return list, items
+}
+
+// Bytes returns the payload of the item, padded with zeroes to maxLen bytes.
+// It asserts that the payload is at most maxLen bytes long.
+func Bytes(api frontend.API, data []frontend.Variable, item Item, maxLen int) []frontend.Variable {
+	payload := window(api, data, indicators(api, item.Start, len(data)), maxLen)
+	return mask(api, payload, item.Len)
+}
+
+// Uint returns the unsigned integer encoded in the item, a string of at most
+// maxLen bytes. It asserts that the integer is canonical, that is without
+// leading zero. maxLen must be less than 32 so that the integer fits in the
+// field.
+func Uint(api frontend.API, data []frontend.Variable, item Item, maxLen int) frontend.Variable {
+	if maxLen >= 32 {
+		panic("integers are at most 31 bytes long")
+	}
+	api.AssertIsEqual(item.IsList, 0)
+	payload := Bytes(api, data, item, maxLen)
+	api.AssertIsEqual(api.Mul(api.Sub(1, api.IsZero(item.Len)), api.IsZero(payload[0])), 0)
+
+	lenInd := indicators(api, item.Len, maxLen+1)
+	values := make([]frontend.Variable, maxLen)
+	var acc frontend.Variable = 0
+	for k := 1; k <= maxLen; k++ {
+		acc = api.Add(api.Mul(acc, 256), payload[k-1])
+		values[k-1] = api.Mul(lenInd[k], acc)
+	}
+	return sum(api, values)
+}
+
+// indicators returns the n variables [idx == i] for i in [0, n). They are
+// all zero if idx is not in this range.
+func indicators(api frontend.API, idx frontend.Variable, n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = api.IsZero(api.Sub(idx, i))
+	}
+	return res
+}
+
+// window returns the width elements of data from the offset given by its
+// indicators, the elements past the end of data being zero.
+func window(api frontend.API, data, ind []frontend.Variable, width int) []frontend.Variable {
+	res := make([]frontend.Variable, width)
+	for j := range res {
+		terms := make([]frontend.Variable, 0, len(ind))
+		for i := range ind {
+			if i+j < len(data) {
+				terms = append(terms, api.Mul(ind[i], data[i+j]))
+			}
+		}
+		res[j] = sum(api, terms)
+	}
+	return res
+}
+
+// mask returns data with the elements from length set to zero. It asserts
+// that length is at most len(data).
+func mask(api frontend.API, data []frontend.Variable, length frontend.Variable) []frontend.Variable {
+	ind := indicators(api, length, len(data)+1)
+	api.AssertIsEqual(sum(api, ind), 1)
+	res := make([]frontend.Variable, len(data))
+	inData := api.Sub(1, ind[0])
+	for i := range res {
+		res[i] = api.Mul(data[i], inData)
+		inData = api.Sub(inData, ind[i+1])
+	}
+	return res
+}
+
+// sum returns the sum of the variables.
+func sum(api frontend.API, vs []frontend.Variable) frontend.Variable {
+	switch len(vs) {
+	case 0:
+		return 0
+	case 1:
+		return vs[0]
+	default:
+		return api.Add(vs[0], vs[1], vs[2:]...)
+	}
+}
+
+// nbBytes returns the number of bytes needed to write n, at least 1.
+func nbBytes(n int) int {
+	res := 1
+	for n >= 256 {
+		n >>= 8
+		res++
+	}
+	return res
+}
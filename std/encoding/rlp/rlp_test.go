@@ -0,0 +1,282 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+// testPadding is the number of zero bytes appended to the encodings, so that
+// their lengths are variable.
+const testPadding = 3
+
+type stringCircuit struct {
+	Encoding   []frontend.Variable
+	Length     frontend.Variable
+	Payload    []frontend.Variable
+	PayloadLen frontend.Variable
+}
+
+func (c *stringCircuit) Define(api frontend.API) error {
+	item := Decode(api, c.Encoding, c.Length, 0)
+	api.AssertIsEqual(item.End, c.Length)
+	api.AssertIsEqual(item.IsList, 0)
+	api.AssertIsEqual(item.Len, c.PayloadLen)
+	payload := Bytes(api, c.Encoding, item, len(c.Payload))
+	for i := range payload {
+		api.AssertIsEqual(payload[i], c.Payload[i])
+	}
+
+	encoding, length := EncodeString(api, c.Payload, c.PayloadLen)
+	api.AssertIsEqual(length, c.Length)
+	assertPrefix(api, encoding, c.Encoding)
+	return nil
+}
+
+type uintCircuit struct {
+	Encoding []frontend.Variable
+	Length   frontend.Variable
+	Value    frontend.Variable
+}
+
+func (c *uintCircuit) Define(api frontend.API) error {
+	item := Decode(api, c.Encoding, c.Length, 0)
+	api.AssertIsEqual(item.End, c.Length)
+	api.AssertIsEqual(Uint(api, c.Encoding, item, 16), c.Value)
+
+	encoding, length := EncodeUint(api, c.Value, 16)
+	api.AssertIsEqual(length, c.Length)
+	assertPrefix(api, encoding, c.Encoding)
+	return nil
+}
+
+type listCircuit struct {
+	Encoding []frontend.Variable
+	Length   frontend.Variable
+	nbItems  int
+}
+
+func (c *listCircuit) Define(api frontend.API) error {
+	list, _ := DecodeList(api, c.Encoding, c.Length, 0, c.nbItems)
+	api.AssertIsEqual(list.End, c.Length)
+	return nil
+}
+
+// assertPrefix asserts that the shorter slice is a prefix of the longer one
+// padded with zeroes.
+func assertPrefix(api frontend.API, a, b []frontend.Variable) {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	for i := range b {
+		if i < len(a) {
+			api.AssertIsEqual(a[i], b[i])
+		} else {
+			api.AssertIsEqual(b[i], 0)
+		}
+	}
+}
+
+func TestVectors(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var vectors map[string]struct {
+		In  interface{} `json:"in"`
+		Out string      `json:"out"`
+	}
+	readTestData(t, "rlptest.json", &vectors)
+
+	for name, v := range vectors {
+		encoding := decodeHex(t, v.Out)
+		var circuit, witness frontend.Circuit
+		switch in := v.In.(type) {
+		case string:
+			if strings.HasPrefix(in, "#") {
+				value, _ := new(big.Int).SetString(in[1:], 10)
+				circuit, witness = newUintCircuit(encoding, value)
+			} else {
+				circuit, witness = newStringCircuit(encoding, []byte(in))
+			}
+		case float64:
+			circuit, witness = newUintCircuit(encoding, big.NewInt(int64(in)))
+		case []interface{}:
+			circuit = &listCircuit{Encoding: make([]frontend.Variable, len(encoding)+testPadding), nbItems: len(in)}
+			witness = &listCircuit{Encoding: padBytes(encoding, len(encoding)+testPadding), Length: len(encoding)}
+		}
+		assert.Run(func(assert *test.Assert) {
+			assert.NoError(test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN))
+		}, name)
+	}
+}
+
+func newStringCircuit(encoding, payload []byte) (frontend.Circuit, frontend.Circuit) {
+	circuit := &stringCircuit{
+		Encoding: make([]frontend.Variable, len(encoding)+testPadding),
+		Payload:  make([]frontend.Variable, len(payload)+testPadding),
+	}
+	witness := &stringCircuit{
+		Encoding:   padBytes(encoding, len(encoding)+testPadding),
+		Length:     len(encoding),
+		Payload:    padBytes(payload, len(payload)+testPadding),
+		PayloadLen: len(payload),
+	}
+	return circuit, witness
+}
+
+func newUintCircuit(encoding []byte, value *big.Int) (frontend.Circuit, frontend.Circuit) {
+	circuit := &uintCircuit{Encoding: make([]frontend.Variable, len(encoding)+testPadding)}
+	witness := &uintCircuit{
+		Encoding: padBytes(encoding, len(encoding)+testPadding),
+		Length:   len(encoding),
+		Value:    value,
+	}
+	return circuit, witness
+}
+
+type decodeCircuit struct {
+	Encoding []frontend.Variable
+	Length   frontend.Variable
+}
+
+func (c *decodeCircuit) Define(api frontend.API) error {
+	item := Decode(api, c.Encoding, c.Length, 0)
+	api.AssertIsEqual(item.End, c.Length)
+	return nil
+}
+
+func TestInvalid(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var vectors map[string]string
+	readTestData(t, "invalid.json", &vectors)
+
+	// the maximal length is large enough for the lengths of the long
+	// encodings to have two bytes
+	const maxLen = 300
+	for name, v := range vectors {
+		encoding := decodeHex(t, v)
+		circuit := decodeCircuit{Encoding: make([]frontend.Variable, maxLen)}
+		witness := decodeCircuit{Encoding: padBytes(encoding, maxLen), Length: len(encoding)}
+		assert.Run(func(assert *test.Assert) {
+			assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN))
+		}, name)
+	}
+}
+
+// headerFields are the maximal lengths of the fields of a block header after
+// the London fork, zero for the integer fields.
+var headerFields = []int{32, 32, 20, 32, 32, 32, 256, 0, 0, 0, 0, 0, 32, 32, 8, 0}
+
+const headerMaxLen = 600
+
+type headerCircuit struct {
+	Encoding  [headerMaxLen]frontend.Variable
+	Length    frontend.Variable
+	StateRoot [32]frontend.Variable
+	Number    frontend.Variable
+	BaseFee   frontend.Variable
+}
+
+func (c *headerCircuit) Define(api frontend.API) error {
+	_, items := DecodeList(api, c.Encoding[:], c.Length, 0, len(headerFields))
+
+	stateRoot := Bytes(api, c.Encoding[:], items[3], 32)
+	for i := range stateRoot {
+		api.AssertIsEqual(stateRoot[i], c.StateRoot[i])
+	}
+	api.AssertIsEqual(Uint(api, c.Encoding[:], items[8], 8), c.Number)
+	api.AssertIsEqual(Uint(api, c.Encoding[:], items[15], 8), c.BaseFee)
+
+	// encode the header back from its fields
+	fields := make([][]frontend.Variable, len(items))
+	lengths := make([]frontend.Variable, len(items))
+	for i, maxLen := range headerFields {
+		if maxLen == 0 {
+			fields[i], lengths[i] = EncodeUint(api, Uint(api, c.Encoding[:], items[i], 8), 8)
+		} else {
+			fields[i], lengths[i] = EncodeString(api, Bytes(api, c.Encoding[:], items[i], maxLen), items[i].Len)
+		}
+	}
+	encoding, length := EncodeList(api, fields, lengths)
+	api.AssertIsEqual(length, c.Length)
+	assertPrefix(api, encoding, c.Encoding[:])
+	return nil
+}
+
+func TestHeader(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var header struct {
+		Root    string `json:"root"`
+		Number  uint64 `json:"number"`
+		BaseFee uint64 `json:"baseFee"`
+		RLP     string `json:"rlp"`
+	}
+	readTestData(t, "header.json", &header)
+	encoding := decodeHex(t, header.RLP)
+
+	var witness headerCircuit
+	copy(witness.Encoding[:], padBytes(encoding, headerMaxLen))
+	witness.Length = len(encoding)
+	copy(witness.StateRoot[:], padBytes(decodeHex(t, header.Root), 32))
+	witness.Number = header.Number
+	witness.BaseFee = header.BaseFee
+	assert.NoError(test.IsSolved(&headerCircuit{}, &witness, ecc.BN254, backend.UNKNOWN))
+
+	witness.Number = header.Number + 1
+	assert.Error(test.IsSolved(&headerCircuit{}, &witness, ecc.BN254, backend.UNKNOWN))
+}
+
+func readTestData(t *testing.T, name string, v interface{}) {
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func padBytes(b []byte, n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		if i < len(b) {
+			res[i] = b[i]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
@@ -0,0 +1,150 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lightclient provides a ZKP-circuit verifying the Altair
+// LightClientUpdate of the Ethereum consensus layer.
+//
+// The circuit proves that a sync committee with a known root signed the
+// header attested by the update, with a participation of at least two
+// thirds, and that the state of the attested header commits to the finalized
+// header and to the next sync committee. It performs the checks of
+// validate_light_client_update of the light client specification which
+// depend on the signature and the Merkle branches. The checks on the store of
+// the light client, such as the sync committee period of the signature slot,
+// are left to the verifier of the proof, using the public inputs.
+//
+// The public keys of the committee are given twice: as the compressed bytes
+// hashed in the root of the committee and as points, which are asserted to be
+// the decompression of the bytes. The points are not checked to be in G1, as
+// the keys of the validators are validated when they are deposited.
+//
+// The circuit requires the emulation of BLS12-381, which makes it large. It
+// is intended to be proved with Groth16 on BN254, see [Setup] and [Prove].
+package lightclient
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/ethereum/ssz"
+	"github.com/consensys/gnark/std/signature/bls"
+)
+
+const (
+	// SyncCommitteeSize is the size of the sync committee on the mainnet.
+	SyncCommitteeSize = 512
+
+	// FinalizedRootGIndex is the generalized index of the root of the
+	// finalized checkpoint in the BeaconState.
+	FinalizedRootGIndex = 105
+	// NextSyncCommitteeGIndex is the generalized index of the next sync
+	// committee in the BeaconState.
+	NextSyncCommitteeGIndex = 55
+
+	// FinalityBranchDepth is the length of the finality branch.
+	FinalityBranchDepth = 6
+	// NextSyncCommitteeBranchDepth is the length of the branch of the next
+	// sync committee.
+	NextSyncCommitteeBranchDepth = 5
+)
+
+// Circuit verifies a LightClientUpdate signed by the current sync committee.
+// It must be created with [NewCircuit].
+type Circuit struct {
+	// CurrentSyncCommitteeRoot is the root of the committee signing the update.
+	CurrentSyncCommitteeRoot ssz.Chunk `gnark:",public"`
+	// Domain is the signature domain of the sync committee for the fork of
+	// the signature slot, see [ComputeDomain].
+	Domain ssz.Chunk `gnark:",public"`
+	// SignatureSlot is the slot at which the update was signed.
+	SignatureSlot frontend.Variable `gnark:",public"`
+	// FinalizedHeaderRoot is the root of the finalized header.
+	FinalizedHeaderRoot ssz.Chunk `gnark:",public"`
+	// NextSyncCommitteeRoot is the root of the next sync committee.
+	NextSyncCommitteeRoot ssz.Chunk `gnark:",public"`
+
+	AttestedHeader          ssz.BeaconBlockHeader
+	FinalizedHeader         ssz.BeaconBlockHeader
+	FinalityBranch          [FinalityBranchDepth]ssz.Chunk
+	NextSyncCommitteeBranch [NextSyncCommitteeBranchDepth]ssz.Chunk
+
+	SyncCommittee          ssz.SyncCommittee
+	Pubkeys                []bls.PublicKey
+	SyncCommitteeBits      []frontend.Variable
+	SyncCommitteeSignature bls.Signature
+}
+
+// NewCircuit returns the circuit for a sync committee of the given size,
+// [SyncCommitteeSize] on the mainnet.
+func NewCircuit(committeeSize int) *Circuit {
+	c := &Circuit{
+		SyncCommittee:          ssz.NewSyncCommittee(committeeSize),
+		Pubkeys:                make([]bls.PublicKey, committeeSize),
+		SyncCommitteeBits:      make([]frontend.Variable, committeeSize),
+		SyncCommitteeSignature: bls.NewSignature(),
+	}
+	for i := range c.Pubkeys {
+		c.Pubkeys[i] = bls.NewPublicKey()
+	}
+	return c
+}
+
+// Define declares the constraints of the circuit.
+func (c *Circuit) Define(api frontend.API) error {
+	size := len(c.Pubkeys)
+
+	// the committee is the current one and the points are its public keys
+	assertChunksEqual(api, c.SyncCommittee.HashTreeRoot(api), c.CurrentSyncCommitteeRoot)
+	for i := range c.Pubkeys {
+		if err := bls.AssertCompressed(api, &c.Pubkeys[i], c.SyncCommittee.Pubkeys[i][:]); err != nil {
+			return err
+		}
+	}
+
+	// at least two thirds of the committee participate: 3*(size-sum) <= size
+	participants := sum(api, c.SyncCommitteeBits)
+	api.AssertIsLessOrEqual(api.Mul(3, api.Sub(size, participants)), size)
+
+	// signature_slot > attested_header.slot >= finalized_header.slot
+	api.AssertIsLessOrEqual(api.Add(c.AttestedHeader.Slot, 1), c.SignatureSlot)
+	api.AssertIsLessOrEqual(c.FinalizedHeader.Slot, c.AttestedHeader.Slot)
+
+	// the state of the attested header commits to the finalized header and to
+	// the next sync committee
+	attestedRoot := c.AttestedHeader.HashTreeRoot(api)
+	finalizedRoot := c.FinalizedHeader.HashTreeRoot(api)
+	assertChunksEqual(api, finalizedRoot, c.FinalizedHeaderRoot)
+	ssz.VerifyBranch(api, finalizedRoot, c.FinalityBranch[:], FinalizedRootGIndex, c.AttestedHeader.StateRoot)
+	ssz.VerifyBranch(api, c.NextSyncCommitteeRoot, c.NextSyncCommitteeBranch[:], NextSyncCommitteeGIndex, c.AttestedHeader.StateRoot)
+
+	// the participants signed the signing root of the attested header
+	signingRoot := ssz.Hash(api, attestedRoot, c.Domain)
+	return bls.VerifyAggregateSubset(api, c.Pubkeys, c.SyncCommitteeBits, signingRoot[:], &c.SyncCommitteeSignature)
+}
+
+func assertChunksEqual(api frontend.API, a, b ssz.Chunk) {
+	for i := range a {
+		api.AssertIsEqual(a[i], b[i])
+	}
+}
+
+func sum(api frontend.API, vs []frontend.Variable) frontend.Variable {
+	switch len(vs) {
+	case 0:
+		return 0
+	case 1:
+		return vs[0]
+	}
+	return api.Add(vs[0], vs[1], vs[2:]...)
+}
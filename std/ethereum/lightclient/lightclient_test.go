@@ -0,0 +1,159 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lightclient
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/test"
+)
+
+// fixture is an update signed by a committee of 32 deterministic keys, the
+// size of the minimal preset, where the validator 3 is also the member 17 and
+// 25 members participate.
+type fixture struct {
+	GenesisValidatorsRoot string          `json:"genesis_validators_root"`
+	ForkVersion           string          `json:"fork_version"`
+	CurrentSyncCommittee  SyncCommittee   `json:"current_sync_committee"`
+	Update                json.RawMessage `json:"update"`
+}
+
+func readFixture(assert *test.Assert) (*Update, *SyncCommittee, [32]byte) {
+	data, err := os.ReadFile("testdata/update.json")
+	assert.NoError(err)
+	var f fixture
+	assert.NoError(json.Unmarshal(data, &f))
+	update, err := ParseUpdate(f.Update)
+	assert.NoError(err)
+
+	var forkVersion [4]byte
+	var gvr [32]byte
+	b, err := decodeHex(f.ForkVersion, 4)
+	assert.NoError(err)
+	copy(forkVersion[:], b)
+	b, err = decodeHex(f.GenesisValidatorsRoot, 32)
+	assert.NoError(err)
+	copy(gvr[:], b)
+	return update, &f.CurrentSyncCommittee, ComputeDomain(forkVersion, gvr)
+}
+
+func TestComputeDomain(t *testing.T) {
+	assert := test.NewAssert(t)
+	// altair on the mainnet
+	gvr, _ := hex.DecodeString("4b363db94e286120d76eb905340fdd4e54bfe9f06bf33ff6cf5ad27f511bfe95")
+	var root [32]byte
+	copy(root[:], gvr)
+	domain := ComputeDomain([4]byte{1, 0, 0, 0}, root)
+	assert.Equal("07000000afcaaba0efab1ca832a15152469bb09bb84641c405171dfa2d3fb45f", hex.EncodeToString(domain[:]))
+}
+
+func TestUpdate(t *testing.T) {
+	assert := test.NewAssert(t)
+	update, committee, domain := readFixture(assert)
+	size := len(committee.Pubkeys)
+
+	witness, err := update.Assign(committee, domain)
+	assert.NoError(err)
+	err = test.IsSolved(NewCircuit(size), witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+
+	// a non-participant is counted
+	witness.SyncCommitteeBits[1] = 1
+	err = test.IsSolved(NewCircuit(size), witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+
+	// wrong next sync committee
+	witness, err = update.Assign(committee, domain)
+	assert.NoError(err)
+	witness.NextSyncCommitteeRoot[0] = 0
+	err = test.IsSolved(NewCircuit(size), witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+
+	// wrong finalized header
+	witness, err = update.Assign(committee, domain)
+	assert.NoError(err)
+	witness.FinalizedHeader.Slot = 65
+	witness.FinalizedHeaderRoot = chunk(nativeRoot(assert, update.FinalizedHeader, 65))
+	err = test.IsSolved(NewCircuit(size), witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+
+	// signed at the attested slot
+	witness, err = update.Assign(committee, domain)
+	assert.NoError(err)
+	witness.SignatureSlot = 95
+	err = test.IsSolved(NewCircuit(size), witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+
+	// point not matching the committee
+	witness, err = update.Assign(committee, domain)
+	assert.NoError(err)
+	witness.Pubkeys[3], witness.Pubkeys[4] = witness.Pubkeys[4], witness.Pubkeys[3]
+	err = test.IsSolved(NewCircuit(size), witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
+
+func TestParticipation(t *testing.T) {
+	assert := test.NewAssert(t)
+	update, committee, domain := readFixture(assert)
+
+	// 21 of the 32 members are less than two thirds, even if they signed
+	bits := []byte{0xff, 0xff, 0x1f, 0x00}
+	update.SyncAggregate.SyncCommitteeBits = "0x" + hex.EncodeToString(bits)
+	witness, err := update.Assign(committee, domain)
+	assert.NoError(err)
+	err = test.IsSolved(NewCircuit(len(committee.Pubkeys)), witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
+
+func TestHeaderJSON(t *testing.T) {
+	assert := test.NewAssert(t)
+	const header = `{"slot":"1","proposer_index":"2","parent_root":"0x01","state_root":"0x02","body_root":"0x03"}`
+	var h, wrapped Header
+	assert.NoError(json.Unmarshal([]byte(header), &h))
+	assert.NoError(json.Unmarshal([]byte(`{"beacon":`+header+`}`), &wrapped))
+	assert.Equal(Header{"1", "2", "0x01", "0x02", "0x03"}, h)
+	assert.Equal(h, wrapped)
+}
+
+func TestGroth16(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping Groth16 proof of the light client update in short mode")
+	}
+	assert := test.NewAssert(t)
+	update, committee, domain := readFixture(assert)
+	witness, err := update.Assign(committee, domain)
+	assert.NoError(err)
+
+	ccs, pk, vk, err := Setup(len(committee.Pubkeys))
+	assert.NoError(err)
+	proof, publicWitness, err := Prove(ccs, pk, witness)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+}
+
+func nativeRoot(assert *test.Assert, h Header, slot uint64) [32]byte {
+	n, err := h.native()
+	assert.NoError(err)
+	n.Slot = slot
+	return n.HashTreeRoot()
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lightclient
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// Setup compiles the circuit for a sync committee of the given size and runs
+// the Groth16 setup on BN254. The setup is not secure for production use, as
+// the toxic waste is known to the caller.
+func Setup(committeeSize int) (frontend.CompiledConstraintSystem, groth16.ProvingKey, groth16.VerifyingKey, error) {
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, NewCircuit(committeeSize))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("compile: %w", err)
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("setup: %w", err)
+	}
+	return ccs, pk, vk, nil
+}
+
+// Prove returns the Groth16 proof of the assignment, as returned by
+// [Update.Assign], with the public witness to verify it.
+func Prove(ccs frontend.CompiledConstraintSystem, pk groth16.ProvingKey, assignment *Circuit) (groth16.Proof, *witness.Witness, error) {
+	w, err := frontend.NewWitness(assignment, ecc.BN254)
+	if err != nil {
+		return nil, nil, fmt.Errorf("witness: %w", err)
+	}
+	proof, err := groth16.Prove(ccs, pk, w)
+	if err != nil {
+		return nil, nil, fmt.Errorf("prove: %w", err)
+	}
+	publicWitness, err := w.Public()
+	if err != nil {
+		return nil, nil, fmt.Errorf("public witness: %w", err)
+	}
+	return proof, publicWitness, nil
+}
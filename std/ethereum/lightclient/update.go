@@ -0,0 +1,268 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lightclient
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/std/ethereum/ssz"
+)
+
+// DomainSyncCommittee is the domain type of the signatures of the sync
+// committee.
+var DomainSyncCommittee = [4]byte{0x07, 0x00, 0x00, 0x00}
+
+// Update is the JSON encoding of an Altair LightClientUpdate, as returned by
+// the light client endpoints of the beacon node API. The numbers are decimal
+// strings and the bytes are hex strings prefixed with 0x.
+type Update struct {
+	AttestedHeader          Header        `json:"attested_header"`
+	NextSyncCommittee       SyncCommittee `json:"next_sync_committee"`
+	NextSyncCommitteeBranch []string      `json:"next_sync_committee_branch"`
+	FinalizedHeader         Header        `json:"finalized_header"`
+	FinalityBranch          []string      `json:"finality_branch"`
+	SyncAggregate           SyncAggregate `json:"sync_aggregate"`
+	SignatureSlot           string        `json:"signature_slot"`
+}
+
+// Header is the JSON encoding of a BeaconBlockHeader. The header may also be
+// wrapped in the "beacon" field of a LightClientHeader.
+type Header struct {
+	Slot          string `json:"slot"`
+	ProposerIndex string `json:"proposer_index"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+	BodyRoot      string `json:"body_root"`
+}
+
+// SyncCommittee is the JSON encoding of a SyncCommittee.
+type SyncCommittee struct {
+	Pubkeys         []string `json:"pubkeys"`
+	AggregatePubkey string   `json:"aggregate_pubkey"`
+}
+
+// SyncAggregate is the JSON encoding of a SyncAggregate.
+type SyncAggregate struct {
+	SyncCommitteeBits      string `json:"sync_committee_bits"`
+	SyncCommitteeSignature string `json:"sync_committee_signature"`
+}
+
+// UnmarshalJSON decodes a BeaconBlockHeader or a LightClientHeader.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type header Header
+	var wrapped struct {
+		Beacon *header `json:"beacon"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return err
+	}
+	if wrapped.Beacon != nil {
+		*h = Header(*wrapped.Beacon)
+		return nil
+	}
+	return json.Unmarshal(data, (*header)(h))
+}
+
+// ParseUpdate parses the JSON encoding of a LightClientUpdate. The input is
+// either the update or the response of the beacon node API, where the update
+// is in the "data" field.
+func ParseUpdate(data []byte) (*Update, error) {
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Data) != 0 {
+		data = response.Data
+	}
+	var res Update
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ComputeDomain returns the signature domain of the sync committee for the
+// given fork version and genesis validators root, as compute_domain.
+func ComputeDomain(forkVersion [4]byte, genesisValidatorsRoot [32]byte) [32]byte {
+	var version [32]byte
+	copy(version[:], forkVersion[:])
+	forkDataRoot := ssz.NativeHash(version, genesisValidatorsRoot)
+	var res [32]byte
+	copy(res[:4], DomainSyncCommittee[:])
+	copy(res[4:], forkDataRoot[:28])
+	return res
+}
+
+// Assign returns the assignment of the circuit verifying the update signed by
+// the committee, with the domain given by [ComputeDomain]. It returns an
+// error if the update is malformed or its size does not match the committee,
+// but does not verify it.
+func (u *Update) Assign(committee *SyncCommittee, domain [32]byte) (*Circuit, error) {
+	current, err := committee.native()
+	if err != nil {
+		return nil, fmt.Errorf("current sync committee: %w", err)
+	}
+	next, err := u.NextSyncCommittee.native()
+	if err != nil {
+		return nil, fmt.Errorf("next sync committee: %w", err)
+	}
+	attested, err := u.AttestedHeader.native()
+	if err != nil {
+		return nil, fmt.Errorf("attested header: %w", err)
+	}
+	finalized, err := u.FinalizedHeader.native()
+	if err != nil {
+		return nil, fmt.Errorf("finalized header: %w", err)
+	}
+	signatureSlot, err := strconv.ParseUint(u.SignatureSlot, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("signature slot: %w", err)
+	}
+	size := len(current.Pubkeys)
+	bits, err := decodeHex(u.SyncAggregate.SyncCommitteeBits, (size+7)/8)
+	if err != nil {
+		return nil, fmt.Errorf("sync committee bits: %w", err)
+	}
+	sig, err := decodeHex(u.SyncAggregate.SyncCommitteeSignature, bls12381.SizeOfG2AffineCompressed)
+	if err != nil {
+		return nil, fmt.Errorf("sync committee signature: %w", err)
+	}
+	var signature bls12381.G2Affine
+	if _, err := signature.SetBytes(sig); err != nil {
+		return nil, fmt.Errorf("sync committee signature: %w", err)
+	}
+
+	res := NewCircuit(size)
+	res.CurrentSyncCommitteeRoot = chunk(current.HashTreeRoot())
+	res.Domain = chunk(domain)
+	res.SignatureSlot = signatureSlot
+	res.FinalizedHeaderRoot = chunk(finalized.HashTreeRoot())
+	res.NextSyncCommitteeRoot = chunk(next.HashTreeRoot())
+	res.AttestedHeader.Assign(attested)
+	res.FinalizedHeader.Assign(finalized)
+	if err := assignBranch(res.FinalityBranch[:], u.FinalityBranch); err != nil {
+		return nil, fmt.Errorf("finality branch: %w", err)
+	}
+	if err := assignBranch(res.NextSyncCommitteeBranch[:], u.NextSyncCommitteeBranch); err != nil {
+		return nil, fmt.Errorf("next sync committee branch: %w", err)
+	}
+	res.SyncCommittee.Assign(current)
+	for i := range current.Pubkeys {
+		var pk bls12381.G1Affine
+		if _, err := pk.SetBytes(current.Pubkeys[i][:]); err != nil {
+			return nil, fmt.Errorf("public key %d: %w", i, err)
+		}
+		res.Pubkeys[i].Assign(&pk)
+		res.SyncCommitteeBits[i] = (bits[i/8] >> (i % 8)) & 1
+	}
+	res.SyncCommitteeSignature.Assign(&signature)
+	return res, nil
+}
+
+func (h *Header) native() (*ssz.NativeBeaconBlockHeader, error) {
+	var res ssz.NativeBeaconBlockHeader
+	var err error
+	if res.Slot, err = strconv.ParseUint(h.Slot, 10, 64); err != nil {
+		return nil, fmt.Errorf("slot: %w", err)
+	}
+	if res.ProposerIndex, err = strconv.ParseUint(h.ProposerIndex, 10, 64); err != nil {
+		return nil, fmt.Errorf("proposer index: %w", err)
+	}
+	for _, f := range []struct {
+		name string
+		src  string
+		dst  *[32]byte
+	}{
+		{"parent root", h.ParentRoot, &res.ParentRoot},
+		{"state root", h.StateRoot, &res.StateRoot},
+		{"body root", h.BodyRoot, &res.BodyRoot},
+	} {
+		b, err := decodeHex(f.src, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		copy(f.dst[:], b)
+	}
+	return &res, nil
+}
+
+func (c *SyncCommittee) native() (*ssz.NativeSyncCommittee, error) {
+	if len(c.Pubkeys) == 0 {
+		return nil, errors.New("no public keys")
+	}
+	res := ssz.NativeSyncCommittee{Pubkeys: make([][ssz.PubkeyLen]byte, len(c.Pubkeys))}
+	for i := range c.Pubkeys {
+		b, err := decodeHex(c.Pubkeys[i], ssz.PubkeyLen)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %w", i, err)
+		}
+		copy(res.Pubkeys[i][:], b)
+	}
+	b, err := decodeHex(c.AggregatePubkey, ssz.PubkeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate public key: %w", err)
+	}
+	copy(res.AggregatePubkey[:], b)
+	return &res, nil
+}
+
+func assignBranch(dst []ssz.Chunk, branch []string) error {
+	if len(branch) != len(dst) {
+		return fmt.Errorf("expected %d nodes, got %d", len(dst), len(branch))
+	}
+	for i := range branch {
+		b, err := decodeHex(branch[i], 32)
+		if err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+		var c [32]byte
+		copy(c[:], b)
+		dst[i] = chunk(c)
+	}
+	return nil
+}
+
+func chunk(b [32]byte) ssz.Chunk {
+	var res ssz.Chunk
+	for i := range b {
+		res[i] = b[i]
+	}
+	return res
+}
+
+// decodeHex decodes the 0x-prefixed hex string s of n bytes.
+func decodeHex(s string, n int) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, errors.New("missing 0x prefix")
+	}
+	res, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != n {
+		return nil, fmt.Errorf("expected %d bytes, got %d", n, len(res))
+	}
+	return res, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mpt
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// GetProofResponse is the result of the eth_getProof JSON-RPC method, as
+// specified by EIP-1186. The fields are hex strings prefixed with 0x.
+type GetProofResponse struct {
+	Address      string         `json:"address"`
+	AccountProof []string       `json:"accountProof"`
+	Balance      string         `json:"balance"`
+	CodeHash     string         `json:"codeHash"`
+	Nonce        string         `json:"nonce"`
+	StorageHash  string         `json:"storageHash"`
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+// StorageProof is the proof of a storage slot in a GetProofResponse.
+type StorageProof struct {
+	Key   string   `json:"key"`
+	Value string   `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+// ParseGetProof parses the JSON result of an eth_getProof call. The input is
+// either the result object or the whole JSON-RPC response.
+func ParseGetProof(data []byte) (*GetProofResponse, error) {
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Result) != 0 {
+		data = response.Result
+	}
+	var res GetProofResponse
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	if len(res.AccountProof) == 0 {
+		return nil, errors.New("empty account proof")
+	}
+	return &res, nil
+}
+
+// AssignAccount returns the assignment of the proof of the account in the
+// state trie with at most maxDepth nodes, with the root of the state trie and
+// the key of the account. The root is the hash of the first node of the
+// proof, it must be checked against the state root of a block.
+func (r *GetProofResponse) AssignAccount(maxDepth int) (root, key []byte, proof Proof, err error) {
+	address, err := decodeHex(r.Address, 20)
+	if err != nil {
+		return nil, nil, Proof{}, fmt.Errorf("address: %w", err)
+	}
+	return assign(r.AccountProof, nil, address, maxDepth)
+}
+
+// AssignStorage returns the assignment of the i-th storage proof with at most
+// maxDepth nodes, with the root of the storage trie of the account and the
+// key of the slot.
+func (r *GetProofResponse) AssignStorage(i, maxDepth int) (root, key []byte, proof Proof, err error) {
+	if i < 0 || i >= len(r.StorageProof) {
+		return nil, nil, Proof{}, fmt.Errorf("no storage proof %d", i)
+	}
+	storageHash, err := decodeHex(r.StorageHash, 32)
+	if err != nil {
+		return nil, nil, Proof{}, fmt.Errorf("storage hash: %w", err)
+	}
+	slot, err := decodeHex(r.StorageProof[i].Key, 32)
+	if err != nil {
+		return nil, nil, Proof{}, fmt.Errorf("storage key: %w", err)
+	}
+	return assign(r.StorageProof[i].Proof, storageHash, slot, maxDepth)
+}
+
+// assign returns the assignment of the proof of the preimage of the key. The
+// root is the hash of the first node if it is nil.
+func assign(encodedNodes []string, root, preimage []byte, maxDepth int) ([]byte, []byte, Proof, error) {
+	if len(encodedNodes) == 0 {
+		return nil, nil, Proof{}, errors.New("empty proof")
+	}
+	if len(encodedNodes) > maxDepth {
+		return nil, nil, Proof{}, fmt.Errorf("proof has %d nodes, more than %d", len(encodedNodes), maxDepth)
+	}
+	nodes := make([][]byte, len(encodedNodes))
+	for i, n := range encodedNodes {
+		var err error
+		if nodes[i], err = decodeHex(n, 0); err != nil {
+			return nil, nil, Proof{}, fmt.Errorf("node %d: %w", i, err)
+		}
+		if len(nodes[i]) > MaxNodeLen {
+			return nil, nil, Proof{}, fmt.Errorf("node %d has %d bytes, more than %d", i, len(nodes[i]), MaxNodeLen)
+		}
+	}
+	if root == nil {
+		root = keccak256(nodes[0])
+	}
+
+	proof := Proof{Nodes: make([]Node, maxDepth), Depth: len(nodes)}
+	for i := range proof.Nodes {
+		var data []byte
+		if i < len(nodes) {
+			data = nodes[i]
+		}
+		for j := range proof.Nodes[i].Data {
+			if j < len(data) {
+				proof.Nodes[i].Data[j] = data[j]
+			} else {
+				proof.Nodes[i].Data[j] = 0
+			}
+		}
+		proof.Nodes[i].Len = len(data)
+	}
+	return root, keccak256(preimage), proof, nil
+}
+
+// decodeHex decodes a hex string prefixed with 0x. If size is not zero, the
+// result is left-padded with zeroes to size bytes.
+func decodeHex(s string, size int) ([]byte, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return b, nil
+	}
+	if len(b) > size {
+		return nil, fmt.Errorf("%d bytes, more than %d", len(b), size)
+	}
+	return append(make([]byte, size-len(b)), b...), nil
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
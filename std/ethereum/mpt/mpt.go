@@ -0,0 +1,256 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mpt provides ZKP-circuit functions to verify proofs in the
+// Merkle-Patricia tries of Ethereum, such as the ones returned by the
+// eth_getProof JSON-RPC method (EIP-1186).
+//
+// A proof is the list of the RLP-encoded nodes on the path from the root to
+// the leaf of a key. Each node is given as MaxNodeLen byte variables with its
+// actual length, and the number of nodes is a variable bounded by the length
+// of the list, so that a single circuit verifies proofs of any shape within
+// these bounds. Each node is hashed with Keccak-256 and compared to the
+// reference in its parent, or to the root for the first one.
+//
+// Only proofs of membership in the secure tries of Ethereum (the state and
+// storage tries) are supported: the keys are 32-byte Keccak-256 digests, the
+// branch nodes have no value and the child nodes are referenced by their
+// hash. Child nodes shorter than 32 bytes, which are embedded in their parent
+// instead, do not happen with 32-byte keys and are rejected.
+//
+// The assignments are built natively from eth_getProof responses with
+// [ParseGetProof].
+package mpt
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+const (
+	// KeyLen is the length of the keys in bytes.
+	KeyLen = 32
+	// MaxNodeLen is the maximal length of an encoded node. It is the length
+	// of a branch node with 16 children.
+	MaxNodeLen = 532
+	// MaxValueLen is the maximal length of a value in a leaf.
+	MaxValueLen = 128
+)
+
+// Node is an RLP-encoded trie node, padded to MaxNodeLen bytes.
+type Node struct {
+	Data [MaxNodeLen]frontend.Variable
+	Len  frontend.Variable
+}
+
+// Proof is a proof of at most len(Nodes) nodes. The first Depth nodes are
+// the path from the root to the leaf and the others are ignored.
+type Proof struct {
+	Nodes []Node
+	Depth frontend.Variable
+}
+
+// NewProof returns a proof of at most maxDepth nodes, to be used in the
+// definition of a circuit.
+func NewProof(maxDepth int) Proof {
+	return Proof{Nodes: make([]Node, maxDepth)}
+}
+
+// VerifyProof asserts that the proof is a valid proof of membership of the
+// key in the trie with the given root, both being 32 bytes. It returns the
+// value of the key, padded with zeroes to MaxValueLen bytes, and its length.
+// The value is returned as stored in the leaf, that is RLP-encoded for the
+// state and storage tries.
+func VerifyProof(api frontend.API, root, key []frontend.Variable, proof Proof) ([]frontend.Variable, frontend.Variable) {
+	if len(root) != 32 || len(key) != KeyLen {
+		panic("root and key must be 32 bytes")
+	}
+	maxDepth := len(proof.Nodes)
+	if maxDepth == 0 {
+		panic("proof has no node")
+	}
+
+	// the key as 2*KeyLen nibbles, most significant first
+	nibbles := make([]frontend.Variable, 0, 2*KeyLen)
+	for i := range key {
+		b := bits.ToBinary(api, key[i], bits.WithNbDigits(8))
+		nibbles = append(nibbles, bits.FromBinary(api, b[4:]), bits.FromBinary(api, b[:4]))
+	}
+
+	// the node i is active iff i < Depth, the last active node being the
+	// leaf. 1 <= Depth <= maxDepth.
+	depth := indicators(api, proof.Depth, maxDepth+1)
+	api.AssertIsEqual(sum(api, depth), 1)
+	api.AssertIsEqual(depth[0], 0)
+
+	value := make([]frontend.Variable, MaxValueLen)
+	for i := range value {
+		value[i] = 0
+	}
+	var length frontend.Variable = 0
+
+	expected := root
+	var pos frontend.Variable = 0 // number of nibbles of the key consumed
+	active := api.Sub(1, depth[0])
+	for i := range proof.Nodes {
+		isLast := depth[i+1]
+		remaining := window(api, nibbles, indicators(api, pos, len(nibbles)+1), len(nibbles))
+		n := verifyNode(api, &proof.Nodes[i], active, isLast, api.Sub(len(nibbles), pos), expected, remaining)
+
+		for j := range value {
+			value[j] = api.Add(value[j], api.Mul(isLast, n.value[j]))
+		}
+		length = api.Add(length, api.Mul(isLast, n.valueLen))
+
+		expected = n.child
+		pos = api.Add(pos, n.consumed)
+		active = api.Sub(active, isLast)
+	}
+
+	return value, length
+}
+
+// verifiedNode is the result of the verification of a node.
+type verifiedNode struct {
+	child    []frontend.Variable // hash of the next node
+	consumed frontend.Variable   // number of nibbles of the key consumed
+	value    []frontend.Variable // value of a leaf, padded with zeroes
+	valueLen frontend.Variable   // length of the value of a leaf
+}
+
+// verifyNode verifies the node if active is set: its hash must be expected,
+// it must be a leaf iff isLast is set and its path must match the nibbles of
+// the key not consumed yet, given with their number remaining and padded
+// with zeroes.
+func verifyNode(api frontend.API, node *Node, active, isLast, remaining frontend.Variable, expected, nibbles []frontend.Variable) verifiedNode {
+	data := node.Data[:]
+
+	digest := sha3.Keccak256VarLen(api, data, node.Len)
+	for i := range digest {
+		assertZeroIf(api, active, api.Sub(digest[i], expected[i]))
+	}
+
+	// list header, with a payload shorter than 2^16 bytes
+	b := bits.ToBinary(api, data[0], bits.WithNbDigits(8))
+	assertZeroIf(api, active, api.Sub(1, api.Mul(b[7], b[6])))
+	isLong := api.Mul(b[5], b[4], b[3])
+	lenOfLen := api.Mul(isLong, api.Sub(data[0], 0xf7))
+	assertZeroIf(api, active, api.Mul(lenOfLen, api.Sub(lenOfLen, 1), api.Sub(lenOfLen, 2)))
+	is1 := api.Mul(lenOfLen, api.Sub(2, lenOfLen))
+	is2 := api.Div(api.Mul(lenOfLen, api.Sub(lenOfLen, 1)), 2)
+	payloadLen := api.Add(
+		api.Mul(api.Sub(1, isLong), api.Sub(data[0], 0xc0)),
+		api.Mul(is1, data[1]),
+		api.Mul(is2, api.Add(api.Mul(data[1], 256), data[2])),
+	)
+	start := api.Add(1, lenOfLen)
+	assertZeroIf(api, active, api.Sub(api.Add(start, payloadLen), node.Len))
+
+	// a list of two items is a leaf or an extension node, otherwise it is a
+	// branch node
+	item0 := decodeString(api, data, indicators(api, start, 4))
+	item1 := decodeString(api, data, indicators(api, item0.end, MaxNodeLen))
+	isPair := api.IsZero(api.Sub(item1.end, node.Len))
+	pair := api.Mul(active, isPair)
+	branch := api.Sub(active, pair)
+	assertZeroIf(api, pair, api.Add(item0.bad, item1.bad))
+
+	// leaf or extension node: the first item is the hex-prefix encoded path
+	// and the second one the value or the hash of the child
+	path := window(api, data, indicators(api, item0.start, MaxNodeLen), 33)
+	flag := bits.ToBinary(api, path[0], bits.WithNbDigits(8))
+	assertZeroIf(api, pair, api.Add(flag[6], flag[7]))
+	isLeaf := flag[5]
+	isOdd := flag[4]
+	pathNibbles := []frontend.Variable{bits.FromBinary(api, flag[:4])}
+	assertZeroIf(api, pair, api.Mul(api.Sub(1, isOdd), pathNibbles[0]))
+	for _, p := range path[1:] {
+		b := bits.ToBinary(api, p, bits.WithNbDigits(8))
+		pathNibbles = append(pathNibbles, bits.FromBinary(api, b[4:]), bits.FromBinary(api, b[:4]))
+	}
+	pathLen := api.Add(api.Mul(api.Sub(item0.length, 1), 2), isOdd)
+	pathLenInd := indicators(api, pathLen, len(nibbles)+1)
+	assertZeroIf(api, pair, api.Sub(1, sum(api, pathLenInd)))
+	inPath := api.Sub(1, pathLenInd[0])
+	for j := range nibbles {
+		// the path starts at the first nibble if it is odd, at the second
+		// one otherwise
+		nibble := api.Select(isOdd, pathNibbles[j], pathNibbles[j+1])
+		assertZeroIf(api, api.Mul(pair, inPath), api.Sub(nibble, nibbles[j]))
+		if j+1 < len(pathLenInd) {
+			inPath = api.Sub(inPath, pathLenInd[j+1])
+		}
+	}
+
+	leaf := api.Mul(pair, isLeaf)
+	extension := api.Sub(pair, leaf)
+	api.AssertIsEqual(leaf, isLast)
+	assertZeroIf(api, leaf, api.Sub(pathLen, remaining))
+	assertZeroIf(api, extension, pathLenInd[0])
+	assertZeroIf(api, extension, api.Sub(item1.length, 32))
+
+	// the value, with the bytes past its length set to zero
+	value := window(api, data, indicators(api, item1.start, MaxNodeLen), MaxValueLen)
+	valueLenInd := indicators(api, item1.length, MaxValueLen+1)
+	assertZeroIf(api, leaf, api.Sub(1, sum(api, valueLenInd)))
+	inValue := api.Sub(1, valueLenInd[0])
+	child := make([]frontend.Variable, 32)
+	copy(child, value)
+	for j := range value {
+		value[j] = api.Mul(value[j], inValue)
+		if j+1 < len(valueLenInd) {
+			inValue = api.Sub(inValue, valueLenInd[j+1])
+		}
+	}
+
+	// branch node: 17 items which are the empty string or the hash of a
+	// child, the last one being empty
+	offset := start
+	offsets := make([]frontend.Variable, 16)
+	isHash := make([]frontend.Variable, 16)
+	for k := 0; k < 17; k++ {
+		prefix := window(api, data, indicators(api, offset, MaxNodeLen), 1)[0]
+		h := api.Div(api.Sub(prefix, 0x80), 32)
+		if k == 16 {
+			assertZeroIf(api, branch, h)
+			offset = api.Add(offset, 1)
+			break
+		}
+		assertZeroIf(api, branch, api.Mul(h, api.Sub(1, h)))
+		offsets[k], isHash[k] = offset, h
+		offset = api.Add(offset, 1, api.Mul(h, 32))
+	}
+	assertZeroIf(api, branch, api.Sub(offset, node.Len))
+	nibble := indicators(api, nibbles[0], 16)
+	var childOffset, childIsHash frontend.Variable = 0, 0
+	for k := range nibble {
+		childOffset = api.Add(childOffset, api.Mul(nibble[k], offsets[k]))
+		childIsHash = api.Add(childIsHash, api.Mul(nibble[k], isHash[k]))
+	}
+	assertZeroIf(api, branch, api.Sub(1, childIsHash))
+	branchChild := window(api, data, indicators(api, api.Add(childOffset, 1), MaxNodeLen), 32)
+
+	for j := range child {
+		child[j] = api.Select(isPair, child[j], branchChild[j])
+	}
+	return verifiedNode{
+		child:    child,
+		consumed: api.Select(isPair, pathLen, 1),
+		value:    value,
+		valueLen: item1.length,
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mpt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type proofCircuit struct {
+	Root   [32]frontend.Variable
+	Key    [KeyLen]frontend.Variable
+	Proof  Proof
+	Value  [MaxValueLen]frontend.Variable
+	Length frontend.Variable
+}
+
+func (c *proofCircuit) Define(api frontend.API) error {
+	value, length := VerifyProof(api, c.Root[:], c.Key[:], c.Proof)
+	for i := range value {
+		api.AssertIsEqual(value[i], c.Value[i])
+	}
+	api.AssertIsEqual(length, c.Length)
+	return nil
+}
+
+const testMaxDepth = 4
+
+func newWitness(root, key []byte, proof Proof, value []byte) *proofCircuit {
+	var w proofCircuit
+	for i := range w.Root {
+		w.Root[i] = root[i]
+		w.Key[i] = key[i]
+	}
+	w.Proof = proof
+	for i := range w.Value {
+		if i < len(value) {
+			w.Value[i] = value[i]
+		} else {
+			w.Value[i] = 0
+		}
+	}
+	w.Length = len(value)
+	return &w
+}
+
+func TestVerifyProof(t *testing.T) {
+	assert := test.NewAssert(t)
+	response, accountValue, slotValue := testGetProof(t)
+
+	res, err := ParseGetProof(response)
+	assert.NoError(err)
+
+	circuit := proofCircuit{Proof: NewProof(testMaxDepth)}
+
+	root, key, proof, err := res.AssignAccount(testMaxDepth)
+	assert.NoError(err)
+	assert.Run(func(assert *test.Assert) {
+		witness := newWitness(root, key, proof, accountValue)
+		assert.NoError(test.IsSolved(&circuit, witness, ecc.BN254, backend.UNKNOWN))
+
+		witness.Value[3] = accountValue[3] ^ 1
+		assert.Error(test.IsSolved(&circuit, witness, ecc.BN254, backend.UNKNOWN))
+	}, "account")
+
+	assert.Run(func(assert *test.Assert) {
+		otherKey := append([]byte{}, key...)
+		otherKey[31] ^= 1
+		witness := newWitness(root, otherKey, proof, accountValue)
+		assert.Error(test.IsSolved(&circuit, witness, ecc.BN254, backend.UNKNOWN))
+	}, "wrong key")
+
+	assert.Run(func(assert *test.Assert) {
+		witness := newWitness(root, key, proof, accountValue)
+		witness.Proof.Depth = proof.Depth.(int) - 1
+		assert.Error(test.IsSolved(&circuit, witness, ecc.BN254, backend.UNKNOWN))
+	}, "truncated")
+
+	root, key, proof, err = res.AssignStorage(0, testMaxDepth)
+	assert.NoError(err)
+	assert.Equal(4, proof.Depth, "branch, extension, branch and leaf")
+	assert.Run(func(assert *test.Assert) {
+		witness := newWitness(root, key, proof, slotValue)
+		assert.NoError(test.IsSolved(&circuit, witness, ecc.BN254, backend.UNKNOWN))
+
+		witness.Proof.Nodes[1].Data[5] = proof.Nodes[1].Data[5].(byte) ^ 1
+		assert.Error(test.IsSolved(&circuit, witness, ecc.BN254, backend.UNKNOWN))
+	}, "storage")
+}
+
+// testGetProof returns an eth_getProof response for an account of a state
+// trie built natively, with the proof of a storage slot, and the expected
+// values of the account and of the slot in their leaves.
+func testGetProof(t *testing.T) ([]byte, []byte, []byte) {
+	// storage trie of two slots whose keys share their first byte, so that
+	// their proofs go through an extension node, and of slots whose keys
+	// start with other nibbles
+	storage := make(map[string][]byte)
+	slotKey := func(i int) []byte {
+		var key [32]byte
+		binary.BigEndian.PutUint64(key[24:], uint64(i))
+		return key[:]
+	}
+	var slot []byte
+	var slotIndex int
+	byFirstByte := make(map[byte]int)
+	for i := 0; slot == nil; i++ {
+		h := keccak256(slotKey(i))
+		if j, ok := byFirstByte[h[0]]; ok {
+			slot, slotIndex = slotKey(i), i
+			storage[string(keccak256(slotKey(j)))] = rlpString(big.NewInt(int64(1000 + j)).Bytes())
+		}
+		byFirstByte[h[0]] = i
+	}
+	storage[string(keccak256(slot))] = rlpString(big.NewInt(int64(1000 + slotIndex)).Bytes())
+	for i := 0; len(storage) < 5; i++ {
+		if h := keccak256(slotKey(i)); h[0]>>4 != keccak256(slot)[0]>>4 {
+			storage[string(h)] = rlpString(big.NewInt(int64(1000 + i)).Bytes())
+		}
+	}
+	storageRoot, storageProof := buildTrie(storage, keccak256(slot))
+
+	// state trie
+	state := make(map[string][]byte)
+	codeHash := keccak256(nil)
+	var address, account []byte
+	for i := 0; i < 50; i++ {
+		var a [8]byte
+		binary.BigEndian.PutUint64(a[:], uint64(i))
+		addr := keccak256(a[:])[:20]
+		root := keccak256(addr)
+		if i == 17 {
+			root = storageRoot
+		}
+		value := rlpList(
+			rlpString(big.NewInt(int64(i)).Bytes()),
+			rlpString(new(big.Int).Lsh(big.NewInt(int64(i+1)), 70).Bytes()),
+			rlpString(root),
+			rlpString(codeHash),
+		)
+		state[string(keccak256(addr))] = value
+		if i == 17 {
+			address, account = addr, value
+		}
+	}
+	_, accountProof := buildTrie(state, keccak256(address))
+
+	response := struct {
+		JSONRPC string           `json:"jsonrpc"`
+		ID      int              `json:"id"`
+		Result  GetProofResponse `json:"result"`
+	}{
+		JSONRPC: "2.0",
+		ID:      1,
+		Result: GetProofResponse{
+			Address:      "0x" + hex.EncodeToString(address),
+			AccountProof: encodeHex(accountProof),
+			Balance:      "0x" + new(big.Int).Lsh(big.NewInt(18), 70).Text(16),
+			CodeHash:     "0x" + hex.EncodeToString(codeHash),
+			Nonce:        "0x11",
+			StorageHash:  "0x" + hex.EncodeToString(storageRoot),
+			StorageProof: []StorageProof{{
+				Key:   "0x" + hex.EncodeToString(slot),
+				Value: "0x" + big.NewInt(int64(1000+slotIndex)).Text(16),
+				Proof: encodeHex(storageProof),
+			}},
+		},
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data, account, storage[string(keccak256(slot))]
+}
+
+func encodeHex(nodes [][]byte) []string {
+	res := make([]string, len(nodes))
+	for i := range nodes {
+		res[i] = "0x" + hex.EncodeToString(nodes[i])
+	}
+	return res
+}
+
+func rlpString(b []byte) []byte {
+	switch {
+	case len(b) == 1 && b[0] < 0x80:
+		return b
+	case len(b) < 56:
+		return append([]byte{0x80 + byte(len(b))}, b...)
+	default:
+		l := big.NewInt(int64(len(b))).Bytes()
+		return append(append([]byte{0xb7 + byte(len(l))}, l...), b...)
+	}
+}
+
+func rlpList(items ...[]byte) []byte {
+	payload := bytes.Join(items, nil)
+	if len(payload) < 56 {
+		return append([]byte{0xc0 + byte(len(payload))}, payload...)
+	}
+	l := big.NewInt(int64(len(payload))).Bytes()
+	return append(append([]byte{0xf7 + byte(len(l))}, l...), payload...)
+}
+
+type keyValue struct {
+	nibbles []byte
+	value   []byte
+}
+
+// buildTrie returns the root of the trie with the given keys and values and
+// the proof of the target key.
+func buildTrie(m map[string][]byte, target []byte) ([]byte, [][]byte) {
+	kvs := make([]keyValue, 0, len(m))
+	for k, v := range m {
+		kvs = append(kvs, keyValue{toNibbles([]byte(k)), v})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return bytes.Compare(kvs[i].nibbles, kvs[j].nibbles) < 0 })
+	root, proof := buildNode(kvs, 0, toNibbles(target))
+	return keccak256(root), proof
+}
+
+// buildNode returns the encoding of the node of the sorted keys at the given
+// depth and the proof of the target from this node, if it is below.
+func buildNode(kvs []keyValue, depth int, target []byte) ([]byte, [][]byte) {
+	withNode := func(node []byte, proof [][]byte) ([]byte, [][]byte) {
+		if proof == nil {
+			return node, nil
+		}
+		return node, append([][]byte{node}, proof...)
+	}
+	if len(kvs) == 1 {
+		node := rlpList(rlpString(hexPrefix(kvs[0].nibbles[depth:], true)), rlpString(kvs[0].value))
+		if bytes.Equal(kvs[0].nibbles, target) {
+			return node, [][]byte{node}
+		}
+		return node, nil
+	}
+
+	first, last := kvs[0].nibbles, kvs[len(kvs)-1].nibbles
+	prefix := 0
+	for first[depth+prefix] == last[depth+prefix] {
+		prefix++
+	}
+	if prefix > 0 {
+		child, proof := buildNode(kvs, depth+prefix, target)
+		node := rlpList(rlpString(hexPrefix(first[depth:depth+prefix], false)), reference(child))
+		return withNode(node, proof)
+	}
+
+	items := make([][]byte, 17)
+	var proof [][]byte
+	for nibble := byte(0); nibble < 16; nibble++ {
+		i := sort.Search(len(kvs), func(i int) bool { return kvs[i].nibbles[depth] >= nibble })
+		j := sort.Search(len(kvs), func(i int) bool { return kvs[i].nibbles[depth] > nibble })
+		if i == j {
+			items[nibble] = rlpString(nil)
+			continue
+		}
+		child, childProof := buildNode(kvs[i:j], depth+1, target)
+		items[nibble] = reference(child)
+		if childProof != nil {
+			proof = childProof
+		}
+	}
+	items[16] = rlpString(nil)
+	return withNode(rlpList(items...), proof)
+}
+
+func reference(node []byte) []byte {
+	if len(node) < 32 {
+		panic("embedded nodes are not supported")
+	}
+	return rlpString(keccak256(node))
+}
+
+func hexPrefix(nibbles []byte, leaf bool) []byte {
+	var flag byte
+	if leaf {
+		flag = 2
+	}
+	var res []byte
+	if len(nibbles)%2 == 1 {
+		res = append(res, (flag+1)<<4|nibbles[0])
+		nibbles = nibbles[1:]
+	} else {
+		res = append(res, flag<<4)
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		res = append(res, nibbles[i]<<4|nibbles[i+1])
+	}
+	return res
+}
+
+func toNibbles(b []byte) []byte {
+	res := make([]byte, 0, 2*len(b))
+	for _, c := range b {
+		res = append(res, c>>4, c&0xf)
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mpt
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// stringHeader is the decoded header of an RLP string.
+type stringHeader struct {
+	start  frontend.Variable // offset of the payload
+	length frontend.Variable // length of the payload
+	end    frontend.Variable // offset of the next item
+	bad    frontend.Variable // zero iff the item is a string of less than 256 bytes
+}
+
+// decodeString decodes the header of the RLP string at the offset given by
+// its indicators in data. The header is not asserted to be valid, the
+// caller must assert that bad is zero when it matters.
+func decodeString(api frontend.API, data, ind []frontend.Variable) stringHeader {
+	header := window(api, data, ind, 2)
+	terms := make([]frontend.Variable, len(ind))
+	for i := range ind {
+		terms[i] = api.Mul(ind[i], i)
+	}
+	offset := sum(api, terms)
+
+	b := bits.ToBinary(api, header[0], bits.WithNbDigits(8))
+	isSingle := api.Sub(1, b[7])
+	isString := api.Sub(b[7], api.Mul(b[7], b[6]))
+	isLong := api.Mul(isString, b[5], b[4], b[3])
+	lenOfLen := api.Mul(isLong, api.Sub(header[0], 0xb7))
+
+	start := api.Add(offset, isString, isLong)
+	length := api.Add(
+		isSingle,
+		api.Mul(api.Sub(isString, isLong), api.Sub(header[0], 0x80)),
+		api.Mul(isLong, header[1]),
+	)
+	return stringHeader{
+		start:  start,
+		length: length,
+		end:    api.Add(start, length),
+		bad:    api.Add(api.Mul(b[7], b[6]), api.Mul(lenOfLen, api.Sub(lenOfLen, 1))),
+	}
+}
+
+// indicators returns the n variables [idx == i] for i in [0, n). They are
+// all zero if idx is not in this range.
+func indicators(api frontend.API, idx frontend.Variable, n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = api.IsZero(api.Sub(idx, i))
+	}
+	return res
+}
+
+// window returns the width elements of data from the offset given by its
+// indicators, the elements past the end of data being zero.
+func window(api frontend.API, data, ind []frontend.Variable, width int) []frontend.Variable {
+	res := make([]frontend.Variable, width)
+	for j := range res {
+		terms := make([]frontend.Variable, 0, len(ind))
+		for i := range ind {
+			if i+j < len(data) {
+				terms = append(terms, api.Mul(ind[i], data[i+j]))
+			}
+		}
+		res[j] = sum(api, terms)
+	}
+	return res
+}
+
+// sum returns the sum of the variables.
+func sum(api frontend.API, vs []frontend.Variable) frontend.Variable {
+	switch len(vs) {
+	case 0:
+		return 0
+	case 1:
+		return vs[0]
+	default:
+		return api.Add(vs[0], vs[1], vs[2:]...)
+	}
+}
+
+// assertZeroIf asserts that v is zero if the condition is set.
+func assertZeroIf(api frontend.API, cond, v frontend.Variable) {
+	api.AssertIsEqual(api.Mul(cond, v), 0)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssz
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// PubkeyLen is the length of a compressed BLS12-381 public key.
+const PubkeyLen = 48
+
+// BeaconBlockHeader is the BeaconBlockHeader container.
+type BeaconBlockHeader struct {
+	Slot          frontend.Variable
+	ProposerIndex frontend.Variable
+	ParentRoot    Chunk
+	StateRoot     Chunk
+	BodyRoot      Chunk
+}
+
+// HashTreeRoot returns the root of the header.
+func (h *BeaconBlockHeader) HashTreeRoot(api frontend.API) Chunk {
+	return Merkleize(api, []Chunk{
+		Uint64(api, h.Slot),
+		Uint64(api, h.ProposerIndex),
+		h.ParentRoot,
+		h.StateRoot,
+		h.BodyRoot,
+	}, 5)
+}
+
+// Assign sets the header to the native one.
+func (h *BeaconBlockHeader) Assign(n *NativeBeaconBlockHeader) {
+	h.Slot = n.Slot
+	h.ProposerIndex = n.ProposerIndex
+	h.ParentRoot = constChunk(n.ParentRoot)
+	h.StateRoot = constChunk(n.StateRoot)
+	h.BodyRoot = constChunk(n.BodyRoot)
+}
+
+// NativeBeaconBlockHeader is the BeaconBlockHeader container out of circuits.
+type NativeBeaconBlockHeader struct {
+	Slot          uint64
+	ProposerIndex uint64
+	ParentRoot    [32]byte
+	StateRoot     [32]byte
+	BodyRoot      [32]byte
+}
+
+// HashTreeRoot returns the root of the header.
+func (h *NativeBeaconBlockHeader) HashTreeRoot() [32]byte {
+	return NativeMerkleize(h.fieldRoots(), 5)
+}
+
+// Branch returns the branch of the field i of the header, whose
+// generalized index is FieldGIndex(5, i).
+func (h *NativeBeaconBlockHeader) Branch(i int) [][32]byte {
+	return NativeBranch(h.fieldRoots(), 5, i)
+}
+
+func (h *NativeBeaconBlockHeader) fieldRoots() [][32]byte {
+	return [][32]byte{
+		NativeUint64(h.Slot),
+		NativeUint64(h.ProposerIndex),
+		h.ParentRoot,
+		h.StateRoot,
+		h.BodyRoot,
+	}
+}
+
+// SyncCommittee is the SyncCommittee container, whose public keys are
+// compressed BLS12-381 points. The number of public keys is the size of the
+// committee, 512 on the mainnet.
+type SyncCommittee struct {
+	Pubkeys         [][PubkeyLen]frontend.Variable
+	AggregatePubkey [PubkeyLen]frontend.Variable
+}
+
+// NewSyncCommittee returns a committee of the given size, to be used in the
+// definition of a circuit.
+func NewSyncCommittee(size int) SyncCommittee {
+	return SyncCommittee{Pubkeys: make([][PubkeyLen]frontend.Variable, size)}
+}
+
+// HashTreeRoot returns the root of the committee.
+func (c *SyncCommittee) HashTreeRoot(api frontend.API) Chunk {
+	roots := make([]Chunk, len(c.Pubkeys))
+	for i := range c.Pubkeys {
+		roots[i] = pubkeyRoot(api, &c.Pubkeys[i])
+	}
+	return Merkleize(api, []Chunk{
+		Merkleize(api, roots, len(roots)),
+		pubkeyRoot(api, &c.AggregatePubkey),
+	}, 2)
+}
+
+// Assign sets the committee to the native one.
+func (c *SyncCommittee) Assign(n *NativeSyncCommittee) {
+	c.Pubkeys = make([][PubkeyLen]frontend.Variable, len(n.Pubkeys))
+	for i := range n.Pubkeys {
+		assignBytes(c.Pubkeys[i][:], n.Pubkeys[i][:])
+	}
+	assignBytes(c.AggregatePubkey[:], n.AggregatePubkey[:])
+}
+
+func pubkeyRoot(api frontend.API, pk *[PubkeyLen]frontend.Variable) Chunk {
+	return Merkleize(api, PackBytes(pk[:]), 2)
+}
+
+// NativeSyncCommittee is the SyncCommittee container out of circuits.
+type NativeSyncCommittee struct {
+	Pubkeys         [][PubkeyLen]byte
+	AggregatePubkey [PubkeyLen]byte
+}
+
+// HashTreeRoot returns the root of the committee.
+func (c *NativeSyncCommittee) HashTreeRoot() [32]byte {
+	roots := make([][32]byte, len(c.Pubkeys))
+	for i := range c.Pubkeys {
+		roots[i] = NativeMerkleize(NativePackBytes(c.Pubkeys[i][:]), 2)
+	}
+	return NativeMerkleize([][32]byte{
+		NativeMerkleize(roots, len(roots)),
+		NativeMerkleize(NativePackBytes(c.AggregatePubkey[:]), 2),
+	}, 2)
+}
+
+func assignBytes(dst []frontend.Variable, src []byte) {
+	for i := range dst {
+		dst[i] = src[i]
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssz
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// zeroHashes[i] is the root of a tree of depth i of zero chunks.
+var zeroHashes [65][32]byte
+
+func init() {
+	for i := 1; i < len(zeroHashes); i++ {
+		zeroHashes[i] = NativeHash(zeroHashes[i-1], zeroHashes[i-1])
+	}
+}
+
+// NativeHash returns the SHA-256 digest of the concatenation of a and b.
+func NativeHash(a, b [32]byte) [32]byte {
+	return sha256.Sum256(append(a[:], b[:]...))
+}
+
+// NativeMerkleize returns the Merkle root of the chunks padded with zero
+// chunks to the next power of two of limit, which is at least len(chunks).
+func NativeMerkleize(chunks [][32]byte, limit int) [32]byte {
+	if limit < len(chunks) {
+		panic("more chunks than the limit")
+	}
+	depth := treeDepth(limit)
+	if len(chunks) == 0 {
+		return zeroHashes[depth]
+	}
+	layer := append([][32]byte{}, chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		for i := 0; i < len(layer)/2; i++ {
+			layer[i] = NativeHash(layer[2*i], layer[2*i+1])
+		}
+		layer = layer[:len(layer)/2]
+	}
+	return layer[0]
+}
+
+// NativeBranch returns the branch of the chunk at the given index in the tree
+// of NativeMerkleize, that is the siblings from the chunk to the root. The
+// generalized index of the chunk is 2^d + index where d is the length of the
+// branch.
+func NativeBranch(chunks [][32]byte, limit, index int) [][32]byte {
+	if limit < len(chunks) || index < 0 || index >= limit {
+		panic("index out of range")
+	}
+	depth := treeDepth(limit)
+	branch := make([][32]byte, depth)
+	layer := append([][32]byte{}, chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		if sibling := index ^ 1; sibling < len(layer) {
+			branch[d] = layer[sibling]
+		} else {
+			branch[d] = zeroHashes[d]
+		}
+		for i := 0; i < len(layer)/2; i++ {
+			layer[i] = NativeHash(layer[2*i], layer[2*i+1])
+		}
+		layer = layer[:len(layer)/2]
+		index /= 2
+	}
+	return branch
+}
+
+// NativeMixInLength returns the root of a list with the given Merkle root of
+// its chunks and length.
+func NativeMixInLength(root [32]byte, length uint64) [32]byte {
+	return NativeHash(root, NativeUint64(length))
+}
+
+// NativeUint64 returns the chunk of the uint64 v.
+func NativeUint64(v uint64) [32]byte {
+	var res [32]byte
+	binary.LittleEndian.PutUint64(res[:], v)
+	return res
+}
+
+// NativePackUint64s returns the chunks of the uint64 values, four per chunk.
+func NativePackUint64s(vs []uint64) [][32]byte {
+	data := make([]byte, 8*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint64(data[8*i:], v)
+	}
+	return NativePackBytes(data)
+}
+
+// NativePackBytes returns the chunks of the bytes, the last one being padded
+// with zeroes. An empty input gives a single zero chunk.
+func NativePackBytes(data []byte) [][32]byte {
+	res := make([][32]byte, (len(data)+31)/32)
+	if len(res) == 0 {
+		res = make([][32]byte, 1)
+	}
+	for i := range res {
+		copy(res[i][:], data[32*i:])
+	}
+	return res
+}
+
+// NativeVerifyBranch reports whether the leaf is at the generalized index
+// gindex in the tree with the given root.
+func NativeVerifyBranch(leaf [32]byte, branch [][32]byte, gindex uint64, root [32]byte) bool {
+	if GIndexDepth(gindex) != len(branch) {
+		return false
+	}
+	node := leaf
+	for i := range branch {
+		if (gindex>>i)&1 == 1 {
+			node = NativeHash(branch[i], node)
+		} else {
+			node = NativeHash(node, branch[i])
+		}
+	}
+	return node == root
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ssz provides ZKP-circuit functions to compute the hash_tree_root
+// of the SSZ objects of the Ethereum consensus layer and to verify Merkle
+// branches in them.
+//
+// The objects are merkleized in 32-byte chunks, given as byte variables and
+// hashed in pairs with SHA-256. The basic types are packed in little-endian
+// order into chunks, a container is the Merkle root of the roots of its
+// fields, and the root of a list is the Merkle root of its chunks up to its
+// limit mixed in with its length. The subtrees made only of zero chunks are
+// constants and cost nothing.
+//
+// The branches are identified by generalized indices, as in the consensus
+// specification: the root has index 1 and the children of the node of index
+// i have indices 2i and 2i+1.
+//
+// The Native functions and types compute the same roots and branches out of
+// circuits, to build the assignments.
+package ssz
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	gbits "github.com/consensys/gnark/std/math/bits"
+)
+
+// Chunk is a 32-byte SSZ chunk as byte variables. It is an alias so that
+// the chunks are parsed as any array of variables in the circuits.
+type Chunk = [32]frontend.Variable
+
+// Hash returns the SHA-256 digest of the concatenation of a and b.
+func Hash(api frontend.API, a, b Chunk) Chunk {
+	data := make([]frontend.Variable, 0, 64)
+	data = append(data, a[:]...)
+	data = append(data, b[:]...)
+	var res Chunk
+	copy(res[:], sha2.Sum256(api, data))
+	return res
+}
+
+// Merkleize returns the Merkle root of the chunks padded with zero chunks to
+// the next power of two of limit, which is at least len(chunks).
+func Merkleize(api frontend.API, chunks []Chunk, limit int) Chunk {
+	if limit < len(chunks) {
+		panic("more chunks than the limit")
+	}
+	depth := treeDepth(limit)
+	if len(chunks) == 0 {
+		return constChunk(zeroHashes[depth])
+	}
+	layer := append([]Chunk{}, chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, constChunk(zeroHashes[d]))
+		}
+		for i := 0; i < len(layer)/2; i++ {
+			layer[i] = Hash(api, layer[2*i], layer[2*i+1])
+		}
+		layer = layer[:len(layer)/2]
+	}
+	return layer[0]
+}
+
+// MixInLength returns the root of a list with the given Merkle root of its
+// chunks and length, of at most 64 bits.
+func MixInLength(api frontend.API, root Chunk, length frontend.Variable) Chunk {
+	return Hash(api, root, Uint64(api, length))
+}
+
+// Uint64 returns the chunk of the uint64 v, which is asserted to fit in 64
+// bits.
+func Uint64(api frontend.API, v frontend.Variable) Chunk {
+	return PackUint64s(api, []frontend.Variable{v})[0]
+}
+
+// PackUint64s returns the chunks of the uint64 values, four per chunk in
+// little-endian order. The values are asserted to fit in 64 bits.
+func PackUint64s(api frontend.API, vs []frontend.Variable) []Chunk {
+	data := make([]frontend.Variable, 0, 8*len(vs))
+	for _, v := range vs {
+		b := gbits.ToBinary(api, v, gbits.WithNbDigits(64))
+		for i := 0; i < 8; i++ {
+			data = append(data, gbits.FromBinary(api, b[8*i:8*i+8]))
+		}
+	}
+	return PackBytes(data)
+}
+
+// PackBytes returns the chunks of the bytes, the last one being padded with
+// zeroes. An empty input gives a single zero chunk.
+func PackBytes(data []frontend.Variable) []Chunk {
+	res := make([]Chunk, (len(data)+31)/32)
+	if len(res) == 0 {
+		res = make([]Chunk, 1)
+	}
+	for i := range res {
+		for j := range res[i] {
+			if k := 32*i + j; k < len(data) {
+				res[i][j] = data[k]
+			} else {
+				res[i][j] = 0
+			}
+		}
+	}
+	return res
+}
+
+// VerifyBranch asserts that the leaf is at the generalized index gindex in
+// the tree with the given root. The branch is the list of the siblings from
+// the leaf to the root.
+func VerifyBranch(api frontend.API, leaf Chunk, branch []Chunk, gindex uint64, root Chunk) {
+	if GIndexDepth(gindex) != len(branch) {
+		panic("branch length does not match the generalized index")
+	}
+	node := leaf
+	for i := range branch {
+		if (gindex>>i)&1 == 1 {
+			node = Hash(api, branch[i], node)
+		} else {
+			node = Hash(api, node, branch[i])
+		}
+	}
+	for i := range node {
+		api.AssertIsEqual(node[i], root[i])
+	}
+}
+
+// FieldGIndex returns the generalized index of the field i in a container
+// of nbFields fields.
+func FieldGIndex(nbFields, i int) uint64 {
+	return uint64(1)<<treeDepth(nbFields) + uint64(i)
+}
+
+// ConcatGIndices returns the generalized index of a node given by the path
+// of generalized indices, each in the subtree rooted at the previous one, as
+// concat_generalized_indices in the consensus specification.
+func ConcatGIndices(gindices ...uint64) uint64 {
+	res := uint64(1)
+	for _, g := range gindices {
+		d := GIndexDepth(g)
+		res = res<<d | (g ^ uint64(1)<<d)
+	}
+	return res
+}
+
+// GIndexDepth returns the depth of the generalized index, that is the length
+// of its branches.
+func GIndexDepth(gindex uint64) int {
+	if gindex == 0 {
+		panic("generalized indices start at 1")
+	}
+	return bits.Len64(gindex) - 1
+}
+
+// treeDepth returns the depth of the tree of n chunks, that is the log2 of
+// the next power of two of n.
+func treeDepth(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+func constChunk(c [32]byte) Chunk {
+	var res Chunk
+	for i := range res {
+		res[i] = c[i]
+	}
+	return res
+}
@@ -0,0 +1,260 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssz
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+func TestZeroHashes(t *testing.T) {
+	assert := test.NewAssert(t)
+	// roots of the empty trees of the deposit contract
+	for i, h := range []string{
+		"0x0000000000000000000000000000000000000000000000000000000000000000",
+		"0xf5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b",
+		"0xdb56114e00fdd4c1f85c892bf35ac9a89289aaecb1ebd0a96cde606a748b5d71",
+		"0xc78009fdf07fc56a11f122370658a353aaa542ed63e44c4bc15ff4cd105ab33c",
+	} {
+		assert.Equal(decodeRoot(t, h), zeroHashes[i])
+	}
+}
+
+func TestGIndex(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.Equal(uint64(11), FieldGIndex(5, 3))
+	assert.Equal(uint64(3), FieldGIndex(2, 1))
+	assert.Equal(uint64(517), ConcatGIndices(2, 256+5))
+	assert.Equal(uint64(105), ConcatGIndices(FieldGIndex(25, 20), FieldGIndex(2, 1)))
+	assert.Equal(9, GIndexDepth(517))
+}
+
+type headerCircuit struct {
+	Header BeaconBlockHeader
+	Root   Chunk
+}
+
+func (c *headerCircuit) Define(api frontend.API) error {
+	root := c.Header.HashTreeRoot(api)
+	for i := range root {
+		api.AssertIsEqual(root[i], c.Root[i])
+	}
+	return nil
+}
+
+type branchCircuit struct {
+	Leaf   Chunk
+	Branch []Chunk
+	Root   Chunk
+	gindex uint64
+}
+
+func (c *branchCircuit) Define(api frontend.API) error {
+	VerifyBranch(api, c.Leaf, c.Branch, c.gindex, c.Root)
+	return nil
+}
+
+func newBranchCircuits(leaf [32]byte, branch [][32]byte, gindex uint64, root [32]byte) (*branchCircuit, *branchCircuit) {
+	circuit := &branchCircuit{Branch: make([]Chunk, len(branch)), gindex: gindex}
+	witness := &branchCircuit{Leaf: constChunk(leaf), Branch: make([]Chunk, len(branch)), Root: constChunk(root)}
+	for i := range branch {
+		witness.Branch[i] = constChunk(branch[i])
+	}
+	return circuit, witness
+}
+
+func TestBeaconBlockHeader(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var vector struct {
+		Value struct {
+			Slot          string `json:"slot"`
+			ProposerIndex string `json:"proposer_index"`
+			ParentRoot    string `json:"parent_root"`
+			StateRoot     string `json:"state_root"`
+			BodyRoot      string `json:"body_root"`
+		} `json:"value"`
+		Root string `json:"root"`
+	}
+	readTestData(t, "BeaconBlockHeader.json", &vector)
+	header := NativeBeaconBlockHeader{
+		Slot:          parseUint64(t, vector.Value.Slot),
+		ProposerIndex: parseUint64(t, vector.Value.ProposerIndex),
+		ParentRoot:    decodeRoot(t, vector.Value.ParentRoot),
+		StateRoot:     decodeRoot(t, vector.Value.StateRoot),
+		BodyRoot:      decodeRoot(t, vector.Value.BodyRoot),
+	}
+	root := decodeRoot(t, vector.Root)
+	assert.Equal(root, header.HashTreeRoot())
+
+	var witness headerCircuit
+	witness.Header.Assign(&header)
+	witness.Root = constChunk(root)
+	assert.NoError(test.IsSolved(&headerCircuit{}, &witness, ecc.BN254, backend.UNKNOWN))
+	witness.Header.Slot = header.Slot + 1
+	assert.Error(test.IsSolved(&headerCircuit{}, &witness, ecc.BN254, backend.UNKNOWN))
+
+	// branch of the state root
+	gindex := FieldGIndex(5, 3)
+	branch := header.Branch(3)
+	assert.True(NativeVerifyBranch(header.StateRoot, branch, gindex, root))
+	circuit, w := newBranchCircuits(header.StateRoot, branch, gindex, root)
+	assert.NoError(test.IsSolved(circuit, w, ecc.BN254, backend.UNKNOWN))
+	circuit, w = newBranchCircuits(header.BodyRoot, branch, gindex, root)
+	assert.Error(test.IsSolved(circuit, w, ecc.BN254, backend.UNKNOWN))
+}
+
+type syncCommitteeCircuit struct {
+	Committee SyncCommittee
+	Root      Chunk
+}
+
+func (c *syncCommitteeCircuit) Define(api frontend.API) error {
+	root := c.Committee.HashTreeRoot(api)
+	for i := range root {
+		api.AssertIsEqual(root[i], c.Root[i])
+	}
+	return nil
+}
+
+func TestSyncCommittee(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var vector struct {
+		Value struct {
+			Pubkeys         []string `json:"pubkeys"`
+			AggregatePubkey string   `json:"aggregate_pubkey"`
+		} `json:"value"`
+		Root string `json:"root"`
+	}
+	readTestData(t, "SyncCommittee.json", &vector)
+	var committee NativeSyncCommittee
+	committee.Pubkeys = make([][PubkeyLen]byte, len(vector.Value.Pubkeys))
+	for i, pk := range vector.Value.Pubkeys {
+		copy(committee.Pubkeys[i][:], decodeHex(t, pk))
+	}
+	copy(committee.AggregatePubkey[:], decodeHex(t, vector.Value.AggregatePubkey))
+	root := decodeRoot(t, vector.Root)
+	assert.Equal(root, committee.HashTreeRoot())
+
+	circuit := syncCommitteeCircuit{Committee: NewSyncCommittee(len(committee.Pubkeys))}
+	var witness syncCommitteeCircuit
+	witness.Committee.Assign(&committee)
+	witness.Root = constChunk(root)
+	assert.NoError(test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN))
+	witness.Committee.Pubkeys[5][0] = committee.Pubkeys[5][0] ^ 1
+	assert.Error(test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN))
+}
+
+// testListLimit is the limit of the list of uint64 of the test vector, whose
+// values fit in testListLimit/4 chunks.
+const testListLimit = 1024
+
+type listCircuit struct {
+	Values [40]frontend.Variable
+	Length frontend.Variable
+	Root   Chunk
+}
+
+func (c *listCircuit) Define(api frontend.API) error {
+	// the values past the length are zero
+	root := MixInLength(api, Merkleize(api, PackUint64s(api, c.Values[:]), testListLimit/4), c.Length)
+	for i := range root {
+		api.AssertIsEqual(root[i], c.Root[i])
+	}
+	return nil
+}
+
+func TestList(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	var vector struct {
+		Value []string `json:"value"`
+		Root  string   `json:"root"`
+	}
+	readTestData(t, "Uint64List.json", &vector)
+	values := make([]uint64, len(vector.Value))
+	for i := range values {
+		values[i] = parseUint64(t, vector.Value[i])
+	}
+	chunks := NativePackUint64s(values)
+	root := decodeRoot(t, vector.Root)
+	assert.Equal(root, NativeMixInLength(NativeMerkleize(chunks, testListLimit/4), uint64(len(values))))
+
+	var witness listCircuit
+	for i := range witness.Values {
+		witness.Values[i] = 0
+		if i < len(values) {
+			witness.Values[i] = values[i]
+		}
+	}
+	witness.Length = len(values)
+	witness.Root = constChunk(root)
+	assert.NoError(test.IsSolved(&listCircuit{}, &witness, ecc.BN254, backend.UNKNOWN))
+	witness.Length = len(values) + 1
+	assert.Error(test.IsSolved(&listCircuit{}, &witness, ecc.BN254, backend.UNKNOWN))
+
+	// branch of the chunk 5, in the left subtree of the length mix-in
+	gindex := ConcatGIndices(2, uint64(testListLimit/4+5))
+	branch := append(NativeBranch(chunks, testListLimit/4, 5), NativeUint64(uint64(len(values))))
+	assert.True(NativeVerifyBranch(chunks[5], branch, gindex, root))
+	circuit, w := newBranchCircuits(chunks[5], branch, gindex, root)
+	assert.NoError(test.IsSolved(circuit, w, ecc.BN254, backend.UNKNOWN))
+}
+
+func readTestData(t *testing.T, name string, v interface{}) {
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func decodeRoot(t *testing.T, s string) [32]byte {
+	var res [32]byte
+	if copy(res[:], decodeHex(t, s)) != 32 {
+		t.Fatal("root is not 32 bytes")
+	}
+	return res
+}
+
+func parseUint64(t *testing.T, s string) uint64 {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
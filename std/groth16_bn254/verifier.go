@@ -0,0 +1,327 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package groth16_bn254 provides a ZKP-circuit function to verify BN254
+// Groth16 proofs inside a BN254 circuit.
+//
+// As the base field of BN254 differs from its scalar field, the pairing is
+// computed over emulated fields (see package
+// [github.com/consensys/gnark/std/algebra/sw_bn254]). The public inputs of
+// the inner proof are elements of the scalar field of BN254 and are given as
+// native variables.
+//
+// If the inner circuit commits to wires with [frontend.Committer], then the
+// proof carries the commitment and its proof of knowledge. The verifying key
+// and the proof must then be defined with [NewVerifyingKeyWithCommitment]
+// and [NewProofWithCommitment], and the commitment is verified as the native
+// verifier does.
+package groth16_bn254
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/compiled"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"github.com/consensys/gnark/std/algebra/sw_bn254"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Proof represents a Groth16 proof
+// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
+type Proof struct {
+	Ar, Krs sw_bn254.G1Affine
+	Bs      sw_bn254.G2Affine
+
+	// Commitment and CommitmentPok are set if the inner circuit commits to
+	// wires, see frontend.Committer
+	Commitment, CommitmentPok sw_bn254.G1Affine
+}
+
+// VerifyingKey represents a Groth16 verifying key
+// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
+type VerifyingKey struct {
+	// e(α, β)
+	E sw_bn254.GTEl
+
+	// -[γ]2, -[δ]2
+	G2 struct {
+		GammaNeg, DeltaNeg sw_bn254.G2Affine
+	}
+
+	// [Kvk]1
+	G1 struct {
+		K []sw_bn254.G1Affine // The indexes correspond to the public wires
+	}
+
+	// [1]2, -[σ]2 to verify the knowledge of the committed values
+	CommitmentKey struct {
+		G, GSigmaNeg sw_bn254.G2Affine
+	}
+	// CommitmentInfo gives the committed public wires. It is set when the
+	// circuit is defined and is not part of the witness.
+	CommitmentInfo compiled.CommitmentInfo `gnark:"-"`
+}
+
+// NewProof returns a proof with allocated limbs. It is used to define the
+// proof in a circuit structure.
+func NewProof() Proof {
+	return Proof{
+		Ar:  sw_bn254.NewG1Affine(),
+		Krs: sw_bn254.NewG1Affine(),
+		Bs:  sw_bn254.NewG2Affine(),
+	}
+}
+
+// NewProofWithCommitment returns a proof with allocated limbs, including the
+// commitment and its proof of knowledge. It is used to define the proof of an
+// inner circuit which commits to wires in a circuit structure.
+func NewProofWithCommitment() Proof {
+	p := NewProof()
+	p.Commitment = sw_bn254.NewG1Affine()
+	p.CommitmentPok = sw_bn254.NewG1Affine()
+	return p
+}
+
+// NewVerifyingKey returns a verifying key with allocated limbs for an inner
+// circuit with nbPublicInputs public inputs (not counting the ONE_WIRE). It
+// is used to define the verifying key in a circuit structure.
+func NewVerifyingKey(nbPublicInputs int) VerifyingKey {
+	var vk VerifyingKey
+	vk.E = sw_bn254.NewGTEl()
+	vk.G2.GammaNeg = sw_bn254.NewG2Affine()
+	vk.G2.DeltaNeg = sw_bn254.NewG2Affine()
+	vk.G1.K = make([]sw_bn254.G1Affine, nbPublicInputs+1)
+	for i := range vk.G1.K {
+		vk.G1.K[i] = sw_bn254.NewG1Affine()
+	}
+	return vk
+}
+
+// NewVerifyingKeyWithCommitment returns a verifying key with allocated limbs
+// for an inner circuit with nbPublicInputs public inputs (not counting the
+// ONE_WIRE nor the commitment) which commits to wires as described by
+// commitmentInfo. It is used to define the verifying key in a circuit
+// structure.
+func NewVerifyingKeyWithCommitment(nbPublicInputs int, commitmentInfo compiled.CommitmentInfo) VerifyingKey {
+	// the commitment is the last public input of the inner circuit
+	vk := NewVerifyingKey(nbPublicInputs + 1)
+	vk.CommitmentKey.G = sw_bn254.NewG2Affine()
+	vk.CommitmentKey.GSigmaNeg = sw_bn254.NewG2Affine()
+	vk.CommitmentInfo = commitmentInfo
+	return vk
+}
+
+// Verify implements the verification function of Groth16.
+// Notation follows Figure 4. in DIZK paper https://eprint.iacr.org/2018/691.pdf
+// publicInputs do NOT contain the ONE_WIRE
+//
+// It asserts that the points of the proof are on the curve and that Bs is in
+// G2, as G1 has no cofactor. It returns an error if the circuit is not
+// defined over BN254 or if the number of public inputs does not match the
+// verifying key.
+func Verify(api frontend.API, vk VerifyingKey, proof Proof, publicInputs []frontend.Variable) error {
+	if api.Compiler().Curve() != ecc.BN254 {
+		return fmt.Errorf("outer circuit must be defined over %s, got %s", ecc.BN254, api.Compiler().Curve())
+	}
+	if len(vk.G1.K) == 0 {
+		return errors.New("inner verifying key needs at least one point; VerifyingKey.G1 must be initialized before compiling circuit")
+	}
+	nbPublicInputs := len(vk.G1.K) - 1
+	if vk.CommitmentInfo.Is() {
+		nbPublicInputs-- // the commitment is not given by the caller
+	}
+	if len(publicInputs) != nbPublicInputs {
+		return fmt.Errorf("invalid number of public inputs, got %d, expected %d", len(publicInputs), nbPublicInputs)
+	}
+	pr, err := sw_bn254.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+	g1, err := sw_bn254.NewG1(api)
+	if err != nil {
+		return fmt.Errorf("new G1: %w", err)
+	}
+
+	// check that the points in the proof are in the correct subgroup
+	g1.AssertIsOnCurve((*weierstrass.AffinePoint[emulated.BN254Fp])(&proof.Ar))
+	g1.AssertIsOnCurve((*weierstrass.AffinePoint[emulated.BN254Fp])(&proof.Krs))
+	pr.G2().AssertIsOnCurve(&proof.Bs)
+	pr.G2().AssertIsInSubgroup(&proof.Bs)
+
+	// the commitment is a public input derived from the committed values
+	if vk.CommitmentInfo.Is() {
+		g1.AssertIsOnCurve((*weierstrass.AffinePoint[emulated.BN254Fp])(&proof.Commitment))
+		g1.AssertIsOnCurve((*weierstrass.AffinePoint[emulated.BN254Fp])(&proof.CommitmentPok))
+
+		// check e(D, -[σ]2) e(Π, [1]2) == 1, that is, the prover knows the committed values
+		if err := pr.PairingCheck(
+			[]*sw_bn254.G1Affine{&proof.Commitment, &proof.CommitmentPok},
+			[]*sw_bn254.G2Affine{&vk.CommitmentKey.GSigmaNeg, &vk.CommitmentKey.G},
+		); err != nil {
+			return fmt.Errorf("commitment proof of knowledge: %w", err)
+		}
+
+		publicCommitted := make([]frontend.Variable, vk.CommitmentInfo.NbPublicCommitted())
+		for i, wID := range vk.CommitmentInfo.PublicCommitted() {
+			publicCommitted[i] = publicInputs[wID-1]
+		}
+		h, err := commitmentHash(api, g1, &proof.Commitment, publicCommitted)
+		if err != nil {
+			return err
+		}
+		publicInputs = append(publicInputs[:len(publicInputs):len(publicInputs)], h)
+	}
+
+	// compute kSum = Σx.[Kvk(t)]1
+	kSum := (*weierstrass.AffinePoint[emulated.BN254Fp])(&vk.G1.K[0])
+	for k, v := range publicInputs {
+		kSum = addScalarMul(api, g1, kSum, (*weierstrass.AffinePoint[emulated.BN254Fp])(&vk.G1.K[k+1]), v)
+	}
+	if vk.CommitmentInfo.Is() {
+		// the proof of knowledge binds D to the commitment key, so that its
+		// discrete logarithm in base kSum is not known either
+		kSum = g1.Add(kSum, (*weierstrass.AffinePoint[emulated.BN254Fp])(&proof.Commitment))
+	}
+
+	// compute e(Σx.[Kvk(t)]1, -[γ]2) * e(Krs,δ) * e(Ar,Bs)
+	pairing, err := pr.Pair(
+		[]*sw_bn254.G1Affine{(*sw_bn254.G1Affine)(kSum), &proof.Krs, &proof.Ar},
+		[]*sw_bn254.G2Affine{&vk.G2.GammaNeg, &vk.G2.DeltaNeg, &proof.Bs},
+	)
+	if err != nil {
+		return err
+	}
+
+	// vk.E must be equal to pairing
+	pr.AssertIsEqual(pairing, &vk.E)
+	return nil
+}
+
+// addScalarMul returns acc + [s]p for the native scalar s using the
+// double-and-add algorithm. The result is correct for all s, including zero,
+// as the accumulator is not the point at infinity. The incomplete additions
+// get equal inputs only if the discrete logarithm of p in base acc is known,
+// which is not the case for the points of a verifying key.
+func addScalarMul(api frontend.API, g1 *sw_bn254.G1, acc, p *weierstrass.AffinePoint[emulated.BN254Fp], s frontend.Variable) *weierstrass.AffinePoint[emulated.BN254Fp] {
+	sBits := bits.ToBinary(api, s)
+	for i := range sBits {
+		acc = g1.Select(sBits[i], g1.Add(acc, p), acc)
+		if i < len(sBits)-1 {
+			p = g1.Double(p)
+		}
+	}
+	return acc
+}
+
+// commitmentHash returns the hash of the commitment and of the committed public
+// inputs as computed by the native verifier: the SHA-256 digest of their
+// big-endian encodings, reduced modulo r.
+func commitmentHash(api frontend.API, g1 *sw_bn254.G1, commitment *sw_bn254.G1Affine, publicCommitted []frontend.Variable) (frontend.Variable, error) {
+	fr, err := emulated.NewField[emulated.BN254Fr](api)
+	if err != nil {
+		return nil, fmt.Errorf("new scalar field: %w", err)
+	}
+	data := toBytes(api, g1.BaseField().ToBits(&commitment.X))
+	data = append(data, toBytes(api, g1.BaseField().ToBits(&commitment.Y))...)
+	for i := range publicCommitted {
+		// the value must be encoded canonically, decompose it as an element
+		// of the emulated scalar field
+		v := fr.FromBits(bits.ToBinary(api, publicCommitted[i])...)
+		data = append(data, toBytes(api, fr.ToBits(v))...)
+	}
+	digest := sha2.Sum256(api, data)
+
+	// the native arithmetic reduces the digest modulo r
+	res := frontend.Variable(0)
+	for i := range digest {
+		res = api.Add(api.Mul(res, 256), digest[i])
+	}
+	return res, nil
+}
+
+// toBytes returns the 32 bytes in big-endian order of the value given by its
+// little-endian bits.
+func toBytes(api frontend.API, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, 32)
+	for i := range res {
+		byteBits := make([]frontend.Variable, 8)
+		for j := range byteBits {
+			if k := 8*(31-i) + j; k < len(b) {
+				byteBits[j] = b[k]
+			} else {
+				byteBits[j] = 0
+			}
+		}
+		res[i] = bits.FromBinary(api, byteBits, bits.WithUnconstrainedInputs())
+	}
+	return res
+}
+
+// Assign values to the "in-circuit" VerifyingKey from a "out-of-circuit" VerifyingKey
+func (vk *VerifyingKey) Assign(_ovk groth16.VerifyingKey) {
+	ovk, ok := _ovk.(*groth16_bn254.VerifyingKey)
+	if !ok {
+		panic("expected *groth16_bn254.VerifyingKey, got " + reflect.TypeOf(_ovk).String())
+	}
+
+	e, err := bn254.Pair([]bn254.G1Affine{ovk.G1.Alpha}, []bn254.G2Affine{ovk.G2.Beta})
+	if err != nil {
+		panic(err)
+	}
+	vk.E.Assign(&e)
+
+	vk.G1.K = make([]sw_bn254.G1Affine, len(ovk.G1.K))
+	for i := 0; i < len(ovk.G1.K); i++ {
+		vk.G1.K[i].Assign(&ovk.G1.K[i])
+	}
+	var deltaNeg, gammaNeg bn254.G2Affine
+	deltaNeg.Neg(&ovk.G2.Delta)
+	gammaNeg.Neg(&ovk.G2.Gamma)
+	vk.G2.DeltaNeg.Assign(&deltaNeg)
+	vk.G2.GammaNeg.Assign(&gammaNeg)
+
+	if ovk.CommitmentInfo.Is() {
+		vk.CommitmentKey.G.Assign(&ovk.CommitmentKey.G)
+		vk.CommitmentKey.GSigmaNeg.Assign(&ovk.CommitmentKey.GSigmaNeg)
+		vk.CommitmentInfo = ovk.CommitmentInfo
+	}
+}
+
+// Assign values to the "in-circuit" Proof from a "out-of-circuit" Proof
+func (p *Proof) Assign(_op groth16.Proof) {
+	op, ok := _op.(*groth16_bn254.Proof)
+	if !ok {
+		panic("expected *groth16_bn254.Proof, got " + reflect.TypeOf(_op).String())
+	}
+	p.Ar.Assign(&op.Ar)
+	p.Krs.Assign(&op.Krs)
+	p.Bs.Assign(&op.Bs)
+	// the commitment of a circuit which commits to wires is blinded, so that
+	// it is not the point at infinity
+	if !op.Commitment.IsInfinity() {
+		p.Commitment.Assign(&op.Commitment)
+		p.CommitmentPok.Assign(&op.CommitmentPok)
+	}
+}
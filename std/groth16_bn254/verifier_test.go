@@ -0,0 +1,229 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groth16_bn254
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	backend_bn254 "github.com/consensys/gnark/internal/backend/bn254/cs"
+	groth16_bn254 "github.com/consensys/gnark/internal/backend/bn254/groth16"
+	"github.com/consensys/gnark/internal/backend/bn254/witness"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type mimcCircuit struct {
+	PreImage frontend.Variable
+	Hash     frontend.Variable `gnark:",public"`
+}
+
+func (circuit *mimcCircuit) Define(api frontend.API) error {
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	mimc.Write(circuit.PreImage)
+	api.AssertIsEqual(mimc.Sum(), circuit.Hash)
+	return nil
+}
+
+// Prepare the data for the inner proof.
+// Returns the public hash of the inner proof
+func generateBn254InnerProof(t *testing.T, vk *groth16_bn254.VerifyingKey, proof *groth16_bn254.Proof) frontend.Variable {
+
+	// create a mock cs: knowing the preimage of a hash using mimc
+	var circuit mimcCircuit
+	r1cs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compute the hash of the preimage natively
+	var preImage fr.Element
+	preImage.SetUint64(42)
+	goMimc := hash.MIMC_BN254.New()
+	goMimc.Write(preImage.Marshal())
+	publicHash := goMimc.Sum(nil)
+
+	var assignment mimcCircuit
+	assignment.PreImage = preImage
+	assignment.Hash = publicHash
+
+	var witness, publicWitness witness.Witness
+	_, err = witness.FromAssignment(&assignment, tVariable, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = publicWitness.FromAssignment(&assignment, tVariable, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// generate the data to return for the bn254 proof
+	var pk groth16_bn254.ProvingKey
+	if err := groth16_bn254.Setup(r1cs.(*backend_bn254.R1CS), &pk, vk); err != nil {
+		t.Fatal(err)
+	}
+
+	_proof, err := groth16_bn254.Prove(r1cs.(*backend_bn254.R1CS), &pk, witness, backend.ProverConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	*proof = *_proof
+
+	// before returning verifies that the proof passes on bn254
+	if err := groth16_bn254.Verify(proof, vk, publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	return publicHash
+}
+
+type verifierCircuit struct {
+	InnerProof Proof
+	InnerVk    VerifyingKey
+	Hash       frontend.Variable
+}
+
+func (circuit *verifierCircuit) Define(api frontend.API) error {
+	// create the verifier cs
+	return Verify(api, circuit.InnerVk, circuit.InnerProof, []frontend.Variable{circuit.Hash})
+}
+
+func TestVerifier(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	// get the data
+	var innerVk groth16_bn254.VerifyingKey
+	var innerProof groth16_bn254.Proof
+	hash := generateBn254InnerProof(t, &innerVk, &innerProof)
+
+	// create an empty cs
+	circuit := verifierCircuit{
+		InnerProof: NewProof(),
+		InnerVk:    NewVerifyingKey(len(innerVk.G1.K) - 1),
+	}
+
+	// create assignment, the private part consists of the proof,
+	// the public part is exactly the public part of the inner proof,
+	// up to the renaming of the inner ONE_WIRE to not conflict with the one wire of the outer proof.
+	var witness verifierCircuit
+	witness.InnerProof.Assign(&innerProof)
+	witness.InnerVk.Assign(&innerVk)
+	witness.Hash = hash
+
+	err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+
+	// the proof is not valid for another public input
+	witness.Hash = 43
+	err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
+
+// commitCircuit checks that {X, Y} and {A, B} are equal as multisets by
+// evaluating both products at a random point derived from the commitment.
+type commitCircuit struct {
+	X    frontend.Variable `gnark:",public"`
+	Y    frontend.Variable
+	A, B frontend.Variable
+}
+
+func (c *commitCircuit) Define(api frontend.API) error {
+	committer, ok := api.(frontend.Committer)
+	if !ok {
+		return errors.New("compiler doesn't implement frontend.Committer")
+	}
+	r := committer.Commit(c.X, c.Y, c.A, c.B)
+	left := api.Mul(api.Sub(r, c.X), api.Sub(r, c.Y))
+	right := api.Mul(api.Sub(r, c.A), api.Sub(r, c.B))
+	api.AssertIsEqual(left, right)
+	return nil
+}
+
+type commitVerifierCircuit struct {
+	InnerProof Proof
+	InnerVk    VerifyingKey
+	X          frontend.Variable
+}
+
+func (circuit *commitVerifierCircuit) Define(api frontend.API) error {
+	return Verify(api, circuit.InnerVk, circuit.InnerProof, []frontend.Variable{circuit.X})
+}
+
+func TestVerifierCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &commitCircuit{})
+	assert.NoError(err)
+	var innerPk groth16_bn254.ProvingKey
+	var innerVk groth16_bn254.VerifyingKey
+	assert.NoError(groth16_bn254.Setup(ccs.(*backend_bn254.R1CS), &innerPk, &innerVk))
+	assert.True(innerVk.CommitmentInfo.Is())
+
+	var w, publicW witness.Witness
+	_, err = w.FromAssignment(&commitCircuit{X: 1, Y: 2, A: 2, B: 1}, tVariable, false)
+	assert.NoError(err)
+	_, err = publicW.FromAssignment(&commitCircuit{X: 1}, tVariable, true)
+	assert.NoError(err)
+	innerProof, err := groth16_bn254.Prove(ccs.(*backend_bn254.R1CS), &innerPk, w, backend.ProverConfig{})
+	assert.NoError(err)
+	assert.NoError(groth16_bn254.Verify(innerProof, &innerVk, publicW))
+
+	circuit := commitVerifierCircuit{
+		InnerProof: NewProofWithCommitment(),
+		InnerVk:    NewVerifyingKeyWithCommitment(len(publicW), innerVk.CommitmentInfo),
+	}
+	var assignment commitVerifierCircuit
+	assignment.InnerProof.Assign(innerProof)
+	assignment.InnerVk.Assign(&innerVk)
+	assignment.X = 1
+	assert.NoError(test.IsSolved(&circuit, &assignment, ecc.BN254, backend.UNKNOWN))
+
+	// the commitment hash depends on the committed public input
+	assignment.X = 2
+	assert.Error(test.IsSolved(&circuit, &assignment, ecc.BN254, backend.UNKNOWN))
+	assignment.X = 1
+
+	// doubling the commitment keeps a valid proof of knowledge but changes the
+	// hash
+	var tampered groth16_bn254.Proof = *innerProof
+	tampered.Commitment.Add(&tampered.Commitment, &tampered.Commitment)
+	tampered.CommitmentPok.Add(&tampered.CommitmentPok, &tampered.CommitmentPok)
+	assignment.InnerProof.Assign(&tampered)
+	assert.Error(test.IsSolved(&circuit, &assignment, ecc.BN254, backend.UNKNOWN))
+
+	// a commitment without matching proof of knowledge is rejected
+	tampered = *innerProof
+	tampered.CommitmentPok.Add(&tampered.CommitmentPok, &tampered.Commitment)
+	assignment.InnerProof.Assign(&tampered)
+	assert.Error(test.IsSolved(&circuit, &assignment, ecc.BN254, backend.UNKNOWN))
+}
+
+var tVariable reflect.Type
+
+func init() {
+	tVariable = reflect.ValueOf(struct{ A frontend.Variable }{}).FieldByName("A").Type()
+}
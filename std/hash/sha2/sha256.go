@@ -0,0 +1,230 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sha2 provides ZKP-circuit functions to compute SHA-2 digests of
+// byte strings.
+//
+// The inputs are given as slices of variables, each variable being a byte.
+// The bytes are range-checked when decomposed into bits. The digests are
+// returned as byte variables.
+package sha2
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// word is a 32-bit word given by its bits in little-endian order.
+type word [32]frontend.Variable
+
+var (
+	sha256Init = [8]uint32{
+		0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+		0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+	}
+
+	sha256K = [64]uint32{
+		0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+		0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+		0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+		0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+		0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+		0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+		0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+		0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+	}
+)
+
+// Sum256 returns the SHA-256 digest of data. Every element of data is
+// asserted to be a byte. The returned digest is 32 bytes long.
+func Sum256(api frontend.API, data []frontend.Variable) []frontend.Variable {
+	// padding: 0x80, zeros and the message length in bits as 64-bit
+	// big-endian integer so that the length is a multiple of 64 bytes.
+	padded := make([]frontend.Variable, len(data), len(data)+72)
+	copy(padded, data)
+	padded = append(padded, 0x80)
+	for len(padded)%64 != 56 {
+		padded = append(padded, 0)
+	}
+	bitLen := uint64(len(data)) * 8
+	for i := 7; i >= 0; i-- {
+		padded = append(padded, (bitLen>>(8*i))&0xff)
+	}
+
+	var h [8]word
+	for i := range h {
+		h[i] = constWord(sha256Init[i])
+	}
+	for block := 0; block < len(padded); block += 64 {
+		var w [16]word
+		for i := range w {
+			w[i] = bytesToWord(api, padded[block+4*i:block+4*i+4])
+		}
+		h = compress256(api, h, w)
+	}
+
+	res := make([]frontend.Variable, 0, 32)
+	for i := range h {
+		res = append(res, wordToBytes(api, h[i])...)
+	}
+	return res
+}
+
+// compress256 applies the SHA-256 compression function on the state h with
+// the message block w.
+func compress256(api frontend.API, h [8]word, w [16]word) [8]word {
+	var schedule [64]word
+	copy(schedule[:], w[:])
+	for t := 16; t < 64; t++ {
+		s0 := xor3(api, rotr(schedule[t-15], 7), rotr(schedule[t-15], 18), shr(schedule[t-15], 3))
+		s1 := xor3(api, rotr(schedule[t-2], 17), rotr(schedule[t-2], 19), shr(schedule[t-2], 10))
+		schedule[t] = add(api, schedule[t-16], s0, schedule[t-7], s1)
+	}
+
+	a, b, c, d, e, f, g, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+	for t := 0; t < 64; t++ {
+		S1 := xor3(api, rotr(e, 6), rotr(e, 11), rotr(e, 25))
+		t1 := add(api, hh, S1, ch(api, e, f, g), constWord(sha256K[t]), schedule[t])
+		S0 := xor3(api, rotr(a, 2), rotr(a, 13), rotr(a, 22))
+		t2 := add(api, S0, maj(api, a, b, c))
+		hh, g, f = g, f, e
+		e = add(api, d, t1)
+		d, c, b = c, b, a
+		a = add(api, t1, t2)
+	}
+
+	return [8]word{
+		add(api, h[0], a), add(api, h[1], b), add(api, h[2], c), add(api, h[3], d),
+		add(api, h[4], e), add(api, h[5], f), add(api, h[6], g), add(api, h[7], hh),
+	}
+}
+
+// constWord returns the bits of the constant v.
+func constWord(v uint32) word {
+	var res word
+	for i := range res {
+		res[i] = (v >> i) & 1
+	}
+	return res
+}
+
+// bytesToWord returns the word from the 4 bytes given in big-endian order.
+func bytesToWord(api frontend.API, b []frontend.Variable) word {
+	var res word
+	for i := 0; i < 4; i++ {
+		bb := bits.ToBinary(api, b[3-i], bits.WithNbDigits(8))
+		copy(res[8*i:8*i+8], bb)
+	}
+	return res
+}
+
+// wordToBytes returns the 4 bytes of the word in big-endian order.
+func wordToBytes(api frontend.API, w word) []frontend.Variable {
+	res := make([]frontend.Variable, 4)
+	for i := 0; i < 4; i++ {
+		res[3-i] = bits.FromBinary(api, w[8*i:8*i+8], bits.WithUnconstrainedInputs())
+	}
+	return res
+}
+
+// rotr returns the word rotated right by n bits. It does not add constraints.
+func rotr(w word, n int) word {
+	var res word
+	for i := range res {
+		res[i] = w[(i+n)%32]
+	}
+	return res
+}
+
+// shr returns the word shifted right by n bits. It does not add constraints.
+func shr(w word, n int) word {
+	var res word
+	for i := range res {
+		if i+n < 32 {
+			res[i] = w[i+n]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+// add returns the sum of the words modulo 2^32.
+func add(api frontend.API, ws ...word) word {
+	var sum frontend.Variable = 0
+	for _, w := range ws {
+		sum = api.Add(sum, bits.FromBinary(api, w[:], bits.WithUnconstrainedInputs()))
+	}
+	// the sum of n words fits into 32 + log2(n) bits
+	nbCarryBits := 0
+	for 1<<nbCarryBits < len(ws) {
+		nbCarryBits++
+	}
+	sumBits := bits.ToBinary(api, sum, bits.WithNbDigits(32+nbCarryBits))
+	var res word
+	copy(res[:], sumBits[:32])
+	return res
+}
+
+// xor3 returns the bitwise XOR of the words.
+func xor3(api frontend.API, a, b, c word) word {
+	var res word
+	for i := range res {
+		res[i] = xor(api, xor(api, a[i], b[i]), c[i])
+	}
+	return res
+}
+
+// ch returns the bitwise choice: e ? f : g.
+func ch(api frontend.API, e, f, g word) word {
+	var res word
+	for i := range res {
+		res[i] = api.Select(e[i], f[i], g[i])
+	}
+	return res
+}
+
+// maj returns the bitwise majority of the words.
+func maj(api frontend.API, a, b, c word) word {
+	var res word
+	for i := range res {
+		// maj(a, b, c) = ab + c(a + b - 2ab)
+		ab := api.Mul(a[i], b[i])
+		t := api.Sub(api.Add(a[i], b[i]), api.Mul(ab, 2))
+		res[i] = api.Add(ab, api.Mul(c[i], t))
+	}
+	return res
+}
+
+// xor returns a XOR b for boolean a and b. It does not add constraints when
+// one of the inputs is constant.
+func xor(api frontend.API, a, b frontend.Variable) frontend.Variable {
+	ca, aConst := api.Compiler().ConstantValue(a)
+	cb, bConst := api.Compiler().ConstantValue(b)
+	switch {
+	case aConst && bConst:
+		return ca.Uint64() ^ cb.Uint64()
+	case aConst && ca.Sign() == 0:
+		return b
+	case aConst:
+		return api.Sub(1, b)
+	case bConst && cb.Sign() == 0:
+		return a
+	case bConst:
+		return api.Sub(1, a)
+	}
+	return api.Xor(a, b, frontend.WithUnconstrainedInputs())
+}
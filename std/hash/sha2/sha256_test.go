@@ -0,0 +1,66 @@
+package sha2
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type sum256Circuit struct {
+	In       []frontend.Variable
+	Expected [32]frontend.Variable
+}
+
+func (c *sum256Circuit) Define(api frontend.API) error {
+	res := Sum256(api, c.In)
+	for i := range c.Expected {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func testInput(length int) []byte {
+	in := make([]byte, length)
+	for i := range in {
+		in[i] = byte(3*i + 1)
+	}
+	return in
+}
+
+func sum256Witness(in []byte) (*sum256Circuit, *sum256Circuit) {
+	expected := sha256.Sum256(in)
+	circuit := sum256Circuit{In: make([]frontend.Variable, len(in))}
+	witness := sum256Circuit{In: make([]frontend.Variable, len(in))}
+	for i := range in {
+		witness.In[i] = in[i]
+	}
+	for i := range expected {
+		witness.Expected[i] = expected[i]
+	}
+	return &circuit, &witness
+}
+
+func TestSum256(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, length := range []int{0, 3, 55, 56, 64, 100} {
+		circuit, witness := sum256Witness(testInput(length))
+		assert.Run(func(assert *test.Assert) {
+			err := test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+			assert.NoError(err)
+		}, fmt.Sprintf("length=%d", length))
+	}
+}
+
+func TestSum256Solve(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit, witness := sum256Witness(testInput(64))
+	assert.SolvingSucceeded(circuit, witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK), test.NoSerialization())
+
+	witness.Expected[0] = witness.Expected[0].(byte) ^ 1
+	assert.SolvingFailed(circuit, witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
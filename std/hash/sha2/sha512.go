@@ -0,0 +1,220 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sha2
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// dword is a 64-bit word given by its bits in little-endian order.
+type dword [64]frontend.Variable
+
+var (
+	sha512Init = [8]uint64{
+		0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+		0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+	}
+
+	sha512K = [80]uint64{
+		0x428a2f98d728ae22, 0x7137449123ef65cd, 0xb5c0fbcfec4d3b2f, 0xe9b5dba58189dbbc,
+		0x3956c25bf348b538, 0x59f111f1b605d019, 0x923f82a4af194f9b, 0xab1c5ed5da6d8118,
+		0xd807aa98a3030242, 0x12835b0145706fbe, 0x243185be4ee4b28c, 0x550c7dc3d5ffb4e2,
+		0x72be5d74f27b896f, 0x80deb1fe3b1696b1, 0x9bdc06a725c71235, 0xc19bf174cf692694,
+		0xe49b69c19ef14ad2, 0xefbe4786384f25e3, 0x0fc19dc68b8cd5b5, 0x240ca1cc77ac9c65,
+		0x2de92c6f592b0275, 0x4a7484aa6ea6e483, 0x5cb0a9dcbd41fbd4, 0x76f988da831153b5,
+		0x983e5152ee66dfab, 0xa831c66d2db43210, 0xb00327c898fb213f, 0xbf597fc7beef0ee4,
+		0xc6e00bf33da88fc2, 0xd5a79147930aa725, 0x06ca6351e003826f, 0x142929670a0e6e70,
+		0x27b70a8546d22ffc, 0x2e1b21385c26c926, 0x4d2c6dfc5ac42aed, 0x53380d139d95b3df,
+		0x650a73548baf63de, 0x766a0abb3c77b2a8, 0x81c2c92e47edaee6, 0x92722c851482353b,
+		0xa2bfe8a14cf10364, 0xa81a664bbc423001, 0xc24b8b70d0f89791, 0xc76c51a30654be30,
+		0xd192e819d6ef5218, 0xd69906245565a910, 0xf40e35855771202a, 0x106aa07032bbd1b8,
+		0x19a4c116b8d2d0c8, 0x1e376c085141ab53, 0x2748774cdf8eeb99, 0x34b0bcb5e19b48a8,
+		0x391c0cb3c5c95a63, 0x4ed8aa4ae3418acb, 0x5b9cca4f7763e373, 0x682e6ff3d6b2b8a3,
+		0x748f82ee5defb2fc, 0x78a5636f43172f60, 0x84c87814a1f0ab72, 0x8cc702081a6439ec,
+		0x90befffa23631e28, 0xa4506cebde82bde9, 0xbef9a3f7b2c67915, 0xc67178f2e372532b,
+		0xca273eceea26619c, 0xd186b8c721c0c207, 0xeada7dd6cde0eb1e, 0xf57d4f7fee6ed178,
+		0x06f067aa72176fba, 0x0a637dc5a2c898a6, 0x113f9804bef90dae, 0x1b710b35131c471b,
+		0x28db77f523047d84, 0x32caab7b40c72493, 0x3c9ebe0a15c9bebc, 0x431d67c49c100d4c,
+		0x4cc5d4becb3e42b6, 0x597f299cfc657e2a, 0x5fcb6fab3ad6faec, 0x6c44198c4a475817,
+	}
+)
+
+// Sum512 returns the SHA-512 digest of data. Every element of data is
+// asserted to be a byte. The returned digest is 64 bytes long.
+func Sum512(api frontend.API, data []frontend.Variable) []frontend.Variable {
+	// padding: 0x80, zeros and the message length in bits as 128-bit
+	// big-endian integer so that the length is a multiple of 128 bytes.
+	padded := make([]frontend.Variable, len(data), len(data)+144)
+	copy(padded, data)
+	padded = append(padded, 0x80)
+	for len(padded)%128 != 112 {
+		padded = append(padded, 0)
+	}
+	bitLen := uint64(len(data)) * 8
+	for i := 0; i < 8; i++ {
+		padded = append(padded, 0)
+	}
+	for i := 7; i >= 0; i-- {
+		padded = append(padded, (bitLen>>(8*i))&0xff)
+	}
+
+	var h [8]dword
+	for i := range h {
+		h[i] = constDword(sha512Init[i])
+	}
+	for block := 0; block < len(padded); block += 128 {
+		var w [16]dword
+		for i := range w {
+			w[i] = bytesToDword(api, padded[block+8*i:block+8*i+8])
+		}
+		h = compress512(api, h, w)
+	}
+
+	res := make([]frontend.Variable, 0, 64)
+	for i := range h {
+		res = append(res, dwordToBytes(api, h[i])...)
+	}
+	return res
+}
+
+// compress512 applies the SHA-512 compression function on the state h with
+// the message block w.
+func compress512(api frontend.API, h [8]dword, w [16]dword) [8]dword {
+	var schedule [80]dword
+	copy(schedule[:], w[:])
+	for t := 16; t < 80; t++ {
+		s0 := xor3Dword(api, rotrDword(schedule[t-15], 1), rotrDword(schedule[t-15], 8), shrDword(schedule[t-15], 7))
+		s1 := xor3Dword(api, rotrDword(schedule[t-2], 19), rotrDword(schedule[t-2], 61), shrDword(schedule[t-2], 6))
+		schedule[t] = addDword(api, schedule[t-16], s0, schedule[t-7], s1)
+	}
+
+	a, b, c, d, e, f, g, hh := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+	for t := 0; t < 80; t++ {
+		S1 := xor3Dword(api, rotrDword(e, 14), rotrDword(e, 18), rotrDword(e, 41))
+		t1 := addDword(api, hh, S1, chDword(api, e, f, g), constDword(sha512K[t]), schedule[t])
+		S0 := xor3Dword(api, rotrDword(a, 28), rotrDword(a, 34), rotrDword(a, 39))
+		t2 := addDword(api, S0, majDword(api, a, b, c))
+		hh, g, f = g, f, e
+		e = addDword(api, d, t1)
+		d, c, b = c, b, a
+		a = addDword(api, t1, t2)
+	}
+
+	return [8]dword{
+		addDword(api, h[0], a), addDword(api, h[1], b), addDword(api, h[2], c), addDword(api, h[3], d),
+		addDword(api, h[4], e), addDword(api, h[5], f), addDword(api, h[6], g), addDword(api, h[7], hh),
+	}
+}
+
+// constDword returns the bits of the constant v.
+func constDword(v uint64) dword {
+	var res dword
+	for i := range res {
+		res[i] = (v >> i) & 1
+	}
+	return res
+}
+
+// bytesToDword returns the dword from the 8 bytes given in big-endian order.
+func bytesToDword(api frontend.API, b []frontend.Variable) dword {
+	var res dword
+	for i := 0; i < 8; i++ {
+		bb := bits.ToBinary(api, b[7-i], bits.WithNbDigits(8))
+		copy(res[8*i:8*i+8], bb)
+	}
+	return res
+}
+
+// dwordToBytes returns the 8 bytes of the dword in big-endian order.
+func dwordToBytes(api frontend.API, w dword) []frontend.Variable {
+	res := make([]frontend.Variable, 8)
+	for i := 0; i < 8; i++ {
+		res[7-i] = bits.FromBinary(api, w[8*i:8*i+8], bits.WithUnconstrainedInputs())
+	}
+	return res
+}
+
+// rotrDword returns the dword rotated right by n bits. It does not add
+// constraints.
+func rotrDword(w dword, n int) dword {
+	var res dword
+	for i := range res {
+		res[i] = w[(i+n)%64]
+	}
+	return res
+}
+
+// shrDword returns the dword shifted right by n bits. It does not add
+// constraints.
+func shrDword(w dword, n int) dword {
+	var res dword
+	for i := range res {
+		if i+n < 64 {
+			res[i] = w[i+n]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+// addDword returns the sum of the dwords modulo 2^64.
+func addDword(api frontend.API, ws ...dword) dword {
+	var sum frontend.Variable = 0
+	for _, w := range ws {
+		sum = api.Add(sum, bits.FromBinary(api, w[:], bits.WithUnconstrainedInputs()))
+	}
+	// the sum of n dwords fits into 64 + log2(n) bits
+	nbCarryBits := 0
+	for 1<<nbCarryBits < len(ws) {
+		nbCarryBits++
+	}
+	sumBits := bits.ToBinary(api, sum, bits.WithNbDigits(64+nbCarryBits))
+	var res dword
+	copy(res[:], sumBits[:64])
+	return res
+}
+
+// xor3Dword returns the bitwise XOR of the dwords.
+func xor3Dword(api frontend.API, a, b, c dword) dword {
+	var res dword
+	for i := range res {
+		res[i] = xor(api, xor(api, a[i], b[i]), c[i])
+	}
+	return res
+}
+
+// chDword returns the bitwise choice: e ? f : g.
+func chDword(api frontend.API, e, f, g dword) dword {
+	var res dword
+	for i := range res {
+		res[i] = api.Select(e[i], f[i], g[i])
+	}
+	return res
+}
+
+// majDword returns the bitwise majority of the dwords.
+func majDword(api frontend.API, a, b, c dword) dword {
+	var res dword
+	for i := range res {
+		ab := api.Mul(a[i], b[i])
+		t := api.Sub(api.Add(a[i], b[i]), api.Mul(ab, 2))
+		res[i] = api.Add(ab, api.Mul(c[i], t))
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package sha2
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type sum512Circuit struct {
+	In       []frontend.Variable
+	Expected [64]frontend.Variable
+}
+
+func (c *sum512Circuit) Define(api frontend.API) error {
+	res := Sum512(api, c.In)
+	for i := range c.Expected {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func sum512Witness(in []byte) (*sum512Circuit, *sum512Circuit) {
+	expected := sha512.Sum512(in)
+	circuit := sum512Circuit{In: make([]frontend.Variable, len(in))}
+	witness := sum512Circuit{In: make([]frontend.Variable, len(in))}
+	for i := range in {
+		witness.In[i] = in[i]
+	}
+	for i := range expected {
+		witness.Expected[i] = expected[i]
+	}
+	return &circuit, &witness
+}
+
+func TestSum512(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, length := range []int{0, 3, 111, 112, 128, 200} {
+		circuit, witness := sum512Witness(testInput(length))
+		assert.Run(func(assert *test.Assert) {
+			err := test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+			assert.NoError(err)
+		}, fmt.Sprintf("length=%d", length))
+	}
+}
+
+func TestSum512Solve(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit, witness := sum512Witness(testInput(128))
+	assert.SolvingSucceeded(circuit, witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+
+	witness.Expected[0] = witness.Expected[0].(byte) ^ 1
+	assert.SolvingFailed(circuit, witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sha3
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// state is the Keccak-f[1600] state of 25 lanes of 64 bits. The lane (x, y)
+// is at index x+5*y and the bits of a lane are in little-endian order.
+type state [25][64]frontend.Variable
+
+// roundConstants are the constants xored into the lane (0, 0) in the iota
+// step.
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotationOffsets are the offsets of the rho step for the lane at index
+// x+5*y.
+var rotationOffsets = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// init sets all the bits of the state to zero.
+func (s *state) init() {
+	for i := range s {
+		for j := range s[i] {
+			s[i][j] = 0
+		}
+	}
+}
+
+// permute applies the Keccak-f[1600] permutation on the state.
+func (s *state) permute(api frontend.API) {
+	for round := 0; round < 24; round++ {
+		s.theta(api)
+		s.rhoPi()
+		s.chi(api)
+		s.iota(api, roundConstants[round])
+	}
+}
+
+func (s *state) theta(api frontend.API) {
+	var c [5][64]frontend.Variable
+	for x := 0; x < 5; x++ {
+		for z := 0; z < 64; z++ {
+			c[x][z] = s[x][z]
+			for y := 1; y < 5; y++ {
+				c[x][z] = xor(api, c[x][z], s[x+5*y][z])
+			}
+		}
+	}
+	for x := 0; x < 5; x++ {
+		for z := 0; z < 64; z++ {
+			d := xor(api, c[(x+4)%5][z], c[(x+1)%5][(z+63)%64])
+			for y := 0; y < 5; y++ {
+				s[x+5*y][z] = xor(api, s[x+5*y][z], d)
+			}
+		}
+	}
+}
+
+// rhoPi rotates the lanes and moves the lane (x, y) to (y, 2x+3y). It does
+// not add any constraint.
+func (s *state) rhoPi() {
+	var b state
+	for x := 0; x < 5; x++ {
+		for y := 0; y < 5; y++ {
+			src := x + 5*y
+			dst := y + 5*((2*x+3*y)%5)
+			for z := 0; z < 64; z++ {
+				b[dst][(z+rotationOffsets[src])%64] = s[src][z]
+			}
+		}
+	}
+	*s = b
+}
+
+func (s *state) chi(api frontend.API) {
+	var row [5][64]frontend.Variable
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			row[x] = s[x+5*y]
+		}
+		for x := 0; x < 5; x++ {
+			for z := 0; z < 64; z++ {
+				t := andNot(api, row[(x+1)%5][z], row[(x+2)%5][z])
+				s[x+5*y][z] = xor(api, row[x][z], t)
+			}
+		}
+	}
+}
+
+func (s *state) iota(api frontend.API, rc uint64) {
+	for z := 0; z < 64; z++ {
+		if (rc>>z)&1 == 1 {
+			s[0][z] = xor(api, s[0][z], 1)
+		}
+	}
+}
+
+// xor returns a XOR b for boolean a and b. It does not add constraints when
+// one of the inputs is constant.
+func xor(api frontend.API, a, b frontend.Variable) frontend.Variable {
+	ca, aConst := api.Compiler().ConstantValue(a)
+	cb, bConst := api.Compiler().ConstantValue(b)
+	switch {
+	case aConst && bConst:
+		return ca.Uint64() ^ cb.Uint64()
+	case aConst:
+		return xorConstant(api, b, ca.Uint64())
+	case bConst:
+		return xorConstant(api, a, cb.Uint64())
+	}
+	return api.Xor(a, b, frontend.WithUnconstrainedInputs())
+}
+
+func xorConstant(api frontend.API, a frontend.Variable, c uint64) frontend.Variable {
+	if c == 0 {
+		return a
+	}
+	return api.Sub(1, a)
+}
+
+// andNot returns (NOT a) AND b for boolean a and b.
+func andNot(api frontend.API, a, b frontend.Variable) frontend.Variable {
+	if ca, ok := api.Compiler().ConstantValue(a); ok {
+		if ca.Sign() == 0 {
+			return b
+		}
+		return 0
+	}
+	if cb, ok := api.Compiler().ConstantValue(b); ok {
+		if cb.Sign() == 0 {
+			return 0
+		}
+		return api.Sub(1, a)
+	}
+	return api.Sub(b, api.Mul(a, b))
+}
@@ -0,0 +1,205 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sha3 provides ZKP-circuit functions to compute the Keccak-256 (as
+// used in Ethereum) and SHA3-256 digests of byte strings.
+//
+// The inputs are given as slices of variables, each variable being a byte.
+// The bytes are range-checked when decomposed into bits. The digests are
+// returned as 32 byte variables.
+package sha3
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+const (
+	// rate256 is the rate in bytes of the sponge for 256-bit digests.
+	rate256 = 136
+	// size256 is the length in bytes of 256-bit digests.
+	size256 = 32
+
+	dsbyteKeccak = 0x01
+	dsbyteSHA3   = 0x06
+)
+
+// Keccak256 returns the legacy Keccak-256 digest of data, as used in
+// Ethereum. Every element of data is asserted to be a byte. The returned
+// digest is 32 bytes long.
+func Keccak256(api frontend.API, data []frontend.Variable) []frontend.Variable {
+	return BytesFromBits(api, Keccak256Bits(api, BytesToBits(api, data)))
+}
+
+// Keccak256VarLen returns the legacy Keccak-256 digest of the first length
+// bytes of data. The length is a variable which is asserted to be at most
+// len(data). Every element of data is asserted to be a byte, including the
+// ignored ones. The cost only depends on len(data).
+func Keccak256VarLen(api frontend.API, data []frontend.Variable, length frontend.Variable) []frontend.Variable {
+	return BytesFromBits(api, sumVarLen(api, BytesToBits(api, data), length, dsbyteKeccak, rate256, size256))
+}
+
+// Sum256 returns the SHA3-256 digest of data. Every element of data is
+// asserted to be a byte. The returned digest is 32 bytes long.
+func Sum256(api frontend.API, data []frontend.Variable) []frontend.Variable {
+	return BytesFromBits(api, sum(api, BytesToBits(api, data), dsbyteSHA3, rate256, size256))
+}
+
+// Keccak256Bits returns the legacy Keccak-256 digest of the message given as
+// bits. The length of the message must be a multiple of 8 and the bits of
+// every byte are in little-endian order (see [BytesToBits]). The bits are
+// asserted to be boolean. The digest is returned as 256 bits in the same
+// order.
+func Keccak256Bits(api frontend.API, msg []frontend.Variable) []frontend.Variable {
+	for i := range msg {
+		api.AssertIsBoolean(msg[i])
+	}
+	return sum(api, msg, dsbyteKeccak, rate256, size256)
+}
+
+// BytesToBits decomposes the bytes into bits. The bits of every byte are in
+// little-endian order and the bytes keep their order. Every input is asserted
+// to be a byte.
+func BytesToBits(api frontend.API, data []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(data))
+	for i := range data {
+		res = append(res, bits.ToBinary(api, data[i], bits.WithNbDigits(8))...)
+	}
+	return res
+}
+
+// BytesFromBits recomposes bytes from bits given in the order defined by
+// [BytesToBits]. The number of bits must be a multiple of 8.
+func BytesFromBits(api frontend.API, bs []frontend.Variable) []frontend.Variable {
+	if len(bs)%8 != 0 {
+		panic("number of bits is not a multiple of 8")
+	}
+	res := make([]frontend.Variable, len(bs)/8)
+	for i := range res {
+		res[i] = bits.FromBinary(api, bs[8*i:8*i+8], bits.WithUnconstrainedInputs())
+	}
+	return res
+}
+
+// sum absorbs the message bits with the domain separation byte and the
+// padding into the sponge and squeezes size bytes of output.
+func sum(api frontend.API, msg []frontend.Variable, dsbyte byte, rate, size int) []frontend.Variable {
+	if len(msg)%8 != 0 {
+		panic("message length is not a multiple of 8 bits")
+	}
+	// pad10*1 with the domain separation bits
+	padded := make([]frontend.Variable, len(msg), len(msg)+8*rate)
+	copy(padded, msg)
+	padLen := rate - (len(msg)/8)%rate
+	pad := make([]byte, padLen)
+	pad[0] = dsbyte
+	pad[padLen-1] |= 0x80
+	for _, b := range pad {
+		for j := 0; j < 8; j++ {
+			padded = append(padded, (b>>j)&1)
+		}
+	}
+
+	var s state
+	s.init()
+	for block := 0; block < len(padded); block += 8 * rate {
+		for i := 0; i < 8*rate; i++ {
+			lane, bit := i/64, i%64
+			s[lane][bit] = xor(api, s[lane][bit], padded[block+i])
+		}
+		s.permute(api)
+	}
+
+	res := make([]frontend.Variable, 8*size)
+	for i := range res {
+		res[i] = s[i/64][i%64]
+	}
+	return res
+}
+
+// sumVarLen is as sum, but only the first length bytes of the message are
+// absorbed. The padding is placed at a variable position and the output is
+// selected among the states after each of the possible last blocks.
+func sumVarLen(api frontend.API, msg []frontend.Variable, length frontend.Variable, dsbyte byte, rate, size int) []frontend.Variable {
+	if len(msg)%8 != 0 {
+		panic("message length is not a multiple of 8 bits")
+	}
+	n := len(msg) / 8
+	nbBlocks := n/rate + 1
+
+	// eq[i] = 1 iff i == length, for i in [0, n]. Exactly one of them is set,
+	// which asserts that length is at most n.
+	eq := make([]frontend.Variable, n+1)
+	var sumEq frontend.Variable = 0
+	for i := range eq {
+		eq[i] = api.IsZero(api.Sub(length, i))
+		sumEq = api.Add(sumEq, eq[i])
+	}
+	api.AssertIsEqual(sumEq, 1)
+
+	// last[b] = 1 iff the padding ends in block b
+	last := make([]frontend.Variable, nbBlocks)
+	for b := range last {
+		last[b] = 0
+		for i := b * rate; i < (b+1)*rate && i <= n; i++ {
+			last[b] = api.Add(last[b], eq[i])
+		}
+	}
+
+	// the byte i is the message byte if i < length, the domain separation
+	// byte if i == length and has the final bit of the padding if it is the
+	// last byte of the last block.
+	padded := make([]frontend.Variable, 0, 8*nbBlocks*rate)
+	var inMsg frontend.Variable = 1
+	for i := 0; i < nbBlocks*rate; i++ {
+		var isLength frontend.Variable = 0
+		if i <= n {
+			isLength = eq[i]
+			inMsg = api.Sub(inMsg, eq[i])
+		}
+		for j := 0; j < 8; j++ {
+			var bit frontend.Variable = 0
+			if i < n {
+				bit = api.Mul(inMsg, msg[8*i+j])
+			}
+			if (dsbyte>>j)&1 == 1 {
+				bit = api.Add(bit, isLength)
+			}
+			if j == 7 && i%rate == rate-1 {
+				bit = api.Add(bit, last[i/rate])
+			}
+			padded = append(padded, bit)
+		}
+	}
+
+	var s state
+	s.init()
+	res := make([]frontend.Variable, 8*size)
+	for i := range res {
+		res[i] = 0
+	}
+	for b := 0; b < nbBlocks; b++ {
+		for i := 0; i < 8*rate; i++ {
+			lane, bit := i/64, i%64
+			s[lane][bit] = xor(api, s[lane][bit], padded[8*b*rate+i])
+		}
+		s.permute(api)
+		for i := range res {
+			res[i] = api.Add(res[i], api.Mul(last[b], s[i/64][i%64]))
+		}
+	}
+	return res
+}
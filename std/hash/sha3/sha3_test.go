@@ -0,0 +1,153 @@
+package sha3
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/sha3"
+)
+
+type keccak256Circuit struct {
+	In       []frontend.Variable
+	Expected [32]frontend.Variable
+}
+
+func (c *keccak256Circuit) Define(api frontend.API) error {
+	res := Keccak256(api, c.In)
+	for i := range c.Expected {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+type keccak256VarLenCircuit struct {
+	In       []frontend.Variable
+	Length   frontend.Variable
+	Expected [32]frontend.Variable
+}
+
+func (c *keccak256VarLenCircuit) Define(api frontend.API) error {
+	res := Keccak256VarLen(api, c.In, c.Length)
+	for i := range c.Expected {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+type sum256Circuit struct {
+	In       []frontend.Variable
+	Expected [32]frontend.Variable
+}
+
+func (c *sum256Circuit) Define(api frontend.API) error {
+	res := Sum256(api, c.In)
+	for i := range c.Expected {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func testInput(length int) []byte {
+	in := make([]byte, length)
+	for i := range in {
+		in[i] = byte(3*i + 1)
+	}
+	return in
+}
+
+func TestKeccak256(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, length := range []int{0, 1, 32, 135, 136, 200} {
+		in := testInput(length)
+		h := sha3.NewLegacyKeccak256()
+		h.Write(in)
+		expected := h.Sum(nil)
+
+		circuit := keccak256Circuit{In: make([]frontend.Variable, length)}
+		witness := keccak256Circuit{In: make([]frontend.Variable, length)}
+		for i := range in {
+			witness.In[i] = in[i]
+		}
+		for i := range expected {
+			witness.Expected[i] = expected[i]
+		}
+		assert.Run(func(assert *test.Assert) {
+			err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+			assert.NoError(err)
+		}, fmt.Sprintf("length=%d", length))
+	}
+}
+
+func TestKeccak256Solve(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := testInput(64)
+	h := sha3.NewLegacyKeccak256()
+	h.Write(in)
+	expected := h.Sum(nil)
+
+	circuit := keccak256Circuit{In: make([]frontend.Variable, len(in))}
+	witness := keccak256Circuit{In: make([]frontend.Variable, len(in))}
+	for i := range in {
+		witness.In[i] = in[i]
+	}
+	for i := range expected {
+		witness.Expected[i] = expected[i]
+	}
+	assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16, backend.PLONK), test.NoSerialization())
+
+	witness.Expected[0] = expected[0] ^ 1
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16), test.NoSerialization())
+}
+
+func TestKeccak256VarLen(t *testing.T) {
+	assert := test.NewAssert(t)
+	const maxLength = 200
+	for _, length := range []int{0, 1, 135, 136, 137, maxLength} {
+		in := testInput(maxLength)
+		h := sha3.NewLegacyKeccak256()
+		h.Write(in[:length])
+		expected := h.Sum(nil)
+
+		circuit := keccak256VarLenCircuit{In: make([]frontend.Variable, maxLength)}
+		witness := keccak256VarLenCircuit{In: make([]frontend.Variable, maxLength), Length: length}
+		for i := range in {
+			witness.In[i] = in[i]
+		}
+		for i := range expected {
+			witness.Expected[i] = expected[i]
+		}
+		assert.Run(func(assert *test.Assert) {
+			err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+			assert.NoError(err)
+
+			witness.Length = length + 1
+			err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+			assert.Error(err)
+		}, fmt.Sprintf("length=%d", length))
+	}
+}
+
+func TestSum256(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, length := range []int{0, 17, 136} {
+		in := testInput(length)
+		expected := sha3.Sum256(in)
+
+		circuit := sum256Circuit{In: make([]frontend.Variable, length)}
+		witness := sum256Circuit{In: make([]frontend.Variable, length)}
+		for i := range in {
+			witness.In[i] = in[i]
+		}
+		for i := range expected {
+			witness.Expected[i] = expected[i]
+		}
+		assert.Run(func(assert *test.Assert) {
+			err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+			assert.NoError(err)
+		}, fmt.Sprintf("length=%d", length))
+	}
+}
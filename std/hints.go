@@ -4,9 +4,20 @@ import (
 	"sync"
 
 	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/std/algebra/fields_bls12381"
+	"github.com/consensys/gnark/std/algebra/fields_bn254"
 	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	"github.com/consensys/gnark/std/algebra/sw_bls12381"
 	"github.com/consensys/gnark/std/algebra/sw_bls24315"
+	"github.com/consensys/gnark/std/internal/logderivarg"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
 	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+	"github.com/consensys/gnark/std/signature/ed25519"
+	"github.com/consensys/gnark/std/signature/eddsa"
 )
 
 var registerOnce sync.Once
@@ -27,4 +38,37 @@ func registerHints() {
 	hint.Register(bits.NNAF)
 	hint.Register(bits.IthBit)
 	hint.Register(bits.NBits)
+	for _, h := range emulated.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range ecdsa.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range eddsa.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range ed25519.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range fields_bls12381.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range sw_bls12381.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range fields_bn254.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range logderivarg.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range logderivlookup.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range rangecheck.GetHints() {
+		hint.Register(h)
+	}
+	for _, h := range selector.GetHints() {
+		hint.Register(h)
+	}
 }
@@ -0,0 +1,196 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logderivarg implements the log-derivative argument.
+//
+// The argument was described in "Multivariate lookups based on logarithmic
+// derivatives" by Ulrich Haböck (https://eprint.iacr.org/2022/1530). It proves
+// that all queries f_i are entries of the table t_j by checking the identity
+//
+//	Σ_i 1/(x-f_i) = Σ_j m_j/(x-t_j)
+//
+// at a random point x, where the multiplicity m_j is the number of times t_j
+// is queried. The multiplicities are given by the prover and the challenge x is
+// a commitment to the table, the queries and the multiplicities obtained with
+// [github.com/consensys/gnark/std/multicommit], so the argument costs one
+// constraint per query and per table row on top of the commitment. Rows of
+// several columns are first compressed with a second challenge.
+//
+// This package is internal, the gadgets are exposed by
+// [github.com/consensys/gnark/std/rangecheck] and
+// [github.com/consensys/gnark/std/lookup/logderivlookup].
+package logderivarg
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/multicommit"
+)
+
+func init() {
+	hint.Register(CountHint)
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []hint.Function {
+	return []hint.Function{CountHint}
+}
+
+// hashCost is the estimated number of constraints for absorbing one variable
+// into the challenge with MiMC, when the builder does not implement
+// [frontend.Committer]. It ranges from 91 (BLS12-377) to 273 R1CS constraints
+// depending on the curve.
+const hashCost = 273
+
+// Table is a table of rows of the same width. Multi-column rows are
+// compressed into single values with a random linear combination.
+type Table [][]frontend.Variable
+
+// Build asserts that every row of queries is a row of table. The entries may
+// be constants or variables. The assertion is added to the circuit once the
+// commitment is computed, when the circuit is compiled.
+func Build(api frontend.API, table, queries Table) error {
+	if len(queries) == 0 {
+		return nil
+	}
+	if len(table) == 0 {
+		return fmt.Errorf("empty table")
+	}
+	width := len(table[0])
+	if width == 0 {
+		return fmt.Errorf("empty rows")
+	}
+	for i := range table {
+		if len(table[i]) != width {
+			return fmt.Errorf("table row %d has width %d, expected %d", i, len(table[i]), width)
+		}
+	}
+	for i := range queries {
+		if len(queries[i]) != width {
+			return fmt.Errorf("query %d has width %d, expected %d", i, len(queries[i]), width)
+		}
+	}
+	inputs := make([]frontend.Variable, 0, 2+(len(table)+len(queries))*width)
+	inputs = append(inputs, len(table), width)
+	inputs = append(inputs, flatten(table)...)
+	inputs = append(inputs, flatten(queries)...)
+	multiplicities, err := api.Compiler().NewHint(CountHint, len(table), inputs...)
+	if err != nil {
+		return fmt.Errorf("new hint: %w", err)
+	}
+
+	toCommit := append(flatten(table), flatten(queries)...)
+	toCommit = append(toCommit, multiplicities...)
+	multicommit.WithCommitment(api, func(api frontend.API, x frontend.Variable) error {
+		var lambda frontend.Variable
+		if width > 1 {
+			h, err := mimc.NewMiMC(api)
+			if err != nil {
+				return fmt.Errorf("new hash: %w", err)
+			}
+			h.Write(x)
+			lambda = h.Sum()
+		}
+
+		// Σ_i 1/(x-f_i)
+		lhs := frontend.Variable(0)
+		for i := range queries {
+			lhs = api.Add(lhs, api.Inverse(api.Sub(x, compress(api, queries[i], lambda))))
+		}
+		// Σ_j m_j/(x-t_j)
+		rhs := frontend.Variable(0)
+		for j := range table {
+			rhs = api.Add(rhs, api.DivUnchecked(multiplicities[j], api.Sub(x, compress(api, table[j], lambda))))
+		}
+		api.AssertIsEqual(lhs, rhs)
+		return nil
+	}, toCommit...)
+	return nil
+}
+
+// Cost returns the estimated number of constraints of Build for a table of
+// nbTable single-column rows and nbQueries single-column queries.
+func Cost(api frontend.API, nbTable, nbQueries int) int {
+	// one inverse per query and one division per table row
+	cost := nbTable + nbQueries
+	if _, ok := api.(frontend.Committer); !ok {
+		// every query and multiplicity is hashed into the commitment
+		cost += (nbTable + nbQueries) * hashCost
+	}
+	return cost
+}
+
+// compress returns Σ_k row_k λ^k.
+func compress(api frontend.API, row []frontend.Variable, lambda frontend.Variable) frontend.Variable {
+	res := row[len(row)-1]
+	for k := len(row) - 2; k >= 0; k-- {
+		res = api.Add(row[k], api.Mul(res, lambda))
+	}
+	return res
+}
+
+func flatten(t Table) []frontend.Variable {
+	var res []frontend.Variable
+	for i := range t {
+		res = append(res, t[i]...)
+	}
+	return res
+}
+
+// CountHint computes the multiplicities of the table rows in the queries. The
+// inputs are the number n of rows and the width w of the rows, followed by the
+// n*w table entries and the query entries, row by row. It returns an error if a
+// query is not in the table.
+func CountHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 2 || !inputs[0].IsInt64() || !inputs[1].IsInt64() {
+		return fmt.Errorf("missing table dimensions")
+	}
+	n, w := int(inputs[0].Int64()), int(inputs[1].Int64())
+	if w <= 0 || len(outputs) != n || len(inputs) < 2+n*w || (len(inputs)-2)%w != 0 {
+		return fmt.Errorf("expected %d table rows of width %d", n, w)
+	}
+	key := func(row []*big.Int) string {
+		var sbb strings.Builder
+		for _, v := range row {
+			sbb.WriteString(v.String())
+			sbb.WriteByte(',')
+		}
+		return sbb.String()
+	}
+	entries := inputs[2:]
+	indices := make(map[string]int, n)
+	for j := n - 1; j >= 0; j-- {
+		// the first occurrence of duplicate rows counts the queries
+		indices[key(entries[j*w:(j+1)*w])] = j
+	}
+	for j := range outputs {
+		outputs[j].SetUint64(0)
+	}
+	for i := n * w; i < len(entries); i += w {
+		j, ok := indices[key(entries[i:i+w])]
+		if !ok {
+			return fmt.Errorf("query (%s) not in table", strings.TrimSuffix(key(entries[i:i+w]), ","))
+		}
+		outputs[j].Add(outputs[j], big.NewInt(1))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logderivarg
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type buildCircuit struct {
+	Table   [4]frontend.Variable
+	Queries [6]frontend.Variable
+}
+
+func (c *buildCircuit) Define(api frontend.API) error {
+	// mixed constant and variable table entries
+	table := Table{{7}}
+	for i := range c.Table {
+		table = append(table, []frontend.Variable{c.Table[i]})
+	}
+	queries := make(Table, len(c.Queries))
+	for i := range c.Queries {
+		queries[i] = []frontend.Variable{c.Queries[i]}
+	}
+	return Build(api, table, queries)
+}
+
+func TestBuild(t *testing.T) {
+	assert := test.NewAssert(t)
+	table := [4]frontend.Variable{3, 5, 5, 11}
+	assert.SolvingSucceeded(&buildCircuit{}, &buildCircuit{
+		Table:   table,
+		Queries: [6]frontend.Variable{5, 5, 7, 11, 3, 5},
+	}, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(&buildCircuit{}, &buildCircuit{
+		Table:   table,
+		Queries: [6]frontend.Variable{5, 5, 7, 11, 4, 5},
+	}, test.WithCurves(ecc.BN254))
+}
+
+type buildTuplesCircuit struct {
+	Table   [3][2]frontend.Variable
+	Queries [4][2]frontend.Variable
+}
+
+func (c *buildTuplesCircuit) Define(api frontend.API) error {
+	table := make(Table, len(c.Table))
+	for i := range c.Table {
+		table[i] = c.Table[i][:]
+	}
+	queries := make(Table, len(c.Queries))
+	for i := range c.Queries {
+		queries[i] = c.Queries[i][:]
+	}
+	return Build(api, table, queries)
+}
+
+func TestBuildTuples(t *testing.T) {
+	assert := test.NewAssert(t)
+	table := [3][2]frontend.Variable{{0, 5}, {1, 7}, {2, 5}}
+	assert.SolvingSucceeded(&buildTuplesCircuit{}, &buildTuplesCircuit{
+		Table:   table,
+		Queries: [4][2]frontend.Variable{{1, 7}, {0, 5}, {2, 5}, {1, 7}},
+	}, test.WithCurves(ecc.BN254))
+	// both columns are in the table, but not as a row
+	assert.SolvingFailed(&buildTuplesCircuit{}, &buildTuplesCircuit{
+		Table:   table,
+		Queries: [4][2]frontend.Variable{{1, 7}, {0, 7}, {2, 5}, {1, 7}},
+	}, test.WithCurves(ecc.BN254))
+}
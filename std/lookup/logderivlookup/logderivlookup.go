@@ -0,0 +1,146 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logderivlookup implements lookups in tables of variables using the
+// log-derivative argument.
+//
+// A table is a list of constant or variable entries. Lookups return the entries
+// at variable indices and all the lookups of a table are proven with a single
+// log-derivative argument when the circuit is compiled: the rows (i, entry_i)
+// of the table must contain the rows (index, result) of the lookups. The cost
+// is one constraint per entry and per lookup on top of the commitment to the
+// entries, the lookups and their multiplicities, from which the challenge of
+// the argument is derived. The commitment is shared with the other gadgets of
+// the circuit, see [github.com/consensys/gnark/std/multicommit].
+//
+// The argument was described in "Multivariate lookups based on logarithmic
+// derivatives" by Ulrich Haböck (https://eprint.iacr.org/2022/1530).
+package logderivlookup
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/internal/logderivarg"
+)
+
+func init() {
+	hint.Register(LookupHint)
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []hint.Function {
+	return []hint.Function{LookupHint}
+}
+
+// Table is an append-only lookup table.
+type Table struct {
+	api       frontend.API
+	entries   []frontend.Variable
+	queries   logderivarg.Table
+	committed bool
+}
+
+// New returns a new empty table. The lookups are proven when the circuit is
+// compiled.
+func New(api frontend.API) *Table {
+	t := &Table{api: api}
+	api.Compiler().Defer(t.commit)
+	return t
+}
+
+// Insert appends val to the table and returns its index.
+func (t *Table) Insert(val frontend.Variable) (index int) {
+	if t.committed {
+		panic("insert in a committed table")
+	}
+	t.entries = append(t.entries, val)
+	return len(t.entries) - 1
+}
+
+// Lookup returns the entries at the indices inds. The indices must be smaller
+// than the number of entries inserted so far, otherwise the circuit is not
+// satisfied. Constant indices select the entries directly.
+func (t *Table) Lookup(inds ...frontend.Variable) (vals []frontend.Variable) {
+	if t.committed {
+		panic("lookup in a committed table")
+	}
+	vals = make([]frontend.Variable, len(inds))
+	var vInds []frontend.Variable
+	var vPos []int
+	for i := range inds {
+		if c, ok := t.api.Compiler().ConstantValue(inds[i]); ok {
+			if !c.IsInt64() || c.Int64() >= int64(len(t.entries)) {
+				panic(fmt.Sprintf("constant index %s out of range", c.String()))
+			}
+			vals[i] = t.entries[c.Int64()]
+			continue
+		}
+		vInds = append(vInds, inds[i])
+		vPos = append(vPos, i)
+	}
+	if len(vInds) == 0 {
+		return vals
+	}
+	inputs := make([]frontend.Variable, 0, 1+len(t.entries)+len(vInds))
+	inputs = append(inputs, len(t.entries))
+	inputs = append(inputs, t.entries...)
+	inputs = append(inputs, vInds...)
+	results, err := t.api.Compiler().NewHint(LookupHint, len(vInds), inputs...)
+	if err != nil {
+		panic(fmt.Sprintf("lookup hint: %v", err))
+	}
+	for i := range vInds {
+		vals[vPos[i]] = results[i]
+		t.queries = append(t.queries, []frontend.Variable{vInds[i], results[i]})
+	}
+	return vals
+}
+
+func (t *Table) commit(api frontend.API) error {
+	t.committed = true
+	if len(t.queries) == 0 {
+		return nil
+	}
+	table := make(logderivarg.Table, len(t.entries))
+	for i := range t.entries {
+		table[i] = []frontend.Variable{i, t.entries[i]}
+	}
+	return logderivarg.Build(api, table, t.queries)
+}
+
+// LookupHint returns the entries at the given indices. The first input is the
+// number n of entries, followed by the n entries and the indices.
+func LookupHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) == 0 || !inputs[0].IsInt64() {
+		return fmt.Errorf("missing number of entries")
+	}
+	n := int(inputs[0].Int64())
+	if len(inputs) != 1+n+len(outputs) {
+		return fmt.Errorf("expected %d entries and %d indices", n, len(outputs))
+	}
+	entries, inds := inputs[1:1+n], inputs[1+n:]
+	for i := range inds {
+		if !inds[i].IsInt64() || inds[i].Int64() >= int64(n) {
+			return fmt.Errorf("index %s out of range", inds[i].String())
+		}
+		outputs[i].Set(entries[inds[i].Int64()])
+	}
+	return nil
+}
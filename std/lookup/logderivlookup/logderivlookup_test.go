@@ -0,0 +1,103 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logderivlookup
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+)
+
+type lookupCircuit struct {
+	Entries  [8]frontend.Variable
+	Queries  [10]frontend.Variable
+	Expected [10]frontend.Variable
+}
+
+func (c *lookupCircuit) Define(api frontend.API) error {
+	t := New(api)
+	for i := range c.Entries {
+		t.Insert(c.Entries[i])
+	}
+	// constant entry and lookup at a constant index
+	idx := t.Insert(42)
+	api.AssertIsEqual(t.Lookup(idx)[0], 42)
+	api.AssertIsEqual(t.Lookup(3)[0], c.Entries[3])
+
+	res := t.Lookup(c.Queries[:]...)
+	for i := range res {
+		api.AssertIsEqual(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestLookup(t *testing.T) {
+	assert := test.NewAssert(t)
+	r := rand.New(rand.NewSource(1))
+
+	var witness lookupCircuit
+	entries := make([]int64, len(witness.Entries)+1)
+	for i := range witness.Entries {
+		entries[i] = r.Int63()
+		witness.Entries[i] = entries[i]
+	}
+	entries[len(witness.Entries)] = 42
+	for i := range witness.Queries {
+		q := r.Intn(len(entries))
+		witness.Queries[i] = q
+		witness.Expected[i] = entries[q]
+	}
+	assert.SolvingSucceeded(&lookupCircuit{}, &witness, test.WithCurves(ecc.BN254))
+
+	invalid := witness
+	invalid.Expected[2] = entries[(witness.Queries[2].(int)+1)%len(entries)]
+	assert.SolvingFailed(&lookupCircuit{}, &invalid, test.WithCurves(ecc.BN254))
+
+	invalid = witness
+	invalid.Queries[4] = len(entries)
+	assert.SolvingFailed(&lookupCircuit{}, &invalid, test.WithCurves(ecc.BN254))
+}
+
+type costCircuit struct {
+	Entries [256]frontend.Variable
+	Queries [1024]frontend.Variable
+}
+
+func (c *costCircuit) Define(api frontend.API) error {
+	t := New(api)
+	for i := range c.Entries {
+		t.Insert(c.Entries[i])
+	}
+	res := t.Lookup(c.Queries[:]...)
+	api.AssertIsEqual(api.Add(res[0], res[1], res[2:]...), 0)
+	return nil
+}
+
+func TestLookupCost(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &costCircuit{})
+	assert.NoError(err)
+	// the challenge is a commitment to the entries, queries, results and
+	// multiplicities, so that the cost stays a few constraints per entry and
+	// per lookup instead of a hash per value
+	nbValues := 2*len(costCircuit{}.Entries) + 2*len(costCircuit{}.Queries)
+	assert.Less(ccs.GetNbConstraints(), 2*nbValues)
+}
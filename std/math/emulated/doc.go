@@ -0,0 +1,60 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package emulated implements operations over an arbitrary (non-native) field.
+
+The native field of a circuit is the scalar field of the SNARK curve. To
+perform arithmetic modulo some other modulus p (for example the base field of
+secp256k1 inside a BN254 circuit), an element x of Z/pZ is decomposed into
+limbs x = Σ x_i 2^(w*i), where w is the number of bits per limb. Every limb is
+stored as a native variable.
+
+Limb-wise additions and subtractions may make the limbs grow beyond w bits.
+Instead of reducing the result after every operation, every element keeps
+track of its overflow: the number of bits by which its limbs may exceed w. The
+element is only reduced modulo p when the next operation would overflow the
+native field (lazy reduction). Multiplications always reduce their result.
+
+The reduction of x is done by computing out-of-circuit (using hints) the
+quotient q and remainder r such that x = q*p + r. The limbs of q and r are
+range-checked and the integer equality x = q*p + r is verified limb by limb
+by propagating carries.
+
+The package is parametrized over the emulated modulus using the FieldParams
+interface. Common moduli are predefined (see for example Secp256k1Fp or
+BN254Fp). To use an emulated element as a circuit input, the limbs must be
+allocated when defining the circuit:
+
+	type Circuit struct {
+		X emulated.Element[emulated.Secp256k1Fp]
+	}
+
+	circuit := Circuit{X: emulated.NewElement[emulated.Secp256k1Fp](nil)}
+	assignment := Circuit{X: emulated.NewElement[emulated.Secp256k1Fp](x)}
+
+In the circuit, the operations are performed using Field:
+
+	func (c *Circuit) Define(api frontend.API) error {
+		f, err := emulated.NewField[emulated.Secp256k1Fp](api)
+		if err != nil {
+			return err
+		}
+		x2 := f.Mul(&c.X, &c.X)
+		...
+	}
+*/
+package emulated
@@ -0,0 +1,87 @@
+package emulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/utils"
+)
+
+// Element defines an element in the ring of integers modulo n. The integer
+// value of the element is split into limbs of nbBits lengths and represented
+// as a slice of limbs. The least significant limb is first.
+//
+// The limbs of an element which is used as a circuit input are allocated by
+// NewElement. The value of the element is only partially reduced: its limbs
+// may have overflow bits and the value may be greater than the modulus.
+type Element[T FieldParams] struct {
+	Limbs []frontend.Variable
+
+	// overflow indicates the number of additions on top of the normal form. To
+	// ensure that none of the limbs overflow the scalar field of the snark
+	// curve, we must check that nbBits+overflow < floor(log2(fr modulus))
+	overflow uint
+
+	// internal indicates if the element is returned from Field methods. If so,
+	// then we can assume that the limbs are already constrained to be correct
+	// width. If the flag is not set, then the Element most probably comes from
+	// the witness (or constructed by the user) and the limbs are
+	// width-constrained on first use. We do not store the enforcement info in
+	// the Element to prevent modifying the witness.
+	internal bool
+}
+
+// NewElement builds a new emulated element from input. The input is converted
+// into a big.Int (see utils.FromInterface), reduced modulo the emulated
+// modulus and decomposed into limbs.
+//
+// If the input is nil, then all the limbs are set to zero. This is useful for
+// allocating the limbs when defining the circuit.
+func NewElement[T FieldParams](v interface{}) Element[T] {
+	var fp T
+	r := Element[T]{Limbs: make([]frontend.Variable, fp.NbLimbs())}
+	if v == nil {
+		for i := range r.Limbs {
+			r.Limbs[i] = 0
+		}
+		return r
+	}
+	switch tv := v.(type) {
+	case Element[T]:
+		copy(r.Limbs, tv.Limbs)
+		return r
+	case *Element[T]:
+		copy(r.Limbs, tv.Limbs)
+		return r
+	}
+	c := utils.FromInterface(v)
+	c.Mod(&c, fp.Modulus())
+	limbs := make([]*big.Int, fp.NbLimbs())
+	for i := range limbs {
+		limbs[i] = new(big.Int)
+	}
+	if err := decompose(&c, fp.BitsPerLimb(), limbs); err != nil {
+		panic(fmt.Errorf("decompose value: %w", err))
+	}
+	for i := range limbs {
+		r.Limbs[i] = limbs[i]
+	}
+	return r
+}
+
+// newInternalElement sets the limbs and overflow. Given as a function for later
+// possible refactor.
+func newInternalElement[T FieldParams](limbs []frontend.Variable, overflow uint) *Element[T] {
+	return &Element[T]{Limbs: limbs, overflow: overflow, internal: true}
+}
+
+// copy returns a deep copy of the element.
+func (e *Element[T]) copy() *Element[T] {
+	r := Element[T]{}
+	r.Limbs = make([]frontend.Variable, len(e.Limbs))
+	copy(r.Limbs, e.Limbs)
+	r.overflow = e.overflow
+	r.internal = e.internal
+	return &r
+}
@@ -0,0 +1,286 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"fmt"
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// Field holds the configuration for non-native field operations. The field
+// parameters (modulus, number of limbs) is given by [FieldParams] type
+// parameter. Every operation over emulated elements is performed through
+// Field, which records the constraints using the native API.
+type Field[T FieldParams] struct {
+	// api is the native API
+	api frontend.API
+
+	nbBits  uint
+	nbLimbs uint
+
+	// modulus and its limb decomposition
+	modulus    *big.Int
+	modLimbs   []frontend.Variable
+	modBitLen  uint
+	topLimbLen uint
+
+	// maxOf is the maximum overflow of a limb so that the carry propagation
+	// does not overflow the native field.
+	maxOf uint
+
+	// constants for often used elements
+	zeroConst, oneConst *Element[T]
+
+	// constrained records the non-internal elements (i.e. circuit inputs)
+	// whose limbs have already been range-checked.
+	constrained map[*Element[T]]struct{}
+}
+
+// NewField returns an object to be used in-circuit to perform emulated
+// arithmetic over the field defined by type parameter [FieldParams]. The
+// operations on this type are defined on [Element]. It returns an error if
+// the limb width is too large compared to the native field.
+func NewField[T FieldParams](native frontend.API) (*Field[T], error) {
+	var fp T
+	f := &Field[T]{
+		api:     native,
+		nbBits:  fp.BitsPerLimb(),
+		nbLimbs: fp.NbLimbs(),
+		modulus: new(big.Int).Set(fp.Modulus()),
+
+		constrained: make(map[*Element[T]]struct{}),
+	}
+	if f.nbBits == 0 || f.nbLimbs == 0 {
+		return nil, fmt.Errorf("invalid limb parameters: %d limbs of %d bits", f.nbLimbs, f.nbBits)
+	}
+	if f.modulus.Sign() <= 0 {
+		return nil, fmt.Errorf("modulus must be positive")
+	}
+	f.modBitLen = uint(f.modulus.BitLen())
+	if f.modBitLen > f.nbBits*f.nbLimbs {
+		return nil, fmt.Errorf("modulus of %d bits does not fit into %d limbs of %d bits", f.modBitLen, f.nbLimbs, f.nbBits)
+	}
+	f.topLimbLen = ((f.modBitLen - 1) % f.nbBits) + 1
+
+	nativeBits := uint(native.Compiler().Curve().Info().Fr.Bits)
+	// the product of two reduced elements must not overflow during the carry
+	// propagation of the reduction.
+	if 2*f.nbBits+uint(mbits.Len(f.nbLimbs))+3 > nativeBits {
+		return nil, fmt.Errorf("elements with limb length %d does not fit into scalar field", f.nbBits)
+	}
+	f.maxOf = nativeBits - 3 - f.nbBits
+
+	limbs := make([]*big.Int, f.nbLimbs)
+	for i := range limbs {
+		limbs[i] = new(big.Int)
+	}
+	if err := decompose(f.modulus, f.nbBits, limbs); err != nil {
+		return nil, fmt.Errorf("decompose modulus: %w", err)
+	}
+	f.modLimbs = make([]frontend.Variable, len(limbs))
+	for i := range limbs {
+		f.modLimbs[i] = limbs[i]
+	}
+	return f, nil
+}
+
+// NewElement returns a pointer to an element. If v is an Element or a pointer
+// to an Element, then it is returned as is. Otherwise v is considered as a
+// constant and is converted into an emulated element.
+func (f *Field[T]) NewElement(v interface{}) *Element[T] {
+	switch tv := v.(type) {
+	case Element[T]:
+		return &tv
+	case *Element[T]:
+		return tv
+	}
+	c := NewElement[T](v)
+	c.internal = true
+	return &c
+}
+
+// Zero returns a constant element with value zero.
+func (f *Field[T]) Zero() *Element[T] {
+	if f.zeroConst == nil {
+		f.zeroConst = f.NewElement(0)
+	}
+	return f.zeroConst
+}
+
+// One returns a constant element with value one.
+func (f *Field[T]) One() *Element[T] {
+	if f.oneConst == nil {
+		f.oneConst = f.NewElement(1)
+	}
+	return f.oneConst
+}
+
+// Modulus returns the modulus of the emulated field as a constant. The
+// returned element is not reduced.
+func (f *Field[T]) Modulus() *Element[T] {
+	limbs := make([]frontend.Variable, len(f.modLimbs))
+	copy(limbs, f.modLimbs)
+	return newInternalElement[T](limbs, 0)
+}
+
+// maxOverflow returns the maximal allowed overflow of an element.
+func (f *Field[T]) maxOverflow() uint {
+	return f.maxOf
+}
+
+// constantElement returns the element with value v mod p as constant limbs.
+func (f *Field[T]) constantElement(v *big.Int) *Element[T] {
+	c := NewElement[T](v)
+	c.internal = true
+	return &c
+}
+
+// constantValue returns the integer value of the element if all its limbs are
+// constant. Otherwise returns false.
+func (f *Field[T]) constantValue(a *Element[T]) (*big.Int, bool) {
+	limbs := make([]*big.Int, len(a.Limbs))
+	for i := range a.Limbs {
+		c, ok := f.api.Compiler().ConstantValue(a.Limbs[i])
+		if !ok {
+			return nil, false
+		}
+		limbs[i] = c
+	}
+	res := new(big.Int)
+	if err := recompose(limbs, f.nbBits, res); err != nil {
+		return nil, false
+	}
+	return res, true
+}
+
+// enforceWidthConditional range-checks the limbs of the element if it has not
+// been returned by Field methods (i.e. is given as a circuit input). The check
+// is recorded in the Field and not in the element to avoid modifying the
+// circuit structure.
+func (f *Field[T]) enforceWidthConditional(a *Element[T]) {
+	if a == nil {
+		panic("nil element")
+	}
+	if a.internal {
+		return
+	}
+	if _, ok := f.constrained[a]; ok {
+		return
+	}
+	if uint(len(a.Limbs)) != f.nbLimbs {
+		panic(fmt.Sprintf("expected %d limbs, got %d", f.nbLimbs, len(a.Limbs)))
+	}
+	f.enforceWidth(a, true)
+	f.constrained[a] = struct{}{}
+}
+
+// enforceWidth range-checks every limb of a to be f.nbBits wide. If modWidth
+// is set, then the most significant limb is checked to have at most as many
+// bits as the most significant limb of the modulus.
+func (f *Field[T]) enforceWidth(a *Element[T], modWidth bool) {
+	for i := range a.Limbs {
+		limbNbBits := f.nbBits
+		if modWidth && uint(i) == f.nbLimbs-1 {
+			limbNbBits = f.topLimbLen
+		}
+		f.rangeCheck(a.Limbs[i], limbNbBits)
+	}
+}
+
+// rangeCheck asserts that v fits into nbBits bits.
+func (f *Field[T]) rangeCheck(v frontend.Variable, nbBits uint) {
+	if c, ok := f.api.Compiler().ConstantValue(v); ok {
+		if c.BitLen() > int(nbBits) {
+			panic(fmt.Sprintf("constant %s does not fit into %d bits", c.String(), nbBits))
+		}
+		return
+	}
+	bits.ToBinary(f.api, v, bits.WithNbDigits(int(nbBits)))
+}
+
+// packLimbs returns an element from the limbs computed by a hint. The limbs
+// are range-checked. If strict is set, then the most significant limb is
+// checked to be as wide as the most significant limb of the modulus.
+func (f *Field[T]) packLimbs(limbs []frontend.Variable, strict bool) *Element[T] {
+	e := newInternalElement[T](limbs, 0)
+	f.enforceWidth(e, strict)
+	return e
+}
+
+// hintInputs returns the inputs for the hints of the package: the limb width,
+// the number of limbs of the modulus, the limbs of the modulus and the given
+// values.
+func (f *Field[T]) hintInputs(values ...[]frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 2+len(f.modLimbs))
+	res = append(res, f.nbBits, f.nbLimbs)
+	res = append(res, f.modLimbs...)
+	for _, v := range values {
+		res = append(res, v...)
+	}
+	return res
+}
+
+// computeHint calls the hint function and panics if it fails.
+func (f *Field[T]) computeHint(hf hint.Function, nbOutputs int, inputs ...frontend.Variable) []frontend.Variable {
+	res, err := f.api.Compiler().NewHint(hf, nbOutputs, inputs...)
+	if err != nil {
+		panic(fmt.Sprintf("call hint: %v", err))
+	}
+	return res
+}
+
+// nbQuoLimbs returns the number of limbs required to store the quotient of a
+// division of an element with nbLimbs limbs of overflow by the modulus.
+func (f *Field[T]) nbQuoLimbs(nbLimbs int, overflow uint) int {
+	valueBits := f.nbBits*uint(nbLimbs) + overflow + 1
+	nbQuoBits := uint(1)
+	if valueBits+1 > f.modBitLen {
+		nbQuoBits = valueBits + 1 - f.modBitLen
+	}
+	return int((nbQuoBits + f.nbBits - 1) / f.nbBits)
+}
+
+func max[T ~int | ~uint](a ...T) T {
+	if len(a) == 0 {
+		return 0
+	}
+	m := a[0]
+	for _, v := range a {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+func min[T ~int | ~uint](a ...T) T {
+	if len(a) == 0 {
+		return 0
+	}
+	m := a[0]
+	for _, v := range a {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,166 @@
+package emulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// AssertIsEqual ensures that a is equal to b modulo the modulus. The inputs
+// do not have to be reduced.
+//
+// The difference d = a-b is computed and the quotient k = d/p is computed in
+// a hint. Then the limbs of d and k*p are asserted to be equal as integers.
+func (f *Field[T]) AssertIsEqual(a, b *Element[T]) {
+	f.enforceWidthConditional(a)
+	f.enforceWidthConditional(b)
+	ca, aConst := f.constantValue(a)
+	cb, bConst := f.constantValue(b)
+	if aConst && bConst {
+		ca.Sub(ca, cb).Mod(ca, f.modulus)
+		if ca.Sign() != 0 {
+			panic(fmt.Sprintf("emulated elements are not equal (difference %s)", ca.String()))
+		}
+		return
+	}
+
+	diff := f.Sub(b, a)
+
+	// we compute k such that diff = k * p
+	nbQuoLimbs := f.nbQuoLimbs(len(diff.Limbs), diff.overflow)
+	k := f.packLimbs(f.computeHint(QuoHint, nbQuoLimbs, f.hintInputs(diff.Limbs)...), false)
+
+	kp, kpOverflow := f.mulModulus(k.Limbs)
+	f.assertLimbsEquality(diff.Limbs, kp, max(diff.overflow, kpOverflow))
+}
+
+// AssertLimbsEquality asserts that the limbs represent a same integer value.
+// This method does not ensure that the values are equal modulo the field
+// order. For strict equality, use AssertIsEqual.
+func (f *Field[T]) AssertLimbsEquality(a, b *Element[T]) {
+	f.enforceWidthConditional(a)
+	f.enforceWidthConditional(b)
+	f.assertLimbsEquality(a.Limbs, b.Limbs, max(a.overflow, b.overflow))
+}
+
+// assertLimbsEquality asserts that the limbs l and r represent the same
+// integer. The limbs may have at most overflow bits of overflow over the limb
+// width.
+//
+// The limbs are compared from the least significant one by computing the
+// difference and propagating the carry. The low bits of every difference must
+// be zero and the carry must be small. To avoid negative carries, the
+// difference is shifted by a constant which is compensated for in the next
+// limb.
+func (f *Field[T]) assertLimbsEquality(l, r []frontend.Variable, overflow uint) {
+	nbLimbs := max(len(l), len(r))
+	nbCarryBits := overflow + 1
+	maxValue := new(big.Int).Lsh(big.NewInt(1), f.nbBits+nbCarryBits)
+	maxValueShift := new(big.Int).Lsh(big.NewInt(1), nbCarryBits)
+
+	var carry frontend.Variable = 0
+	for i := 0; i < nbLimbs; i++ {
+		diff := f.api.Add(maxValue, carry)
+		if i < len(l) {
+			diff = f.api.Add(diff, l[i])
+		}
+		if i < len(r) {
+			diff = f.api.Sub(diff, r[i])
+		}
+		if i > 0 {
+			diff = f.api.Sub(diff, maxValueShift)
+		}
+		// carry is stored in the highest bits of diff[nbBits:nbBits+nbCarryBits+1]
+		// we know that diff[:nbBits] are 0 bits, but still need to constrain them.
+		carry = f.rsh(diff, f.nbBits, nbCarryBits+1)
+	}
+	f.api.AssertIsEqual(carry, maxValueShift)
+}
+
+// rsh returns v >> shift. It asserts that the shift-many least significant
+// bits of v are zero and that the result fits into nbBits bits.
+func (f *Field[T]) rsh(v frontend.Variable, shift, nbBits uint) frontend.Variable {
+	if c, ok := f.api.Compiler().ConstantValue(v); ok {
+		if c.TrailingZeroBits() < shift && c.Sign() != 0 {
+			panic(fmt.Sprintf("limbs are not equal: %s is not divisible by 2^%d", c.String(), shift))
+		}
+		c.Rsh(c, shift)
+		f.rangeCheck(c, nbBits)
+		return c
+	}
+	shifted := f.computeHint(RightShift, 1, shift, v)
+	f.rangeCheck(shifted[0], nbBits)
+	f.api.AssertIsEqual(f.api.Mul(shifted[0], new(big.Int).Lsh(big.NewInt(1), shift)), v)
+	return shifted[0]
+}
+
+// AssertIsInRange ensures that the integer value of a is less than the
+// modulus. The element must not have overflow, for example it may be a
+// circuit input or the result of Reduce.
+func (f *Field[T]) AssertIsInRange(a *Element[T]) {
+	f.enforceWidthConditional(a)
+	if a.overflow != 0 {
+		panic("range check of an element with overflow, reduce first")
+	}
+	f.toBitsStrict(a)
+}
+
+// IsZero returns a boolean indicating if the element is zero modulo the
+// modulus.
+func (f *Field[T]) IsZero(a *Element[T]) frontend.Variable {
+	ca := f.Reduce(a)
+	if c, ok := f.constantValue(ca); ok {
+		if c.Mod(c, f.modulus).Sign() == 0 {
+			return 1
+		}
+		return 0
+	}
+	// after the reduction the value is less than 2p. We ensure that the value
+	// is less than p and then check that all limbs are zero. As the limbs are
+	// small, then it is sufficient to check that the sum of the limbs is zero.
+	f.AssertIsInRange(ca)
+	var sum frontend.Variable = 0
+	for i := range ca.Limbs {
+		sum = f.api.Add(sum, ca.Limbs[i])
+	}
+	return f.api.IsZero(sum)
+}
+
+// assertBitsLessOrEqual asserts that the value defined by the boolean
+// little-endian bits aBits is less or equal than the constant bound. The bits
+// are assumed to be already boolean-constrained.
+func (f *Field[T]) assertBitsLessOrEqual(aBits []frontend.Variable, bound *big.Int) {
+	nbBits := len(aBits)
+	if bound.BitLen() > nbBits {
+		return
+	}
+
+	// t trailing bits in the bound
+	t := 0
+	for i := 0; i < nbBits; i++ {
+		if bound.Bit(i) == 0 {
+			break
+		}
+		t++
+	}
+
+	p := make([]frontend.Variable, nbBits+1)
+	// p[i] == 1 → a[j] == c[j] for all j ⩾ i
+	p[nbBits] = 1
+	for i := nbBits - 1; i >= t; i-- {
+		if bound.Bit(i) == 0 {
+			p[i] = p[i+1]
+		} else {
+			p[i] = f.api.Mul(p[i+1], aBits[i])
+		}
+	}
+
+	for i := nbBits - 1; i >= 0; i-- {
+		if bound.Bit(i) == 0 {
+			// (1 - p(i+1) - ai) * ai == 0
+			l := f.api.Sub(1, p[i+1], aBits[i])
+			f.api.AssertIsEqual(f.api.Mul(l, aBits[i]), 0)
+		}
+	}
+}
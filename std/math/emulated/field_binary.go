@@ -0,0 +1,68 @@
+package emulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// ToBits returns the bit representation of the element. The element is
+// reduced and the returned bits represent the canonical value in [0, p), so
+// that the number of returned bits is the bit length of the modulus. The bits
+// are in little-endian order.
+func (f *Field[T]) ToBits(a *Element[T]) []frontend.Variable {
+	ca := f.Reduce(a)
+	return f.toBitsStrict(ca)
+}
+
+// toBitsStrict decomposes the limbs of an element without overflow and
+// asserts that the value is less than the modulus.
+func (f *Field[T]) toBitsStrict(a *Element[T]) []frontend.Variable {
+	if c, ok := f.constantValue(a); ok {
+		if c.Cmp(f.modulus) >= 0 {
+			panic(fmt.Sprintf("constant %s is not less than the modulus", c.String()))
+		}
+		res := make([]frontend.Variable, f.modBitLen)
+		for i := range res {
+			res[i] = c.Bit(i)
+		}
+		return res
+	}
+	var fullBits []frontend.Variable
+	for i := range a.Limbs {
+		limbBits := bits.ToBinary(f.api, a.Limbs[i], bits.WithNbDigits(int(f.nbBits)))
+		fullBits = append(fullBits, limbBits...)
+	}
+	f.assertBitsLessOrEqual(fullBits, new(big.Int).Sub(f.modulus, big.NewInt(1)))
+	return fullBits[:f.modBitLen]
+}
+
+// FromBits returns a new Element given the little-endian bits. The bits are
+// constrained to be boolean. The number of bits must not exceed the total
+// width of the limbs. The returned element is not reduced.
+//
+// If the number of bits is at least the bit length of the modulus, then the
+// value may be larger than the modulus. In that case the element is marked
+// to have overflow so that the next call to Reduce performs the reduction.
+func (f *Field[T]) FromBits(bs ...frontend.Variable) *Element[T] {
+	nbLimbs := (uint(len(bs)) + f.nbBits - 1) / f.nbBits
+	if nbLimbs > f.nbLimbs {
+		panic(fmt.Sprintf("%d bits do not fit into %d limbs of %d bits", len(bs), f.nbLimbs, f.nbBits))
+	}
+	limbs := make([]frontend.Variable, f.nbLimbs)
+	for i := range limbs {
+		limbs[i] = 0
+	}
+	for i := uint(0); i < nbLimbs; i++ {
+		start := i * f.nbBits
+		end := min(start+f.nbBits, uint(len(bs)))
+		limbs[i] = bits.FromBinary(f.api, bs[start:end])
+	}
+	var overflow uint
+	if uint(len(bs)) >= f.modBitLen {
+		overflow = 1
+	}
+	return newInternalElement[T](limbs, overflow)
+}
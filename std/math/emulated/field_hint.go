@@ -0,0 +1,98 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulated
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+)
+
+// NewHint calls the hint function hf with the emulated elements inputs and
+// returns nbOutputs emulated elements. The hint function must be registered
+// and should use [UnwrapHint] to work on the integer values of the emulated
+// elements instead of the limbs.
+//
+// The limbs of the returned elements are range-checked to the width of the
+// modulus, but the values are not otherwise constrained. The caller must
+// constrain the outputs.
+func (f *Field[T]) NewHint(hf hint.Function, nbOutputs int, inputs ...*Element[T]) []*Element[T] {
+	hintInputs := f.hintInputs([]frontend.Variable{len(inputs)})
+	for i := range inputs {
+		f.enforceWidthConditional(inputs[i])
+		hintInputs = append(hintInputs, len(inputs[i].Limbs))
+		hintInputs = append(hintInputs, inputs[i].Limbs...)
+	}
+	limbs := f.computeHint(hf, nbOutputs*int(f.nbLimbs), hintInputs...)
+	res := make([]*Element[T], nbOutputs)
+	for i := range res {
+		res[i] = f.packLimbs(limbs[i*int(f.nbLimbs):(i+1)*int(f.nbLimbs)], true)
+	}
+	return res
+}
+
+// UnwrapHint is a helper for implementing hint functions called with
+// [Field.NewHint]. It recomposes the emulated inputs from the native inputs,
+// calls nonnativeHint with the modulus and the integer values of the inputs
+// and decomposes the integer outputs (reduced modulo the modulus) into the
+// native outputs.
+func UnwrapHint(nativeInputs, nativeOutputs []*big.Int, nonnativeHint func(mod *big.Int, inputs, outputs []*big.Int) error) error {
+	nbBits, nbLimbs, p, rest, err := parseHintInputs(nativeInputs)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 1 {
+		return errors.New("number of emulated inputs missing")
+	}
+	nbInputs := int(rest[0].Int64())
+	rest = rest[1:]
+	inputs := make([]*big.Int, nbInputs)
+	for i := range inputs {
+		if len(rest) < 1 {
+			return errors.New("number of limbs missing")
+		}
+		n := int(rest[0].Int64())
+		if len(rest) < 1+n {
+			return errors.New("input limbs missing")
+		}
+		inputs[i] = new(big.Int)
+		if err := recompose(rest[1:1+n], nbBits, inputs[i]); err != nil {
+			return fmt.Errorf("recompose input %d: %w", i, err)
+		}
+		rest = rest[1+n:]
+	}
+	if len(nativeOutputs)%nbLimbs != 0 {
+		return errors.New("number of outputs is not a multiple of the number of limbs")
+	}
+	outputs := make([]*big.Int, len(nativeOutputs)/nbLimbs)
+	for i := range outputs {
+		outputs[i] = new(big.Int)
+	}
+	if err := nonnativeHint(p, inputs, outputs); err != nil {
+		return err
+	}
+	for i := range outputs {
+		outputs[i].Mod(outputs[i], p)
+		if err := decompose(outputs[i], nbBits, nativeOutputs[i*nbLimbs:(i+1)*nbLimbs]); err != nil {
+			return fmt.Errorf("decompose output %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+package emulated
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// overflowError is returned by the pre-conditions of the operations when the
+// result would overflow the native field. reduceRight indicates which of the
+// operands should be reduced.
+type overflowError struct {
+	op           string
+	nextOverflow uint
+	maxOverflow  uint
+	reduceRight  bool
+}
+
+func (e overflowError) Error() string {
+	return fmt.Sprintf("op %s overflow %d exceeds max %d", e.op, e.nextOverflow, e.maxOverflow)
+}
+
+// reduceAndOp applies op on the inputs. If the pre-condition check preCond
+// errs, then first reduces the input arguments. The reduction is done
+// one-by-one with the element with highest overflow reduced first.
+func (f *Field[T]) reduceAndOp(op func(*Element[T], *Element[T], uint) *Element[T], preCond func(*Element[T], *Element[T]) (uint, error), a, b *Element[T]) *Element[T] {
+	f.enforceWidthConditional(a)
+	f.enforceWidthConditional(b)
+	var nextOverflow uint
+	var err error
+	var target overflowError
+
+	for nextOverflow, err = preCond(a, b); errors.As(err, &target); nextOverflow, err = preCond(a, b) {
+		if !target.reduceRight {
+			a = f.Reduce(a)
+		} else {
+			b = f.Reduce(b)
+		}
+	}
+	return op(a, b, nextOverflow)
+}
+
+// Add computes a+b and returns it. If the result would overflow the native
+// field, then the inputs are reduced first.
+func (f *Field[T]) Add(a, b *Element[T]) *Element[T] {
+	return f.reduceAndOp(f.add, f.addPreCond, a, b)
+}
+
+func (f *Field[T]) addPreCond(a, b *Element[T]) (nextOverflow uint, err error) {
+	reduceRight := a.overflow < b.overflow
+	nextOverflow = max(a.overflow, b.overflow) + 1
+	if nextOverflow > f.maxOverflow() {
+		err = overflowError{op: "add", nextOverflow: nextOverflow, maxOverflow: f.maxOverflow(), reduceRight: reduceRight}
+	}
+	return
+}
+
+func (f *Field[T]) add(a, b *Element[T], nextOverflow uint) *Element[T] {
+	ca, aConst := f.constantValue(a)
+	cb, bConst := f.constantValue(b)
+	if aConst && bConst {
+		return f.constantElement(ca.Add(ca, cb))
+	}
+
+	nbLimbs := max(len(a.Limbs), len(b.Limbs))
+	limbs := make([]frontend.Variable, nbLimbs)
+	for i := range limbs {
+		limbs[i] = 0
+		if i < len(a.Limbs) {
+			limbs[i] = f.api.Add(limbs[i], a.Limbs[i])
+		}
+		if i < len(b.Limbs) {
+			limbs[i] = f.api.Add(limbs[i], b.Limbs[i])
+		}
+	}
+	return newInternalElement[T](limbs, nextOverflow)
+}
+
+// Sub subtracts b from a and returns it. The subtraction is computed by
+// adding a multiple of the modulus to a so that the result limbs are
+// non-negative. If the result would overflow the native field, then the
+// inputs are reduced first.
+func (f *Field[T]) Sub(a, b *Element[T]) *Element[T] {
+	return f.reduceAndOp(f.sub, f.subPreCond, a, b)
+}
+
+func (f *Field[T]) subPreCond(a, b *Element[T]) (nextOverflow uint, err error) {
+	reduceRight := a.overflow < b.overflow+2
+	nextOverflow = max(b.overflow+1, a.overflow) + 1
+	if nextOverflow > f.maxOverflow() {
+		err = overflowError{op: "sub", nextOverflow: nextOverflow, maxOverflow: f.maxOverflow(), reduceRight: reduceRight}
+	}
+	return
+}
+
+func (f *Field[T]) sub(a, b *Element[T], nextOverflow uint) *Element[T] {
+	ca, aConst := f.constantValue(a)
+	cb, bConst := f.constantValue(b)
+	if aConst && bConst {
+		return f.constantElement(ca.Sub(ca, cb))
+	}
+
+	nbLimbs := max(len(a.Limbs), len(b.Limbs))
+	pad := subPadding(f.modulus, f.nbBits, b.overflow, uint(nbLimbs))
+	limbs := make([]frontend.Variable, nbLimbs)
+	for i := range limbs {
+		limbs[i] = pad[i]
+		if i < len(a.Limbs) {
+			limbs[i] = f.api.Add(limbs[i], a.Limbs[i])
+		}
+		if i < len(b.Limbs) {
+			limbs[i] = f.api.Sub(limbs[i], b.Limbs[i])
+		}
+	}
+	return newInternalElement[T](limbs, nextOverflow)
+}
+
+// Neg returns -a.
+func (f *Field[T]) Neg(a *Element[T]) *Element[T] {
+	return f.Sub(f.Zero(), a)
+}
+
+// Mul computes a*b and reduces it modulo the field order. The result has no
+// overflow, but is not necessarily less than the modulus.
+//
+// The polynomial product of the limbs is computed by a hint and verified by
+// evaluating both sides at len(a)+len(b)-1 distinct points. Then the product
+// is reduced.
+func (f *Field[T]) Mul(a, b *Element[T]) *Element[T] {
+	return f.reduceAndOp(f.mul, f.mulPreCond, a, b)
+}
+
+func (f *Field[T]) mulPreCond(a, b *Element[T]) (nextOverflow uint, err error) {
+	reduceRight := a.overflow < b.overflow
+	nbLimbsOverflow := uint(mbits.Len(uint(min(len(a.Limbs), len(b.Limbs)))))
+	nextOverflow = f.nbBits + nbLimbsOverflow + a.overflow + b.overflow
+	if nextOverflow > f.maxOverflow() {
+		err = overflowError{op: "mul", nextOverflow: nextOverflow, maxOverflow: f.maxOverflow(), reduceRight: reduceRight}
+	}
+	return
+}
+
+func (f *Field[T]) mul(a, b *Element[T], nextOverflow uint) *Element[T] {
+	ca, aConst := f.constantValue(a)
+	cb, bConst := f.constantValue(b)
+	if aConst && bConst {
+		return f.constantElement(ca.Mul(ca, cb))
+	}
+	product := newInternalElement[T](f.mulPoly(a.Limbs, b.Limbs, aConst || bConst), nextOverflow)
+	return f.reduce(product)
+}
+
+// mulPoly returns the coefficients of the polynomial product of the limbs. If
+// linear is set, then one of the inputs is constant and the coefficients are
+// computed directly. Otherwise the coefficients are computed in a hint and
+// verified by evaluating the polynomials at distinct points.
+func (f *Field[T]) mulPoly(a, b []frontend.Variable, linear bool) []frontend.Variable {
+	res := make([]frontend.Variable, len(a)+len(b)-1)
+	if linear {
+		for i := range res {
+			res[i] = 0
+		}
+		for i := range a {
+			for j := range b {
+				res[i+j] = f.api.Add(res[i+j], f.api.Mul(a[i], b[j]))
+			}
+		}
+		return res
+	}
+
+	hintInputs := make([]frontend.Variable, 0, 1+len(a)+len(b))
+	hintInputs = append(hintInputs, len(a))
+	hintInputs = append(hintInputs, a...)
+	hintInputs = append(hintInputs, b...)
+	res = f.computeHint(MultiplicationHint, len(res), hintInputs...)
+
+	// the polynomials a(X)*b(X) and res(X) are of degree len(res)-1. The
+	// product coefficients fit into the native field, so it is sufficient to
+	// check the equality at len(res) distinct points.
+	for c := 1; c <= len(res); c++ {
+		cb := big.NewInt(int64(c))
+		f.api.AssertIsEqual(
+			f.api.Mul(evalPoly(f.api, a, cb), evalPoly(f.api, b, cb)),
+			evalPoly(f.api, res, cb),
+		)
+	}
+	return res
+}
+
+// evalPoly evaluates the polynomial with coefficients coeffs at the constant
+// point at.
+func evalPoly(api frontend.API, coeffs []frontend.Variable, at *big.Int) frontend.Variable {
+	var res frontend.Variable = 0
+	cp := big.NewInt(1)
+	for i := range coeffs {
+		res = api.Add(res, api.Mul(coeffs[i], cp))
+		cp = new(big.Int).Mul(cp, at)
+	}
+	return res
+}
+
+// MulConst multiplies a by a constant c and returns it. If c is small (not
+// larger than the limb width), then the limbs are multiplied by c directly
+// and the result is not reduced. Otherwise the multiplication is performed
+// using Mul.
+func (f *Field[T]) MulConst(a *Element[T], c *big.Int) *Element[T] {
+	f.enforceWidthConditional(a)
+	if c.Sign() < 0 || uint(c.BitLen()) > f.nbBits {
+		return f.Mul(a, f.constantElement(c))
+	}
+	if ca, ok := f.constantValue(a); ok {
+		return f.constantElement(ca.Mul(ca, c))
+	}
+	cbl := uint(c.BitLen())
+	if a.overflow+cbl > f.maxOverflow() {
+		a = f.Reduce(a)
+	}
+	limbs := make([]frontend.Variable, len(a.Limbs))
+	for i := range a.Limbs {
+		limbs[i] = f.api.Mul(a.Limbs[i], c)
+	}
+	return newInternalElement[T](limbs, a.overflow+cbl)
+}
+
+// Square computes a*a and reduces it modulo the field order.
+func (f *Field[T]) Square(a *Element[T]) *Element[T] {
+	return f.Mul(a, a)
+}
+
+// Reduce reduces a modulo the field order and returns it. The result has no
+// overflow and its limbs are of the width of the limbs of the modulus, but the
+// value is not necessarily less than the modulus.
+func (f *Field[T]) Reduce(a *Element[T]) *Element[T] {
+	f.enforceWidthConditional(a)
+	return f.reduce(a)
+}
+
+func (f *Field[T]) reduce(a *Element[T]) *Element[T] {
+	if a.overflow == 0 && uint(len(a.Limbs)) == f.nbLimbs {
+		// fits into the normal form already
+		return a
+	}
+	if ca, ok := f.constantValue(a); ok {
+		return f.constantElement(ca)
+	}
+	nbQuoLimbs := f.nbQuoLimbs(len(a.Limbs), a.overflow)
+	res := f.computeHint(QuoRemHint, nbQuoLimbs+int(f.nbLimbs), f.hintInputs(a.Limbs)...)
+	quo := f.packLimbs(res[:nbQuoLimbs], false)
+	rem := f.packLimbs(res[nbQuoLimbs:], true)
+
+	// a = quo * p + rem
+	qp, qpOverflow := f.mulModulus(quo.Limbs)
+	rhs := f.add(newInternalElement[T](qp, qpOverflow), rem, qpOverflow+1)
+	f.assertLimbsEquality(a.Limbs, rhs.Limbs, max(a.overflow, rhs.overflow))
+	return rem
+}
+
+// mulModulus returns the limbs of the polynomial product of k and the modulus
+// and the overflow of the limbs.
+func (f *Field[T]) mulModulus(k []frontend.Variable) ([]frontend.Variable, uint) {
+	res := f.mulPoly(k, f.modLimbs, true)
+	return res, f.nbBits + uint(mbits.Len(uint(min(len(k), len(f.modLimbs)))))
+}
+
+// Inverse returns multiplicative inverse of a. The inverse is computed in a
+// hint and constrained by checking a*a^-1 = 1. The inverse of zero does not
+// exist and the constraints are not satisfied.
+func (f *Field[T]) Inverse(a *Element[T]) *Element[T] {
+	f.enforceWidthConditional(a)
+	if ca, ok := f.constantValue(a); ok {
+		if ca.ModInverse(ca, f.modulus) == nil {
+			panic("no inverse")
+		}
+		return f.constantElement(ca)
+	}
+	k := f.computeHint(InverseHint, int(f.nbLimbs), f.hintInputs(a.Limbs)...)
+	e := f.packLimbs(k, true)
+	res := f.Mul(e, a)
+	f.AssertIsEqual(res, f.One())
+	return e
+}
+
+// Div computes a/b and returns it. The result is computed in a hint and
+// constrained by checking (a/b)*b = a. If b is zero, then the constraints are
+// not satisfied.
+func (f *Field[T]) Div(a, b *Element[T]) *Element[T] {
+	f.enforceWidthConditional(a)
+	f.enforceWidthConditional(b)
+	ca, aConst := f.constantValue(a)
+	cb, bConst := f.constantValue(b)
+	if aConst && bConst {
+		if cb.ModInverse(cb, f.modulus) == nil {
+			panic("no inverse")
+		}
+		return f.constantElement(ca.Mul(ca, cb))
+	}
+	inputs := f.hintInputs([]frontend.Variable{len(a.Limbs)}, a.Limbs, b.Limbs)
+	d := f.computeHint(DivHint, int(f.nbLimbs), inputs...)
+	e := f.packLimbs(d, true)
+	res := f.Mul(e, b)
+	f.AssertIsEqual(res, a)
+	return e
+}
+
+// Select sets e to a if selector == 1 and to b otherwise. Assumes that
+// selector is boolean.
+func (f *Field[T]) Select(selector frontend.Variable, a, b *Element[T]) *Element[T] {
+	f.enforceWidthConditional(a)
+	f.enforceWidthConditional(b)
+	nbLimbs := max(len(a.Limbs), len(b.Limbs))
+	limbs := make([]frontend.Variable, nbLimbs)
+	for i := range limbs {
+		var al, bl frontend.Variable = 0, 0
+		if i < len(a.Limbs) {
+			al = a.Limbs[i]
+		}
+		if i < len(b.Limbs) {
+			bl = b.Limbs[i]
+		}
+		limbs[i] = f.api.Select(selector, al, bl)
+	}
+	return newInternalElement[T](limbs, max(a.overflow, b.overflow))
+}
+
+// Lookup2 performs two-bit lookup between a, b, c, d based on lookup bits b1
+// and b2 such that:
+//   - if b0=0 and b1=0, sets to a,
+//   - if b0=1 and b1=0, sets to b,
+//   - if b0=0 and b1=1, sets to c,
+//   - if b0=1 and b1=1, sets to d.
+//
+// The number of the limbs and overflow in result is the maximum of the
+// inputs'. If the inputs are very unbalanced, then it may beneficial to
+// reduce the inputs before calling the method.
+func (f *Field[T]) Lookup2(b0, b1 frontend.Variable, a, b, c, d *Element[T]) *Element[T] {
+	f.enforceWidthConditional(a)
+	f.enforceWidthConditional(b)
+	f.enforceWidthConditional(c)
+	f.enforceWidthConditional(d)
+	nbLimbs := max(len(a.Limbs), len(b.Limbs), len(c.Limbs), len(d.Limbs))
+	limb := func(e *Element[T], i int) frontend.Variable {
+		if i < len(e.Limbs) {
+			return e.Limbs[i]
+		}
+		return 0
+	}
+	limbs := make([]frontend.Variable, nbLimbs)
+	for i := range limbs {
+		limbs[i] = f.api.Lookup2(b0, b1, limb(a, i), limb(b, i), limb(c, i), limb(d, i))
+	}
+	return newInternalElement[T](limbs, max(a.overflow, b.overflow, c.overflow, d.overflow))
+}
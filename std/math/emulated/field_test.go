@@ -0,0 +1,797 @@
+package emulated
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const testCurve = ecc.BN254
+
+func name[T FieldParams]() string {
+	var fp T
+	return reflect.TypeOf(fp).Name()
+}
+
+type AssertLimbEqualityCircuit[T FieldParams] struct {
+	A, B Element[T]
+}
+
+func (c *AssertLimbEqualityCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	f.AssertLimbsEquality(&c.A, &c.B)
+	return nil
+}
+
+func testAssertLimbEqualityNoOverflow[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness AssertLimbEqualityCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		val, _ := rand.Int(rand.Reader, fp.Modulus())
+		witness.A = NewElement[T](val)
+		witness.B = NewElement[T](val)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestAssertLimbEqualityNoOverflow(t *testing.T) {
+	testAssertLimbEqualityNoOverflow[Goldilocks](t)
+	testAssertLimbEqualityNoOverflow[Secp256k1Fp](t)
+	testAssertLimbEqualityNoOverflow[BN254Fp](t)
+}
+
+type AddCircuit[T FieldParams] struct {
+	A, B, C Element[T]
+}
+
+func (c *AddCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Add(&c.A, &c.B)
+	f.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func testAddCircuitNoOverflow[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness AddCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		bound := new(big.Int).Rsh(fp.Modulus(), 1)
+		val1, _ := rand.Int(rand.Reader, bound)
+		val2, _ := rand.Int(rand.Reader, bound)
+		res := new(big.Int).Add(val1, val2)
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestAddCircuitNoOverflow(t *testing.T) {
+	testAddCircuitNoOverflow[Goldilocks](t)
+	testAddCircuitNoOverflow[Secp256k1Fp](t)
+	testAddCircuitNoOverflow[BN254Fp](t)
+}
+
+type MulNoOverflowCircuit[T FieldParams] struct {
+	A Element[T]
+	B Element[T]
+	C Element[T]
+}
+
+func (c *MulNoOverflowCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Mul(&c.A, &c.B)
+	f.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func testMulCircuitNoOverflow[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness MulNoOverflowCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(fp.Modulus().BitLen())/2))
+		val2, _ := rand.Int(rand.Reader, new(big.Int).Div(fp.Modulus(), val1))
+		res := new(big.Int).Mul(val1, val2)
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestMulCircuitNoOverflow(t *testing.T) {
+	testMulCircuitNoOverflow[Goldilocks](t)
+	testMulCircuitNoOverflow[Secp256k1Fp](t)
+	testMulCircuitNoOverflow[BN254Fp](t)
+}
+
+type MulCircuitOverflow[T FieldParams] struct {
+	A Element[T]
+	B Element[T]
+	C Element[T]
+}
+
+func (c *MulCircuitOverflow[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Mul(&c.A, &c.B)
+	f.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func testMulCircuitOverflow[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness MulCircuitOverflow[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		val2, _ := rand.Int(rand.Reader, fp.Modulus())
+		res := new(big.Int).Mul(val1, val2)
+		res.Mod(res, fp.Modulus())
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization())
+	}, name[T]())
+}
+
+func TestMulCircuitOverflow(t *testing.T) {
+	testMulCircuitOverflow[Goldilocks](t)
+	testMulCircuitOverflow[Secp256k1Fp](t)
+	testMulCircuitOverflow[BN254Fp](t)
+	testMulCircuitOverflow[BLS12381Fp](t)
+}
+
+type SubtractCircuit[T FieldParams] struct {
+	A Element[T]
+	B Element[T]
+	C Element[T]
+}
+
+func (c *SubtractCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Sub(&c.A, &c.B)
+	f.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func testSubtractNoOverflow[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness SubtractCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		val2, _ := rand.Int(rand.Reader, val1)
+		res := new(big.Int).Sub(val1, val2)
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestSubtractNoOverflow(t *testing.T) {
+	testSubtractNoOverflow[Goldilocks](t)
+	testSubtractNoOverflow[Secp256k1Fp](t)
+	testSubtractNoOverflow[BN254Fp](t)
+}
+
+func testSubtractOverflow[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness SubtractCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		val2, _ := rand.Int(rand.Reader, new(big.Int).Sub(fp.Modulus(), val1))
+		val2.Add(val2, val1)
+		res := new(big.Int).Sub(val1, val2)
+		res.Mod(res, fp.Modulus())
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestSubtractOverflow(t *testing.T) {
+	testSubtractOverflow[Goldilocks](t)
+	testSubtractOverflow[Secp256k1Fp](t)
+	testSubtractOverflow[BN254Fp](t)
+}
+
+type NegationCircuit[T FieldParams] struct {
+	A Element[T]
+	C Element[T]
+}
+
+func (c *NegationCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Neg(&c.A)
+	f.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func testNegation[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness NegationCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		res := new(big.Int).Sub(fp.Modulus(), val1)
+		witness.A = NewElement[T](val1)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestNegation(t *testing.T) {
+	testNegation[Goldilocks](t)
+	testNegation[Secp256k1Fp](t)
+	testNegation[BN254Fp](t)
+}
+
+type InverseCircuit[T FieldParams] struct {
+	A Element[T]
+	C Element[T]
+}
+
+func (c *InverseCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Inverse(&c.A)
+	f.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func testInverse[T FieldParams](t *testing.T) {
+	var fp T
+	if !fp.IsPrime() {
+		t.Skip()
+	}
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness InverseCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		res := new(big.Int).ModInverse(val1, fp.Modulus())
+		witness.A = NewElement[T](val1)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestInverse(t *testing.T) {
+	testInverse[Goldilocks](t)
+	testInverse[Secp256k1Fp](t)
+	testInverse[BN254Fp](t)
+}
+
+type DivisionCircuit[T FieldParams] struct {
+	A Element[T]
+	B Element[T]
+	C Element[T]
+}
+
+func (c *DivisionCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Div(&c.A, &c.B)
+	f.AssertIsEqual(res, &c.C)
+	return nil
+}
+
+func testDivision[T FieldParams](t *testing.T) {
+	var fp T
+	if !fp.IsPrime() {
+		t.Skip()
+	}
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness DivisionCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		val2, _ := rand.Int(rand.Reader, fp.Modulus())
+		res := new(big.Int)
+		res.ModInverse(val2, fp.Modulus())
+		res.Mul(val1, res)
+		res.Mod(res, fp.Modulus())
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](res)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization())
+	}, name[T]())
+}
+
+func TestDivision(t *testing.T) {
+	testDivision[Goldilocks](t)
+	testDivision[Secp256k1Fp](t)
+	testDivision[BN254Fp](t)
+}
+
+type ToBinaryCircuit[T FieldParams] struct {
+	Value Element[T]
+	Bits  []frontend.Variable
+}
+
+func (c *ToBinaryCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	bits := f.ToBits(&c.Value)
+	if len(bits) != len(c.Bits) {
+		return fmt.Errorf("got %d bits, expected %d", len(bits), len(c.Bits))
+	}
+	for i := range bits {
+		api.AssertIsEqual(bits[i], c.Bits[i])
+	}
+	return nil
+}
+
+func testToBinary[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		bitLen := fp.Modulus().BitLen()
+		var circuit, witness ToBinaryCircuit[T]
+		circuit.Value = NewElement[T](nil)
+		circuit.Bits = make([]frontend.Variable, bitLen)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		bits := make([]frontend.Variable, bitLen)
+		for i := 0; i < len(bits); i++ {
+			bits[i] = val1.Bit(i)
+		}
+		witness.Value = NewElement[T](val1)
+		witness.Bits = bits
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestToBinary(t *testing.T) {
+	testToBinary[Goldilocks](t)
+	testToBinary[Secp256k1Fp](t)
+	testToBinary[BN254Fp](t)
+}
+
+type FromBinaryCircuit[T FieldParams] struct {
+	Bits []frontend.Variable
+	Res  Element[T]
+}
+
+func (c *FromBinaryCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.FromBits(c.Bits...)
+	f.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func testFromBinary[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		bitLen := fp.Modulus().BitLen()
+		var circuit, witness FromBinaryCircuit[T]
+		circuit.Bits = make([]frontend.Variable, bitLen)
+		circuit.Res = NewElement[T](nil)
+		// the value may be larger than the modulus
+		val1, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bitLen)))
+		bits := make([]frontend.Variable, bitLen)
+		for i := 0; i < len(bits); i++ {
+			bits[i] = val1.Bit(i)
+		}
+		witness.Res = NewElement[T](val1)
+		witness.Bits = bits
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestFromBinary(t *testing.T) {
+	testFromBinary[Goldilocks](t)
+	testFromBinary[Secp256k1Fp](t)
+	testFromBinary[BN254Fp](t)
+}
+
+type EqualityCheckCircuit[T FieldParams] struct {
+	A Element[T]
+	B Element[T]
+}
+
+func (c *EqualityCheckCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	// add and subtract the same value to have overflowing limbs
+	res := f.Sub(f.Add(&c.A, &c.B), &c.B)
+	f.AssertIsEqual(res, &c.A)
+	return nil
+}
+
+func testEqualityCheck[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness EqualityCheckCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		val2, _ := rand.Int(rand.Reader, fp.Modulus())
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestEqualityCheck(t *testing.T) {
+	testEqualityCheck[Goldilocks](t)
+	testEqualityCheck[Secp256k1Fp](t)
+	testEqualityCheck[BN254Fp](t)
+}
+
+func TestEqualityCheckFailure(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp Secp256k1Fp
+	var circuit, witness MulNoOverflowCircuit[Secp256k1Fp]
+	circuit.A = NewElement[Secp256k1Fp](nil)
+	circuit.B = NewElement[Secp256k1Fp](nil)
+	circuit.C = NewElement[Secp256k1Fp](nil)
+	val1, _ := rand.Int(rand.Reader, fp.Modulus())
+	val2, _ := rand.Int(rand.Reader, fp.Modulus())
+	res := new(big.Int).Mul(val1, val2)
+	res.Add(res, big.NewInt(1))
+	witness.A = NewElement[Secp256k1Fp](val1)
+	witness.B = NewElement[Secp256k1Fp](val2)
+	witness.C = NewElement[Secp256k1Fp](res)
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(testCurve), test.WithBackends(backend.GROTH16))
+}
+
+func TestUnreducedLimbsFailure(t *testing.T) {
+	assert := test.NewAssert(t)
+	var circuit, witness AddCircuit[Secp256k1Fp]
+	circuit.A = NewElement[Secp256k1Fp](nil)
+	circuit.B = NewElement[Secp256k1Fp](nil)
+	circuit.C = NewElement[Secp256k1Fp](nil)
+	witness.A = NewElement[Secp256k1Fp](1)
+	witness.B = NewElement[Secp256k1Fp](1)
+	witness.C = NewElement[Secp256k1Fp](2)
+	// the limb does not fit into 64 bits, but the value is equal as integers
+	witness.A.Limbs[0] = new(big.Int).Lsh(big.NewInt(1), 64)
+	witness.A.Limbs[0] = new(big.Int).Add(witness.A.Limbs[0].(*big.Int), big.NewInt(1))
+	witness.A.Limbs[1] = -1
+	assert.SolvingFailed(&circuit, &witness, test.WithCurves(testCurve), test.WithBackends(backend.GROTH16))
+}
+
+type SelectCircuit[T FieldParams] struct {
+	Selector frontend.Variable
+	A        Element[T]
+	B        Element[T]
+	C        Element[T]
+	D        Element[T]
+}
+
+func (c *SelectCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	l := f.Mul(&c.A, &c.B)
+	res := f.Select(c.Selector, l, &c.C)
+	f.AssertIsEqual(res, &c.D)
+	return nil
+}
+
+func testSelect[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness SelectCircuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		circuit.D = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		val2, _ := rand.Int(rand.Reader, fp.Modulus())
+		val3, _ := rand.Int(rand.Reader, fp.Modulus())
+		l := new(big.Int).Mul(val1, val2)
+		l.Mod(l, fp.Modulus())
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](val3)
+		witness.D = NewElement[T](l)
+		witness.Selector = 1
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestSelect(t *testing.T) {
+	testSelect[Goldilocks](t)
+	testSelect[Secp256k1Fp](t)
+	testSelect[BN254Fp](t)
+}
+
+type Lookup2Circuit[T FieldParams] struct {
+	Bit0 frontend.Variable
+	Bit1 frontend.Variable
+	A    Element[T]
+	B    Element[T]
+	C    Element[T]
+	D    Element[T]
+	E    Element[T]
+}
+
+func (c *Lookup2Circuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.Lookup2(c.Bit0, c.Bit1, &c.A, &c.B, &c.C, &c.D)
+	f.AssertIsEqual(res, &c.E)
+	return nil
+}
+
+func testLookup2[T FieldParams](t *testing.T) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness Lookup2Circuit[T]
+		circuit.A = NewElement[T](nil)
+		circuit.B = NewElement[T](nil)
+		circuit.C = NewElement[T](nil)
+		circuit.D = NewElement[T](nil)
+		circuit.E = NewElement[T](nil)
+		val1, _ := rand.Int(rand.Reader, fp.Modulus())
+		val2, _ := rand.Int(rand.Reader, fp.Modulus())
+		val3, _ := rand.Int(rand.Reader, fp.Modulus())
+		val4, _ := rand.Int(rand.Reader, fp.Modulus())
+		witness.A = NewElement[T](val1)
+		witness.B = NewElement[T](val2)
+		witness.C = NewElement[T](val3)
+		witness.D = NewElement[T](val4)
+		witness.E = NewElement[T](val3)
+		witness.Bit0 = 0
+		witness.Bit1 = 1
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestLookup2(t *testing.T) {
+	testLookup2[Goldilocks](t)
+	testLookup2[Secp256k1Fp](t)
+	testLookup2[BN254Fp](t)
+}
+
+type IsZeroCircuit[T FieldParams] struct {
+	A, B   Element[T]
+	IsZero frontend.Variable
+}
+
+func (c *IsZeroCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	res := f.IsZero(f.Sub(&c.A, &c.B))
+	api.AssertIsEqual(res, c.IsZero)
+	return nil
+}
+
+func TestIsZero(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp Secp256k1Fp
+	var circuit IsZeroCircuit[Secp256k1Fp]
+	circuit.A = NewElement[Secp256k1Fp](nil)
+	circuit.B = NewElement[Secp256k1Fp](nil)
+	val1, _ := rand.Int(rand.Reader, fp.Modulus())
+	val2, _ := rand.Int(rand.Reader, fp.Modulus())
+	assert.ProverSucceeded(&circuit, &IsZeroCircuit[Secp256k1Fp]{
+		A:      NewElement[Secp256k1Fp](val1),
+		B:      NewElement[Secp256k1Fp](val1),
+		IsZero: 1,
+	}, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	assert.ProverSucceeded(&circuit, &IsZeroCircuit[Secp256k1Fp]{
+		A:      NewElement[Secp256k1Fp](val1),
+		B:      NewElement[Secp256k1Fp](val2),
+		IsZero: 0,
+	}, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+}
+
+type ComputationCircuit[T FieldParams] struct {
+	noReduce bool
+
+	X1, X2, X3, X4, X5, X6 Element[T]
+	Res                    Element[T]
+}
+
+func (c *ComputationCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	// compute x1^3 + 5*x2 + (x3-x4) / (x5+x6)
+	x13 := f.Mul(&c.X1, &c.X1)
+	if !c.noReduce {
+		x13 = f.Reduce(x13)
+	}
+	x13 = f.Mul(x13, &c.X1)
+	if !c.noReduce {
+		x13 = f.Reduce(x13)
+	}
+
+	fx2 := f.MulConst(&c.X2, big.NewInt(5))
+	if !c.noReduce {
+		fx2 = f.Reduce(fx2)
+	}
+
+	nom := f.Sub(&c.X3, &c.X4)
+	if !c.noReduce {
+		nom = f.Reduce(nom)
+	}
+	denom := f.Add(&c.X5, &c.X6)
+	if !c.noReduce {
+		denom = f.Reduce(denom)
+	}
+	free := f.Div(nom, denom)
+	res := f.Add(x13, fx2)
+	res = f.Add(res, free)
+	f.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func testComputation[T FieldParams](t *testing.T, noReduce bool) {
+	var fp T
+	assert := test.NewAssert(t)
+	assert.Run(func(assert *test.Assert) {
+		var circuit, witness ComputationCircuit[T]
+		circuit.noReduce = noReduce
+		circuit.X1 = NewElement[T](nil)
+		circuit.X2 = NewElement[T](nil)
+		circuit.X3 = NewElement[T](nil)
+		circuit.X4 = NewElement[T](nil)
+		circuit.X5 = NewElement[T](nil)
+		circuit.X6 = NewElement[T](nil)
+		circuit.Res = NewElement[T](nil)
+
+		p := fp.Modulus()
+		val1, _ := rand.Int(rand.Reader, p)
+		val2, _ := rand.Int(rand.Reader, p)
+		val3, _ := rand.Int(rand.Reader, p)
+		val4, _ := rand.Int(rand.Reader, p)
+		val5, _ := rand.Int(rand.Reader, p)
+		val6, _ := rand.Int(rand.Reader, p)
+
+		tmp := new(big.Int)
+		res := new(big.Int)
+		// res = x1^3
+		tmp.Exp(val1, big.NewInt(3), p)
+		res.Set(tmp)
+		// res = x1^3 + 5*x2
+		tmp.Mul(val2, big.NewInt(5))
+		res.Add(res, tmp)
+		// tmp = (x3-x4)
+		tmp.Sub(val3, val4)
+		tmp.Mod(tmp, p)
+		// tmp2 = (x5+x6)
+		tmp2 := new(big.Int)
+		tmp2.Add(val5, val6)
+		// tmp = tmp / tmp2
+		tmp2.ModInverse(tmp2, p)
+		tmp.Mul(tmp, tmp2)
+		tmp.Mod(tmp, p)
+		// res = x1^3 + 5*x2 + (x3-x4)/(x5+x6)
+		res.Add(res, tmp)
+		res.Mod(res, p)
+
+		witness.X1 = NewElement[T](val1)
+		witness.X2 = NewElement[T](val2)
+		witness.X3 = NewElement[T](val3)
+		witness.X4 = NewElement[T](val4)
+		witness.X5 = NewElement[T](val5)
+		witness.X6 = NewElement[T](val6)
+		witness.Res = NewElement[T](res)
+
+		assert.ProverSucceeded(&circuit, &witness, test.WithCurves(testCurve), test.NoSerialization(), test.WithBackends(backend.GROTH16))
+	}, name[T]())
+}
+
+func TestComputation(t *testing.T) {
+	testComputation[Goldilocks](t, false)
+	testComputation[Goldilocks](t, true)
+	testComputation[Secp256k1Fp](t, false)
+	testComputation[Secp256k1Fp](t, true)
+	testComputation[BN254Fp](t, false)
+	testComputation[BN254Fp](t, true)
+}
+
+type ConstantCircuit[T FieldParams] struct {
+	A   Element[T]
+	Res Element[T]
+}
+
+func (c *ConstantCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	// res = (a + 3) * 7 - 2 / 5
+	three := f.NewElement(3)
+	res := f.Mul(f.Add(&c.A, three), f.NewElement(7))
+	res = f.Sub(res, f.Div(f.NewElement(2), f.NewElement(5)))
+	f.AssertIsEqual(res, &c.Res)
+	return nil
+}
+
+func TestConstant(t *testing.T) {
+	assert := test.NewAssert(t)
+	var fp Secp256k1Fp
+	p := fp.Modulus()
+	var circuit ConstantCircuit[Secp256k1Fp]
+	circuit.A = NewElement[Secp256k1Fp](nil)
+	circuit.Res = NewElement[Secp256k1Fp](nil)
+	val, _ := rand.Int(rand.Reader, p)
+	res := new(big.Int).Add(val, big.NewInt(3))
+	res.Mul(res, big.NewInt(7))
+	tmp := new(big.Int).ModInverse(big.NewInt(5), p)
+	tmp.Mul(tmp, big.NewInt(2))
+	res.Sub(res, tmp)
+	res.Mod(res, p)
+	assert.ProverSucceeded(&circuit, &ConstantCircuit[Secp256k1Fp]{
+		A:   NewElement[Secp256k1Fp](val),
+		Res: NewElement[Secp256k1Fp](res),
+	}, test.WithCurves(testCurve), test.NoSerialization())
+}
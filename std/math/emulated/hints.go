@@ -0,0 +1,199 @@
+package emulated
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+func init() {
+	for _, h := range GetHints() {
+		hint.Register(h)
+	}
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []hint.Function {
+	return []hint.Function{
+		QuoHint,
+		QuoRemHint,
+		InverseHint,
+		DivHint,
+		MultiplicationHint,
+		RightShift,
+	}
+}
+
+// parseHintInputs parses the common prefix of the hint inputs: the limb width
+// nbBits, the number of limbs of the modulus and the limbs of the modulus. It
+// returns the parsed values and the remaining inputs.
+func parseHintInputs(inputs []*big.Int) (nbBits uint, nbLimbs int, p *big.Int, rest []*big.Int, err error) {
+	if len(inputs) < 2 {
+		return 0, 0, nil, nil, errors.New("input must be at least two elements")
+	}
+	nbBits = uint(inputs[0].Uint64())
+	nbLimbs = int(inputs[1].Int64())
+	if len(inputs) < 2+nbLimbs {
+		return 0, 0, nil, nil, errors.New("modulus limbs missing")
+	}
+	p = new(big.Int)
+	if err := recompose(inputs[2:2+nbLimbs], nbBits, p); err != nil {
+		return 0, 0, nil, nil, fmt.Errorf("recompose modulus: %w", err)
+	}
+	if p.Sign() == 0 {
+		return 0, 0, nil, nil, errors.New("modulus is zero")
+	}
+	return nbBits, nbLimbs, p, inputs[2+nbLimbs:], nil
+}
+
+// QuoHint computes the quotient x / p for the modulus p. The inputs are the
+// limb width, the number of limbs of p, the limbs of p and the limbs of x. The
+// quotient is decomposed into the outputs.
+func QuoHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	nbBits, _, p, xLimbs, err := parseHintInputs(inputs)
+	if err != nil {
+		return err
+	}
+	x := new(big.Int)
+	if err := recompose(xLimbs, nbBits, x); err != nil {
+		return fmt.Errorf("recompose value: %w", err)
+	}
+	q := new(big.Int).Quo(x, p)
+	if err := decompose(q, nbBits, outputs); err != nil {
+		return fmt.Errorf("decompose quotient: %w", err)
+	}
+	return nil
+}
+
+// QuoRemHint computes the quotient q and remainder r such that x = q*p + r for
+// the modulus p. The inputs are the limb width, the number of limbs of p, the
+// limbs of p and the limbs of x. The quotient is decomposed into the first
+// outputs and the remainder into the last len(p) outputs.
+func QuoRemHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	nbBits, nbLimbs, p, xLimbs, err := parseHintInputs(inputs)
+	if err != nil {
+		return err
+	}
+	if len(outputs) < nbLimbs {
+		return errors.New("not enough outputs for remainder")
+	}
+	x := new(big.Int)
+	if err := recompose(xLimbs, nbBits, x); err != nil {
+		return fmt.Errorf("recompose value: %w", err)
+	}
+	q, r := new(big.Int), new(big.Int)
+	q.QuoRem(x, p, r)
+	nbQuoLimbs := len(outputs) - nbLimbs
+	if err := decompose(q, nbBits, outputs[:nbQuoLimbs]); err != nil {
+		return fmt.Errorf("decompose quotient: %w", err)
+	}
+	if err := decompose(r, nbBits, outputs[nbQuoLimbs:]); err != nil {
+		return fmt.Errorf("decompose remainder: %w", err)
+	}
+	return nil
+}
+
+// InverseHint computes the inverse x^-1 mod p. The inputs are the limb width,
+// the number of limbs of p, the limbs of p and the limbs of x. If x is not
+// invertible, then the outputs are set to zero.
+func InverseHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	nbBits, _, p, xLimbs, err := parseHintInputs(inputs)
+	if err != nil {
+		return err
+	}
+	x := new(big.Int)
+	if err := recompose(xLimbs, nbBits, x); err != nil {
+		return fmt.Errorf("recompose value: %w", err)
+	}
+	res := new(big.Int)
+	if res.ModInverse(x, p) == nil {
+		res.SetUint64(0)
+	}
+	if err := decompose(res, nbBits, outputs); err != nil {
+		return fmt.Errorf("decompose inverse: %w", err)
+	}
+	return nil
+}
+
+// DivHint computes the value x / y mod p. The inputs are the limb width, the
+// number of limbs of p, the limbs of p, the number of limbs of x, the limbs of
+// x and the limbs of y. If y is not invertible, then the outputs are set to
+// zero.
+func DivHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	nbBits, _, p, rest, err := parseHintInputs(inputs)
+	if err != nil {
+		return err
+	}
+	if len(rest) < 1 {
+		return errors.New("number of limbs of dividend missing")
+	}
+	nbLimbsX := int(rest[0].Int64())
+	if len(rest) < 1+nbLimbsX {
+		return errors.New("dividend limbs missing")
+	}
+	x, y := new(big.Int), new(big.Int)
+	if err := recompose(rest[1:1+nbLimbsX], nbBits, x); err != nil {
+		return fmt.Errorf("recompose dividend: %w", err)
+	}
+	if err := recompose(rest[1+nbLimbsX:], nbBits, y); err != nil {
+		return fmt.Errorf("recompose divisor: %w", err)
+	}
+	res := new(big.Int)
+	if res.ModInverse(y, p) == nil {
+		res.SetUint64(0)
+	}
+	res.Mul(res, x).Mod(res, p)
+	if err := decompose(res, nbBits, outputs); err != nil {
+		return fmt.Errorf("decompose division: %w", err)
+	}
+	return nil
+}
+
+// MultiplicationHint computes the coefficients of the product of the
+// polynomials defined by the limbs of a and b. The first input is the number
+// of limbs of a, followed by the limbs of a and the limbs of b. The outputs
+// are the len(a)+len(b)-1 coefficients of the product.
+func MultiplicationHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 1 {
+		return errors.New("number of limbs missing")
+	}
+	nbLimbsA := int(inputs[0].Int64())
+	if len(inputs) < 1+nbLimbsA {
+		return errors.New("limbs missing")
+	}
+	a, b := inputs[1:1+nbLimbsA], inputs[1+nbLimbsA:]
+	if len(a) == 0 || len(b) == 0 {
+		return errors.New("empty operand")
+	}
+	if len(outputs) != len(a)+len(b)-1 {
+		return errors.New("number of outputs mismatch")
+	}
+	for i := range outputs {
+		outputs[i].SetUint64(0)
+	}
+	tmp := new(big.Int)
+	for i := range a {
+		for j := range b {
+			tmp.Mul(a[i], b[j])
+			outputs[i+j].Add(outputs[i+j], tmp)
+		}
+	}
+	return nil
+}
+
+// RightShift shifts the second input to the right by the number of bits given
+// by the first input.
+func RightShift(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return errors.New("expecting two inputs")
+	}
+	if len(outputs) != 1 {
+		return errors.New("expecting single output")
+	}
+	shift := inputs[0].Uint64()
+	outputs[0].Rsh(inputs[1], uint(shift))
+	return nil
+}
@@ -0,0 +1,165 @@
+package emulated
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// FieldParams describes the emulated field characteristics. The limb width
+// and the number of limbs must be chosen such that BitsPerLimb()*NbLimbs() is
+// at least the bit length of the modulus.
+type FieldParams interface {
+	NbLimbs() uint     // number of limbs to represent field element
+	BitsPerLimb() uint // number of bits per limb. Top limb may contain less than limbSize bits.
+	IsPrime() bool     // indicates if the modulus is prime
+	Modulus() *big.Int // returns modulus. Do not modify.
+}
+
+var (
+	qGoldilocks, qSecp256k1, rSecp256k1  *big.Int
+	qBN254, rBN254, qBLS12381, rBLS12381 *big.Int
+	rBLS12377                            *big.Int
+	qP256, rP256, qEd25519, rEd25519     *big.Int
+)
+
+func init() {
+	qGoldilocks, _ = new(big.Int).SetString("ffffffff00000001", 16)
+	qSecp256k1, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	rSecp256k1, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	qBN254 = ecc.BN254.Info().Fp.Modulus()
+	rBN254 = ecc.BN254.Info().Fr.Modulus()
+	qBLS12381 = ecc.BLS12_381.Info().Fp.Modulus()
+	rBLS12381 = ecc.BLS12_381.Info().Fr.Modulus()
+	rBLS12377 = ecc.BLS12_377.Info().Fr.Modulus()
+	qP256, _ = new(big.Int).SetString("ffffffff00000001000000000000000000000000ffffffffffffffffffffffff", 16)
+	rP256, _ = new(big.Int).SetString("ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551", 16)
+	qEd25519, _ = new(big.Int).SetString("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed", 16)
+	rEd25519, _ = new(big.Int).SetString("1000000000000000000000000000000014def9dea2f79cd65812631a5cf5d3ed", 16)
+}
+
+// Goldilocks provides type parametrization for emulated field on 1 limb of width 64bits
+// for modulus 0xffffffff00000001
+type Goldilocks struct{}
+
+func (fp Goldilocks) NbLimbs() uint     { return 1 }
+func (fp Goldilocks) BitsPerLimb() uint { return 64 }
+func (fp Goldilocks) IsPrime() bool     { return true }
+func (fp Goldilocks) Modulus() *big.Int { return qGoldilocks }
+
+// Secp256k1Fp provides type parametrization for emulated field on 4 limb of width 64bits
+// for modulus 0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f.
+// This is the base field of secp256k1 curve
+type Secp256k1Fp struct{}
+
+func (fp Secp256k1Fp) NbLimbs() uint     { return 4 }
+func (fp Secp256k1Fp) BitsPerLimb() uint { return 64 }
+func (fp Secp256k1Fp) IsPrime() bool     { return true }
+func (fp Secp256k1Fp) Modulus() *big.Int { return qSecp256k1 }
+
+// Secp256k1Fr provides type parametrization for emulated field on 4 limbs of width 64bits
+// for modulus 0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141.
+// This is the scalar field of secp256k1 curve.
+type Secp256k1Fr struct{}
+
+func (fp Secp256k1Fr) NbLimbs() uint     { return 4 }
+func (fp Secp256k1Fr) BitsPerLimb() uint { return 64 }
+func (fp Secp256k1Fr) IsPrime() bool     { return true }
+func (fp Secp256k1Fr) Modulus() *big.Int { return rSecp256k1 }
+
+// BN254Fp provides type parametrization for emulated field on 4 limb of width
+// 64bits for modulus
+// 0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47. This is
+// the base field of the BN254 curve.
+type BN254Fp struct{}
+
+func (fp BN254Fp) NbLimbs() uint     { return 4 }
+func (fp BN254Fp) BitsPerLimb() uint { return 64 }
+func (fp BN254Fp) IsPrime() bool     { return true }
+func (fp BN254Fp) Modulus() *big.Int { return qBN254 }
+
+// BN254Fr provides type parametrization for emulated field on 4 limbs of width
+// 64bits for modulus
+// 0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001. This is
+// the scalar field of the BN254 curve.
+type BN254Fr struct{}
+
+func (fp BN254Fr) NbLimbs() uint     { return 4 }
+func (fp BN254Fr) BitsPerLimb() uint { return 64 }
+func (fp BN254Fr) IsPrime() bool     { return true }
+func (fp BN254Fr) Modulus() *big.Int { return rBN254 }
+
+// BLS12381Fp provides type parametrization for emulated field on 6 limbs of
+// width 64bits for modulus
+// 0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab.
+// This is the base field of the BLS12-381 curve.
+type BLS12381Fp struct{}
+
+func (fp BLS12381Fp) NbLimbs() uint     { return 6 }
+func (fp BLS12381Fp) BitsPerLimb() uint { return 64 }
+func (fp BLS12381Fp) IsPrime() bool     { return true }
+func (fp BLS12381Fp) Modulus() *big.Int { return qBLS12381 }
+
+// BLS12381Fr provides type parametrization for emulated field on 4 limbs of
+// width 64bits for modulus
+// 0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001. This is
+// the scalar field of the BLS12-381 curve.
+type BLS12381Fr struct{}
+
+func (fp BLS12381Fr) NbLimbs() uint     { return 4 }
+func (fp BLS12381Fr) BitsPerLimb() uint { return 64 }
+func (fp BLS12381Fr) IsPrime() bool     { return true }
+func (fp BLS12381Fr) Modulus() *big.Int { return rBLS12381 }
+
+// BLS12377Fr provides type parametrization for emulated field on 4 limbs of
+// width 64bits for modulus
+// 0x12ab655e9a2ca55660b44d1e5c37b00159aa76fed00000010a11800000000001. This is
+// the scalar field of the BLS12-377 curve.
+type BLS12377Fr struct{}
+
+func (fp BLS12377Fr) NbLimbs() uint     { return 4 }
+func (fp BLS12377Fr) BitsPerLimb() uint { return 64 }
+func (fp BLS12377Fr) IsPrime() bool     { return true }
+func (fp BLS12377Fr) Modulus() *big.Int { return rBLS12377 }
+
+// P256Fp provides type parametrization for emulated field on 4 limbs of width
+// 64bits for modulus
+// 0xffffffff00000001000000000000000000000000ffffffffffffffffffffffff. This is
+// the base field of the NIST P-256 curve.
+type P256Fp struct{}
+
+func (fp P256Fp) NbLimbs() uint     { return 4 }
+func (fp P256Fp) BitsPerLimb() uint { return 64 }
+func (fp P256Fp) IsPrime() bool     { return true }
+func (fp P256Fp) Modulus() *big.Int { return qP256 }
+
+// P256Fr provides type parametrization for emulated field on 4 limbs of width
+// 64bits for modulus
+// 0xffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551. This is
+// the scalar field of the NIST P-256 curve.
+type P256Fr struct{}
+
+func (fp P256Fr) NbLimbs() uint     { return 4 }
+func (fp P256Fr) BitsPerLimb() uint { return 64 }
+func (fp P256Fr) IsPrime() bool     { return true }
+func (fp P256Fr) Modulus() *big.Int { return rP256 }
+
+// Ed25519Fp provides type parametrization for emulated field on 4 limbs of
+// width 64bits for modulus 2^255-19. This is the base field of the
+// Curve25519/Ed25519 curve.
+type Ed25519Fp struct{}
+
+func (fp Ed25519Fp) NbLimbs() uint     { return 4 }
+func (fp Ed25519Fp) BitsPerLimb() uint { return 64 }
+func (fp Ed25519Fp) IsPrime() bool     { return true }
+func (fp Ed25519Fp) Modulus() *big.Int { return qEd25519 }
+
+// Ed25519Fr provides type parametrization for emulated field on 4 limbs of
+// width 64bits for modulus 2^252+27742317777372353535851937790883648493. This
+// is the order of the prime subgroup of the Ed25519 curve.
+type Ed25519Fr struct{}
+
+func (fp Ed25519Fr) NbLimbs() uint     { return 4 }
+func (fp Ed25519Fr) BitsPerLimb() uint { return 64 }
+func (fp Ed25519Fr) IsPrime() bool     { return true }
+func (fp Ed25519Fr) Modulus() *big.Int { return rEd25519 }
@@ -0,0 +1,227 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixedpoint implements fixed-point decimal arithmetic.
+//
+// A [Fixed] number is the non-negative rational Value·10^-Scale, where Value is
+// an integer which fits in the number of bits given to [New]. The operations
+// of [Arithmetic] return numbers at the largest scale of their operands. The
+// results of Mul, Div and Rescale to a lower scale are rounded with the given
+// [Rounding] mode.
+//
+// The operations assert that their results fit in the number of bits, which
+// detects overflows and, for Sub, negative results. The operands which were
+// not returned by [Arithmetic] are range checked before they are used. The
+// range checks are batched with [github.com/consensys/gnark/std/rangecheck].
+//
+// [NativeArithmetic] is the native reference implementation, which can be used
+// to compute the assignments.
+package fixedpoint
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Rounding is the rounding mode of the operations which can not be computed
+// exactly.
+type Rounding int
+
+const (
+	// Floor rounds towards zero.
+	Floor Rounding = iota
+	// Ceil rounds away from zero.
+	Ceil
+	// Nearest rounds to the nearest number, and halves away from zero.
+	Nearest
+)
+
+// Fixed is the fixed-point number Value·10^-Scale.
+type Fixed struct {
+	Value frontend.Variable
+	Scale uint
+
+	// checked is set if Value is known to fit in the number of bits.
+	checked bool
+}
+
+// Arithmetic implements the operations on fixed-point numbers whose values fit
+// in a given number of bits.
+type Arithmetic struct {
+	api      frontend.API
+	rchecker frontend.Rangechecker
+	nbBits   int
+}
+
+// New returns a new Arithmetic for fixed-point numbers whose values fit in
+// nbBits bits. As the products of values must not wrap around, nbBits must be
+// less than half of the bit size of the field.
+func New(api frontend.API, nbBits int) (*Arithmetic, error) {
+	if max := maxBits(api.Compiler().Curve().Info().Fr.Bits); nbBits <= 0 || nbBits > max {
+		return nil, fmt.Errorf("number of bits %d out of range [1, %d]", nbBits, max)
+	}
+	return &Arithmetic{
+		api:      api,
+		rchecker: rangecheck.New(api),
+		nbBits:   nbBits,
+	}, nil
+}
+
+// maxBits returns the largest number of bits such that q·d + r does not wrap
+// around for q, d, r smaller than 2^maxBits, and that 2r is a valid operand of
+// the bounded comparisons.
+func maxBits(fieldBits int) int {
+	return (fieldBits - 3) / 2
+}
+
+// ValueOf returns the number v·10^-scale. It asserts that v fits in the number
+// of bits.
+func (f *Arithmetic) ValueOf(v frontend.Variable, scale uint) Fixed {
+	return f.checked(Fixed{Value: v, Scale: scale})
+}
+
+// Add returns a + b.
+func (f *Arithmetic) Add(a, b Fixed) Fixed {
+	a, b = f.align(a, b)
+	return f.result(f.api.Add(a.Value, b.Value), a.Scale)
+}
+
+// Sub returns a - b. It asserts that the result is not negative.
+func (f *Arithmetic) Sub(a, b Fixed) Fixed {
+	a, b = f.align(a, b)
+	return f.result(f.api.Sub(a.Value, b.Value), a.Scale)
+}
+
+// Mul returns a·b rounded with the rounding mode.
+func (f *Arithmetic) Mul(a, b Fixed, rounding Rounding) Fixed {
+	a, b = f.checked(a), f.checked(b)
+	scale := maxScale(a, b)
+	// a·b = a.Value·b.Value·10^-(a.Scale+b.Scale)
+	d := f.pow10(a.Scale + b.Scale - scale)
+	return f.divRound(f.api.Mul(a.Value, b.Value), d, scale, rounding)
+}
+
+// Div returns a/b rounded with the rounding mode. It asserts that b is not
+// zero.
+func (f *Arithmetic) Div(a, b Fixed, rounding Rounding) Fixed {
+	a, b = f.checked(a), f.checked(b)
+	scale := maxScale(a, b)
+	// a/b = a.Value·10^(scale-a.Scale+b.Scale)/b.Value·10^-scale
+	num := f.api.Mul(a.Value, f.pow10(scale-a.Scale+b.Scale))
+	return f.divRound(num, b.Value, scale, rounding)
+}
+
+// Rescale returns a at the given scale, rounded with the rounding mode if the
+// scale is lower than the scale of a.
+func (f *Arithmetic) Rescale(a Fixed, scale uint, rounding Rounding) Fixed {
+	a = f.checked(a)
+	if scale >= a.Scale {
+		return f.result(f.api.Mul(a.Value, f.pow10(scale-a.Scale)), scale)
+	}
+	return f.divRound(a.Value, f.pow10(a.Scale-scale), scale, rounding)
+}
+
+// AssertIsEqual asserts that a and b are equal numbers, which may be given at
+// different scales.
+func (f *Arithmetic) AssertIsEqual(a, b Fixed) {
+	a, b = f.align(a, b)
+	f.api.AssertIsEqual(a.Value, b.Value)
+}
+
+// divRound returns num/d·10^-scale rounded with the rounding mode. num must be
+// smaller than 2^(2·nbBits). It asserts that d is not zero and that the result
+// fits in the number of bits.
+func (f *Arithmetic) divRound(num, d frontend.Variable, scale uint, rounding Rounding) Fixed {
+	cNum, numConstant := f.api.Compiler().ConstantValue(num)
+	cD, dConstant := f.api.Compiler().ConstantValue(d)
+	if numConstant && dConstant {
+		res, err := NativeArithmetic{NbBits: f.nbBits}.divRound(cNum, cD, rounding)
+		if err != nil {
+			panic(err)
+		}
+		return f.result(res, scale)
+	}
+
+	res, err := f.api.Compiler().NewHint(hint.DivRem, 2, num, d)
+	if err != nil {
+		panic(err)
+	}
+	q, r := res[0], res[1]
+	f.rchecker.Check(q, f.nbBits)
+	// r < d also asserts that d is not zero
+	f.api.AssertIsLess(r, d, f.nbBits)
+	f.api.AssertIsEqual(f.api.Add(f.api.Mul(q, d), r), num)
+
+	switch rounding {
+	case Floor:
+		return Fixed{Value: q, Scale: scale, checked: true}
+	case Ceil:
+		// q + 1 if r != 0
+		return f.result(f.api.Add(q, f.api.Sub(1, f.api.IsZero(r))), scale)
+	case Nearest:
+		// q + 1 if 2r >= d
+		return f.result(f.api.Add(q, f.api.Sub(1, f.api.IsLess(f.api.Mul(r, 2), d, f.nbBits+1))), scale)
+	default:
+		panic(fmt.Sprintf("unknown rounding mode %d", rounding))
+	}
+}
+
+// align returns a and b, range checked, at the largest of their scales.
+func (f *Arithmetic) align(a, b Fixed) (Fixed, Fixed) {
+	scale := maxScale(a, b)
+	return f.Rescale(a, scale, Floor), f.Rescale(b, scale, Floor)
+}
+
+// pow10 returns 10^e. It panics if 10^e does not fit in the number of bits, as
+// the numerators would then not fit in twice the number of bits.
+func (f *Arithmetic) pow10(e uint) *big.Int {
+	res := pow10(e)
+	if res.BitLen() > f.nbBits {
+		panic(fmt.Sprintf("10^%d does not fit in %d bits", e, f.nbBits))
+	}
+	return res
+}
+
+// result returns the number v·10^-scale. It asserts that v fits in the number
+// of bits.
+func (f *Arithmetic) result(v frontend.Variable, scale uint) Fixed {
+	return f.checked(Fixed{Value: v, Scale: scale})
+}
+
+// checked returns a, range checked if its value is not known to fit in the
+// number of bits.
+func (f *Arithmetic) checked(a Fixed) Fixed {
+	if !a.checked {
+		f.rchecker.Check(a.Value, f.nbBits)
+		a.checked = true
+	}
+	return a
+}
+
+func maxScale(a, b Fixed) uint {
+	if a.Scale > b.Scale {
+		return a.Scale
+	}
+	return b.Scale
+}
+
+func pow10(e uint) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), new(big.Int).SetUint64(uint64(e)), nil)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixedpoint
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	nbBits = 64
+	scaleA = 6
+	scaleB = 4
+)
+
+var roundings = []Rounding{Floor, Ceil, Nearest}
+
+type fixedCircuit struct {
+	A, B       Fixed
+	Sum, Diff  Fixed
+	Prod, Quo  [3]Fixed
+	Rescaled   [3]Fixed
+	Upscaled   Fixed
+	SumAtScale Fixed
+}
+
+func newFixedCircuit() *fixedCircuit {
+	c := &fixedCircuit{
+		A:          Fixed{Scale: scaleA},
+		B:          Fixed{Scale: scaleB},
+		Sum:        Fixed{Scale: scaleA},
+		Diff:       Fixed{Scale: scaleA},
+		Upscaled:   Fixed{Scale: scaleA + 2},
+		SumAtScale: Fixed{Scale: scaleB},
+	}
+	for i := range roundings {
+		c.Prod[i].Scale = scaleA
+		c.Quo[i].Scale = scaleA
+		c.Rescaled[i].Scale = scaleB - 2
+	}
+	return c
+}
+
+func (c *fixedCircuit) Define(api frontend.API) error {
+	f, err := New(api, nbBits)
+	if err != nil {
+		return err
+	}
+	a := f.ValueOf(c.A.Value, c.A.Scale)
+	f.AssertIsEqual(f.Add(a, c.B), c.Sum)
+	f.AssertIsEqual(f.Sub(a, c.B), c.Diff)
+	for i, r := range roundings {
+		f.AssertIsEqual(f.Mul(a, c.B, r), c.Prod[i])
+		f.AssertIsEqual(f.Div(a, c.B, r), c.Quo[i])
+		f.AssertIsEqual(f.Rescale(c.B, scaleB-2, r), c.Rescaled[i])
+	}
+	f.AssertIsEqual(f.Rescale(a, scaleA+2, Floor), c.Upscaled)
+	// numbers at different scales are compared exactly
+	f.AssertIsEqual(f.Rescale(f.Add(a, c.B), scaleB, Floor), c.SumAtScale)
+	return nil
+}
+
+// assignment computes the outputs of the circuit with the reference
+// implementation.
+func assignment(a, b *big.Int) (*fixedCircuit, error) {
+	f := NativeArithmetic{NbBits: nbBits}
+	na, nb := Native{Value: a, Scale: scaleA}, Native{Value: b, Scale: scaleB}
+	res := newFixedCircuit()
+	res.A, res.B = na.Fixed(), nb.Fixed()
+	sum, err := f.Add(na, nb)
+	if err != nil {
+		return nil, err
+	}
+	res.Sum = sum.Fixed()
+	diff, err := f.Sub(na, nb)
+	if err != nil {
+		return nil, err
+	}
+	res.Diff = diff.Fixed()
+	for i, r := range roundings {
+		prod, err := f.Mul(na, nb, r)
+		if err != nil {
+			return nil, err
+		}
+		res.Prod[i] = prod.Fixed()
+		quo, err := f.Div(na, nb, r)
+		if err != nil {
+			return nil, err
+		}
+		res.Quo[i] = quo.Fixed()
+		rescaled, err := f.Rescale(nb, scaleB-2, r)
+		if err != nil {
+			return nil, err
+		}
+		res.Rescaled[i] = rescaled.Fixed()
+	}
+	upscaled, err := f.Rescale(na, scaleA+2, Floor)
+	if err != nil {
+		return nil, err
+	}
+	res.Upscaled = upscaled.Fixed()
+	sumAtScale, err := f.Rescale(sum, scaleB, Floor)
+	if err != nil {
+		return nil, err
+	}
+	res.SumAtScale = sumAtScale.Fixed()
+	return res, nil
+}
+
+func TestFixed(t *testing.T) {
+	assert := test.NewAssert(t)
+	r := rand.New(rand.NewSource(1))
+
+	// 123.456789 and 45.6789, the remainders exercise the rounding modes
+	w, err := assignment(big.NewInt(123456789), big.NewInt(456789))
+	assert.NoError(err)
+	assert.ProverSucceeded(newFixedCircuit(), w, test.WithCurves(ecc.BN254))
+
+	for i := 0; i < 10; i++ {
+		a := new(big.Int).Rand(r, big.NewInt(1<<40))
+		b := new(big.Int).Rand(r, new(big.Int).Div(a, big.NewInt(100)))
+		b.Add(b, big.NewInt(1))
+		w, err := assignment(a, b)
+		assert.NoError(err)
+		assert.SolvingSucceeded(newFixedCircuit(), w, test.WithCurves(ecc.BN254))
+	}
+
+	// every wrongly rounded result is rejected
+	w, err = assignment(big.NewInt(123456789), big.NewInt(456789))
+	assert.NoError(err)
+	for i := range roundings {
+		wrong := *w
+		wrong.Prod[i].Value = new(big.Int).Add(w.Prod[i].Value.(*big.Int), big.NewInt(1))
+		assert.SolvingFailed(newFixedCircuit(), &wrong, test.WithCurves(ecc.BN254))
+		wrong = *w
+		wrong.Quo[i].Value = new(big.Int).Sub(w.Quo[i].Value.(*big.Int), big.NewInt(1))
+		assert.SolvingFailed(newFixedCircuit(), &wrong, test.WithCurves(ecc.BN254))
+	}
+}
+
+type overflowCircuit struct {
+	A, B Fixed
+	op   string
+}
+
+func (c *overflowCircuit) Define(api frontend.API) error {
+	f, err := New(api, nbBits)
+	if err != nil {
+		return err
+	}
+	switch c.op {
+	case "add":
+		f.Add(c.A, c.B)
+	case "sub":
+		f.Sub(c.A, c.B)
+	case "mul":
+		f.Mul(c.A, c.B, Ceil)
+	case "div":
+		f.Div(c.A, c.B, Nearest)
+	}
+	return nil
+}
+
+func TestOverflow(t *testing.T) {
+	max := new(big.Int).Lsh(big.NewInt(1), nbBits)
+	max.Sub(max, big.NewInt(1))
+	f := NativeArithmetic{NbBits: nbBits}
+	for _, tc := range []struct {
+		name, op string
+		a, b     *big.Int
+	}{
+		{"add", "add", max, big.NewInt(1)},
+		{"sub", "sub", big.NewInt(1), big.NewInt(2)},
+		{"mul", "mul", max, big.NewInt(1000001)},
+		{"div", "div", max, big.NewInt(999999)},
+		{"div by zero", "div", big.NewInt(1), big.NewInt(0)},
+		{"operand", "add", new(big.Int).Add(max, big.NewInt(1)), big.NewInt(0)},
+	} {
+		assert := test.NewAssert(t)
+		assert.Run(func(assert *test.Assert) {
+			a, b := Native{Value: tc.a, Scale: 6}, Native{Value: tc.b, Scale: 6}
+			var err error
+			switch tc.op {
+			case "add":
+				_, err = f.Add(a, b)
+			case "sub":
+				_, err = f.Sub(a, b)
+			case "mul":
+				_, err = f.Mul(a, b, Ceil)
+			case "div":
+				_, err = f.Div(a, b, Nearest)
+			}
+			assert.Error(err)
+			assert.Equal(tc.name != "div by zero", errors.Is(err, ErrOverflow))
+			circuit := &overflowCircuit{A: Fixed{Scale: 6}, B: Fixed{Scale: 6}, op: tc.op}
+			assert.SolvingFailed(circuit, &overflowCircuit{A: Fixed{Value: tc.a}, B: Fixed{Value: tc.b}}, test.WithCurves(ecc.BN254))
+		}, tc.name)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fixedpoint
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Native is the native counterpart of [Fixed], the number Value·10^-Scale.
+type Native struct {
+	Value *big.Int
+	Scale uint
+}
+
+// Fixed returns n as a Fixed, for example to be used in an assignment.
+func (n Native) Fixed() Fixed {
+	return Fixed{Value: n.Value, Scale: n.Scale}
+}
+
+// NativeArithmetic is the native reference implementation of [Arithmetic]. It
+// returns the same results and returns an error where the circuit would not be
+// satisfied.
+type NativeArithmetic struct {
+	NbBits int
+}
+
+// ErrOverflow is returned when a result does not fit in the number of bits.
+var ErrOverflow = errors.New("fixed-point overflow")
+
+// Add returns a + b.
+func (f NativeArithmetic) Add(a, b Native) (Native, error) {
+	a, b, err := f.align(a, b)
+	if err != nil {
+		return Native{}, err
+	}
+	return f.result(new(big.Int).Add(a.Value, b.Value), a.Scale)
+}
+
+// Sub returns a - b.
+func (f NativeArithmetic) Sub(a, b Native) (Native, error) {
+	a, b, err := f.align(a, b)
+	if err != nil {
+		return Native{}, err
+	}
+	return f.result(new(big.Int).Sub(a.Value, b.Value), a.Scale)
+}
+
+// Mul returns a·b rounded with the rounding mode.
+func (f NativeArithmetic) Mul(a, b Native, rounding Rounding) (Native, error) {
+	if err := f.check(a, b); err != nil {
+		return Native{}, err
+	}
+	scale := a.Scale
+	if b.Scale > scale {
+		scale = b.Scale
+	}
+	d, err := f.pow10(a.Scale + b.Scale - scale)
+	if err != nil {
+		return Native{}, err
+	}
+	res, err := f.divRound(new(big.Int).Mul(a.Value, b.Value), d, rounding)
+	if err != nil {
+		return Native{}, err
+	}
+	return Native{Value: res, Scale: scale}, nil
+}
+
+// Div returns a/b rounded with the rounding mode.
+func (f NativeArithmetic) Div(a, b Native, rounding Rounding) (Native, error) {
+	if err := f.check(a, b); err != nil {
+		return Native{}, err
+	}
+	scale := a.Scale
+	if b.Scale > scale {
+		scale = b.Scale
+	}
+	e, err := f.pow10(scale - a.Scale + b.Scale)
+	if err != nil {
+		return Native{}, err
+	}
+	res, err := f.divRound(new(big.Int).Mul(a.Value, e), b.Value, rounding)
+	if err != nil {
+		return Native{}, err
+	}
+	return Native{Value: res, Scale: scale}, nil
+}
+
+// Rescale returns a at the given scale, rounded with the rounding mode if the
+// scale is lower than the scale of a.
+func (f NativeArithmetic) Rescale(a Native, scale uint, rounding Rounding) (Native, error) {
+	if err := f.check(a); err != nil {
+		return Native{}, err
+	}
+	if scale >= a.Scale {
+		e, err := f.pow10(scale - a.Scale)
+		if err != nil {
+			return Native{}, err
+		}
+		return f.result(new(big.Int).Mul(a.Value, e), scale)
+	}
+	d, err := f.pow10(a.Scale - scale)
+	if err != nil {
+		return Native{}, err
+	}
+	res, err := f.divRound(a.Value, d, rounding)
+	if err != nil {
+		return Native{}, err
+	}
+	return Native{Value: res, Scale: scale}, nil
+}
+
+// divRound returns num/d rounded with the rounding mode.
+func (f NativeArithmetic) divRound(num, d *big.Int, rounding Rounding) (*big.Int, error) {
+	if d.Sign() == 0 {
+		return nil, errors.New("division by zero")
+	}
+	var q, r big.Int
+	q.QuoRem(num, d, &r)
+	switch rounding {
+	case Floor:
+	case Ceil:
+		if r.Sign() != 0 {
+			q.Add(&q, big.NewInt(1))
+		}
+	case Nearest:
+		if new(big.Int).Lsh(&r, 1).Cmp(d) >= 0 {
+			q.Add(&q, big.NewInt(1))
+		}
+	default:
+		return nil, fmt.Errorf("unknown rounding mode %d", rounding)
+	}
+	if err := f.fits(&q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (f NativeArithmetic) align(a, b Native) (Native, Native, error) {
+	scale := a.Scale
+	if b.Scale > scale {
+		scale = b.Scale
+	}
+	a, err := f.Rescale(a, scale, Floor)
+	if err != nil {
+		return Native{}, Native{}, err
+	}
+	b, err = f.Rescale(b, scale, Floor)
+	return a, b, err
+}
+
+func (f NativeArithmetic) result(v *big.Int, scale uint) (Native, error) {
+	if err := f.fits(v); err != nil {
+		return Native{}, err
+	}
+	return Native{Value: v, Scale: scale}, nil
+}
+
+func (f NativeArithmetic) check(a ...Native) error {
+	for i := range a {
+		if err := f.fits(a[i].Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pow10 returns 10^e, or an error if it does not fit in the number of bits as
+// the circuit does not support such scale differences.
+func (f NativeArithmetic) pow10(e uint) (*big.Int, error) {
+	res := pow10(e)
+	if res.BitLen() > f.NbBits {
+		return nil, fmt.Errorf("10^%d does not fit in %d bits", e, f.NbBits)
+	}
+	return res, nil
+}
+
+func (f NativeArithmetic) fits(v *big.Int) error {
+	if v.Sign() < 0 || v.BitLen() > f.NbBits {
+		return fmt.Errorf("%w: %s does not fit in %d bits", ErrOverflow, v, f.NbBits)
+	}
+	return nil
+}
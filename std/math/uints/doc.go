@@ -0,0 +1,42 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package uints implements unsigned integers of fixed width and the bitwise
+// operations on them.
+//
+// The integers are represented as arrays of bytes [U8] in little-endian
+// order: [U32] has four bytes and [U64] has eight bytes. The operations are
+// performed by [BinaryField], which is parametrized by the integer type.
+//
+// The bitwise operations work on the bit decompositions of the bytes. A byte
+// keeps its decomposition once it is known, so that chaining bitwise
+// operations does not decompose the bytes again. Rotations and shifts by
+// multiples of eight only permute the bytes and are free.
+//
+// The bytes which are not produced by this package, for example the bytes
+// given in the witness, are range checked before they are used. The range
+// checks are batched with [github.com/consensys/gnark/std/rangecheck], which
+// chooses the cheapest method for the builder.
+//
+// The implementation of the addition depends on the builder. If the builder
+// implements [github.com/consensys/gnark/frontend.Committer], such as the
+// R1CS builder, then the bytes of the sum are range checked, which the range
+// checker does with cheap lookups, and their bits are decomposed only if a
+// bitwise operation needs them. Otherwise the sum is decomposed into bits,
+// which are kept for the following bitwise operations. The bitwise operations
+// themselves always work on bits: a lookup table of the pairs of bytes has
+// 2^16 entries and pays off only for very large circuits.
+package uints
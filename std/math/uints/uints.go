@@ -0,0 +1,413 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import (
+	"fmt"
+	"math/big"
+	mbits "math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// U8 is an unsigned 8-bit integer.
+type U8 struct {
+	Val frontend.Variable
+
+	// internal is set if Val is known to fit in 8 bits.
+	internal bool
+	// bits is the little-endian bit decomposition of Val, if known.
+	bits []frontend.Variable
+}
+
+// U32 is an unsigned 32-bit integer given by its bytes in little-endian order.
+type U32 [4]U8
+
+// U64 is an unsigned 64-bit integer given by its bytes in little-endian order.
+type U64 [8]U8
+
+// Long is the set of the integer types supported by [BinaryField].
+type Long interface {
+	U32 | U64
+}
+
+// NewU8 returns the constant byte v. It can also be used as an assignment.
+func NewU8(v uint8) U8 {
+	b := make([]frontend.Variable, 8)
+	for i := range b {
+		b[i] = uint((v >> i) & 1)
+	}
+	return U8{Val: v, internal: true, bits: b}
+}
+
+// NewU32 returns the constant v. It can also be used as an assignment.
+func NewU32(v uint32) U32 {
+	var r U32
+	for i := range r {
+		r[i] = NewU8(uint8(v >> (8 * i)))
+	}
+	return r
+}
+
+// NewU64 returns the constant v. It can also be used as an assignment.
+func NewU64(v uint64) U64 {
+	var r U64
+	for i := range r {
+		r[i] = NewU8(uint8(v >> (8 * i)))
+	}
+	return r
+}
+
+// BinaryField implements the arithmetic and the bitwise operations on the
+// integers of type T.
+type BinaryField[T Long] struct {
+	api      frontend.API
+	rchecker frontend.Rangechecker
+
+	// lazyBits is set if the builder commits to the range checked bytes, the
+	// results of the arithmetic are then range checked with lookups and
+	// decomposed only when a bitwise operation needs their bits.
+	lazyBits bool
+}
+
+// New returns a new BinaryField for the integers of type T.
+func New[T Long](api frontend.API) *BinaryField[T] {
+	_, lazyBits := api.(frontend.Committer)
+	return &BinaryField[T]{
+		api:      api,
+		rchecker: rangecheck.New(api),
+		lazyBits: lazyBits,
+	}
+}
+
+// nbBytes returns the number of bytes of T.
+func (bf *BinaryField[T]) nbBytes() int {
+	var a T
+	return len(a)
+}
+
+// ByteValueOf returns the byte a. It asserts that a fits in 8 bits.
+func (bf *BinaryField[T]) ByteValueOf(a frontend.Variable) U8 {
+	if c, ok := bf.api.Compiler().ConstantValue(a); ok {
+		if !c.IsUint64() || c.Uint64() > 0xff {
+			panic(fmt.Sprintf("constant %s does not fit in a byte", c))
+		}
+		return NewU8(uint8(c.Uint64()))
+	}
+	bf.rchecker.Check(a, 8)
+	return U8{Val: a, internal: true}
+}
+
+// ValueOf returns the integer a. It asserts that a fits in the width of T.
+func (bf *BinaryField[T]) ValueOf(a frontend.Variable) T {
+	nbBits := 8 * bf.nbBytes()
+	if c, ok := bf.api.Compiler().ConstantValue(a); ok {
+		if c.Sign() < 0 || c.BitLen() > nbBits {
+			panic(fmt.Sprintf("constant %s does not fit in %d bits", c, nbBits))
+		}
+	}
+	return bf.fromBits(bits.ToBinary(bf.api, a, bits.WithNbDigits(nbBits)))
+}
+
+// ToValue returns the integer a as a single variable.
+func (bf *BinaryField[T]) ToValue(a T) frontend.Variable {
+	res := frontend.Variable(0)
+	coef := big.NewInt(1)
+	for i := 0; i < len(a); i++ {
+		res = bf.api.Add(res, bf.api.Mul(bf.checked(a[i]).Val, coef))
+		coef.Lsh(coef, 8)
+	}
+	return res
+}
+
+// PackLSB returns the integer given by its bytes in little-endian order.
+func (bf *BinaryField[T]) PackLSB(a ...U8) T {
+	var r T
+	if len(a) != len(r) {
+		panic(fmt.Sprintf("expected %d bytes, got %d", len(r), len(a)))
+	}
+	for i := 0; i < len(r); i++ {
+		r[i] = a[i]
+	}
+	return r
+}
+
+// PackMSB returns the integer given by its bytes in big-endian order.
+func (bf *BinaryField[T]) PackMSB(a ...U8) T {
+	var r T
+	if len(a) != len(r) {
+		panic(fmt.Sprintf("expected %d bytes, got %d", len(r), len(a)))
+	}
+	for i := 0; i < len(r); i++ {
+		r[len(r)-1-i] = a[i]
+	}
+	return r
+}
+
+// UnpackLSB returns the bytes of a in little-endian order.
+func (bf *BinaryField[T]) UnpackLSB(a T) []U8 {
+	r := make([]U8, len(a))
+	for i := range r {
+		r[i] = a[i]
+	}
+	return r
+}
+
+// UnpackMSB returns the bytes of a in big-endian order.
+func (bf *BinaryField[T]) UnpackMSB(a T) []U8 {
+	r := make([]U8, len(a))
+	for i := range r {
+		r[i] = a[len(a)-1-i]
+	}
+	return r
+}
+
+// Add returns the sum of the integers modulo 2^n, where n is the width of T.
+func (bf *BinaryField[T]) Add(a ...T) T {
+	if len(a) == 0 {
+		panic("no integers to add")
+	}
+	nbBits := 8 * bf.nbBytes()
+	// the sum is smaller than len(a)·2^n
+	nbCarry := mbits.Len(uint(len(a) - 1))
+	sum := frontend.Variable(0)
+	for i := range a {
+		sum = bf.api.Add(sum, bf.ToValue(a[i]))
+	}
+	var sumBits []frontend.Variable
+	if c, ok := bf.api.Compiler().ConstantValue(sum); ok {
+		sumBits = make([]frontend.Variable, nbBits)
+		for i := range sumBits {
+			sumBits[i] = c.Bit(i)
+		}
+	} else if bf.lazyBits && nbCarry <= 8 {
+		return bf.splitBytes(sum, nbCarry)
+	} else {
+		sumBits = bits.ToBinary(bf.api, sum, bits.WithNbDigits(nbBits+nbCarry))
+	}
+	return bf.fromBits(sumBits[:nbBits])
+}
+
+// splitBytes returns the integer given by the low bytes of v, where v is
+// smaller than 2^(n+nbCarry) and n is the width of T. The bytes and the carry
+// are range checked and the bits of the bytes are left unknown.
+func (bf *BinaryField[T]) splitBytes(v frontend.Variable, nbCarry int) T {
+	var r T
+	limbs, err := bf.api.Compiler().NewHint(rangecheck.DecomposeHint, len(r)+1, 8, v)
+	if err != nil {
+		panic(err)
+	}
+	composed := frontend.Variable(0)
+	coef := big.NewInt(1)
+	for i := range limbs {
+		composed = bf.api.Add(composed, bf.api.Mul(limbs[i], coef))
+		coef.Lsh(coef, 8)
+	}
+	bf.api.AssertIsEqual(composed, v)
+	for i := 0; i < len(r); i++ {
+		r[i] = bf.ByteValueOf(limbs[i])
+	}
+	bf.rchecker.Check(limbs[len(r)], nbCarry)
+	return r
+}
+
+// Xor returns the bitwise exclusive or of the integers.
+func (bf *BinaryField[T]) Xor(a ...T) T {
+	return bf.bitwise(a, bf.api.Xor, func(x, y uint) uint { return x ^ y })
+}
+
+// And returns the bitwise and of the integers.
+func (bf *BinaryField[T]) And(a ...T) T {
+	return bf.bitwise(a, bf.api.And, func(x, y uint) uint { return x & y })
+}
+
+// Or returns the bitwise or of the integers.
+func (bf *BinaryField[T]) Or(a ...T) T {
+	return bf.bitwise(a, bf.api.Or, func(x, y uint) uint { return x | y })
+}
+
+// Not returns the bitwise negation of a.
+func (bf *BinaryField[T]) Not(a T) T {
+	var r T
+	for i := 0; i < len(r); i++ {
+		ab := bf.bitsOf(a[i])
+		rb := make([]frontend.Variable, 8)
+		for j := range rb {
+			rb[j] = bf.api.Sub(1, ab[j])
+			bf.api.Compiler().MarkBoolean(rb[j])
+		}
+		r[i] = bf.byteOf(rb)
+	}
+	return r
+}
+
+// Lrot returns a rotated left by c bits. The rotation is right if c is
+// negative.
+func (bf *BinaryField[T]) Lrot(a T, c int) T {
+	nbBits := 8 * len(a)
+	c %= nbBits
+	if c < 0 {
+		c += nbBits
+	}
+	var r T
+	if c%8 == 0 {
+		for i := 0; i < len(r); i++ {
+			r[(i+c/8)%len(r)] = a[i]
+		}
+		return r
+	}
+	ab := bf.toBits(a)
+	rb := make([]frontend.Variable, nbBits)
+	for i := range ab {
+		rb[(i+c)%nbBits] = ab[i]
+	}
+	return bf.fromBits(rb)
+}
+
+// Rrot returns a rotated right by c bits. The rotation is left if c is
+// negative.
+func (bf *BinaryField[T]) Rrot(a T, c int) T {
+	return bf.Lrot(a, -c)
+}
+
+// Shr returns a shifted right by c bits.
+func (bf *BinaryField[T]) Shr(a T, c int) T {
+	if c < 0 {
+		panic("negative shift")
+	}
+	var r T
+	if c >= 8*len(a) {
+		for i := 0; i < len(r); i++ {
+			r[i] = NewU8(0)
+		}
+		return r
+	}
+	if c%8 == 0 {
+		for i := 0; i < len(r); i++ {
+			if i+c/8 < len(a) {
+				r[i] = a[i+c/8]
+			} else {
+				r[i] = NewU8(0)
+			}
+		}
+		return r
+	}
+	ab := bf.toBits(a)
+	rb := make([]frontend.Variable, len(ab))
+	for i := range rb {
+		if i+c < len(ab) {
+			rb[i] = ab[i+c]
+		} else {
+			rb[i] = 0
+		}
+	}
+	return bf.fromBits(rb)
+}
+
+// ByteAssertEq asserts that the bytes a and b are equal.
+func (bf *BinaryField[T]) ByteAssertEq(a, b U8) {
+	bf.api.AssertIsEqual(a.Val, b.Val)
+}
+
+// AssertEq asserts that the integers a and b are equal.
+func (bf *BinaryField[T]) AssertEq(a, b T) {
+	for i := 0; i < len(a); i++ {
+		bf.ByteAssertEq(a[i], b[i])
+	}
+}
+
+// bitwise folds the bitwise operation op over the bits of the integers a.
+// Pairs of constant bits are folded with native.
+func (bf *BinaryField[T]) bitwise(a []T, op func(frontend.Variable, frontend.Variable, ...frontend.ApiOption) frontend.Variable, native func(uint, uint) uint) T {
+	if len(a) == 0 {
+		panic("no integers given")
+	}
+	if len(a) == 1 {
+		return a[0]
+	}
+	res := bf.toBits(a[0])
+	for _, ai := range a[1:] {
+		ab := bf.toBits(ai)
+		for j := range res {
+			x, xConstant := bf.api.Compiler().ConstantValue(res[j])
+			y, yConstant := bf.api.Compiler().ConstantValue(ab[j])
+			if xConstant && yConstant {
+				res[j] = native(x.Bit(0), y.Bit(0))
+			} else {
+				res[j] = op(res[j], ab[j])
+				bf.api.Compiler().MarkBoolean(res[j])
+			}
+		}
+	}
+	return bf.fromBits(res)
+}
+
+// toBits returns the little-endian bit decomposition of a.
+func (bf *BinaryField[T]) toBits(a T) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(a))
+	for i := 0; i < len(a); i++ {
+		res = append(res, bf.bitsOf(a[i])...)
+	}
+	return res
+}
+
+// fromBits returns the integer given by its little-endian bits. The bits must
+// be constrained to be boolean.
+func (bf *BinaryField[T]) fromBits(b []frontend.Variable) T {
+	var r T
+	if len(b) != 8*len(r) {
+		panic(fmt.Sprintf("expected %d bits, got %d", 8*len(r), len(b)))
+	}
+	for i := 0; i < len(r); i++ {
+		r[i] = bf.byteOf(b[8*i : 8*i+8])
+	}
+	return r
+}
+
+// byteOf returns the byte given by its little-endian bits. The bits must be
+// constrained to be boolean.
+func (bf *BinaryField[T]) byteOf(b []frontend.Variable) U8 {
+	bb := make([]frontend.Variable, 8)
+	copy(bb, b)
+	val := bits.FromBinary(bf.api, bb, bits.WithUnconstrainedInputs())
+	return U8{Val: val, internal: true, bits: bb}
+}
+
+// bitsOf returns the little-endian bit decomposition of a. If it is not known,
+// then the byte is decomposed, which also asserts that it fits in 8 bits.
+func (bf *BinaryField[T]) bitsOf(a U8) []frontend.Variable {
+	if a.bits != nil {
+		return a.bits
+	}
+	if c, ok := bf.api.Compiler().ConstantValue(a.Val); ok {
+		if !c.IsUint64() || c.Uint64() > 0xff {
+			panic(fmt.Sprintf("constant %s does not fit in a byte", c))
+		}
+		return NewU8(uint8(c.Uint64())).bits
+	}
+	return bits.ToBinary(bf.api, a.Val, bits.WithNbDigits(8))
+}
+
+// checked returns a, range checked if it is not known to fit in 8 bits.
+func (bf *BinaryField[T]) checked(a U8) U8 {
+	if a.internal {
+		return a
+	}
+	return bf.ByteValueOf(a.Val)
+}
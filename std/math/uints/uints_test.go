@@ -0,0 +1,188 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uints
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+)
+
+type u32Circuit struct {
+	A, B, C                 U32
+	Sum, Xor, And, Or, NotA U32
+	Lrot7, Lrot8, Rrot13    U32
+	Shr10, Shr16            U32
+	Packed                  frontend.Variable
+}
+
+func (c *u32Circuit) Define(api frontend.API) error {
+	bf := New[U32](api)
+	bf.AssertEq(bf.Add(c.A, c.B, c.C), c.Sum)
+	bf.AssertEq(bf.Xor(c.A, c.B, c.C), c.Xor)
+	bf.AssertEq(bf.And(c.A, c.B), c.And)
+	bf.AssertEq(bf.Or(c.A, c.B), c.Or)
+	bf.AssertEq(bf.Not(c.A), c.NotA)
+	bf.AssertEq(bf.Lrot(c.A, 7), c.Lrot7)
+	bf.AssertEq(bf.Lrot(c.A, 8), c.Lrot8)
+	bf.AssertEq(bf.Rrot(c.A, 13), c.Rrot13)
+	bf.AssertEq(bf.Shr(c.A, 10), c.Shr10)
+	bf.AssertEq(bf.Shr(c.A, 16), c.Shr16)
+	// shifts by the width or more clear the integer
+	bf.AssertEq(bf.Shr(c.A, 32), NewU32(0))
+	bf.AssertEq(bf.Shr(c.A, 33), NewU32(0))
+	bf.AssertEq(bf.Shr(c.A, 40), NewU32(0))
+	bf.AssertEq(bf.Xor(bf.Shr(c.A, 32), c.A), c.A)
+	// chained operations reuse the bit decompositions
+	bf.AssertEq(bf.Xor(bf.Not(bf.Lrot(c.A, 3)), bf.Lrot(c.A, 3)), NewU32(0xffffffff))
+	// constants
+	bf.AssertEq(bf.Add(NewU32(0xffffffff), NewU32(2)), NewU32(1))
+	bf.AssertEq(bf.Xor(c.A, NewU32(0)), c.A)
+	// conversions
+	api.AssertIsEqual(bf.ToValue(c.A), c.Packed)
+	bf.AssertEq(bf.ValueOf(c.Packed), c.A)
+	bf.AssertEq(bf.PackMSB(bf.UnpackMSB(c.A)...), c.A)
+	bf.AssertEq(bf.PackLSB(bf.UnpackLSB(c.A)...), c.A)
+	bf.ByteAssertEq(bf.ByteValueOf(c.A[3].Val), bf.UnpackMSB(c.A)[0])
+	return nil
+}
+
+func u32Assignment(a, b, c uint32) *u32Circuit {
+	return &u32Circuit{
+		A:      NewU32(a),
+		B:      NewU32(b),
+		C:      NewU32(c),
+		Sum:    NewU32(a + b + c),
+		Xor:    NewU32(a ^ b ^ c),
+		And:    NewU32(a & b),
+		Or:     NewU32(a | b),
+		NotA:   NewU32(^a),
+		Lrot7:  NewU32(bits.RotateLeft32(a, 7)),
+		Lrot8:  NewU32(bits.RotateLeft32(a, 8)),
+		Rrot13: NewU32(bits.RotateLeft32(a, -13)),
+		Shr10:  NewU32(a >> 10),
+		Shr16:  NewU32(a >> 16),
+		Packed: a,
+	}
+}
+
+func TestU32(t *testing.T) {
+	assert := test.NewAssert(t)
+	r := rand.New(rand.NewSource(1))
+	a, b, c := r.Uint32(), r.Uint32(), r.Uint32()
+
+	assert.ProverSucceeded(&u32Circuit{}, u32Assignment(a, b, c), test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(&u32Circuit{}, u32Assignment(0xffffffff, 0xffffffff, 0xffffffff), test.WithCurves(ecc.BN254))
+
+	wrong := u32Assignment(a, b, c)
+	wrong.Sum = NewU32(a + b + c + 1)
+	assert.ProverFailed(&u32Circuit{}, wrong, test.WithCurves(ecc.BN254))
+
+	// the first byte does not fit in 8 bits, but the bytes pack to a
+	wrong = u32Assignment(a|0x100, b, c)
+	wrong.A[0].Val = a&0xff + 0x100
+	wrong.A[1].Val = (a>>8)&0xff - 1
+	assert.ProverFailed(&u32Circuit{}, wrong, test.WithCurves(ecc.BN254))
+}
+
+type u64Circuit struct {
+	A, B         U64
+	Sum, Xor     U64
+	Lrot17, Shr3 U64
+}
+
+func (c *u64Circuit) Define(api frontend.API) error {
+	bf := New[U64](api)
+	bf.AssertEq(bf.Add(c.A, c.B), c.Sum)
+	bf.AssertEq(bf.Xor(c.A, c.B), c.Xor)
+	bf.AssertEq(bf.Lrot(c.A, 17), c.Lrot17)
+	bf.AssertEq(bf.Shr(c.A, 3), c.Shr3)
+	return nil
+}
+
+func TestU64(t *testing.T) {
+	assert := test.NewAssert(t)
+	r := rand.New(rand.NewSource(2))
+	a, b := r.Uint64(), r.Uint64()
+
+	assert.ProverSucceeded(&u64Circuit{}, &u64Circuit{
+		A:      NewU64(a),
+		B:      NewU64(b),
+		Sum:    NewU64(a + b),
+		Xor:    NewU64(a ^ b),
+		Lrot17: NewU64(bits.RotateLeft64(a, 17)),
+		Shr3:   NewU64(a >> 3),
+	})
+	assert.ProverFailed(&u64Circuit{}, &u64Circuit{
+		A:      NewU64(a),
+		B:      NewU64(b),
+		Sum:    NewU64(a + b),
+		Xor:    NewU64(a ^ b),
+		Lrot17: NewU64(bits.RotateLeft64(a, 18)),
+		Shr3:   NewU64(a >> 3),
+	})
+}
+
+type addCircuit struct {
+	forceBits bool
+	Values    [64]U32
+	Sum       U32
+}
+
+func (c *addCircuit) Define(api frontend.API) error {
+	bf := New[U32](api)
+	if c.forceBits {
+		bf.lazyBits = false
+	}
+	sum := c.Values[0]
+	for i := 1; i < len(c.Values); i++ {
+		sum = bf.Add(sum, c.Values[i])
+	}
+	bf.AssertEq(sum, c.Sum)
+	return nil
+}
+
+func TestAdd(t *testing.T) {
+	assert := test.NewAssert(t)
+	r := rand.New(rand.NewSource(3))
+	var assignment addCircuit
+	var sum uint32
+	for i := range assignment.Values {
+		v := r.Uint32()
+		assignment.Values[i] = NewU32(v)
+		sum += v
+	}
+	assignment.Sum = NewU32(sum)
+	assert.ProverSucceeded(&addCircuit{}, &assignment, test.WithCurves(ecc.BN254))
+
+	compile := func(newBuilder frontend.NewBuilder, circuit frontend.Circuit) int {
+		ccs, err := frontend.Compile(ecc.BN254, newBuilder, circuit)
+		assert.NoError(err)
+		return ccs.GetNbConstraints()
+	}
+	// the R1CS builder range checks the bytes of the sums with lookups instead
+	// of decomposing them into bits
+	assert.Less(compile(r1cs.NewBuilder, &addCircuit{}), compile(r1cs.NewBuilder, &addCircuit{forceBits: true}))
+	// the PLONK builder decomposes them
+	assert.Equal(compile(scs.NewBuilder, &addCircuit{}), compile(scs.NewBuilder, &addCircuit{forceBits: true}))
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package multicommit shares the commitment of the builder between gadgets.
+//
+// The builders support at most a single commitment per circuit, but several
+// gadgets such as range checks, lookups and permutation checks may need a
+// challenge in the same circuit. With [WithCommitment], the gadgets register
+// the variables their challenge depends on and a callback. When the circuit is
+// compiled, all the registered variables are committed to at once and every
+// callback is called with its own commitment derived from the common one.
+//
+// The common commitment is computed with [frontend.Committer] if the builder
+// implements it, which costs at most a constraint per committed variable. It is
+// the MiMC hash of the variables otherwise.
+package multicommit
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// WithCommitmentFn is the function called with a commitment to the variables
+// given to [WithCommitment].
+type WithCommitmentFn func(api frontend.API, commitment frontend.Variable) error
+
+type ctxMultiCommitterKey struct{}
+
+// multiCommitter collects the committed variables and the callbacks of a
+// circuit.
+type multiCommitter struct {
+	vars   []frontend.Variable
+	cbs    []WithCommitmentFn
+	closed bool
+	// position of commit in the deferred callbacks
+	deferredAt int
+}
+
+func getCommitter(api frontend.API) *multiCommitter {
+	kv := api.Compiler()
+	if stored := kv.GetKeyValue(ctxMultiCommitterKey{}); stored != nil {
+		if mc, ok := stored.(*multiCommitter); ok {
+			return mc
+		}
+		panic("stored multicommitter has wrong type")
+	}
+	mc := &multiCommitter{}
+	kv.SetKeyValue(ctxMultiCommitterKey{}, mc)
+	mc.deferCommit(api)
+	return mc
+}
+
+// WithCommitment schedules cb to be called with a commitment to
+// committedVariables, once the circuit is defined. The commitment binds all
+// the variables registered by all the calls to WithCommitment in the circuit,
+// and it differs from one callback to the other. Constants are not committed.
+//
+// WithCommitment may also be called from a deferred callback, but not from a
+// WithCommitmentFn.
+func WithCommitment(api frontend.API, cb WithCommitmentFn, committedVariables ...frontend.Variable) {
+	mc := getCommitter(api)
+	if mc.closed {
+		panic("commitment registered after the commitment was computed")
+	}
+	for _, v := range committedVariables {
+		if _, ok := api.Compiler().ConstantValue(v); !ok {
+			mc.vars = append(mc.vars, v)
+		}
+	}
+	mc.cbs = append(mc.cbs, cb)
+}
+
+func (mc *multiCommitter) deferCommit(api frontend.API) {
+	api.Compiler().Defer(mc.commit)
+	mc.deferredAt = len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler())) - 1
+}
+
+// commit computes the commitment and calls the callbacks. It is postponed as
+// long as callbacks deferred after it may register more variables.
+func (mc *multiCommitter) commit(api frontend.API) error {
+	if len(circuitdefer.GetAll[func(frontend.API) error](api.Compiler())) > mc.deferredAt+1 {
+		mc.deferCommit(api)
+		return nil
+	}
+	mc.closed = true
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return fmt.Errorf("new hash: %w", err)
+	}
+	var commitment frontend.Variable
+	if committer, ok := api.(frontend.Committer); ok && len(mc.vars) > 0 {
+		commitment = committer.Commit(mc.vars...)
+	} else {
+		h.Write(mc.vars...)
+		commitment = h.Sum()
+	}
+	if len(mc.cbs) == 1 {
+		return mc.cbs[0](api, commitment)
+	}
+	for i := range mc.cbs {
+		h.Reset()
+		h.Write(commitment, i)
+		if err := mc.cbs[i](api, h.Sum()); err != nil {
+			return fmt.Errorf("callback %d: %w", i, err)
+		}
+	}
+	return nil
+}
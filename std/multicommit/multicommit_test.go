@@ -0,0 +1,88 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multicommit_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/lookup/logderivlookup"
+	"github.com/consensys/gnark/std/multicommit"
+	"github.com/consensys/gnark/std/permutation"
+	"github.com/consensys/gnark/test"
+)
+
+type multiCommitCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (c *multiCommitCircuit) Define(api frontend.API) error {
+	api.AssertIsDifferent(c.X, c.Y)
+	var first frontend.Variable
+	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+		first = commitment
+		return nil
+	}, c.X)
+	// registered from a deferred callback, after the first one
+	api.Compiler().Defer(func(api frontend.API) error {
+		multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
+			api.AssertIsDifferent(first, commitment)
+			return nil
+		}, c.Y)
+		return nil
+	})
+	return nil
+}
+
+func TestMultiCommit(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.ProverSucceeded(&multiCommitCircuit{}, &multiCommitCircuit{X: 3, Y: 5}, test.WithCurves(ecc.BN254))
+}
+
+type gadgetsCircuit struct {
+	Entries [4]frontend.Variable
+	Index   frontend.Variable
+	A, B    [3]frontend.Variable
+}
+
+func (c *gadgetsCircuit) Define(api frontend.API) error {
+	t := logderivlookup.New(api)
+	for i := range c.Entries {
+		t.Insert(c.Entries[i])
+	}
+	api.AssertIsEqual(t.Lookup(c.Index)[0], c.A[0])
+	permutation.AssertIsPermutation(api, c.A[:], c.B[:])
+	return nil
+}
+
+func TestGadgets(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the lookup and the permutation check share the commitment
+	assert.ProverSucceeded(&gadgetsCircuit{}, &gadgetsCircuit{
+		Entries: [4]frontend.Variable{10, 11, 12, 13},
+		Index:   2,
+		A:       [3]frontend.Variable{12, 1, 2},
+		B:       [3]frontend.Variable{2, 12, 1},
+	}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(&gadgetsCircuit{}, &gadgetsCircuit{
+		Entries: [4]frontend.Variable{10, 11, 12, 13},
+		Index:   2,
+		A:       [3]frontend.Variable{12, 1, 2},
+		B:       [3]frontend.Variable{2, 12, 3},
+	}, test.WithCurves(ecc.BN254))
+}
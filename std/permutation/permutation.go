@@ -0,0 +1,208 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package permutation implements permutation and sortedness checks.
+//
+// [AssertIsPermutation] asserts that two vectors are permutations of each
+// other, that is that they are equal as multisets. [AssertIsKeyedPermutation]
+// does the same for rows of several columns, such as a key and its values,
+// which are permuted as a whole. [AssertIsSorted] asserts that a vector is
+// sorted.
+//
+// The permutation checks use the grand-product argument: a and b are
+// permutations of each other if and only if
+//
+//	Π_i (x - a_i) = Π_i (x - b_i)
+//
+// as polynomials in x, which is checked at a random point x. Rows of several
+// columns are first compressed with a second challenge. The checks are
+// collected and performed with shared challenges when the circuit is compiled.
+// The challenge x is a commitment to all the checked variables obtained with
+// [github.com/consensys/gnark/std/multicommit], so that the permutation checks
+// share the commitment of the circuit with the other gadgets.
+// The cost is one constraint per element and per column on top of deriving
+// the challenges.
+package permutation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/multicommit"
+)
+
+// AssertIsPermutation asserts that b is a permutation of a.
+func AssertIsPermutation(api frontend.API, a, b []frontend.Variable) {
+	AssertIsKeyedPermutation(api, column(a), column(b))
+}
+
+// AssertIsKeyedPermutation asserts that the rows of b are a permutation of the
+// rows of a. The rows are permuted as a whole, so that for example the values
+// stay with their keys. All the rows must have the same width.
+func AssertIsKeyedPermutation(api frontend.API, a, b [][]frontend.Variable) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("permutation of %d rows into %d rows", len(a), len(b)))
+	}
+	if len(a) == 0 {
+		return
+	}
+	width := len(a[0])
+	if width == 0 {
+		panic("empty rows")
+	}
+	for i := range a {
+		if len(a[i]) != width || len(b[i]) != width {
+			panic(fmt.Sprintf("row %d has width %d and %d, expected %d", i, len(a[i]), len(b[i]), width))
+		}
+	}
+	newChecker(api).add(a, b)
+}
+
+type ctxCheckerKey struct{}
+
+type check struct {
+	a, b [][]frontend.Variable
+}
+
+// checker collects the permutation checks of a circuit and performs them when
+// the circuit is compiled.
+type checker struct {
+	checks []check
+	closed bool
+}
+
+func newChecker(api frontend.API) *checker {
+	if stored := api.Compiler().GetKeyValue(ctxCheckerKey{}); stored != nil {
+		if c, ok := stored.(*checker); ok {
+			return c
+		}
+		panic("stored permutation checker has wrong type")
+	}
+	c := &checker{}
+	api.Compiler().SetKeyValue(ctxCheckerKey{}, c)
+	api.Compiler().Defer(c.commit)
+	return c
+}
+
+func (c *checker) add(a, b [][]frontend.Variable) {
+	if c.closed {
+		panic("permutation check registered after the checks were performed")
+	}
+	c.checks = append(c.checks, check{a: a, b: b})
+}
+
+// commit derives the challenges from all the checked variables and performs
+// the collected checks.
+func (c *checker) commit(api frontend.API) error {
+	c.closed = true
+	var toCommit []frontend.Variable
+	withLambda := false
+	for _, ch := range c.checks {
+		if len(ch.a[0]) > 1 {
+			withLambda = true
+		}
+		for _, rows := range [][][]frontend.Variable{ch.a, ch.b} {
+			for i := range rows {
+				for _, v := range rows[i] {
+					if _, ok := api.Compiler().ConstantValue(v); !ok {
+						toCommit = append(toCommit, v)
+					}
+				}
+			}
+		}
+	}
+	if len(toCommit) == 0 {
+		// the checks are fixed by the circuit
+		for i, ch := range c.checks {
+			if !equalMultisets(api, ch.a, ch.b) {
+				return fmt.Errorf("constant permutation check %d failed", i)
+			}
+		}
+		return nil
+	}
+
+	multicommit.WithCommitment(api, func(api frontend.API, x frontend.Variable) error {
+		var lambda frontend.Variable
+		if withLambda {
+			h, err := mimc.NewMiMC(api)
+			if err != nil {
+				return fmt.Errorf("new hash: %w", err)
+			}
+			h.Write(x)
+			lambda = h.Sum()
+		}
+		for _, ch := range c.checks {
+			api.AssertIsEqual(grandProduct(api, ch.a, x, lambda), grandProduct(api, ch.b, x, lambda))
+		}
+		return nil
+	}, toCommit...)
+	return nil
+}
+
+// grandProduct returns Π_i (x - Σ_k rows_i,k λ^k).
+func grandProduct(api frontend.API, rows [][]frontend.Variable, x, lambda frontend.Variable) frontend.Variable {
+	res := frontend.Variable(1)
+	for i := range rows {
+		res = api.Mul(res, api.Sub(x, compress(api, rows[i], lambda)))
+	}
+	return res
+}
+
+// compress returns Σ_k row_k λ^k.
+func compress(api frontend.API, row []frontend.Variable, lambda frontend.Variable) frontend.Variable {
+	res := row[len(row)-1]
+	for k := len(row) - 2; k >= 0; k-- {
+		res = api.Add(row[k], api.Mul(res, lambda))
+	}
+	return res
+}
+
+// equalMultisets returns true if the constant rows of a and b are equal as
+// multisets.
+func equalMultisets(api frontend.API, a, b [][]frontend.Variable) bool {
+	keys := func(rows [][]frontend.Variable) []string {
+		res := make([]string, len(rows))
+		for i := range rows {
+			var sbb strings.Builder
+			for _, v := range rows[i] {
+				c, _ := api.Compiler().ConstantValue(v)
+				sbb.WriteString(c.String())
+				sbb.WriteByte(',')
+			}
+			res[i] = sbb.String()
+		}
+		sort.Strings(res)
+		return res
+	}
+	ka, kb := keys(a), keys(b)
+	for i := range ka {
+		if ka[i] != kb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func column(v []frontend.Variable) [][]frontend.Variable {
+	res := make([][]frontend.Variable, len(v))
+	for i := range v {
+		res[i] = []frontend.Variable{v[i]}
+	}
+	return res
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permutation
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type permutationCircuit struct {
+	A, B []frontend.Variable
+	// the rows of C and D are (key, value) pairs
+	C, D [][2]frontend.Variable
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	AssertIsPermutation(api, c.A, c.B)
+	a := make([][]frontend.Variable, len(c.C))
+	b := make([][]frontend.Variable, len(c.D))
+	for i := range c.C {
+		a[i] = c.C[i][:]
+		b[i] = c.D[i][:]
+	}
+	AssertIsKeyedPermutation(api, a, b)
+	// constant rows are not committed
+	AssertIsPermutation(api, []frontend.Variable{1, c.A[0], 2}, []frontend.Variable{c.A[0], 2, 1})
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	const n = 8
+	assert := test.NewAssert(t)
+	r := rand.New(rand.NewSource(1))
+	perm := r.Perm(n)
+
+	assignment := func() *permutationCircuit {
+		res := &permutationCircuit{
+			A: make([]frontend.Variable, n), B: make([]frontend.Variable, n),
+			C: make([][2]frontend.Variable, n), D: make([][2]frontend.Variable, n),
+		}
+		for i := 0; i < n; i++ {
+			// duplicate values test the multiset equality
+			res.A[i] = i / 2
+			res.B[perm[i]] = i / 2
+			res.C[i] = [2]frontend.Variable{i, 100 + i}
+			res.D[perm[i]] = [2]frontend.Variable{i, 100 + i}
+		}
+		return res
+	}
+	circuit := &permutationCircuit{
+		A: make([]frontend.Variable, n), B: make([]frontend.Variable, n),
+		C: make([][2]frontend.Variable, n), D: make([][2]frontend.Variable, n),
+	}
+	assert.ProverSucceeded(circuit, assignment(), test.WithCurves(ecc.BN254, ecc.BLS12_377))
+
+	// same elements, different multiplicities
+	wrong := assignment()
+	for i := range wrong.B {
+		if wrong.B[i] == 0 {
+			wrong.B[i] = 1
+			break
+		}
+	}
+	assert.ProverFailed(circuit, wrong, test.WithCurves(ecc.BN254))
+
+	// the columns are permutations, but not the rows
+	wrong = assignment()
+	wrong.D[0][1], wrong.D[1][1] = wrong.D[1][1], wrong.D[0][1]
+	assert.ProverFailed(circuit, wrong, test.WithCurves(ecc.BN254))
+}
+
+type sortedCircuit struct {
+	A []frontend.Variable
+}
+
+func (c *sortedCircuit) Define(api frontend.API) error {
+	AssertIsSorted(api, c.A, 16)
+	return nil
+}
+
+func TestSorted(t *testing.T) {
+	assert := test.NewAssert(t)
+	circuit := &sortedCircuit{A: make([]frontend.Variable, 5)}
+	assert.ProverSucceeded(circuit, &sortedCircuit{A: []frontend.Variable{0, 3, 3, 100, 1<<16 - 1}}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(circuit, &sortedCircuit{A: []frontend.Variable{0, 3, 2, 100, 1000}}, test.WithCurves(ecc.BN254))
+	assert.ProverFailed(circuit, &sortedCircuit{A: []frontend.Variable{0, 3, 3, 100, 1 << 16}}, test.WithCurves(ecc.BN254))
+	// the differences do not fit in 16 bits
+	assert.ProverFailed(circuit, &sortedCircuit{A: []frontend.Variable{-1, 0, 1, 2, 3}}, test.WithCurves(ecc.BN254))
+}
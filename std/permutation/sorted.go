@@ -0,0 +1,40 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permutation
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// AssertIsSorted asserts that a is sorted in non-decreasing order and that its
+// elements fit in nbBits bits. As for [frontend.API.AssertIsLess], the
+// comparisons are bounded: a_i <= a_{i+1} if and only if a_{i+1} - a_i fits in
+// nbBits bits, which is checked with [rangecheck] together with the elements.
+func AssertIsSorted(api frontend.API, a []frontend.Variable, nbBits int) {
+	// the differences must not wrap around
+	if nbBits <= 0 || nbBits > api.Compiler().Curve().Info().Fr.Bits-2 {
+		panic("number of bits out of range")
+	}
+	rc := rangecheck.New(api)
+	for i := range a {
+		rc.Check(a[i], nbBits)
+		if i > 0 {
+			rc.Check(api.Sub(a[i], a[i-1]), nbBits)
+		}
+	}
+}
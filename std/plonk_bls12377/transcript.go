@@ -0,0 +1,151 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plonk_bls12377
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+// digest is the SHA-256 digest of the written bytes. It is returned by
+// [sha256Hash.Sum] so that the digest of the previous challenge can be
+// written again byte by byte.
+type digest []frontend.Variable
+
+// sha256Hash implements [hash.Hash] using SHA-256 so that the challenges
+// derived by [fiatshamir.Transcript] are the challenges of the native
+// verifier. Every written variable is a byte, except for the byte slices of
+// the challenge names and the digests of the previous challenges which are
+// expanded into their bytes.
+type sha256Hash struct {
+	api  frontend.API
+	data []frontend.Variable
+}
+
+func newSHA256(api frontend.API) *sha256Hash {
+	return &sha256Hash{api: api}
+}
+
+func (h *sha256Hash) Write(data ...frontend.Variable) {
+	for _, d := range data {
+		switch v := d.(type) {
+		case []byte:
+			for _, b := range v {
+				h.data = append(h.data, b)
+			}
+		case digest:
+			h.data = append(h.data, v...)
+		default:
+			h.data = append(h.data, v)
+		}
+	}
+}
+
+func (h *sha256Hash) Sum() frontend.Variable {
+	return digest(sha2.Sum256(h.api, h.data))
+}
+
+func (h *sha256Hash) Reset() {
+	h.data = nil
+}
+
+var (
+	// fpModulus is the modulus of the base field of BLS12-377, which is
+	// the native field of the outer BW6-761 circuit.
+	fpModulus = ecc.BLS12_377.Info().Fp.Modulus()
+	// fpMax is p-1, the largest canonical base field element.
+	fpMax = new(big.Int).Sub(fpModulus, big.NewInt(1))
+	// frModulus is the modulus of the scalar field of BLS12-377.
+	frModulus = ecc.BLS12_377.Info().Fr.Modulus()
+)
+
+const (
+	sizeFp = 48 // number of bytes of an encoded base field element
+	sizeFr = 32 // number of bytes of an encoded scalar field element
+)
+
+// isLessOrEqual returns 1 if the value given by the little-endian bits bs is
+// at most the constant bound and 0 otherwise. The bits are assumed to be
+// boolean.
+func isLessOrEqual(api frontend.API, bs []frontend.Variable, bound *big.Int) frontend.Variable {
+	// eq is 1 if the bits seen so far are equal to the bits of the bound and
+	// lt is 1 if the bits seen so far are less than the bits of the bound.
+	var eq, lt frontend.Variable = 1, 0
+	for i := len(bs) - 1; i >= 0; i-- {
+		if bound.Bit(i) == 1 {
+			lt = api.Add(lt, api.Sub(eq, api.Mul(eq, bs[i])))
+			eq = api.Mul(eq, bs[i])
+		} else {
+			eq = api.Sub(eq, api.Mul(eq, bs[i]))
+		}
+	}
+	return api.Add(lt, eq)
+}
+
+// toBinaryCanonical returns the nbBits little-endian bits of v and asserts
+// that they represent an integer at most bound. It ensures that the
+// decomposition is unique when 2^nbBits exceeds the native modulus.
+func toBinaryCanonical(api frontend.API, v frontend.Variable, nbBits int, bound *big.Int) []frontend.Variable {
+	bs := bits.ToBinary(api, v, bits.WithNbDigits(nbBits))
+	api.AssertIsEqual(isLessOrEqual(api, bs, bound), 1)
+	return bs
+}
+
+// bitsToBytes returns the nbBytes bytes in big-endian order of the integer
+// given by its little-endian bits.
+func bitsToBytes(api frontend.API, bs []frontend.Variable, nbBytes int) []frontend.Variable {
+	res := make([]frontend.Variable, nbBytes)
+	for i := range res {
+		start := 8 * (nbBytes - 1 - i)
+		if start >= len(bs) {
+			res[i] = 0
+			continue
+		}
+		end := start + 8
+		if end > len(bs) {
+			end = len(bs)
+		}
+		res[i] = bits.FromBinary(api, bs[start:end])
+	}
+	return res
+}
+
+// bytesToBits returns the little-endian bits of the integer given by its
+// bytes in big-endian order.
+func bytesToBits(api frontend.API, bs []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(bs))
+	for i := len(bs) - 1; i >= 0; i-- {
+		res = append(res, bits.ToBinary(api, bs[i], bits.WithNbDigits(8))...)
+	}
+	return res
+}
+
+// marshalG1 returns the encoding of p as given by Marshal of the native
+// G1Affine, that is the uncompressed coordinates in big-endian order. p must
+// not be the point at infinity.
+func marshalG1(api frontend.API, p sw_bls12377.G1Affine) []frontend.Variable {
+	nbBits := fpModulus.BitLen()
+	xBits := toBinaryCanonical(api, p.X, nbBits, fpMax)
+	yBits := toBinaryCanonical(api, p.Y, nbBits, fpMax)
+	res := bitsToBytes(api, xBits, sizeFp)
+	return append(res, bitsToBytes(api, yBits, sizeFp)...)
+}
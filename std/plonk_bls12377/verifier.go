@@ -0,0 +1,474 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plonk_bls12377 provides a ZKP-circuit function to verify BLS12_377
+// PLONK proofs inside a BW6_761 circuit.
+//
+// The verifier follows the native verifier of the plonk backend. The
+// Fiat-Shamir challenges are derived with SHA-256 over the same encodings
+// so that the native proofs are verified as is. The arithmetic in the scalar
+// field of BLS12-377 is emulated, whereas the operations on the commitments
+// use the native arithmetic of the 2-chain.
+package plonk_bls12377
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	plonk_bls12377 "github.com/consensys/gnark/internal/backend/bls12-377/plonk"
+	"github.com/consensys/gnark/std/algebra/fields_bls12377"
+	"github.com/consensys/gnark/std/algebra/sw_bls12377"
+	fiatshamir "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash/mimc"
+	stdbits "github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Scalar is an element of the scalar field of BLS12-377.
+type Scalar = emulated.Element[emulated.BLS12377Fr]
+
+// OpeningProof is a KZG opening proof of a single polynomial.
+type OpeningProof struct {
+	// H quotient polynomial (f - f(z))/(x-z)
+	H sw_bls12377.G1Affine
+
+	// ClaimedValue purported value
+	ClaimedValue Scalar
+}
+
+// BatchOpeningProof is a KZG opening proof of several polynomials at a
+// single point.
+type BatchOpeningProof struct {
+	// H quotient polynomial Sum_i gamma**i*(f - f(z))/(x-z)
+	H sw_bls12377.G1Affine
+
+	// ClaimedValues purported values
+	ClaimedValues [7]Scalar
+}
+
+// Proof represents a PLONK proof.
+type Proof struct {
+	// Commitments to the solution vectors
+	LRO [3]sw_bls12377.G1Affine
+
+	// Commitment to Z, the permutation polynomial
+	Z sw_bls12377.G1Affine
+
+	// Commitments to h1, h2, h3 such that h = h1 + Xh2 + X**2h3 is the quotient polynomial
+	H [3]sw_bls12377.G1Affine
+
+	// Batch opening proof of h1 + zeta*h2 + zeta**2h3, linearizedPolynomial, l, r, o, s1, s2
+	BatchedProof BatchOpeningProof
+
+	// Opening proof of Z at zeta*mu
+	ZShiftedOpening OpeningProof
+}
+
+// NewProof returns a proof with allocated limbs for the claimed values. It is
+// used to define the proof in a circuit structure.
+func NewProof() Proof {
+	var proof Proof
+	for i := range proof.BatchedProof.ClaimedValues {
+		proof.BatchedProof.ClaimedValues[i] = emulated.NewElement[emulated.BLS12377Fr](nil)
+	}
+	proof.ZShiftedOpening.ClaimedValue = emulated.NewElement[emulated.BLS12377Fr](nil)
+	return proof
+}
+
+// VerifyingKey represents a PLONK verifying key.
+//
+// The parameters of the evaluation domain are constants of the circuit, so
+// the verifying key of the circuit definition must also be assigned, see
+// [VerifyingKey.Assign].
+type VerifyingKey struct {
+	// Size circuit
+	Size       uint64
+	SizeInv    fr.Element
+	Generator  fr.Element
+	CosetShift fr.Element
+
+	// KZG elements of the SRS: [1]G1, [1]G2 and [α]G2
+	KZG struct {
+		G1 sw_bls12377.G1Affine
+		G2 [2]sw_bls12377.G2Affine
+	}
+
+	// S commitments to S1, S2, S3
+	S [3]sw_bls12377.G1Affine
+
+	// Commitments to ql, qr, qm, qo, qk
+	Ql, Qr, Qm, Qo, Qk sw_bls12377.G1Affine
+}
+
+// verifier holds the emulated scalar field used by Verify.
+type verifier struct {
+	api frontend.API
+	fr  *emulated.Field[emulated.BLS12377Fr]
+}
+
+// Verify implements the verification function of PLONK. It asserts that the
+// proof is valid for the verifying key and the public inputs. The public
+// inputs are elements of the scalar field of BLS12-377 and are asserted to be
+// canonical. The commitments must not be the point at infinity.
+//
+// The KZG batch opening at ζ and ζω is checked with a single pairing check.
+// Instead of the random combination of the native verifier, the combination
+// is derived from the openings using MiMC.
+//
+// It returns an error if the circuit is not defined over BW6_761 or if the
+// verifying key has not been assigned.
+func Verify(api frontend.API, vk VerifyingKey, proof Proof, publicInputs []frontend.Variable) error {
+	if api.Compiler().Curve() != ecc.BW6_761 {
+		return errors.New("the BLS12-377 PLONK verifier must be defined over BW6_761")
+	}
+	if vk.Size == 0 || bits.OnesCount64(vk.Size) != 1 {
+		return fmt.Errorf("invalid domain size %d, the verifying key must be assigned", vk.Size)
+	}
+	f, err := emulated.NewField[emulated.BLS12377Fr](api)
+	if err != nil {
+		return fmt.Errorf("new scalar field: %w", err)
+	}
+	v := &verifier{api: api, fr: f}
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewTranscript(api, newSHA256(api), "gamma", "beta", "alpha", "zeta")
+
+	// The first challenge is derived using the public data: the commitments
+	// to the permutation, the coefficients of the circuit, and the public
+	// inputs.
+	for _, p := range []sw_bls12377.G1Affine{vk.S[0], vk.S[1], vk.S[2], vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk} {
+		if err := fs.Bind("gamma", marshalG1(api, p)); err != nil {
+			return err
+		}
+	}
+	frMax := new(big.Int).Sub(frModulus, big.NewInt(1))
+	wPub := make([]*Scalar, len(publicInputs))
+	for i := range publicInputs {
+		pBits := toBinaryCanonical(api, publicInputs[i], frModulus.BitLen(), frMax)
+		if err := fs.Bind("gamma", bitsToBytes(api, pBits, sizeFr)); err != nil {
+			return err
+		}
+		wPub[i] = f.FromBits(pBits...)
+	}
+	gamma, err := v.deriveRandomness(&fs, "gamma")
+	if err != nil {
+		return err
+	}
+
+	// derive beta from Comm(l), Comm(r), Comm(o)
+	beta, err := v.deriveRandomness(&fs, "beta")
+	if err != nil {
+		return err
+	}
+
+	// derive alpha from Comm(l), Comm(r), Comm(o), Com(Z)
+	alpha, err := v.deriveRandomness(&fs, "alpha", proof.Z)
+	if err != nil {
+		return err
+	}
+
+	// derive zeta, the point of evaluation
+	zeta, err := v.deriveRandomness(&fs, "zeta", proof.H[0], proof.H[1], proof.H[2])
+	if err != nil {
+		return err
+	}
+
+	// evaluation of Z=Xⁿ⁻¹ at ζ
+	zetaPowerM := zeta
+	for s := vk.Size; s > 1; s >>= 1 {
+		zetaPowerM = f.Mul(zetaPowerM, zetaPowerM)
+	}
+	zzeta := f.Sub(zetaPowerM, f.One())
+
+	// compute PI = ∑_{i<n} Lᵢ*wᵢ
+	var acc fr.Element
+	acc.SetOne()
+	den := f.Sub(zeta, f.One())
+	lagrange := f.Mul(f.Div(zzeta, den), v.constant(&vk.SizeInv)) // (1/n)*(ζⁿ⁻¹)/(ζ-1)
+	lagrangeOne := lagrange                                       // save it for later
+	pi := f.Zero()
+	for i := range wPub {
+		pi = f.Add(pi, f.Mul(lagrange, wPub[i]))
+		if i == len(wPub)-1 {
+			break
+		}
+		// use Lᵢ₊₁ = w*L_i*(X-zⁱ)/(X-zⁱ⁺¹)
+		lagrange = f.Mul(f.Mul(lagrange, v.constant(&vk.Generator)), den)
+		acc.Mul(&acc, &vk.Generator)
+		den = f.Sub(zeta, v.constant(&acc))
+		lagrange = f.Div(lagrange, den)
+	}
+
+	// linearizedpolynomial + pi(ζ) + α*(Z(μζ))*(l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*(o(ζ)+γ) - α²*L₁(ζ)
+	zu := &proof.ZShiftedOpening.ClaimedValue
+	claimedQuotient := &proof.BatchedProof.ClaimedValues[0]
+	linearizedPolynomialZeta := &proof.BatchedProof.ClaimedValues[1]
+	l := &proof.BatchedProof.ClaimedValues[2]
+	r := &proof.BatchedProof.ClaimedValues[3]
+	o := &proof.BatchedProof.ClaimedValues[4]
+	s1 := &proof.BatchedProof.ClaimedValues[5]
+	s2 := &proof.BatchedProof.ClaimedValues[6]
+
+	_s1 := f.Add(f.Add(f.Mul(s1, beta), l), gamma) // (l(ζ)+β*s1(ζ)+γ)
+	_s2 := f.Add(f.Add(f.Mul(s2, beta), r), gamma) // (r(ζ)+β*s2(ζ)+γ)
+	_o := f.Add(o, gamma)                          // (o(ζ)+γ)
+
+	//  α*(Z(μζ))*(l(ζ)+β*s1(ζ)+γ)*(r(ζ)+β*s2(ζ)+γ)*(o(ζ)+γ)
+	ordering := f.Mul(f.Mul(f.Mul(f.Mul(_s1, _s2), _o), alpha), zu)
+	alphaSquareLagrange := f.Mul(f.Mul(lagrangeOne, alpha), alpha) // α²*L₁(ζ)
+
+	expected := f.Sub(f.Add(f.Add(linearizedPolynomialZeta, pi), ordering), alphaSquareLagrange)
+
+	// check that H(ζ) = prev_result/(ζⁿ-1) is as claimed, multiplying both sides
+	// by ζⁿ-1 instead of dividing
+	f.AssertIsEqual(f.Mul(claimedQuotient, zzeta), expected)
+
+	// compute the folded commitment to H: Comm(h₁) + ζᵐ⁺²*Comm(h₂) + ζ²⁽ᵐ⁺²⁾*Comm(h₃)
+	zetaMPlusTwo := f.Mul(f.Mul(zetaPowerM, zeta), zeta)
+	foldedH := v.scalarMul(proof.H[2], zetaMPlusTwo)
+	foldedH.AddAssign(api, proof.H[1])
+	foldedH = v.scalarMul(foldedH, zetaMPlusTwo)
+	foldedH.AddAssign(api, proof.H[0])
+
+	// Compute the commitment to the linearized polynomial
+	// linearizedPolynomialDigest =
+	// 		l(ζ)*ql+r(ζ)*qr+r(ζ)l(ζ)*qm+o(ζ)*qo+qk +
+	// 		α*( Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*s₃(X)-Z(X)(l(ζ)+β*id_1(ζ)+γ)*(r(ζ)+β*id_2(ζ)+γ)*(o(ζ)+β*id_3(ζ)+γ) ) +
+	// 		α²*L₁(ζ)*Z
+	rl := f.Mul(l, r)
+
+	// α*Z(μζ)(l(ζ)+β*s₁(ζ)+γ)*(r(ζ)+β*s₂(ζ)+γ)*β
+	coeffS3 := f.Mul(f.Mul(f.Mul(f.Mul(zu, beta), _s1), _s2), alpha)
+
+	var cosetSquare fr.Element
+	cosetSquare.Square(&vk.CosetShift)
+	betaZeta := f.Mul(beta, zeta)
+	u := f.Add(f.Add(betaZeta, l), gamma)                                    // (l(ζ)+β*ζ+γ)
+	w := f.Add(f.Add(f.Mul(betaZeta, v.constant(&vk.CosetShift)), r), gamma) // (r(ζ)+β*μ*ζ+γ)
+	x := f.Add(f.Add(f.Mul(betaZeta, v.constant(&cosetSquare)), o), gamma)   // (o(ζ)+β*μ²*ζ+γ)
+	coeffZ := f.Neg(f.Mul(f.Mul(u, w), x))                                   // -(l(ζ)+β*ζ+γ)*(r(ζ)+β*u*ζ+γ)*(o(ζ)+β*u²*ζ+γ)
+	coeffZ = f.Add(f.Mul(coeffZ, alpha), alphaSquareLagrange)                // -α*(l(ζ)+β*ζ+γ)*(r(ζ)+β*u*ζ+γ)*(o(ζ)+β*u²*ζ+γ) + α²*L₁(ζ)
+
+	linearizedPolynomialDigest := vk.Qk
+	points := []sw_bls12377.G1Affine{vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.S[2], proof.Z}
+	scalars := []*Scalar{l, r, rl, o, coeffS3, coeffZ}
+	for i := range points {
+		linearizedPolynomialDigest.AddAssign(api, v.scalarMul(points[i], scalars[i]))
+	}
+
+	// Fold the first proof
+	foldedDigest, foldedEval, err := v.foldProof([]sw_bls12377.G1Affine{
+		foldedH,
+		linearizedPolynomialDigest,
+		proof.LRO[0],
+		proof.LRO[1],
+		proof.LRO[2],
+		vk.S[0],
+		vk.S[1],
+	}, proof.BatchedProof.ClaimedValues[:], zeta)
+	if err != nil {
+		return err
+	}
+
+	// Batch verify the openings at ζ and ζω
+	shiftedZeta := f.Mul(zeta, v.constant(&vk.Generator))
+	return v.batchVerifyMultiPoints(
+		[2]sw_bls12377.G1Affine{foldedDigest, proof.Z},
+		[2]sw_bls12377.G1Affine{proof.BatchedProof.H, proof.ZShiftedOpening.H},
+		[2]*Scalar{foldedEval, zu},
+		[2]*Scalar{zeta, shiftedZeta},
+		vk,
+	)
+}
+
+// constant returns the constant emulated element x.
+func (v *verifier) constant(x *fr.Element) *Scalar {
+	return v.fr.NewElement(x.ToBigIntRegular(new(big.Int)))
+}
+
+// deriveRandomness binds the points to the challenge and
+// returns the challenge as a scalar, as the native verifier does.
+func (v *verifier) deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...sw_bls12377.G1Affine) (*Scalar, error) {
+	for _, p := range points {
+		if err := fs.Bind(challenge, marshalG1(v.api, p)); err != nil {
+			return nil, err
+		}
+	}
+	c, err := fs.ComputeChallenge(challenge)
+	if err != nil {
+		return nil, err
+	}
+	return v.digestToScalar(c.(digest)), nil
+}
+
+// digestToScalar returns the digest interpreted as a big-endian integer
+// reduced modulo r.
+func (v *verifier) digestToScalar(d digest) *Scalar {
+	return v.fr.Reduce(v.fr.FromBits(bytesToBits(v.api, d)...))
+}
+
+// scalarMul returns [s]p.
+func (v *verifier) scalarMul(p sw_bls12377.G1Affine, s *Scalar) sw_bls12377.G1Affine {
+	var res sw_bls12377.G1Affine
+	res.ScalarMul(v.api, p, stdbits.FromBinary(v.api, v.fr.ToBits(s)))
+	return res
+}
+
+// foldProof folds the digests and the claimed values of a batch opening
+// proof at the point ζ with the powers of γ, the challenge derived from ζ and
+// the digests.
+func (v *verifier) foldProof(digests []sw_bls12377.G1Affine, claimedValues []Scalar, zeta *Scalar) (sw_bls12377.G1Affine, *Scalar, error) {
+	fs := fiatshamir.NewTranscript(v.api, newSHA256(v.api), "gamma")
+	if err := fs.Bind("gamma", bitsToBytes(v.api, v.fr.ToBits(zeta), sizeFr)); err != nil {
+		return sw_bls12377.G1Affine{}, nil, err
+	}
+	for i := range digests {
+		if err := fs.Bind("gamma", marshalG1(v.api, digests[i])); err != nil {
+			return sw_bls12377.G1Affine{}, nil, err
+		}
+	}
+	c, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return sw_bls12377.G1Affine{}, nil, err
+	}
+	gamma := v.digestToScalar(c.(digest))
+
+	// ∑ᵢγⁱ[fᵢ(α)]G₁ and ∑ᵢγⁱfᵢ(ζ) using Horner's method
+	n := len(digests)
+	foldedDigest := digests[n-1]
+	foldedEval := &claimedValues[n-1]
+	for i := n - 2; i >= 0; i-- {
+		foldedDigest = v.scalarMul(foldedDigest, gamma)
+		foldedDigest.AddAssign(v.api, digests[i])
+		foldedEval = v.fr.Add(v.fr.Mul(foldedEval, gamma), &claimedValues[i])
+	}
+	return foldedDigest, foldedEval, nil
+}
+
+// batchVerifyMultiPoints asserts that the openings of the digests at the
+// points are valid, that is
+//
+//	e(∑ᵢλᵢ([fᵢ(α)]G₁ - [fᵢ(pᵢ)]G₁ + [pᵢ][Hᵢ(α)]G₁), G₂) e(-∑ᵢλᵢ[Hᵢ(α)]G₁, [α]G₂) = 1
+//
+// where λ₀ = 1 and λ₁ is a 128-bit challenge derived from the openings.
+func (v *verifier) batchVerifyMultiPoints(digests, quotients [2]sw_bls12377.G1Affine, evals, points [2]*Scalar, vk VerifyingKey) error {
+	api, f := v.api, v.fr
+
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	fs := fiatshamir.NewTranscript(api, &h, "lambda")
+	for i := 0; i < 2; i++ {
+		if err := fs.Bind("lambda", []frontend.Variable{digests[i].X, digests[i].Y, quotients[i].X, quotients[i].Y}); err != nil {
+			return err
+		}
+		if err := fs.Bind("lambda", f.Reduce(evals[i]).Limbs); err != nil {
+			return err
+		}
+	}
+	c, err := fs.ComputeChallenge("lambda")
+	if err != nil {
+		return err
+	}
+	lambda := f.FromBits(stdbits.ToBinary(api, c)[:128]...)
+
+	// ∑ᵢλᵢ[Hᵢ(α)]G₁
+	foldedQuotients := v.scalarMul(quotients[1], lambda)
+	foldedQuotients.AddAssign(api, quotients[0])
+
+	// ∑ᵢλᵢ[fᵢ(α)]G₁ - [∑ᵢλᵢfᵢ(pᵢ)]G₁
+	foldedDigests := v.scalarMul(digests[1], lambda)
+	foldedDigests.AddAssign(api, digests[0])
+	foldedEvals := f.Add(evals[0], f.Mul(lambda, evals[1]))
+	var foldedEvalsCommit sw_bls12377.G1Affine
+	foldedEvalsCommit.Neg(api, v.scalarMul(vk.KZG.G1, foldedEvals))
+	foldedDigests.AddAssign(api, foldedEvalsCommit)
+
+	// ∑ᵢλᵢ[pᵢ]([Hᵢ(α)]G₁)
+	foldedDigests.AddAssign(api, v.scalarMul(quotients[0], points[0]))
+	foldedDigests.AddAssign(api, v.scalarMul(quotients[1], f.Mul(lambda, points[1])))
+
+	// -∑ᵢλᵢ[Hᵢ(α)]G₁
+	foldedQuotients.Neg(api, foldedQuotients)
+
+	res, err := sw_bls12377.Pair(api,
+		[]sw_bls12377.G1Affine{foldedDigests, foldedQuotients},
+		[]sw_bls12377.G2Affine{vk.KZG.G2[0], vk.KZG.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	var one fields_bls12377.E12
+	one.SetOne()
+	res.AssertIsEqual(api, one)
+	return nil
+}
+
+// Assign values to the "in-circuit" VerifyingKey from a "out-of-circuit"
+// VerifyingKey. The KZG SRS of the verifying key must be initialized.
+func (vk *VerifyingKey) Assign(_ovk plonk.VerifyingKey) {
+	ovk, ok := _ovk.(*plonk_bls12377.VerifyingKey)
+	if !ok {
+		panic("expected *plonk_bls12377.VerifyingKey, got " + reflect.TypeOf(_ovk).String())
+	}
+	if ovk.KZGSRS == nil {
+		panic("the KZG SRS of the verifying key is not initialized")
+	}
+	vk.Size = ovk.Size
+	vk.SizeInv = ovk.SizeInv
+	vk.Generator = ovk.Generator
+	vk.CosetShift = ovk.CosetShift
+	vk.KZG.G1.Assign(&ovk.KZGSRS.G1[0])
+	vk.KZG.G2[0].Assign(&ovk.KZGSRS.G2[0])
+	vk.KZG.G2[1].Assign(&ovk.KZGSRS.G2[1])
+	for i := range vk.S {
+		vk.S[i].Assign(&ovk.S[i])
+	}
+	vk.Ql.Assign(&ovk.Ql)
+	vk.Qr.Assign(&ovk.Qr)
+	vk.Qm.Assign(&ovk.Qm)
+	vk.Qo.Assign(&ovk.Qo)
+	vk.Qk.Assign(&ovk.Qk)
+}
+
+// Assign values to the "in-circuit" Proof from a "out-of-circuit" Proof.
+func (proof *Proof) Assign(_oproof plonk.Proof) {
+	oproof, ok := _oproof.(*plonk_bls12377.Proof)
+	if !ok {
+		panic("expected *plonk_bls12377.Proof, got " + reflect.TypeOf(_oproof).String())
+	}
+	for i := range proof.LRO {
+		proof.LRO[i].Assign(&oproof.LRO[i])
+	}
+	proof.Z.Assign(&oproof.Z)
+	for i := range proof.H {
+		proof.H[i].Assign(&oproof.H[i])
+	}
+	proof.BatchedProof.H.Assign(&oproof.BatchedProof.H)
+	for i := range proof.BatchedProof.ClaimedValues {
+		proof.BatchedProof.ClaimedValues[i] = emulated.NewElement[emulated.BLS12377Fr](&oproof.BatchedProof.ClaimedValues[i])
+	}
+	proof.ZShiftedOpening.H.Assign(&oproof.ZShiftedOpening.H)
+	proof.ZShiftedOpening.ClaimedValue = emulated.NewElement[emulated.BLS12377Fr](&oproof.ZShiftedOpening.ClaimedValue)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plonk_bls12377
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+const (
+	preImage   = "4992816046196248432836492760315135318126925090839638585255611512962528270024"
+	publicHash = "4458332240632096997117977163518118563548842578509780924154021342053538349576"
+)
+
+type mimcCircuit struct {
+	PreImage frontend.Variable
+	Hash     frontend.Variable `gnark:",public"`
+}
+
+func (circuit *mimcCircuit) Define(api frontend.API) error {
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	mimc.Write(circuit.PreImage)
+	api.AssertIsEqual(mimc.Sum(), circuit.Hash)
+	return nil
+}
+
+// generateBls12377InnerProof returns a PLONK proof over BLS12-377 of the
+// knowledge of a MiMC preimage and its verifying key.
+func generateBls12377InnerProof(t *testing.T) (plonk.VerifyingKey, plonk.Proof) {
+	var circuit mimcCircuit
+	ccs, err := frontend.Compile(ecc.BLS12_377, scs.NewBuilder, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srs, err := test.NewKZGSRS(ccs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, vk, err := plonk.Setup(ccs, srs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assignment := mimcCircuit{PreImage: preImage, Hash: publicHash}
+	witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicWitness, err := frontend.NewWitness(&assignment, ecc.BLS12_377, frontend.PublicOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := plonk.Prove(ccs, pk, witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// before returning verifies that the proof passes on bls12377
+	if err := plonk.Verify(proof, vk, publicWitness); err != nil {
+		t.Fatal(err)
+	}
+	return vk, proof
+}
+
+type verifierCircuit struct {
+	InnerProof Proof
+	InnerVk    VerifyingKey
+	Hash       frontend.Variable
+}
+
+func (circuit *verifierCircuit) Define(api frontend.API) error {
+	return Verify(api, circuit.InnerVk, circuit.InnerProof, []frontend.Variable{circuit.Hash})
+}
+
+func TestVerifier(t *testing.T) {
+	assert := test.NewAssert(t)
+	innerVk, innerProof := generateBls12377InnerProof(t)
+
+	// the domain of the verifying key is a constant of the circuit
+	circuit := verifierCircuit{InnerProof: NewProof()}
+	circuit.InnerVk.Assign(innerVk)
+
+	var witness verifierCircuit
+	witness.InnerProof.Assign(innerProof)
+	witness.InnerVk.Assign(innerVk)
+	witness.Hash = publicHash
+	err := test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN)
+	assert.NoError(err)
+
+	witness.Hash = 42
+	err = test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN)
+	assert.Error(err)
+
+	witness.Hash = publicHash
+	witness.InnerProof.ZShiftedOpening.H = witness.InnerProof.LRO[0]
+	err = test.IsSolved(&circuit, &witness, ecc.BW6_761, backend.UNKNOWN)
+	assert.Error(err)
+}
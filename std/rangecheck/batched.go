@@ -0,0 +1,210 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rangecheck
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/internal/logderivarg"
+	"github.com/consensys/gnark/std/math/bits"
+)
+
+func init() {
+	hint.Register(DecomposeHint)
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []hint.Function {
+	return []hint.Function{DecomposeHint}
+}
+
+// maxLimbBits is the bit size of the largest lookup table.
+const maxLimbBits = 16
+
+type ctxCheckerKey struct{}
+
+type checkedVariable struct {
+	v      frontend.Variable
+	nbBits int
+}
+
+// batchedChecker collects the range checks of a circuit and performs them when
+// the circuit is compiled.
+type batchedChecker struct {
+	api       frontend.API
+	collected []checkedVariable
+	closed    bool
+
+	// forceLimbs performs the checks with the log-derivative argument
+	// regardless of the cost estimate.
+	forceLimbs bool
+}
+
+func newBatchedChecker(api frontend.API) *batchedChecker {
+	if stored := api.Compiler().GetKeyValue(ctxCheckerKey{}); stored != nil {
+		if c, ok := stored.(*batchedChecker); ok {
+			return c
+		}
+		panic("stored range checker is not batched")
+	}
+	c := &batchedChecker{api: api}
+	api.Compiler().SetKeyValue(ctxCheckerKey{}, c)
+	api.Compiler().Defer(c.commit)
+	return c
+}
+
+// Check asserts that v fits in nbBits bits. Constants are checked
+// immediately, variables when the circuit is compiled.
+func (c *batchedChecker) Check(v frontend.Variable, nbBits int) {
+	if nbBits < 0 {
+		panic("negative number of bits")
+	}
+	if c.closed {
+		panic("range check registered after the checks were performed")
+	}
+	if cv, ok := c.api.Compiler().ConstantValue(v); ok {
+		if cv.BitLen() > nbBits {
+			panic(fmt.Sprintf("constant %s does not fit in %d bits", cv.String(), nbBits))
+		}
+		return
+	}
+	if nbBits >= c.api.Compiler().Curve().Info().Fr.Bits {
+		// every field element fits
+		return
+	}
+	c.collected = append(c.collected, checkedVariable{v: v, nbBits: nbBits})
+}
+
+// commit performs the collected checks with the cheapest method.
+func (c *batchedChecker) commit(api frontend.API) error {
+	c.closed = true
+	if len(c.collected) == 0 {
+		return nil
+	}
+	limbBits, cost := c.limbBits(api)
+	if !c.forceLimbs && cost >= c.binaryCost() {
+		for _, cv := range c.collected {
+			if cv.nbBits == 0 {
+				api.AssertIsEqual(cv.v, 0)
+				continue
+			}
+			bits.ToBinary(api, cv.v, bits.WithNbDigits(cv.nbBits))
+		}
+		return nil
+	}
+	return c.checkLimbs(api, limbBits)
+}
+
+// binaryCost returns the estimated number of constraints for decomposing the
+// variables into bits.
+func (c *batchedChecker) binaryCost() int {
+	cost := 0
+	for _, cv := range c.collected {
+		cost += cv.nbBits + 1
+	}
+	return cost
+}
+
+// limbBits returns the limb size minimizing the estimated number of
+// constraints of the log-derivative argument, and that number.
+func (c *batchedChecker) limbBits(api frontend.API) (limbBits, cost int) {
+	cost = -1
+	for w := 1; w <= maxLimbBits; w++ {
+		nbQueries := 0
+		for _, cv := range c.collected {
+			nbQueries += nbQueriesOf(cv.nbBits, w)
+		}
+		// one recomposition per variable
+		wCost := logderivarg.Cost(api, 1<<w, nbQueries) + len(c.collected)
+		if cost < 0 || wCost < cost {
+			limbBits, cost = w, wCost
+		}
+	}
+	return limbBits, cost
+}
+
+// nbQueriesOf returns the number of table queries for checking nbBits bits
+// with limbs of limbBits bits. A partial top limb is queried twice, directly
+// and shifted to the top of the table range.
+func nbQueriesOf(nbBits, limbBits int) int {
+	nbQueries := (nbBits + limbBits - 1) / limbBits
+	if nbBits%limbBits != 0 {
+		nbQueries++
+	}
+	return nbQueries
+}
+
+// checkLimbs decomposes the variables into limbs of limbBits bits and looks
+// the limbs up in the table of all limbBits-bit values.
+func (c *batchedChecker) checkLimbs(api frontend.API, limbBits int) error {
+	table := make(logderivarg.Table, 1<<limbBits)
+	for i := range table {
+		table[i] = []frontend.Variable{i}
+	}
+	var queries logderivarg.Table
+	for _, cv := range c.collected {
+		if cv.nbBits == 0 {
+			api.AssertIsEqual(cv.v, 0)
+			continue
+		}
+		nbLimbs := (cv.nbBits + limbBits - 1) / limbBits
+		limbs, err := api.Compiler().NewHint(DecomposeHint, nbLimbs, limbBits, cv.v)
+		if err != nil {
+			return fmt.Errorf("decompose: %w", err)
+		}
+		composed := frontend.Variable(0)
+		coef := new(big.Int).SetInt64(1)
+		for i := range limbs {
+			composed = api.Add(composed, api.Mul(limbs[i], coef))
+			coef.Lsh(coef, uint(limbBits))
+		}
+		api.AssertIsEqual(composed, cv.v)
+		for i := range limbs {
+			queries = append(queries, []frontend.Variable{limbs[i]})
+		}
+		if r := cv.nbBits % limbBits; r != 0 {
+			// the top limb is in the table and so is the top limb multiplied
+			// by 2^(limbBits-r), hence it is smaller than 2^r
+			queries = append(queries, []frontend.Variable{api.Mul(limbs[nbLimbs-1], 1<<(limbBits-r))})
+		}
+	}
+	return logderivarg.Build(api, table, queries)
+}
+
+// DecomposeHint decomposes the second input into limbs of the size given by
+// the first input. The number of limbs is given by the number of outputs.
+func DecomposeHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expected 2 inputs, got %d", len(inputs))
+	}
+	if !inputs[0].IsUint64() {
+		return fmt.Errorf("limb size does not fit in uint64")
+	}
+	limbBits := uint(inputs[0].Uint64())
+	mask := new(big.Int).Lsh(big.NewInt(1), limbBits)
+	mask.Sub(mask, big.NewInt(1))
+	tmp := new(big.Int).Set(inputs[1])
+	for i := range outputs {
+		outputs[i].And(tmp, mask)
+		tmp.Rsh(tmp, limbBits)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rangecheck implements range checks of variables.
+//
+// The checks are registered with [New] and Check. The package chooses the best
+// method for the compiler:
+//   - if the compiler implements [frontend.Rangechecker], such as the test
+//     engine, the checks are delegated to it;
+//   - otherwise the checks are collected and batched when the circuit is
+//     compiled. The variables are then either decomposed into bits or
+//     decomposed into fixed-size limbs, which are looked up in a shared table
+//     using a log-derivative argument, whichever is estimated to be cheaper.
+//
+// The checks are identical for the R1CS and the PLONK builders.
+package rangecheck
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+// New returns a range checker for api. All the range checkers returned for the
+// same circuit share the batch of checks.
+func New(api frontend.API) frontend.Rangechecker {
+	if rc, ok := api.(frontend.Rangechecker); ok {
+		return rc
+	}
+	return newBatchedChecker(api)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rangecheck
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test"
+)
+
+var widths = []int{0, 1, 7, 8, 13, 16, 64, 100}
+
+type checkCircuit struct {
+	forceLimbs bool
+	Values     []frontend.Variable
+}
+
+func (c *checkCircuit) Define(api frontend.API) error {
+	for i := range c.Values {
+		// every call shares the same batch
+		rc := New(api)
+		if bc, ok := rc.(*batchedChecker); ok {
+			bc.forceLimbs = c.forceLimbs
+		}
+		rc.Check(c.Values[i], widths[i])
+	}
+	New(api).Check(255, 8)
+	return nil
+}
+
+func TestCheck(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := make([]frontend.Variable, len(widths))
+	for i, w := range widths {
+		values[i] = new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), uint(w)))
+	}
+	for _, forceLimbs := range []bool{false, true} {
+		// the compiled circuits are cached by address, use a fresh assert
+		assert := test.NewAssert(t)
+		circuit := checkCircuit{forceLimbs: forceLimbs, Values: make([]frontend.Variable, len(widths))}
+		assert.SolvingSucceeded(&circuit, &checkCircuit{Values: values}, test.WithCurves(ecc.BN254))
+
+		for i, w := range widths {
+			invalid := make([]frontend.Variable, len(values))
+			copy(invalid, values)
+			invalid[i] = new(big.Int).Lsh(big.NewInt(1), uint(w))
+			assert.SolvingFailed(&circuit, &checkCircuit{Values: invalid}, test.WithCurves(ecc.BN254))
+		}
+	}
+}
+
+type constantCircuit struct {
+	constant int
+	X        frontend.Variable
+}
+
+func (c *constantCircuit) Define(api frontend.API) error {
+	rc := New(api)
+	rc.Check(c.X, 8)
+	rc.Check(c.constant, 8)
+	return nil
+}
+
+func TestCheckConstant(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := frontend.Compile(ecc.BN254, r1cs.NewBuilder, &constantCircuit{constant: 255})
+	assert.NoError(err)
+	_, err = frontend.Compile(ecc.BN254, r1cs.NewBuilder, &constantCircuit{constant: 256})
+	assert.Error(err)
+}
+
+type batchCircuit struct {
+	forceLimbs bool
+	Values     [256]frontend.Variable
+}
+
+func (c *batchCircuit) Define(api frontend.API) error {
+	rc := New(api)
+	if bc, ok := rc.(*batchedChecker); ok {
+		bc.forceLimbs = c.forceLimbs
+	}
+	for i := range c.Values {
+		rc.Check(c.Values[i], 64)
+	}
+	return nil
+}
+
+func TestStrategy(t *testing.T) {
+	assert := test.NewAssert(t)
+	compile := func(newBuilder frontend.NewBuilder, circuit frontend.Circuit) int {
+		ccs, err := frontend.Compile(ecc.BN254, newBuilder, circuit)
+		assert.NoError(err)
+		return ccs.GetNbConstraints()
+	}
+
+	// the R1CS builder commits to the limbs for the challenge of the argument,
+	// so that the lookups are chosen for a large batch and are much cheaper
+	// than the bit decompositions
+	limbs := compile(r1cs.NewBuilder, &batchCircuit{})
+	assert.Equal(compile(r1cs.NewBuilder, &batchCircuit{forceLimbs: true}), limbs)
+	assert.Less(2*limbs, len(batchCircuit{}.Values)*64)
+
+	// the PLONK builder hashes them in-circuit, which does not pay off
+	circuit := checkCircuit{Values: make([]frontend.Variable, len(widths))}
+	binary := compile(scs.NewBuilder, &circuit)
+	circuit.forceLimbs = true
+	assert.Less(binary, compile(scs.NewBuilder, &circuit))
+}
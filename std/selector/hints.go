@@ -0,0 +1,55 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selector
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+)
+
+func init() {
+	for _, h := range GetHints() {
+		hint.Register(h)
+	}
+}
+
+// GetHints returns all hints used in this package.
+func GetHints() []hint.Function {
+	return []hint.Function{IndicatorHint}
+}
+
+// IndicatorHint computes the indicator vector of the first input among the
+// other inputs: output i is one if the first input equals input i+1 and zero
+// otherwise. Only the first match is indicated.
+func IndicatorHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != len(outputs)+1 {
+		return errors.New("expected one more input than outputs")
+	}
+	found := false
+	for i := range outputs {
+		if !found && inputs[0].Cmp(inputs[i+1]) == 0 {
+			outputs[i].SetUint64(1)
+			found = true
+		} else {
+			outputs[i].SetUint64(0)
+		}
+	}
+	return nil
+}
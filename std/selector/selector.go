@@ -0,0 +1,271 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selector implements the selection of elements of arrays by
+// variable indices and keys.
+//
+// The gadgets assert that the selectors are in range: [Mux] asserts that the
+// index is smaller than the number of inputs, [Map] that the key is one of
+// the keys, and [Partition] and [Slice] that the positions are at most the
+// length of the input.
+//
+// Constant selectors are resolved at compile time. Otherwise, the gadgets
+// either decompose the selector into bits and select the input with a binary
+// tree of multiplexers, or compute the indicator vector of the selector and
+// take its dot product with the inputs. The dot product does not cost any
+// constraint with constant inputs in R1CS, which is accounted for when
+// choosing the strategy.
+package selector
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// Mux returns inputs[sel]. It asserts that 0 <= sel < len(inputs).
+func Mux(api frontend.API, sel frontend.Variable, inputs ...frontend.Variable) frontend.Variable {
+	n := len(inputs)
+	if n == 0 {
+		panic("no inputs to select from")
+	}
+	if c, ok := api.Compiler().ConstantValue(sel); ok {
+		if !c.IsUint64() || c.Uint64() >= uint64(n) {
+			panic(fmt.Sprintf("selector %s out of range [0, %d)", c, n))
+		}
+		return inputs[c.Uint64()]
+	}
+	if n == 1 {
+		api.AssertIsEqual(sel, 0)
+		return inputs[0]
+	}
+	nbBits := bits.Len(uint(n - 1))
+	constantInputs := allConstant(api, inputs)
+	if binaryCost(n, nbBits, constantInputs) < indicatorCost(n, constantInputs) {
+		return binaryMux(api, sel, nbBits, inputs)
+	}
+	keys := make([]frontend.Variable, n)
+	for i := range keys {
+		keys[i] = i
+	}
+	return dotProduct(api, indicators(api, sel, keys), inputs)
+}
+
+// Map returns values[i] for the index i such that keys[i] == key. It asserts
+// that key is one of the keys. The keys must be distinct, which is checked at
+// compile time for constant keys. Otherwise the value of any of the matching
+// keys may be returned.
+func Map(api frontend.API, key frontend.Variable, keys []frontend.Variable, values []frontend.Variable) frontend.Variable {
+	if len(keys) != len(values) {
+		panic(fmt.Sprintf("%d keys for %d values", len(keys), len(values)))
+	}
+	if len(keys) == 0 {
+		panic("no values to select from")
+	}
+	if allConstant(api, keys) {
+		seen := make(map[string]struct{}, len(keys))
+		for i := range keys {
+			c, _ := api.Compiler().ConstantValue(keys[i])
+			if _, ok := seen[c.String()]; ok {
+				panic(fmt.Sprintf("duplicate key %s", c))
+			}
+			seen[c.String()] = struct{}{}
+		}
+	}
+	ind := indicators(api, key, keys)
+	if !allConstant(api, keys) {
+		// with equal keys, the indicators could otherwise weight the values
+		for i := range ind {
+			api.AssertIsBoolean(ind[i])
+		}
+	}
+	return dotProduct(api, ind, values)
+}
+
+// Partition returns the inputs on one side of the pivot position, the other
+// inputs being set to zero. If rightSide is false, then out[i] = inputs[i] for
+// i < pivotPosition and zero otherwise. If rightSide is true, then out[i] =
+// inputs[i] for i >= pivotPosition and zero otherwise. It asserts that
+// 0 <= pivotPosition <= len(inputs).
+func Partition(api frontend.API, pivotPosition frontend.Variable, rightSide bool, inputs []frontend.Variable) []frontend.Variable {
+	mask := stepMask(api, pivotPosition, len(inputs))
+	out := make([]frontend.Variable, len(inputs))
+	for i := range out {
+		if rightSide {
+			out[i] = api.Mul(mask[i], inputs[i])
+		} else {
+			out[i] = api.Mul(api.Sub(1, mask[i]), inputs[i])
+		}
+	}
+	return out
+}
+
+// Slice returns the inputs between start (inclusive) and end (exclusive), the
+// other inputs being set to zero. That is, out[i] = inputs[i] for start <= i <
+// end and zero otherwise. It asserts that 0 <= start <= end <= len(inputs).
+func Slice(api frontend.API, start, end frontend.Variable, inputs []frontend.Variable) []frontend.Variable {
+	startMask := stepMask(api, start, len(inputs))
+	endMask := stepMask(api, end, len(inputs))
+	out := make([]frontend.Variable, len(inputs))
+	for i := range out {
+		// start <= end if and only if i >= end implies i >= start
+		api.AssertIsEqual(api.Mul(endMask[i], api.Sub(1, startMask[i])), 0)
+		out[i] = api.Mul(api.Sub(startMask[i], endMask[i]), inputs[i])
+	}
+	return out
+}
+
+// stepMask returns the n values mask[i] = 1 if i >= pos and 0 otherwise. It
+// asserts that 0 <= pos <= n.
+func stepMask(api frontend.API, pos frontend.Variable, n int) []frontend.Variable {
+	mask := make([]frontend.Variable, n)
+	if c, ok := api.Compiler().ConstantValue(pos); ok {
+		if !c.IsUint64() || c.Uint64() > uint64(n) {
+			panic(fmt.Sprintf("position %s out of range [0, %d]", c, n))
+		}
+		for i := range mask {
+			if uint64(i) >= c.Uint64() {
+				mask[i] = 1
+			} else {
+				mask[i] = 0
+			}
+		}
+		return mask
+	}
+	keys := make([]frontend.Variable, n+1)
+	for i := range keys {
+		keys[i] = i
+	}
+	ind := indicators(api, pos, keys)
+	// the mask is the prefix sum of the indicators
+	acc := frontend.Variable(0)
+	for i := range mask {
+		acc = api.Add(acc, ind[i])
+		mask[i] = acc
+	}
+	return mask
+}
+
+// indicators returns the indicator vector of key among the distinct keys:
+// res[i] = 1 if key == keys[i] and 0 otherwise. It asserts that key is one of
+// the keys.
+func indicators(api frontend.API, key frontend.Variable, keys []frontend.Variable) []frontend.Variable {
+	res, err := api.Compiler().NewHint(IndicatorHint, len(keys), append([]frontend.Variable{key}, keys...)...)
+	if err != nil {
+		panic(err)
+	}
+	// res[i] is zero unless key == keys[i], and one of them is non-zero. With
+	// distinct keys, exactly one indicator is non-zero and it is one.
+	sum := frontend.Variable(0)
+	for i := range res {
+		api.AssertIsEqual(api.Mul(res[i], api.Sub(key, keys[i])), 0)
+		sum = api.Add(sum, res[i])
+	}
+	api.AssertIsEqual(sum, 1)
+	return res
+}
+
+// dotProduct returns Σ a[i]·b[i].
+func dotProduct(api frontend.API, a, b []frontend.Variable) frontend.Variable {
+	res := frontend.Variable(0)
+	for i := range a {
+		res = api.Add(res, api.Mul(a[i], b[i]))
+	}
+	return res
+}
+
+// binaryMux returns inputs[sel] using the nbBits-bit decomposition of sel. It
+// asserts that sel < len(inputs).
+func binaryMux(api frontend.API, sel frontend.Variable, nbBits int, inputs []frontend.Variable) frontend.Variable {
+	selBits := api.ToBinary(sel, nbBits)
+	if len(inputs) < 1<<nbBits {
+		assertBitsLessOrEqual(api, selBits, len(inputs)-1)
+		padded := make([]frontend.Variable, 1<<nbBits)
+		copy(padded, inputs)
+		for i := len(inputs); i < len(padded); i++ {
+			padded[i] = 0
+		}
+		inputs = padded
+	}
+	return binaryTree(api, selBits, inputs)
+}
+
+// binaryTree returns inputs[Σ 2^i·selBits[i]] for len(inputs) = 2^len(selBits).
+func binaryTree(api frontend.API, selBits []frontend.Variable, inputs []frontend.Variable) frontend.Variable {
+	switch len(selBits) {
+	case 0:
+		return inputs[0]
+	case 1:
+		return api.Select(selBits[0], inputs[1], inputs[0])
+	case 2:
+		return api.Lookup2(selBits[0], selBits[1], inputs[0], inputs[1], inputs[2], inputs[3])
+	}
+	top := len(selBits) - 1
+	half := len(inputs) / 2
+	left := binaryTree(api, selBits[:top], inputs[:half])
+	right := binaryTree(api, selBits[:top], inputs[half:])
+	return api.Select(selBits[top], right, left)
+}
+
+// assertBitsLessOrEqual asserts that the integer given by its little-endian
+// bits is at most bound.
+func assertBitsLessOrEqual(api frontend.API, b []frontend.Variable, bound int) {
+	// prefixEq is one if the bits above i are the bits of the bound. The bits
+	// must then be zero where the bits of the bound are.
+	prefixEq := frontend.Variable(1)
+	for i := len(b) - 1; i >= 0; i-- {
+		if (bound>>i)&1 == 1 {
+			prefixEq = api.Mul(prefixEq, b[i])
+		} else {
+			api.AssertIsEqual(api.Mul(prefixEq, b[i]), 0)
+		}
+	}
+}
+
+// allConstant returns true if all the variables are constants.
+func allConstant(api frontend.API, v []frontend.Variable) bool {
+	for i := range v {
+		if _, ok := api.Compiler().ConstantValue(v[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// indicatorCost returns the estimated number of constraints for selecting
+// among n inputs with the indicator vector.
+func indicatorCost(n int, constantInputs bool) int {
+	cost := n + 1
+	if !constantInputs {
+		cost += n
+	}
+	return cost
+}
+
+// binaryCost returns the estimated number of constraints for selecting among
+// n inputs with the nbBits-bit decomposition of the selector.
+func binaryCost(n, nbBits int, constantInputs bool) int {
+	cost := nbBits + 1
+	if n < 1<<nbBits {
+		cost += nbBits
+	}
+	if constantInputs {
+		// the lowest level of the tree is linear in the selector bits
+		return cost + (1<<nbBits)/2
+	}
+	return cost + (1<<nbBits - 1)
+}
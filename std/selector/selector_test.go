@@ -0,0 +1,184 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selector
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type muxCircuit struct {
+	constantInputs int
+	Sel            frontend.Variable
+	Inputs         []frontend.Variable
+	Expected       frontend.Variable
+}
+
+func (c *muxCircuit) Define(api frontend.API) error {
+	inputs := c.Inputs
+	if c.constantInputs > 0 {
+		inputs = muxInputs(c.constantInputs)
+	}
+	api.AssertIsEqual(Mux(api, c.Sel, inputs...), c.Expected)
+	return nil
+}
+
+func muxInputs(n int) []frontend.Variable {
+	res := make([]frontend.Variable, n)
+	for i := range res {
+		res[i] = i*i + 7
+	}
+	return res
+}
+
+func TestMux(t *testing.T) {
+	// 8 and 24 use the binary tree, 24 with the bound on the selector
+	for _, n := range []int{1, 2, 3, 4, 5, 8, 24} {
+		for _, constantInputs := range []bool{false, true} {
+			assert := test.NewAssert(t)
+			assert.Run(func(assert *test.Assert) {
+				circuit := &muxCircuit{Inputs: make([]frontend.Variable, n)}
+				inputs := muxInputs(n)
+				if constantInputs {
+					circuit = &muxCircuit{constantInputs: n}
+					inputs = nil
+				}
+				for _, sel := range []int{0, n / 2, n - 1} {
+					assert.SolvingSucceeded(circuit, &muxCircuit{Sel: sel, Inputs: inputs, Expected: sel*sel + 7}, test.WithCurves(ecc.BN254))
+				}
+				assert.SolvingFailed(circuit, &muxCircuit{Sel: 1, Inputs: inputs, Expected: 0}, test.WithCurves(ecc.BN254))
+				for _, sel := range []int{n, n + 1, -1} {
+					assert.SolvingFailed(circuit, &muxCircuit{Sel: sel, Inputs: inputs, Expected: 7}, test.WithCurves(ecc.BN254))
+				}
+			}, fmt.Sprintf("n=%d/constant=%t", n, constantInputs))
+		}
+	}
+}
+
+type mapCircuit struct {
+	Key      frontend.Variable
+	Keys     []frontend.Variable // constant keys if nil
+	Values   []frontend.Variable
+	Expected frontend.Variable
+}
+
+func (c *mapCircuit) Define(api frontend.API) error {
+	keys := c.Keys
+	if keys == nil {
+		keys = mapKeys
+	}
+	api.AssertIsEqual(Map(api, c.Key, keys, c.Values), c.Expected)
+	return nil
+}
+
+var mapKeys = []frontend.Variable{5, 17, 3, 42}
+
+func TestMap(t *testing.T) {
+	values := []frontend.Variable{10, 20, 30, 40}
+	for _, constantKeys := range []bool{false, true} {
+		assert := test.NewAssert(t)
+		circuit := &mapCircuit{Keys: make([]frontend.Variable, 4), Values: make([]frontend.Variable, 4)}
+		keys := mapKeys
+		if constantKeys {
+			circuit.Keys, keys = nil, nil
+		}
+		assert.ProverSucceeded(circuit, &mapCircuit{Key: 3, Keys: keys, Values: values, Expected: 30}, test.WithCurves(ecc.BN254))
+		assert.ProverSucceeded(circuit, &mapCircuit{Key: 42, Keys: keys, Values: values, Expected: 40}, test.WithCurves(ecc.BN254))
+		assert.ProverFailed(circuit, &mapCircuit{Key: 4, Keys: keys, Values: values, Expected: 0}, test.WithCurves(ecc.BN254))
+		assert.ProverFailed(circuit, &mapCircuit{Key: 17, Keys: keys, Values: values, Expected: 10}, test.WithCurves(ecc.BN254))
+	}
+}
+
+type partitionCircuit struct {
+	rightSide bool
+	Pivot     frontend.Variable
+	Inputs    []frontend.Variable
+	Expected  []frontend.Variable
+}
+
+func (c *partitionCircuit) Define(api frontend.API) error {
+	out := Partition(api, c.Pivot, c.rightSide, c.Inputs)
+	for i := range out {
+		api.AssertIsEqual(out[i], c.Expected[i])
+	}
+	return nil
+}
+
+type sliceCircuit struct {
+	Start, End frontend.Variable
+	Inputs     []frontend.Variable
+	Expected   []frontend.Variable
+}
+
+func (c *sliceCircuit) Define(api frontend.API) error {
+	out := Slice(api, c.Start, c.End, c.Inputs)
+	for i := range out {
+		api.AssertIsEqual(out[i], c.Expected[i])
+	}
+	return nil
+}
+
+// slice returns the inputs in [start, end) and zeros elsewhere.
+func slice(inputs []frontend.Variable, start, end int) []frontend.Variable {
+	res := make([]frontend.Variable, len(inputs))
+	for i := range res {
+		if i >= start && i < end {
+			res[i] = inputs[i]
+		} else {
+			res[i] = 0
+		}
+	}
+	return res
+}
+
+func TestPartition(t *testing.T) {
+	const n = 5
+	inputs := muxInputs(n)
+	for _, rightSide := range []bool{false, true} {
+		assert := test.NewAssert(t)
+		circuit := &partitionCircuit{rightSide: rightSide, Inputs: make([]frontend.Variable, n), Expected: make([]frontend.Variable, n)}
+		for pivot := 0; pivot <= n; pivot++ {
+			expected := slice(inputs, 0, pivot)
+			if rightSide {
+				expected = slice(inputs, pivot, n)
+			}
+			assert.SolvingSucceeded(circuit, &partitionCircuit{Pivot: pivot, Inputs: inputs, Expected: expected}, test.WithCurves(ecc.BN254))
+		}
+		assert.SolvingFailed(circuit, &partitionCircuit{Pivot: n + 1, Inputs: inputs, Expected: slice(inputs, 0, 0)}, test.WithCurves(ecc.BN254))
+		assert.SolvingFailed(circuit, &partitionCircuit{Pivot: 2, Inputs: inputs, Expected: slice(inputs, 0, 3)}, test.WithCurves(ecc.BN254))
+	}
+}
+
+func TestSlice(t *testing.T) {
+	const n = 5
+	assert := test.NewAssert(t)
+	inputs := muxInputs(n)
+	circuit := &sliceCircuit{Inputs: make([]frontend.Variable, n), Expected: make([]frontend.Variable, n)}
+	for start := 0; start <= n; start++ {
+		for end := start; end <= n; end++ {
+			assert.SolvingSucceeded(circuit, &sliceCircuit{Start: start, End: end, Inputs: inputs, Expected: slice(inputs, start, end)}, test.WithCurves(ecc.BN254))
+		}
+	}
+	assert.SolvingFailed(circuit, &sliceCircuit{Start: 3, End: 2, Inputs: inputs, Expected: slice(inputs, 0, 0)}, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(circuit, &sliceCircuit{Start: 1, End: n + 1, Inputs: inputs, Expected: slice(inputs, 1, n)}, test.WithCurves(ecc.BN254))
+	assert.SolvingFailed(circuit, &sliceCircuit{Start: 1, End: 3, Inputs: inputs, Expected: slice(inputs, 1, 4)}, test.WithCurves(ecc.BN254))
+	assert.ProverSucceeded(circuit, &sliceCircuit{Start: 1, End: 3, Inputs: inputs, Expected: slice(inputs, 1, 3)}, test.WithCurves(ecc.BN254))
+}
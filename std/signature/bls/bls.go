@@ -0,0 +1,192 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bls provides ZKP-circuit functions to verify BLS signatures on the
+// BLS12-381 curve, as used in the Ethereum consensus layer.
+//
+// The public keys are points of G1 and the signatures are points of G2. The
+// messages are byte strings hashed to G2 with the domain separation tag
+// [DST] of the proof of possession scheme. As the base field of BLS12-381
+// differs from the native field, the arithmetic is performed using emulated
+// fields (see package [github.com/consensys/gnark/std/algebra/sw_bls12381]).
+//
+// The public keys are only checked to be on the curve. They are assumed to
+// have been validated out of circuit, as it is done when registering the keys
+// with a proof of possession.
+package bls
+
+import (
+	"errors"
+	"fmt"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/sw_bls12381"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// DST is the domain separation tag of the BLS signature scheme with proof of
+// possession over BLS12-381 with the signatures in G2.
+const DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+// PublicKey stores a BLS public key (to be used in gnark circuit).
+type PublicKey sw_bls12381.G1Affine
+
+// Signature stores a BLS signature (to be used in gnark circuit).
+type Signature sw_bls12381.G2Affine
+
+// NewPublicKey returns a public key with allocated limbs. It is used to
+// define the public key in a circuit structure.
+func NewPublicKey() PublicKey {
+	return PublicKey(sw_bls12381.NewG1Affine())
+}
+
+// NewSignature returns a signature with allocated limbs. It is used to define
+// the signature in a circuit structure.
+func NewSignature() Signature {
+	return Signature(sw_bls12381.NewG2Affine())
+}
+
+// Assign is a helper to assign the native public key v into the coordinates.
+func (pk *PublicKey) Assign(v *bls12381.G1Affine) {
+	(*sw_bls12381.G1Affine)(pk).Assign(v)
+}
+
+// Assign is a helper to assign the native signature v into the coordinates.
+func (s *Signature) Assign(v *bls12381.G2Affine) {
+	(*sw_bls12381.G2Affine)(s).Assign(v)
+}
+
+// AssertCompressed asserts that compressed is the encoding of the public key
+// pubKey in compressed form, as stored in the Ethereum consensus layer: the
+// 48 bytes of the big-endian x-coordinate with the compression flag (0x80)
+// set, the infinity flag (0x40) unset and the sign flag (0x20) set if and
+// only if the y-coordinate is lexicographically largest.
+//
+// The point is not checked to be on the curve.
+func AssertCompressed(api frontend.API, pubKey *PublicKey, compressed []frontend.Variable) error {
+	if len(compressed) != bls12381.SizeOfG1AffineCompressed {
+		return fmt.Errorf("expected %d bytes, got %d", bls12381.SizeOfG1AffineCompressed, len(compressed))
+	}
+	fp, err := emulated.NewField[emulated.BLS12381Fp](api)
+	if err != nil {
+		return fmt.Errorf("new field: %w", err)
+	}
+	x := fp.ToBits(&pubKey.X)
+	// y > (p-1)/2 if and only if 2y mod p is odd, as p is odd
+	sign := fp.ToBits(fp.Add(&pubKey.Y, &pubKey.Y))[0]
+
+	n := len(compressed)
+	for i := 0; i < n-1; i++ {
+		api.AssertIsEqual(compressed[n-1-i], bits.FromBinary(api, x[8*i:8*i+8]))
+	}
+	top := bits.FromBinary(api, x[8*(n-1):])
+	api.AssertIsEqual(compressed[0], api.Add(top, api.Mul(sign, 0x20), 0x80))
+	return nil
+}
+
+// Verify verifies the BLS signature sig of the message msg by the public key
+// pubKey. The message is given as bytes. See [VerifyAggregate] for the
+// checks performed.
+func Verify(api frontend.API, pubKey *PublicKey, msg []frontend.Variable, sig *Signature) error {
+	return VerifyAggregate(api, []PublicKey{*pubKey}, msg, sig)
+}
+
+// VerifyAggregate verifies the aggregate BLS signature sig of the message msg
+// by all the public keys pubKeys. The message is given as bytes. It asserts
+// that the public keys are on the curve and that the signature is in G2.
+//
+// The public keys are aggregated using incomplete additions, so that the
+// constraints are not satisfiable if partial sums collide, which happens with
+// negligible probability for independent keys. In particular the same public
+// key must not be given twice.
+func VerifyAggregate(api frontend.API, pubKeys []PublicKey, msg []frontend.Variable, sig *Signature) error {
+	return verifyAggregate(api, pubKeys, nil, msg, sig)
+}
+
+// VerifyAggregateSubset verifies the aggregate BLS signature sig of the
+// message msg by the public keys pubKeys[i] for which selected[i] is 1, as
+// for the participants of a sync committee. The selectors are asserted to be
+// boolean and at least one of them must be set. See [VerifyAggregate] for the
+// other checks performed.
+func VerifyAggregateSubset(api frontend.API, pubKeys []PublicKey, selected []frontend.Variable, msg []frontend.Variable, sig *Signature) error {
+	if len(selected) != len(pubKeys) {
+		return errors.New("number of selectors and public keys mismatch")
+	}
+	return verifyAggregate(api, pubKeys, selected, msg, sig)
+}
+
+// verifyAggregate verifies the aggregate signature by the selected public
+// keys, or all of them if selected is nil.
+func verifyAggregate(api frontend.API, pubKeys []PublicKey, selected []frontend.Variable, msg []frontend.Variable, sig *Signature) error {
+	if len(pubKeys) == 0 {
+		return errors.New("no public keys")
+	}
+	pr, err := sw_bls12381.NewPairing(api)
+	if err != nil {
+		return fmt.Errorf("new pairing: %w", err)
+	}
+	g1, err := sw_bls12381.NewG1(api)
+	if err != nil {
+		return fmt.Errorf("new G1: %w", err)
+	}
+	g2 := pr.G2()
+
+	aggPk := aggregate(g1, pubKeys, selected)
+	s := (*sw_bls12381.G2Affine)(sig)
+	g2.AssertIsOnCurve(s)
+	g2.AssertIsInSubgroup(s)
+	h, err := g2.HashToG2(msg, []byte(DST))
+	if err != nil {
+		return fmt.Errorf("hash to G2: %w", err)
+	}
+
+	// e(aggPk, H(msg)) = e(G, sig)
+	negG := (*sw_bls12381.G1Affine)(g1.Neg(g1.Generator()))
+	return pr.PairingCheck([]*sw_bls12381.G1Affine{aggPk, negG}, []*sw_bls12381.G2Affine{h, s})
+}
+
+// aggregate asserts that the public keys are on the curve and returns the
+// sum of the selected ones, or of all of them if selected is nil. The sum is
+// initialised with the base point, which is subtracted at the end, so that
+// the incomplete additions do not get the point at infinity.
+func aggregate(g1 *sw_bls12381.G1, pubKeys []PublicKey, selected []frontend.Variable) *sw_bls12381.G1Affine {
+	api := g1.API()
+	res := g1.Generator()
+	for i := range pubKeys {
+		pk := (*weierstrass.AffinePoint[emulated.BLS12381Fp])(&pubKeys[i])
+		g1.AssertIsOnCurve(pk)
+		if selected == nil {
+			res = addDistinct(g1, res, pk)
+		} else {
+			api.AssertIsBoolean(selected[i])
+			res = g1.Select(selected[i], addDistinct(g1, res, pk), res)
+		}
+	}
+	res = addDistinct(g1, res, g1.Neg(g1.Generator()))
+	return (*sw_bls12381.G1Affine)(res)
+}
+
+// addDistinct returns p+q and asserts that p and q have different
+// x-coordinates. The incomplete addition does not constrain the result if p
+// equals q.
+func addDistinct(g1 *sw_bls12381.G1, p, q *weierstrass.AffinePoint[emulated.BLS12381Fp]) *weierstrass.AffinePoint[emulated.BLS12381Fp] {
+	fp := g1.BaseField()
+	fp.Inverse(fp.Sub(&q.X, &p.X))
+	return g1.Add(p, q)
+}
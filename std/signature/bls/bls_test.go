@@ -0,0 +1,171 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+type verifyAggregateCircuit struct {
+	PubKeys []PublicKey
+	Msg     []frontend.Variable
+	Sig     Signature
+}
+
+func (c *verifyAggregateCircuit) Define(api frontend.API) error {
+	return VerifyAggregate(api, c.PubKeys, c.Msg, &c.Sig)
+}
+
+// sign returns the public keys and the aggregate signature of msg by nbKeys
+// random secret keys.
+func sign(assert *test.Assert, nbKeys int, msg []byte) ([]bls12381.G1Affine, bls12381.G2Affine) {
+	_, _, g1, _ := bls12381.Generators()
+	h, err := bls12381.HashToCurveG2SSWU(msg, []byte(DST))
+	assert.NoError(err)
+	pubKeys := make([]bls12381.G1Affine, nbKeys)
+	var sum fr.Element
+	for i := range pubKeys {
+		var sk fr.Element
+		_, err := sk.SetRandom()
+		assert.NoError(err)
+		pubKeys[i].ScalarMultiplication(&g1, sk.ToBigIntRegular(new(big.Int)))
+		sum.Add(&sum, &sk)
+	}
+	var sig bls12381.G2Affine
+	sig.ScalarMultiplication(&h, sum.ToBigIntRegular(new(big.Int)))
+	return pubKeys, sig
+}
+
+func TestVerifyAggregate(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := []byte("sync committee signing root")
+	pubKeys, sig := sign(assert, 2, msg)
+
+	circuit := verifyAggregateCircuit{
+		PubKeys: []PublicKey{NewPublicKey(), NewPublicKey()},
+		Msg:     make([]frontend.Variable, len(msg)),
+		Sig:     NewSignature(),
+	}
+	witness := verifyAggregateCircuit{
+		PubKeys: make([]PublicKey, len(pubKeys)),
+		Msg:     make([]frontend.Variable, len(msg)),
+	}
+	for i := range pubKeys {
+		witness.PubKeys[i].Assign(&pubKeys[i])
+	}
+	for i := range msg {
+		witness.Msg[i] = msg[i]
+	}
+	witness.Sig.Assign(&sig)
+	err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+
+	// the signature is not valid for a subset of the keys
+	_, _, g1, _ := bls12381.Generators()
+	witness.PubKeys[1].Assign(&g1)
+	err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
+
+type verifyAggregateSubsetCircuit struct {
+	PubKeys  []PublicKey
+	Selected []frontend.Variable
+	Msg      []frontend.Variable
+	Sig      Signature
+}
+
+func (c *verifyAggregateSubsetCircuit) Define(api frontend.API) error {
+	return VerifyAggregateSubset(api, c.PubKeys, c.Selected, c.Msg, &c.Sig)
+}
+
+func TestVerifyAggregateSubset(t *testing.T) {
+	assert := test.NewAssert(t)
+	msg := []byte("sync committee signing root")
+	signers, sig := sign(assert, 2, msg)
+	others, _ := sign(assert, 1, msg)
+	pubKeys := []bls12381.G1Affine{signers[0], others[0], signers[1]}
+
+	circuit := verifyAggregateSubsetCircuit{
+		PubKeys:  []PublicKey{NewPublicKey(), NewPublicKey(), NewPublicKey()},
+		Selected: make([]frontend.Variable, len(pubKeys)),
+		Msg:      make([]frontend.Variable, len(msg)),
+		Sig:      NewSignature(),
+	}
+	witness := verifyAggregateSubsetCircuit{
+		PubKeys:  make([]PublicKey, len(pubKeys)),
+		Selected: []frontend.Variable{1, 0, 1},
+		Msg:      make([]frontend.Variable, len(msg)),
+	}
+	for i := range pubKeys {
+		witness.PubKeys[i].Assign(&pubKeys[i])
+	}
+	for i := range msg {
+		witness.Msg[i] = msg[i]
+	}
+	witness.Sig.Assign(&sig)
+	err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+
+	// the signature is not valid if the non-signer is selected
+	witness.Selected = []frontend.Variable{1, 1, 1}
+	err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
+
+type assertCompressedCircuit struct {
+	PubKey     PublicKey
+	Compressed [bls12381.SizeOfG1AffineCompressed]frontend.Variable
+}
+
+func (c *assertCompressedCircuit) Define(api frontend.API) error {
+	return AssertCompressed(api, &c.PubKey, c.Compressed[:])
+}
+
+func TestAssertCompressed(t *testing.T) {
+	assert := test.NewAssert(t)
+	pubKeys, _ := sign(assert, 2, nil)
+	// both signs of the y-coordinate
+	var neg bls12381.G1Affine
+	neg.Neg(&pubKeys[1])
+	pubKeys[1] = neg
+
+	for i := range pubKeys {
+		b := pubKeys[i].Bytes()
+		circuit := assertCompressedCircuit{PubKey: NewPublicKey()}
+		var witness assertCompressedCircuit
+		witness.PubKey.Assign(&pubKeys[i])
+		for j := range b {
+			witness.Compressed[j] = b[j]
+		}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+		assert.NoError(err)
+
+		// the negated point has a different sign flag
+		neg.Neg(&pubKeys[i])
+		witness.PubKey.Assign(&neg)
+		err = test.IsSolved(&circuit, &witness, ecc.BN254, backend.UNKNOWN)
+		assert.Error(err)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ecdsa provides ZKP-circuit functions to verify ECDSA signatures on
+// curves in short Weierstrass form, for example secp256k1 and P-256.
+//
+// As the base and scalar fields of the signature curve differ from the native
+// field, the arithmetic is performed using emulated fields (see package
+// [github.com/consensys/gnark/std/math/emulated]). The message is given as
+// the hash of the signed data, interpreted as an element of the scalar field.
+//
+// The package also provides public key recovery from the signature and the
+// recovery id and the derivation of Ethereum addresses from public keys.
+package ecdsa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// PublicKey stores an ECDSA public key (to be used in gnark circuit). Base is
+// the base field and Scalar the scalar field of the signature curve.
+type PublicKey[Base, Scalar emulated.FieldParams] weierstrass.AffinePoint[Base]
+
+// Signature stores an ECDSA signature (to be used in gnark circuit). The
+// signature is the pair (R, S) of scalars.
+type Signature[Scalar emulated.FieldParams] struct {
+	R, S emulated.Element[Scalar]
+}
+
+// NewPublicKey returns a public key with allocated limbs. It is used to
+// define the public key in a circuit structure.
+func NewPublicKey[Base, Scalar emulated.FieldParams]() PublicKey[Base, Scalar] {
+	return PublicKey[Base, Scalar](weierstrass.NewAffinePoint[Base]())
+}
+
+// NewSignature returns a signature with allocated limbs. It is used to define
+// the signature in a circuit structure.
+func NewSignature[Scalar emulated.FieldParams]() Signature[Scalar] {
+	return Signature[Scalar]{
+		R: emulated.NewElement[Scalar](nil),
+		S: emulated.NewElement[Scalar](nil),
+	}
+}
+
+// Verify verifies the ECDSA signature sig of the message hash msg by the
+// public key pubKey on the curve. It asserts that the public key is on the
+// curve and that the signature values are non-zero.
+func Verify[Base, Scalar emulated.FieldParams](curve *weierstrass.Curve[Base, Scalar], sig *Signature[Scalar], msg *emulated.Element[Scalar], pubKey *PublicKey[Base, Scalar]) error {
+	fr := curve.ScalarField()
+	pk := (*weierstrass.AffinePoint[Base])(pubKey)
+	curve.AssertIsOnCurve(pk)
+
+	// the inverse exists only if s is non-zero
+	sInv := fr.Inverse(&sig.S)
+	// R = [msg/s]G + [r/s]PK
+	u1 := fr.Mul(msg, sInv)
+	u2 := fr.Mul(&sig.R, sInv)
+	p1 := curve.ScalarMulBase(u1)
+	p2 := curve.ScalarMul(pk, u2)
+	R := addDistinct(curve, p1, p2)
+
+	// R.x = r mod n
+	rx := fr.FromBits(curve.BaseField().ToBits(&R.X)...)
+	fr.AssertIsEqual(rx, &sig.R)
+	// r is non-zero
+	curve.API().AssertIsEqual(fr.IsZero(&sig.R), 0)
+	return nil
+}
+
+// addDistinct returns p+q and asserts that p and q have different
+// x-coordinates. The incomplete addition does not constrain the result if p
+// equals q.
+func addDistinct[Base, Scalar emulated.FieldParams](curve *weierstrass.Curve[Base, Scalar], p, q *weierstrass.AffinePoint[Base]) *weierstrass.AffinePoint[Base] {
+	fp := curve.BaseField()
+	fp.Inverse(fp.Sub(&q.X, &p.X))
+	return curve.Add(p, q)
+}
+
+// Assign is a helper to assign a binary public key representation into the
+// coordinates. The accepted encodings are the SEC 1 uncompressed (0x04 || X ||
+// Y) and compressed (0x02 or 0x03 || X) forms and the raw concatenation X ||
+// Y of the coordinates (as used in Ethereum). The curve parameters are used to
+// decompress the point. It panics if the encoding is invalid.
+func (p *PublicKey[Base, Scalar]) Assign(params weierstrass.CurveParams, buf []byte) {
+	x, y, err := parsePublicKey[Base](params, buf)
+	if err != nil {
+		panic(err)
+	}
+	p.X = emulated.NewElement[Base](x)
+	p.Y = emulated.NewElement[Base](y)
+}
+
+// Assign is a helper to assign a binary signature representation r || s,
+// with r and s big-endian encoded on the byte length of the scalar field. If
+// the buffer has one more byte (Ethereum signatures r || s || v), then the
+// last byte is the recovery id and is ignored; it should be assigned
+// separately when recovering the public key. It panics if the length of the
+// buffer is invalid.
+func (s *Signature[Scalar]) Assign(buf []byte) {
+	var fr Scalar
+	size := (fr.Modulus().BitLen() + 7) / 8
+	if len(buf) != 2*size && len(buf) != 2*size+1 {
+		panic(fmt.Sprintf("invalid signature length %d", len(buf)))
+	}
+	s.R = emulated.NewElement[Scalar](new(big.Int).SetBytes(buf[:size]))
+	s.S = emulated.NewElement[Scalar](new(big.Int).SetBytes(buf[size : 2*size]))
+}
+
+// parsePublicKey parses the binary public key into the coordinates.
+func parsePublicKey[Base emulated.FieldParams](params weierstrass.CurveParams, buf []byte) (*big.Int, *big.Int, error) {
+	var fp Base
+	modulus := fp.Modulus()
+	size := (modulus.BitLen() + 7) / 8
+	var x, y *big.Int
+	switch {
+	case len(buf) == 2*size:
+		x = new(big.Int).SetBytes(buf[:size])
+		y = new(big.Int).SetBytes(buf[size:])
+	case len(buf) == 2*size+1 && buf[0] == 0x04:
+		x = new(big.Int).SetBytes(buf[1 : 1+size])
+		y = new(big.Int).SetBytes(buf[1+size:])
+	case len(buf) == size+1 && (buf[0] == 0x02 || buf[0] == 0x03):
+		x = new(big.Int).SetBytes(buf[1:])
+		y = computeY(params, modulus, x, uint(buf[0]&1))
+		if y == nil {
+			return nil, nil, errors.New("invalid compressed point")
+		}
+	default:
+		return nil, nil, errors.New("invalid public key encoding")
+	}
+	if x.Cmp(modulus) >= 0 || y.Cmp(modulus) >= 0 {
+		return nil, nil, errors.New("coordinate larger than modulus")
+	}
+	// y² = x³ + ax + b
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, modulus)
+	if lhs.Cmp(curveEquation(params, modulus, x)) != 0 {
+		return nil, nil, errors.New("point not on curve")
+	}
+	return x, y, nil
+}
+
+// curveEquation returns x³ + ax + b mod p.
+func curveEquation(params weierstrass.CurveParams, p, x *big.Int) *big.Int {
+	res := new(big.Int).Mul(x, x)
+	res.Mul(res, x)
+	ax := new(big.Int).Mul(params.A, x)
+	res.Add(res, ax)
+	res.Add(res, params.B)
+	return res.Mod(res, p)
+}
+
+// computeY returns the y-coordinate of the point with x-coordinate x and
+// given parity of y. It returns nil if there is no such point.
+func computeY(params weierstrass.CurveParams, p, x *big.Int, parity uint) *big.Int {
+	y := new(big.Int).ModSqrt(curveEquation(params, p, x), p)
+	if y == nil {
+		return nil
+	}
+	if y.Bit(0) != parity {
+		y.Sub(p, y)
+	}
+	return y.Mod(y, p)
+}
@@ -0,0 +1,243 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/sha3"
+)
+
+var testCurve = ecc.BN254
+
+// secp256k1 is a minimal native implementation of secp256k1 arithmetic used
+// for generating the test signatures.
+type secp256k1 struct {
+	p, n, gx, gy *big.Int
+}
+
+func newSecp256k1() secp256k1 {
+	params := weierstrass.GetSecp256k1Params()
+	var fp emulated.Secp256k1Fp
+	var fr emulated.Secp256k1Fr
+	return secp256k1{p: fp.Modulus(), n: fr.Modulus(), gx: params.Gx, gy: params.Gy}
+}
+
+// add returns p+q with affine coordinates. The point at infinity is
+// represented by nil coordinates.
+func (c secp256k1) add(px, py, qx, qy *big.Int) (*big.Int, *big.Int) {
+	if px == nil {
+		return qx, qy
+	}
+	if qx == nil {
+		return px, py
+	}
+	var λ *big.Int
+	if px.Cmp(qx) == 0 {
+		if new(big.Int).Add(py, qy).Mod(new(big.Int).Add(py, qy), c.p).Sign() == 0 {
+			return nil, nil
+		}
+		// λ = 3x²/2y
+		λ = new(big.Int).Mul(px, px)
+		λ.Mul(λ, big.NewInt(3))
+		λ.Mul(λ, new(big.Int).ModInverse(new(big.Int).Lsh(py, 1), c.p))
+	} else {
+		λ = new(big.Int).Sub(qy, py)
+		λ.Mul(λ, new(big.Int).ModInverse(new(big.Int).Sub(qx, px).Mod(new(big.Int).Sub(qx, px), c.p), c.p))
+	}
+	λ.Mod(λ, c.p)
+	rx := new(big.Int).Mul(λ, λ)
+	rx.Sub(rx, px).Sub(rx, qx).Mod(rx, c.p)
+	ry := new(big.Int).Sub(px, rx)
+	ry.Mul(ry, λ).Sub(ry, py).Mod(ry, c.p)
+	return rx, ry
+}
+
+func (c secp256k1) scalarBaseMult(k *big.Int) (*big.Int, *big.Int) {
+	var rx, ry *big.Int
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		rx, ry = c.add(rx, ry, rx, ry)
+		if k.Bit(i) == 1 {
+			rx, ry = c.add(rx, ry, c.gx, c.gy)
+		}
+	}
+	return rx, ry
+}
+
+// sign returns the signature (r, s) and the recovery id v of the message hash
+// by the private key.
+func (c secp256k1) sign(priv *big.Int, hash []byte) (r, s *big.Int, v uint) {
+	e := new(big.Int).SetBytes(hash)
+	for {
+		k, err := rand.Int(rand.Reader, c.n)
+		if err != nil {
+			panic(err)
+		}
+		if k.Sign() == 0 {
+			continue
+		}
+		rx, ry := c.scalarBaseMult(k)
+		if rx.Cmp(c.n) >= 0 {
+			continue
+		}
+		r = new(big.Int).Set(rx)
+		s = new(big.Int).Mul(r, priv)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, c.n))
+		s.Mod(s, c.n)
+		if r.Sign() == 0 || s.Sign() == 0 {
+			continue
+		}
+		return r, s, ry.Bit(0)
+	}
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+func (c secp256k1) publicKeyBytes(priv *big.Int) []byte {
+	x, y := c.scalarBaseMult(priv)
+	buf := make([]byte, 64)
+	x.FillBytes(buf[:32])
+	y.FillBytes(buf[32:])
+	return buf
+}
+
+type verifyCircuit[B, S emulated.FieldParams] struct {
+	params weierstrass.CurveParams
+	Sig    Signature[S]
+	Msg    emulated.Element[S]
+	Pub    PublicKey[B, S]
+}
+
+func (c *verifyCircuit[B, S]) Define(api frontend.API) error {
+	curve, err := weierstrass.New[B, S](api, c.params)
+	if err != nil {
+		return err
+	}
+	return Verify(curve, &c.Sig, &c.Msg, &c.Pub)
+}
+
+func TestVerifySecp256k1(t *testing.T) {
+	assert := test.NewAssert(t)
+	c := newSecp256k1()
+	priv, err := rand.Int(rand.Reader, c.n)
+	assert.NoError(err)
+	hash := keccak256([]byte("testing ECDSA (pre-hashed)"))
+	r, s, _ := c.sign(priv, hash)
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	circuit := verifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		params: weierstrass.GetSecp256k1Params(),
+		Sig:    NewSignature[emulated.Secp256k1Fr](),
+		Msg:    emulated.NewElement[emulated.Secp256k1Fr](nil),
+		Pub:    NewPublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr](),
+	}
+	var witness verifyCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	witness.Sig.Assign(sig)
+	witness.Pub.Assign(weierstrass.GetSecp256k1Params(), c.publicKeyBytes(priv))
+	witness.Msg = emulated.NewElement[emulated.Secp256k1Fr](new(big.Int).SetBytes(hash))
+	assert.NoError(test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN))
+
+	// wrong message
+	witness.Msg = emulated.NewElement[emulated.Secp256k1Fr](new(big.Int).SetBytes(keccak256(hash)))
+	assert.Error(test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN))
+}
+
+func TestVerifyP256(t *testing.T) {
+	assert := test.NewAssert(t)
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	hash := sha256.Sum256([]byte("testing ECDSA on P-256"))
+	r, s, err := ecdsa.Sign(rand.Reader, priv, hash[:])
+	assert.NoError(err)
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	circuit := verifyCircuit[emulated.P256Fp, emulated.P256Fr]{
+		params: weierstrass.GetP256Params(),
+		Sig:    NewSignature[emulated.P256Fr](),
+		Msg:    emulated.NewElement[emulated.P256Fr](nil),
+		Pub:    NewPublicKey[emulated.P256Fp, emulated.P256Fr](),
+	}
+	var witness verifyCircuit[emulated.P256Fp, emulated.P256Fr]
+	witness.Sig.Assign(sig)
+	witness.Pub.Assign(weierstrass.GetP256Params(), elliptic.MarshalCompressed(elliptic.P256(), priv.X, priv.Y))
+	witness.Msg = emulated.NewElement[emulated.P256Fr](new(big.Int).SetBytes(hash[:]))
+	assert.NoError(test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN))
+
+	// wrong signature
+	witness.Sig.S = emulated.NewElement[emulated.P256Fr](new(big.Int).Add(s, big.NewInt(1)))
+	assert.Error(test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN))
+}
+
+type recoverCircuit[B, S emulated.FieldParams] struct {
+	params  weierstrass.CurveParams
+	Sig     Signature[S]
+	V       frontend.Variable
+	Msg     emulated.Element[S]
+	Address frontend.Variable `gnark:",public"`
+}
+
+func (c *recoverCircuit[B, S]) Define(api frontend.API) error {
+	curve, err := weierstrass.New[B, S](api, c.params)
+	if err != nil {
+		return err
+	}
+	pk := RecoverPublicKey(curve, &c.Sig, c.V, &c.Msg)
+	api.AssertIsEqual(Address(curve, pk), c.Address)
+	return nil
+}
+
+func TestRecoverAddress(t *testing.T) {
+	assert := test.NewAssert(t)
+	c := newSecp256k1()
+
+	// known private key and address pair
+	priv, _ := new(big.Int).SetString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318", 16)
+	addr, _ := hex.DecodeString("2c7536e3605d9c16a7a3d7b1898e529396a65c23")
+	assert.Equal(addr, keccak256(c.publicKeyBytes(priv))[12:])
+
+	hash := keccak256([]byte("recover the signer"))
+	r, s, v := c.sign(priv, hash)
+	sig := make([]byte, 65)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = byte(v) + 27
+
+	circuit := recoverCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+		params: weierstrass.GetSecp256k1Params(),
+		Sig:    NewSignature[emulated.Secp256k1Fr](),
+		Msg:    emulated.NewElement[emulated.Secp256k1Fr](nil),
+	}
+	var witness recoverCircuit[emulated.Secp256k1Fp, emulated.Secp256k1Fr]
+	witness.Sig.Assign(sig)
+	witness.V = sig[64] - 27
+	witness.Msg = emulated.NewElement[emulated.Secp256k1Fr](new(big.Int).SetBytes(hash))
+	witness.Address = new(big.Int).SetBytes(addr)
+	assert.NoError(test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN))
+
+	// wrong recovery id recovers another key
+	witness.V = 1 - v
+	assert.Error(test.IsSolved(&circuit, &witness, testCurve, backend.UNKNOWN))
+}
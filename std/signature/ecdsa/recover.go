@@ -0,0 +1,131 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecdsa
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/weierstrass"
+	"github.com/consensys/gnark/std/hash/sha3"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	for _, h := range GetHints() {
+		hint.Register(h)
+	}
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []hint.Function {
+	return []hint.Function{
+		RecoverYHint,
+	}
+}
+
+// RecoverYHint computes the y-coordinate of a point given its x-coordinate
+// and the parity of y. The emulated inputs are the coefficients a and b of the
+// curve equation, x and the parity. It is called with
+// [emulated.Field.NewHint].
+func RecoverYHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(p *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 4 {
+			return errors.New("expecting a, b, x and parity as inputs")
+		}
+		if len(outputs) != 1 {
+			return errors.New("expecting one output")
+		}
+		params := weierstrass.CurveParams{A: inputs[0], B: inputs[1]}
+		y := computeY(params, p, inputs[2], inputs[3].Bit(0))
+		if y == nil {
+			return errors.New("no point with the given x-coordinate")
+		}
+		outputs[0].Set(y)
+		return nil
+	})
+}
+
+// RecoverPublicKey returns the public key which signed the message hash msg
+// with the signature sig. The recovery id v is the parity of the
+// y-coordinate of the ephemeral point R and is asserted to be boolean. For
+// Ethereum signatures v is the last byte of the signature minus 27.
+//
+// The case where the x-coordinate of R is larger than the order of the curve
+// (recovery ids 2 and 3) happens with negligible probability and is not
+// supported.
+func RecoverPublicKey[Base, Scalar emulated.FieldParams](curve *weierstrass.Curve[Base, Scalar], sig *Signature[Scalar], v frontend.Variable, msg *emulated.Element[Scalar]) *PublicKey[Base, Scalar] {
+	api := curve.API()
+	fp := curve.BaseField()
+	fr := curve.ScalarField()
+	params := curve.Params()
+	api.AssertIsBoolean(v)
+
+	// R = (r, y) where y is the square root of r³+ar+b with parity v
+	rx := fp.FromBits(fr.ToBits(&sig.R)...)
+	ry := fp.NewHint(RecoverYHint, 1, fp.NewElement(params.A), fp.NewElement(params.B), rx, fp.FromBits(v))[0]
+	R := &weierstrass.AffinePoint[Base]{X: *fp.Reduce(rx), Y: *ry}
+	curve.AssertIsOnCurve(R)
+	yBits := fp.ToBits(ry)
+	api.AssertIsEqual(yBits[0], v)
+
+	// PK = [-msg/r]G + [s/r]R, the inverse exists only if r is non-zero
+	rInv := fr.Inverse(&sig.R)
+	u1 := fr.Neg(fr.Mul(msg, rInv))
+	u2 := fr.Mul(&sig.S, rInv)
+	p1 := curve.ScalarMulBase(u1)
+	p2 := curve.ScalarMul(R, u2)
+	pk := addDistinct(curve, p1, p2)
+	return (*PublicKey[Base, Scalar])(pk)
+}
+
+// Address returns the Ethereum address of the public key pubKey: the last 20
+// bytes of the Keccak-256 digest of the big-endian encoded coordinates X || Y.
+// The address is returned as a single variable holding the 160-bit big-endian
+// integer.
+func Address[Base, Scalar emulated.FieldParams](curve *weierstrass.Curve[Base, Scalar], pubKey *PublicKey[Base, Scalar]) frontend.Variable {
+	api := curve.API()
+	fp := curve.BaseField()
+	var fpParams Base
+	size := (fpParams.Modulus().BitLen() + 7) / 8
+
+	msg := make([]frontend.Variable, 0, 16*size)
+	for _, c := range []*emulated.Element[Base]{&pubKey.X, &pubKey.Y} {
+		cBits := fp.ToBits(c)
+		for len(cBits) < 8*size {
+			cBits = append(cBits, 0)
+		}
+		// big-endian bytes with little-endian bits
+		for i := size - 1; i >= 0; i-- {
+			msg = append(msg, cBits[8*i:8*i+8]...)
+		}
+	}
+	digest := sha3.Keccak256Bits(api, msg)
+
+	// the digest bytes 12..31 as big-endian integer
+	addrBits := make([]frontend.Variable, 160)
+	for i := 12; i < 32; i++ {
+		for j := 0; j < 8; j++ {
+			addrBits[8*(31-i)+j] = digest[8*i+j]
+		}
+	}
+	return bits.FromBinary(api, addrBits)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ed25519 provides a ZKP-circuit function to verify Ed25519
+// signatures, as specified in RFC 8032.
+//
+// Unlike package [github.com/consensys/gnark/std/signature/eddsa], which
+// works with the twisted Edwards curves defined over the scalar field of the
+// SNARK curve, the arithmetic modulo 2^255-19 is emulated (see package
+// [github.com/consensys/gnark/std/math/emulated]). This allows to verify the
+// signatures of existing systems, for example Cosmos or Solana validators,
+// in any circuit, albeit at a much larger cost.
+//
+// The public keys, signatures and messages are given as bytes, as produced by
+// the standard library package crypto/ed25519. The encodings of the points
+// and of the scalar S must be canonical and the challenge is computed with
+// SHA-512. The verification equation is the cofactored one:
+//
+//	[8][S]B = [8]R + [8][k]A
+package ed25519
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/bits"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+const (
+	// PublicKeySize is the size of an encoded public key.
+	PublicKeySize = 32
+	// SignatureSize is the size of an encoded signature.
+	SignatureSize = 64
+)
+
+// PublicKey stores an Ed25519 public key (to be used in gnark circuit), given
+// by the 32 bytes of the encoding of the point A.
+type PublicKey struct {
+	A [PublicKeySize]frontend.Variable
+}
+
+// Signature stores an Ed25519 signature (to be used in gnark circuit), given
+// by the 32 bytes of the encoding of the point R and the 32 bytes of the
+// little-endian scalar S.
+type Signature struct {
+	R [32]frontend.Variable
+	S [32]frontend.Variable
+}
+
+// Assign is a helper to assign the encoded public key buf.
+func (pk *PublicKey) Assign(buf []byte) {
+	if len(buf) != PublicKeySize {
+		panic(fmt.Sprintf("public key must be %d bytes", PublicKeySize))
+	}
+	for i := range pk.A {
+		pk.A[i] = buf[i]
+	}
+}
+
+// Assign is a helper to assign the encoded signature buf.
+func (s *Signature) Assign(buf []byte) {
+	if len(buf) != SignatureSize {
+		panic(fmt.Sprintf("signature must be %d bytes", SignatureSize))
+	}
+	for i := range s.R {
+		s.R[i] = buf[i]
+		s.S[i] = buf[32+i]
+	}
+}
+
+// twoTo256 is 2^256 modulo the order L of the prime subgroup.
+var twoTo256 = new(big.Int).Mod(new(big.Int).Lsh(big.NewInt(1), 256), emulated.Ed25519Fr{}.Modulus())
+
+// Verify verifies the Ed25519 signature sig of the message msg by the public
+// key pubKey. The message is given as bytes.
+func Verify(api frontend.API, sig Signature, msg []frontend.Variable, pubKey PublicKey) error {
+	c, err := newCurve(api)
+	if err != nil {
+		return err
+	}
+	fr, err := emulated.NewField[emulated.Ed25519Fr](api)
+	if err != nil {
+		return fmt.Errorf("new scalar field: %w", err)
+	}
+
+	A := c.decompress(pubKey.A[:])
+	R := c.decompress(sig.R[:])
+
+	// S < L
+	sBits := bytesToBits(api, sig.S[:])
+	s := fr.FromBits(sBits...)
+	sCanonical := fr.ToBits(s)
+	for i := range sBits {
+		if i < len(sCanonical) {
+			api.AssertIsEqual(sBits[i], sCanonical[i])
+		} else {
+			api.AssertIsEqual(sBits[i], 0)
+		}
+	}
+
+	// k = SHA-512(R || A || M) mod L
+	data := make([]frontend.Variable, 0, 64+len(msg))
+	data = append(data, sig.R[:]...)
+	data = append(data, pubKey.A[:]...)
+	data = append(data, msg...)
+	h := bytesToBits(api, sha2.Sum512(api, data))
+	lo := fr.FromBits(h[:256]...)
+	hi := fr.FromBits(h[256:]...)
+	k := fr.Add(lo, fr.MulConst(hi, twoTo256))
+	kBits := fr.ToBits(k)
+
+	// [S]B - [k]A - R, computed with a joint double-and-add
+	negA := c.neg(A)
+	table := [4]point{c.identity(), c.base(), negA, c.add(c.base(), negA)}
+	acc := c.identity()
+	for i := len(kBits) - 1; i >= 0; i-- {
+		acc = c.double(acc)
+		acc = c.add(acc, c.lookup2(sCanonical[i], kBits[i], table))
+	}
+	acc = c.add(acc, c.neg(R))
+
+	// clear the cofactor
+	for i := 0; i < 3; i++ {
+		acc = c.double(acc)
+	}
+	c.assertIsIdentity(acc)
+	return nil
+}
+
+// bytesToBits returns the little-endian bits of the little-endian bytes. The
+// bytes are asserted to fit in 8 bits.
+func bytesToBits(api frontend.API, b []frontend.Variable) []frontend.Variable {
+	res := make([]frontend.Variable, 0, 8*len(b))
+	for i := range b {
+		res = append(res, bits.ToBinary(api, b[i], bits.WithNbDigits(8))...)
+	}
+	return res
+}
@@ -0,0 +1,185 @@
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type verifyCircuit struct {
+	PublicKey PublicKey
+	Signature Signature
+	Msg       []frontend.Variable
+}
+
+func (c *verifyCircuit) Define(api frontend.API) error {
+	return Verify(api, c.Signature, c.Msg, c.PublicKey)
+}
+
+// rfc8032 are the test vectors of RFC 8032, section 7.1.
+var rfc8032 = []struct {
+	name, publicKey, msg, sig string
+}{
+	{
+		"TEST 1",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"",
+		"e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+	},
+	{
+		"TEST 2",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"72",
+		"92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00",
+	},
+	{
+		"TEST 3",
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		"af82",
+		"6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a",
+	},
+	{
+		"TEST SHA(abc)",
+		"ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+		"ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		"dc2a4459e7369633a52b1bf277839a00201009a3efbf3ecb69bea2186c26b58909351fc9ac90b3ecfdfbc7c66431e0303dca179c138ac17ad9bef1177331a704",
+	},
+}
+
+func decode(assert *test.Assert, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.NoError(err)
+	return b
+}
+
+func verifyWitness(publicKey, msg, sig []byte) (*verifyCircuit, *verifyCircuit) {
+	circuit := verifyCircuit{Msg: make([]frontend.Variable, len(msg))}
+	witness := verifyCircuit{Msg: make([]frontend.Variable, len(msg))}
+	witness.PublicKey.Assign(publicKey)
+	witness.Signature.Assign(sig)
+	for i := range msg {
+		witness.Msg[i] = msg[i]
+	}
+	return &circuit, &witness
+}
+
+func TestVerifyRFC8032(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, v := range rfc8032 {
+		publicKey, msg, sig := decode(assert, v.publicKey), decode(assert, v.msg), decode(assert, v.sig)
+		assert.True(ed25519.Verify(publicKey, msg, sig))
+		assert.Run(func(assert *test.Assert) {
+			circuit, witness := verifyWitness(publicKey, msg, sig)
+			err := test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+			assert.NoError(err)
+		}, v.name)
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	assert := test.NewAssert(t)
+	v := rfc8032[2]
+	publicKey, msg, sig := decode(assert, v.publicKey), decode(assert, v.msg), decode(assert, v.sig)
+
+	// wrong message
+	circuit, witness := verifyWitness(publicKey, []byte{0xaf, 0x83}, sig)
+	err := test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+
+	// negated public key
+	negated := append([]byte{}, publicKey...)
+	negated[31] ^= 0x80
+	circuit, witness = verifyWitness(negated, msg, sig)
+	err = test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+
+	// non-canonical S+L, accepted by the equation but not by RFC 8032
+	s := new(big.Int).SetBytes(reverse(sig[32:]))
+	s.Add(s, emulated.Ed25519Fr{}.Modulus())
+	malleable := append(append([]byte{}, sig[:32]...), reverse(s.FillBytes(make([]byte, 32)))...)
+	assert.False(ed25519.Verify(publicKey, msg, malleable))
+	circuit, witness = verifyWitness(publicKey, msg, malleable)
+	err = test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+
+	// non-canonical y = p+1 of the identity
+	identity := make([]byte, 32)
+	p := emulated.Ed25519Fp{}.Modulus()
+	new(big.Int).Add(p, big.NewInt(1)).FillBytes(identity)
+	circuit, witness = verifyWitness(reverse(identity), msg, sig)
+	err = test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
+
+func TestVerifyCofactored(t *testing.T) {
+	assert := test.NewAssert(t)
+	p := emulated.Ed25519Fp{}.Modulus()
+	l := emulated.Ed25519Fr{}.Modulus()
+	msg := []byte("cofactored")
+
+	// R = [r]B + T where T = (sqrt(-1), 0) has order 4, and S = r + k a
+	a, r := big.NewInt(123456789), big.NewInt(987654321)
+	publicKey := encodePoint(p, scalarMul(p, [2]*big.Int{baseX, baseY}, a))
+	sqrtMinusOne := new(big.Int).ModSqrt(new(big.Int).Sub(p, big.NewInt(1)), p)
+	R := addPoints(p, scalarMul(p, [2]*big.Int{baseX, baseY}, r), [2]*big.Int{sqrtMinusOne, big.NewInt(0)})
+	h := sha512.New()
+	h.Write(encodePoint(p, R))
+	h.Write(publicKey)
+	h.Write(msg)
+	k := new(big.Int).SetBytes(reverse(h.Sum(nil)))
+	s := new(big.Int).Mul(k, a)
+	s.Add(s, r).Mod(s, l)
+	sig := append(encodePoint(p, R), reverse(s.FillBytes(make([]byte, 32)))...)
+
+	// rejected by the cofactorless verification of the standard library
+	assert.False(ed25519.Verify(publicKey, msg, sig))
+	circuit, witness := verifyWitness(publicKey, msg, sig)
+	err := test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+	assert.NoError(err)
+}
+
+func addPoints(p *big.Int, p1, p2 [2]*big.Int) [2]*big.Int {
+	x1x2 := new(big.Int).Mul(p1[0], p2[0])
+	y1y2 := new(big.Int).Mul(p1[1], p2[1])
+	t := new(big.Int).Mul(d, x1x2)
+	t.Mul(t, y1y2).Mod(t, p)
+	x := new(big.Int).Mul(p1[0], p2[1])
+	x.Add(x, new(big.Int).Mul(p1[1], p2[0]))
+	x.Mul(x, new(big.Int).ModInverse(new(big.Int).Add(t, big.NewInt(1)), p)).Mod(x, p)
+	y := new(big.Int).Add(y1y2, x1x2)
+	y.Mul(y, new(big.Int).ModInverse(new(big.Int).Sub(big.NewInt(1), t), p)).Mod(y, p)
+	return [2]*big.Int{x, y}
+}
+
+func scalarMul(p *big.Int, q [2]*big.Int, s *big.Int) [2]*big.Int {
+	res := [2]*big.Int{big.NewInt(0), big.NewInt(1)}
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res = addPoints(p, res, res)
+		if s.Bit(i) == 1 {
+			res = addPoints(p, res, q)
+		}
+	}
+	return res
+}
+
+func encodePoint(p *big.Int, q [2]*big.Int) []byte {
+	res := reverse(new(big.Int).Mod(q[1], p).FillBytes(make([]byte, 32)))
+	res[31] |= byte(q[0].Bit(0)) << 7
+	return res
+}
+
+func reverse(b []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[len(b)-1-i] = b[i]
+	}
+	return res
+}
@@ -0,0 +1,60 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ed25519
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	for _, h := range GetHints() {
+		hint.Register(h)
+	}
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []hint.Function {
+	return []hint.Function{
+		RecoverXHint,
+	}
+}
+
+// RecoverXHint computes the even x-coordinate of the point of Ed25519 with
+// the given y-coordinate. The input is the emulated element y. It is called
+// with [emulated.Field.NewHint].
+func RecoverXHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	return emulated.UnwrapHint(inputs, outputs, func(p *big.Int, inputs, outputs []*big.Int) error {
+		if len(inputs) != 1 || len(outputs) != 1 {
+			return errors.New("expecting one input and one output")
+		}
+		y := new(big.Int).Mod(inputs[0], p)
+		x := recoverX(p, y)
+		if x == nil {
+			return errors.New("no point with the y-coordinate")
+		}
+		if x.Bit(0) == 1 {
+			x.Sub(p, x)
+		}
+		outputs[0].Set(x)
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ed25519
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+type fp = emulated.Element[emulated.Ed25519Fp]
+
+// d is the parameter of the curve -x^2 + y^2 = 1 + d x^2 y^2 and (baseX,
+// baseY) its base point.
+var d, baseX, baseY *big.Int
+
+func init() {
+	p := emulated.Ed25519Fp{}.Modulus()
+	// d = -121665/121666
+	d = new(big.Int).ModInverse(big.NewInt(121666), p)
+	d.Mul(d, big.NewInt(-121665)).Mod(d, p)
+	// y = 4/5 and x is even
+	baseY = new(big.Int).ModInverse(big.NewInt(5), p)
+	baseY.Mul(baseY, big.NewInt(4)).Mod(baseY, p)
+	baseX = recoverX(p, baseY)
+	if baseX.Bit(0) == 1 {
+		baseX.Sub(p, baseX)
+	}
+}
+
+// recoverX returns a square root of (y^2-1)/(d y^2+1) modulo p, or nil if it
+// does not exist.
+func recoverX(p, y *big.Int) *big.Int {
+	y2 := new(big.Int).Mul(y, y)
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	v := new(big.Int).Mul(d, y2)
+	v.Add(v, big.NewInt(1))
+	if v.ModInverse(v, p) == nil {
+		return nil
+	}
+	u.Mul(u, v).Mod(u, p)
+	return new(big.Int).ModSqrt(u, p)
+}
+
+// point is a point of Ed25519 in affine coordinates.
+type point struct {
+	X, Y *fp
+}
+
+// curve implements the arithmetic of Ed25519 over the emulated base field.
+// As the curve is complete, the addition has no exceptional cases.
+type curve struct {
+	api frontend.API
+	fp  *emulated.Field[emulated.Ed25519Fp]
+	d   *fp
+}
+
+func newCurve(api frontend.API) (*curve, error) {
+	f, err := emulated.NewField[emulated.Ed25519Fp](api)
+	if err != nil {
+		return nil, fmt.Errorf("new base field: %w", err)
+	}
+	return &curve{api: api, fp: f, d: f.NewElement(d)}, nil
+}
+
+func (c *curve) identity() point {
+	return point{X: c.fp.Zero(), Y: c.fp.One()}
+}
+
+func (c *curve) base() point {
+	return point{X: c.fp.NewElement(baseX), Y: c.fp.NewElement(baseY)}
+}
+
+func (c *curve) neg(p point) point {
+	return point{X: c.fp.Neg(p.X), Y: p.Y}
+}
+
+// add returns p+q:
+//
+//	x = (x1 y2 + y1 x2) / (1 + d x1 x2 y1 y2)
+//	y = (y1 y2 + x1 x2) / (1 - d x1 x2 y1 y2)
+func (c *curve) add(p, q point) point {
+	x1y2 := c.fp.Mul(p.X, q.Y)
+	y1x2 := c.fp.Mul(p.Y, q.X)
+	x1x2 := c.fp.Mul(p.X, q.X)
+	y1y2 := c.fp.Mul(p.Y, q.Y)
+	t := c.fp.Mul(c.d, c.fp.Mul(x1x2, y1y2))
+	return point{
+		X: c.fp.Div(c.fp.Add(x1y2, y1x2), c.fp.Add(c.fp.One(), t)),
+		Y: c.fp.Div(c.fp.Add(y1y2, x1x2), c.fp.Sub(c.fp.One(), t)),
+	}
+}
+
+// double returns 2p, using the curve equation to simplify the denominators:
+//
+//	x = 2 x y / (y^2 - x^2)
+//	y = (y^2 + x^2) / (2 - y^2 + x^2)
+func (c *curve) double(p point) point {
+	xx := c.fp.Mul(p.X, p.X)
+	yy := c.fp.Mul(p.Y, p.Y)
+	xy := c.fp.Mul(p.X, p.Y)
+	return point{
+		X: c.fp.Div(c.fp.Add(xy, xy), c.fp.Sub(yy, xx)),
+		Y: c.fp.Div(c.fp.Add(yy, xx), c.fp.Sub(c.fp.NewElement(2), c.fp.Sub(yy, xx))),
+	}
+}
+
+// lookup2 returns table[b0 + 2 b1].
+func (c *curve) lookup2(b0, b1 frontend.Variable, table [4]point) point {
+	return point{
+		X: c.fp.Lookup2(b0, b1, table[0].X, table[1].X, table[2].X, table[3].X),
+		Y: c.fp.Lookup2(b0, b1, table[0].Y, table[1].Y, table[2].Y, table[3].Y),
+	}
+}
+
+func (c *curve) assertIsIdentity(p point) {
+	c.fp.AssertIsEqual(p.X, c.fp.Zero())
+	c.fp.AssertIsEqual(p.Y, c.fp.One())
+}
+
+// decompress returns the point encoded by the 32 bytes b: the little-endian
+// y-coordinate on 255 bits and the parity of the x-coordinate in the top bit.
+// It asserts that the encoding is canonical and that the point is on the
+// curve.
+func (c *curve) decompress(b []frontend.Variable) point {
+	bs := bytesToBits(c.api, b)
+	sign := bs[255]
+	y := c.fp.FromBits(bs[:255]...)
+	// y < p
+	yBits := c.fp.ToBits(y)
+	for i := range yBits {
+		c.api.AssertIsEqual(yBits[i], bs[i])
+	}
+
+	// x^2 (d y^2 + 1) = y^2 - 1
+	yy := c.fp.Mul(y, y)
+	u := c.fp.Sub(yy, c.fp.One())
+	v := c.fp.Add(c.fp.Mul(c.d, yy), c.fp.One())
+	x := c.fp.NewHint(RecoverXHint, 1, y)[0]
+	c.fp.AssertIsEqual(c.fp.Mul(c.fp.Mul(x, x), v), u)
+	x = c.fp.Select(sign, c.fp.Neg(x), x)
+	// the parity is the sign, so that x = 0 is not encoded with the sign 1
+	c.api.AssertIsEqual(c.fp.ToBits(x)[0], sign)
+	return point{X: x, Y: y}
+}
@@ -0,0 +1,538 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eddsa
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/hint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+func init() {
+	for _, h := range GetHints() {
+		hint.Register(h)
+	}
+}
+
+// GetHints returns all hint functions used in the package.
+func GetHints() []hint.Function {
+	return []hint.Function{
+		ReduceHint,
+		DecomposeHint,
+	}
+}
+
+// BatchVerify verifies the eddsa signatures sigs of the messages msgs by the
+// public keys pubKeys, so that sigs[i] is the signature of msgs[i] by
+// pubKeys[i]. It is equivalent to calling [Verify] for each signature, but is
+// cheaper.
+//
+// The verification equations are combined with random odd coefficients c_i
+// derived from a transcript of the challenges H(R_i,A_i,M_i) and the scalars
+// S_i of the signatures:
+//
+//	[cofactor]([Σ c_i S_i]G - Σ [c_i]R_i - Σ [c_i H(R_i,A_i,M_i)]A_i) = 0
+//
+// The left hand side is computed with a single multi-scalar multiplication,
+// so that the doublings are shared by all the signatures and two points are
+// added at a time. The scalars of G and A_i are reduced modulo the order of
+// the subgroup. The coefficients are of 119 bits on the curves with a 254-bit
+// scalar field, so that a batch with an invalid signature passes with
+// probability about 2^-118.
+func BatchVerify(curve twistededwards.Curve, sigs []Signature, msgs []frontend.Variable, pubKeys []PublicKey, hash hash.Hash) error {
+	if len(sigs) == 0 {
+		return errors.New("no signature")
+	}
+	if len(sigs) != len(msgs) || len(sigs) != len(pubKeys) {
+		return errors.New("number of signatures, messages and public keys mismatch")
+	}
+	api := curve.API()
+	params := curve.Params()
+	if !params.Cofactor.IsUint64() {
+		return fmt.Errorf("invalid cofactor %s", params.Cofactor.String())
+	}
+	nbBits := api.Compiler().Curve().Info().Fr.Bits
+	// the coefficients c = 2z+1 are small enough for the reduction of c*H
+	// with a single limb, see reduce
+	nbZBits := (nbBits-5)/2 - 6
+
+	// challenges H(R, A, M)
+	challenges := make([]frontend.Variable, len(sigs))
+	for i := range sigs {
+		curve.AssertIsOnCurve(sigs[i].R)
+		curve.AssertIsOnCurve(pubKeys[i].A)
+		hash.Reset()
+		hash.Write(sigs[i].R.X, sigs[i].R.Y, pubKeys[i].A.X, pubKeys[i].A.Y, msgs[i])
+		challenges[i] = hash.Sum()
+	}
+
+	// the coefficients are taken from the bits of the powers of the hash of
+	// the challenges and the scalars, two per power. The decomposition of the
+	// powers is unique, otherwise the prover could pick the coefficients
+	// among the bits of ρ^k and of ρ^k+r.
+	hash.Reset()
+	for i := range sigs {
+		hash.Write(challenges[i], sigs[i].S)
+	}
+	rho := hash.Sum()
+	hash.Reset()
+	zs := make([][]frontend.Variable, len(sigs))
+	coefficients := make([]frontend.Variable, len(sigs))
+	power := rho
+	for i := 0; i < len(sigs); i += 2 {
+		if i > 0 {
+			power = api.Mul(power, rho)
+		}
+		z, zBits := decompose(api, power, true, nbZBits, nbZBits, nbBits-2*nbZBits)
+		for k := 0; k < 2 && i+k < len(sigs); k++ {
+			zs[i+k] = zBits[k]
+			coefficients[i+k] = api.Add(api.Mul(z[k], 2), 1)
+		}
+	}
+
+	points := make([]twistededwards.Point, 0, 2*len(sigs)+1)
+	scalars := make([][]frontend.Variable, 0, 2*len(sigs)+1)
+	S := make([]frontend.Variable, len(sigs))
+	for i := range sigs {
+		S[i] = sigs[i].S
+
+		// [c](-R)
+		points = append(points, curve.Neg(sigs[i].R))
+		scalars = append(scalars, zs[i])
+
+		// [c H(R,A,M) mod l](-A)
+		points = append(points, curve.Neg(pubKeys[i].A))
+		scalars = append(scalars, reduce(api, coefficients[i:i+1], nbZBits+1, challenges[i:i+1], params.Order))
+	}
+
+	// [Σ c S mod l]G
+	points = append(points, twistededwards.Point{X: params.Base[0], Y: params.Base[1]})
+	scalars = append(scalars, reduce(api, coefficients, nbZBits+1, S, params.Order))
+
+	Q := msm(curve, points, scalars)
+	for c := params.Cofactor.Uint64(); c > 1; c >>= 1 {
+		Q = curve.Double(Q)
+	}
+	api.AssertIsEqual(Q.X, 0)
+	api.AssertIsEqual(Q.Y, 1)
+	return nil
+}
+
+// msm returns Σ [2m_i+1]P_i where the scalars are given by the bits of m_i
+// in little-endian order. The odd scalars are written with the signed digits
+// ±1, the digit j being 2b_j-1 for the bit b_j of m_i and the top digit being
+// 1. The doublings are shared and the points with scalars of the same length
+// are added by pairs: the sum of the digits times the points is ±(P+Q) or
+// ±(P-Q), a polynomial of degree one in each bit with precomputed
+// coefficients.
+func msm(curve twistededwards.Curve, points []twistededwards.Point, scalars [][]frontend.Variable) twistededwards.Point {
+	api := curve.API()
+	type entry struct {
+		a, b   int
+		nbBits int
+		single bool
+		// the coordinates of the sum of the digits times the points are
+		// x0 + b0*xa + b1*xb and y0 + (b0 xor b1)*dy
+		x0, xa, xb, y0, dy frontend.Variable
+	}
+	// pair the points with the scalars of the same length
+	order := make([]int, len(points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(scalars[order[i]]) > len(scalars[order[j]])
+	})
+	entries := make([]entry, 0, (len(points)+1)/2)
+	nbBits := 0
+	for i := 0; i < len(order); {
+		e := entry{a: order[i], nbBits: len(scalars[order[i]])}
+		if e.nbBits > nbBits {
+			nbBits = e.nbBits
+		}
+		if i+1 < len(order) && len(scalars[order[i+1]]) == e.nbBits {
+			e.b = order[i+1]
+			plus := curve.Add(points[e.a], points[e.b])
+			minus := curve.Add(points[e.a], curve.Neg(points[e.b]))
+			e.x0 = api.Neg(plus.X)
+			e.xa = api.Add(plus.X, minus.X)
+			e.xb = api.Sub(plus.X, minus.X)
+			e.y0 = plus.Y
+			e.dy = api.Sub(minus.Y, plus.Y)
+			i += 2
+		} else {
+			e.single = true
+			i++
+		}
+		entries = append(entries, e)
+	}
+	// the digit is 2b-1, or 1 for the top digit
+	digit := func(i, j int) frontend.Variable {
+		if j == len(scalars[i]) {
+			return 1
+		}
+		return scalars[i][j]
+	}
+
+	var acc twistededwards.Point
+	started := false
+	for j := nbBits; j >= 0; j-- {
+		if started {
+			acc = curve.Double(acc)
+		}
+		for _, e := range entries {
+			if j > e.nbBits {
+				continue
+			}
+			var t twistededwards.Point
+			b0 := digit(e.a, j)
+			if e.single {
+				t.X = api.Mul(points[e.a].X, api.Sub(api.Mul(b0, 2), 1))
+				t.Y = points[e.a].Y
+			} else {
+				// b0 = b1: ±(P+Q), b0 != b1: ±(P-Q), with the sign of b0
+				b1 := digit(e.b, j)
+				t.X = api.Add(e.x0, api.Mul(b0, e.xa), api.Mul(b1, e.xb))
+				t.Y = api.Add(e.y0, api.Mul(api.Xor(b0, b1), e.dy))
+			}
+			if started {
+				acc = curve.Add(acc, t)
+			} else {
+				acc = t
+				started = true
+			}
+		}
+	}
+	return acc
+}
+
+// reduce returns the bits of m such that 2m+1 is congruent to x = Σ a_i b_i
+// modulo the constant odd order and 2m+1 < 2*order. The a_i are of at most
+// aBits bits.
+//
+// The limbs of the quotient q and of m are computed in a hint. The equality
+//
+//	x = q*order + 2m+1
+//
+// is checked modulo the native modulus and modulo 2^(w*L) for the limb width
+// w and a number of limbs L such that the product of the moduli is larger
+// than the terms, so that the equality holds over the integers. The equality
+// modulo 2^(w*L) is checked limb by limb with the carries computed in the
+// hint, so that no limb equation wraps around the native modulus.
+//
+// The limbs of b_i are not asserted to be canonical, so that they may stand
+// for b_i+r instead of b_i. The result is then the reduction of another
+// representative of b_i, which gives the prover no freedom that matters: any
+// residue modulo the order is already reached by a canonical scalar S, and a
+// challenge H can only be replaced by H+r, as in [Verify] where the scalar
+// multiplication by H decomposes it without the check either.
+func reduce(api frontend.API, a []frontend.Variable, aBits int, b []frontend.Variable, order *big.Int) []frontend.Variable {
+	nbBits := api.Compiler().Curve().Info().Fr.Bits
+	nbTermBits := bitLen(len(a))
+	// x < 2^xBits and the equation is over integers less than 2^(xBits+3)
+	xBits := aBits + nbBits + nbTermBits
+	// the limb equations have terms less than 2^(aBits+w+nbTermBits) and
+	// 2^(2w+bitLen(L)), which must be less than the native modulus
+	w := intMin((nbBits-5)/2, nbBits-6-aBits-nbTermBits)
+	nbLimbs := (xBits + 4 - (nbBits - 1) + w - 1) / w
+	termBits := intMax(aBits+w+nbTermBits, 2*w+bitLen(nbLimbs)) + 1
+	nbCarryBits := termBits - w + 1
+	if w <= 0 || termBits+2 > nbBits-1 {
+		panic("native field too small for the reduction")
+	}
+	qWidths := widths(xBits-order.BitLen()+1, w)
+	mWidths := widths(order.BitLen(), w)
+
+	inputs := []frontend.Variable{order, w, len(qWidths), len(mWidths), nbLimbs, nbCarryBits, len(a)}
+	inputs = append(inputs, a...)
+	inputs = append(inputs, b...)
+	res, err := api.Compiler().NewHint(ReduceHint, len(qWidths)+len(mWidths)+nbLimbs, inputs...)
+	if err != nil {
+		panic(err)
+	}
+	q, m, carries := res[:len(qWidths)], res[len(qWidths):len(qWidths)+len(mWidths)], res[len(qWidths)+len(mWidths):]
+	mBits := make([]frontend.Variable, 0, order.BitLen())
+	for k := range q {
+		api.ToBinary(q[k], qWidths[k])
+	}
+	for k := range m {
+		mBits = append(mBits, api.ToBinary(m[k], mWidths[k])...)
+	}
+
+	// x = q*order + 2m+1 modulo the native modulus
+	x := make([]frontend.Variable, len(a))
+	bLimbs := make([][]frontend.Variable, len(b))
+	for i := range a {
+		x[i] = api.Mul(a[i], b[i])
+		bLimbs[i], _ = decompose(api, b[i], false, widths(nbBits, w)...)
+	}
+	api.AssertIsEqual(sum(api, x), api.Add(api.Mul(recompose(api, q, w), order), api.Mul(recompose(api, m, w), 2), 1))
+
+	// x = q*order + 2m+1 modulo 2^(w*nbLimbs)
+	orderLimbs := bigLimbs(order, w)
+	shift := new(big.Int).Lsh(big.NewInt(1), uint(w))
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(nbCarryBits))
+	var carry frontend.Variable = 0
+	for j := 0; j < nbLimbs; j++ {
+		terms := make([]frontend.Variable, 0, len(a)+len(q)+3)
+		for i := range a {
+			if j < len(bLimbs[i]) {
+				terms = append(terms, api.Mul(a[i], bLimbs[i][j]))
+			}
+		}
+		for k := range q {
+			if l := j - k; l >= 0 && l < len(orderLimbs) {
+				terms = append(terms, api.Mul(q[k], api.Neg(orderLimbs[l])))
+			}
+		}
+		if j < len(m) {
+			terms = append(terms, api.Mul(m[j], -2))
+		}
+		if j == 0 {
+			terms = append(terms, -1)
+		}
+		terms = append(terms, carry)
+		// the carry is range checked with an offset to be non-negative
+		api.ToBinary(carries[j], nbCarryBits+1)
+		carry = api.Sub(carries[j], offset)
+		api.AssertIsEqual(sum(api, terms), api.Mul(carry, shift))
+	}
+	return mBits
+}
+
+// ReduceHint computes the limbs of the quotient q and of m such that Σ a_i
+// b_i = q*order + 2m+1 with 2m+1 < 2*order, and the carries of the limb by
+// limb check of the equality. The inputs are the order, the width of the
+// limbs, the numbers of limbs of q and m, the number of limbs of the check,
+// the number of bits of the carries, the number n of terms, the a_i and the
+// b_i. The carries are offset to be non-negative.
+func ReduceHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) < 7 {
+		return errors.New("expecting at least seven inputs")
+	}
+	order := inputs[0]
+	w := int(inputs[1].Int64())
+	nbQLimbs := int(inputs[2].Int64())
+	nbMLimbs := int(inputs[3].Int64())
+	nbLimbs := int(inputs[4].Int64())
+	nbCarryBits := uint(inputs[5].Uint64())
+	n := int(inputs[6].Int64())
+	if len(inputs) != 7+2*n || len(outputs) != nbQLimbs+nbMLimbs+nbLimbs {
+		return errors.New("unexpected number of inputs or outputs")
+	}
+	a, b := inputs[7:7+n], inputs[7+n:]
+
+	x := new(big.Int)
+	for i := range a {
+		x.Add(x, new(big.Int).Mul(a[i], b[i]))
+	}
+	q, t := new(big.Int).QuoRem(x, order, new(big.Int))
+	if t.Bit(0) == 0 {
+		// the representative must be odd
+		t.Add(t, order)
+		q.Sub(q, big.NewInt(1))
+	}
+	if q.Sign() < 0 {
+		return errors.New("no odd representative")
+	}
+	m := new(big.Int).Rsh(t, 1)
+	qLimbs, mLimbs, orderLimbs := bigLimbs(q, w), bigLimbs(m, w), bigLimbs(order, w)
+	if len(qLimbs) > nbQLimbs || len(mLimbs) > nbMLimbs {
+		return errors.New("quotient or remainder too large")
+	}
+	for k := range qLimbs {
+		outputs[k].Set(qLimbs[k])
+	}
+	for k := range mLimbs {
+		outputs[nbQLimbs+k].Set(mLimbs[k])
+	}
+
+	offset := new(big.Int).Lsh(big.NewInt(1), nbCarryBits)
+	carry := new(big.Int)
+	for j := 0; j < nbLimbs; j++ {
+		d := new(big.Int).Set(carry)
+		for i := range a {
+			if bj := bigLimbs(b[i], w); j < len(bj) {
+				d.Add(d, new(big.Int).Mul(a[i], bj[j]))
+			}
+		}
+		for k := range qLimbs {
+			if l := j - k; l >= 0 && l < len(orderLimbs) {
+				d.Sub(d, new(big.Int).Mul(qLimbs[k], orderLimbs[l]))
+			}
+		}
+		if j < len(mLimbs) {
+			d.Sub(d, new(big.Int).Lsh(mLimbs[j], 1))
+		}
+		if j == 0 {
+			d.Sub(d, big.NewInt(1))
+		}
+		carry.Rsh(d, uint(w)) // d is a multiple of 2^w
+		outputs[nbQLimbs+nbMLimbs+j].Add(carry, offset)
+	}
+	return nil
+}
+
+// DecomposeHint computes the limbs of the first input, from the least
+// significant, with the widths given by the other inputs.
+func DecomposeHint(_ ecc.ID, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 1+len(outputs) {
+		return errors.New("expecting one input per output and the value")
+	}
+	v := new(big.Int).Set(inputs[0])
+	for i := range outputs {
+		w := uint(inputs[1+i].Uint64())
+		mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), w), big.NewInt(1))
+		outputs[i].And(v, mask)
+		v.Rsh(v, w)
+	}
+	return nil
+}
+
+// decompose returns the limbs of v of the given widths, from the least
+// significant, and their bits in little-endian order. The widths must add up
+// to at most the number of bits of the native field.
+//
+// When the widths cover the native modulus r, the limbs could represent v+r
+// as well as v. If unique is set, the bits are then asserted to be at most
+// r-1 for the decomposition to be unique.
+func decompose(api frontend.API, v frontend.Variable, unique bool, widths ...int) ([]frontend.Variable, [][]frontend.Variable) {
+	inputs := []frontend.Variable{v}
+	for _, w := range widths {
+		inputs = append(inputs, w)
+	}
+	limbs, err := api.Compiler().NewHint(DecomposeHint, len(widths), inputs...)
+	if err != nil {
+		panic(err)
+	}
+	bits := make([][]frontend.Variable, len(widths))
+	terms := make([]frontend.Variable, len(widths))
+	allBits := make([]frontend.Variable, 0, api.Compiler().Curve().Info().Fr.Bits)
+	shift := 0
+	for i, w := range widths {
+		bits[i] = api.ToBinary(limbs[i], w)
+		allBits = append(allBits, bits[i]...)
+		terms[i] = api.Mul(limbs[i], new(big.Int).Lsh(big.NewInt(1), uint(shift)))
+		shift += w
+	}
+	api.AssertIsEqual(sum(api, terms), v)
+	modulus := api.Compiler().Curve().Info().Fr.Modulus()
+	if unique && shift >= modulus.BitLen() {
+		assertBitsLessOrEqual(api, allBits, new(big.Int).Sub(modulus, big.NewInt(1)))
+	}
+	return limbs, bits
+}
+
+// assertBitsLessOrEqual asserts that the value of the boolean little-endian
+// bits is at most the constant bound.
+func assertBitsLessOrEqual(api frontend.API, bits []frontend.Variable, bound *big.Int) {
+	nbBits := len(bits)
+	if bound.BitLen() > nbBits {
+		return
+	}
+	// t trailing ones in the bound
+	t := 0
+	for t < nbBits && bound.Bit(t) == 1 {
+		t++
+	}
+	// p[i] == 1 → bits[j] == bound[j] for all j ⩾ i
+	p := make([]frontend.Variable, nbBits+1)
+	p[nbBits] = 1
+	for i := nbBits - 1; i >= t; i-- {
+		if bound.Bit(i) == 0 {
+			p[i] = p[i+1]
+		} else {
+			p[i] = api.Mul(p[i+1], bits[i])
+		}
+	}
+	for i := nbBits - 1; i >= 0; i-- {
+		if bound.Bit(i) == 0 {
+			// the bit must be 0 when the higher bits equal those of the bound
+			api.AssertIsEqual(api.Mul(api.Sub(1, p[i+1], bits[i]), bits[i]), 0)
+		}
+	}
+}
+
+// recompose returns the value of the limbs of w bits.
+func recompose(api frontend.API, limbs []frontend.Variable, w int) frontend.Variable {
+	terms := make([]frontend.Variable, len(limbs))
+	for i := range limbs {
+		terms[i] = api.Mul(limbs[i], new(big.Int).Lsh(big.NewInt(1), uint(i*w)))
+	}
+	return sum(api, terms)
+}
+
+// widths returns the widths of the limbs of w bits of a value of nbBits bits.
+func widths(nbBits, w int) []int {
+	res := make([]int, 0, (nbBits+w-1)/w)
+	for ; nbBits > 0; nbBits -= w {
+		res = append(res, intMin(nbBits, w))
+	}
+	return res
+}
+
+// bigLimbs returns the limbs of w bits of the non-negative c.
+func bigLimbs(c *big.Int, w int) []*big.Int {
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(w)), big.NewInt(1))
+	var res []*big.Int
+	for r := new(big.Int).Set(c); r.Sign() > 0; r.Rsh(r, uint(w)) {
+		res = append(res, new(big.Int).And(r, mask))
+	}
+	if len(res) == 0 {
+		res = append(res, big.NewInt(0))
+	}
+	return res
+}
+
+func sum(api frontend.API, vs []frontend.Variable) frontend.Variable {
+	switch len(vs) {
+	case 0:
+		return 0
+	case 1:
+		return vs[0]
+	}
+	return api.Add(vs[0], vs[1], vs[2:]...)
+}
+
+func bitLen(n int) int {
+	res := 0
+	for 1<<res < n {
+		res++
+	}
+	return res
+}
+
+func intMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func intMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
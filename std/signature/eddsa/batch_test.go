@@ -0,0 +1,168 @@
+package eddsa
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark-crypto/signature/eddsa"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type batchCircuit struct {
+	curveID    tedwards.ID
+	PublicKeys []PublicKey
+	Signatures []Signature
+	Messages   []frontend.Variable
+}
+
+func (circuit *batchCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return BatchVerify(curve, circuit.Signatures, circuit.Messages, circuit.PublicKeys, &mimc)
+}
+
+func newBatchCircuit(curveID tedwards.ID, n int) *batchCircuit {
+	return &batchCircuit{
+		curveID:    curveID,
+		PublicKeys: make([]PublicKey, n),
+		Signatures: make([]Signature, n),
+		Messages:   make([]frontend.Variable, n),
+	}
+}
+
+// batchWitness returns the witness of n signatures of random messages by
+// random keys.
+func batchWitness(assert *test.Assert, curveID tedwards.ID, h hash.Hash, n int) *batchCircuit {
+	snarkCurve, err := twistededwards.GetSnarkCurve(curveID)
+	assert.NoError(err)
+	randomness := rand.New(rand.NewSource(int64(n)))
+	witness := newBatchCircuit(curveID, n)
+	for i := 0; i < n; i++ {
+		privKey, err := eddsa.New(curveID, randomness)
+		assert.NoError(err)
+		var msg big.Int
+		msg.Rand(randomness, snarkCurve.Info().Fr.Modulus())
+		signature, err := privKey.Sign(msg.Bytes(), h.New())
+		assert.NoError(err)
+		witness.Messages[i] = msg
+		witness.PublicKeys[i].Assign(snarkCurve, privKey.Public().Bytes())
+		witness.Signatures[i].Assign(snarkCurve, signature)
+	}
+	return witness
+}
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+
+	confs := []struct {
+		hash  hash.Hash
+		curve tedwards.ID
+	}{
+		{hash.MIMC_BN254, tedwards.BN254},
+		{hash.MIMC_BLS12_381, tedwards.BLS12_381},
+		{hash.MIMC_BLS12_377, tedwards.BLS12_377},
+		{hash.MIMC_BW6_761, tedwards.BW6_761},
+		{hash.MIMC_BLS24_315, tedwards.BLS24_315},
+		{hash.MIMC_BW6_633, tedwards.BW6_633},
+	}
+	for _, conf := range confs {
+		conf := conf
+		snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+		assert.NoError(err)
+		assert.Run(func(assert *test.Assert) {
+			const n = 3
+			circuit := newBatchCircuit(conf.curve, n)
+			witness := batchWitness(assert, conf.curve, conf.hash, n)
+			err := test.IsSolved(circuit, witness, snarkCurve, backend.UNKNOWN)
+			assert.NoError(err)
+
+			// one signature of another message
+			witness.Messages[1] = witness.Messages[0]
+			err = test.IsSolved(circuit, witness, snarkCurve, backend.UNKNOWN)
+			assert.Error(err)
+		}, snarkCurve.String())
+	}
+}
+
+func TestBatchVerifySolving(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n = 4
+	circuit := newBatchCircuit(tedwards.BN254, n)
+	witness := batchWitness(assert, tedwards.BN254, hash.MIMC_BN254, n)
+	assert.SolvingSucceeded(circuit, witness, test.WithCurves(ecc.BN254))
+
+	// the keys of two signatures are swapped
+	witness.PublicKeys[0], witness.PublicKeys[1] = witness.PublicKeys[1], witness.PublicKeys[0]
+	assert.SolvingFailed(circuit, witness, test.WithCurves(ecc.BN254), test.WithBackends(backend.GROTH16))
+}
+
+func TestBatchVerifyCancellation(t *testing.T) {
+	assert := test.NewAssert(t)
+	const n = 2
+	circuit := newBatchCircuit(tedwards.BN254, n)
+	witness := batchWitness(assert, tedwards.BN254, hash.MIMC_BN254, n)
+
+	// the errors of S1+δ and S2-δ cancel out in the sum of the verification
+	// equations, but not in their random linear combination
+	params, err := twistededwards.GetCurveParams(tedwards.BN254)
+	assert.NoError(err)
+	order := params.Order
+	delta := big.NewInt(1)
+	s0 := new(big.Int).SetBytes(witness.Signatures[0].S.([]byte))
+	s1 := new(big.Int).SetBytes(witness.Signatures[1].S.([]byte))
+	s0.Add(s0, delta).Mod(s0, order)
+	s1.Sub(s1, delta).Mod(s1, order)
+	witness.Signatures[0].S = s0
+	witness.Signatures[1].S = s1
+	err = test.IsSolved(circuit, witness, ecc.BN254, backend.UNKNOWN)
+	assert.Error(err)
+}
+
+type bitsLessOrEqualCircuit struct {
+	Bits []frontend.Variable
+}
+
+func (circuit *bitsLessOrEqualCircuit) Define(api frontend.API) error {
+	for _, b := range circuit.Bits {
+		api.AssertIsBoolean(b)
+	}
+	bound := new(big.Int).Sub(api.Compiler().Curve().Info().Fr.Modulus(), big.NewInt(1))
+	assertBitsLessOrEqual(api, circuit.Bits, bound)
+	return nil
+}
+
+func TestDecomposeUnique(t *testing.T) {
+	assert := test.NewAssert(t)
+	modulus := ecc.BN254.Info().Fr.Modulus()
+	nbBits := ecc.BN254.Info().Fr.Bits
+	bitsOf := func(v *big.Int) *bitsLessOrEqualCircuit {
+		res := &bitsLessOrEqualCircuit{Bits: make([]frontend.Variable, nbBits)}
+		for i := range res.Bits {
+			res.Bits[i] = v.Bit(i)
+		}
+		return res
+	}
+	circuit := &bitsLessOrEqualCircuit{Bits: make([]frontend.Variable, nbBits)}
+
+	for _, v := range []*big.Int{big.NewInt(0), big.NewInt(5), new(big.Int).Sub(modulus, big.NewInt(1))} {
+		assert.NoError(test.IsSolved(circuit, bitsOf(v), ecc.BN254, backend.UNKNOWN))
+	}
+	// the limbs of 5 and 5+r have the same value modulo r
+	for _, v := range []*big.Int{modulus, new(big.Int).Add(modulus, big.NewInt(5))} {
+		assert.Error(test.IsSolved(circuit, bitsOf(v), ecc.BN254, backend.UNKNOWN))
+	}
+}
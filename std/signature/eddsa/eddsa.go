@@ -70,7 +70,7 @@ func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pu
 
 	//[S]G-[H(R,A,M)]*A
 	_A := curve.Neg(pubKey.A)
-	Q := curve.DoubleBaseScalarMul(base, _A, sig.S, hRAM)
+	Q := curve.Add(curve.FixedBaseScalarMul(base, sig.S), curve.ScalarMul(_A, hRAM))
 	curve.AssertIsOnCurve(Q)
 
 	//[S]G-[H(R,A,M)]*A-R
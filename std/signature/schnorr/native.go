@@ -0,0 +1,266 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+)
+
+// Verifier is a native schnorr verifier for a public key.
+type Verifier struct {
+	curve *nativeCurve
+	X, Y  *big.Int
+}
+
+// Signer is a native schnorr signer for a secret key.
+type Signer struct {
+	Verifier
+	secret *big.Int
+}
+
+// NewVerifier returns a verifier for the public key (x, y), which must be a
+// point of the prime order subgroup of the curve.
+func NewVerifier(id tedwards.ID, x, y *big.Int) (*Verifier, error) {
+	curve, err := newNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.isOnCurve(x, y) {
+		return nil, errors.New("public key is not on the curve")
+	}
+	if ox, oy := curve.scalarMul(x, y, curve.params.Order); ox.Sign() != 0 || oy.Cmp(big.NewInt(1)) != 0 {
+		return nil, errors.New("public key is not in the prime order subgroup")
+	}
+	return &Verifier{curve: curve, X: new(big.Int).Set(x), Y: new(big.Int).Set(y)}, nil
+}
+
+// NewSigner returns a signer for the secret key, which is reduced modulo the
+// order of the subgroup and must not be zero.
+func NewSigner(id tedwards.ID, secret *big.Int) (*Signer, error) {
+	curve, err := newNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	return curve.newSigner(secret)
+}
+
+// GenerateSigner returns a signer for a secret key read from r, or from
+// crypto/rand if r is nil.
+func GenerateSigner(id tedwards.ID, r io.Reader) (*Signer, error) {
+	curve, err := newNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		r = rand.Reader
+	}
+	var buf [64]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, err
+	}
+	return curve.newSigner(new(big.Int).SetBytes(buf[:]))
+}
+
+// DeriveSigner returns the signer for the secret key derived from the seed:
+// the secret key is SHA-512("schnorr/seed" || seed) modulo the order of the
+// subgroup.
+func DeriveSigner(id tedwards.ID, seed []byte) (*Signer, error) {
+	curve, err := newNativeCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	return curve.newSigner(curve.hashToScalar("schnorr/seed", seed))
+}
+
+// Child returns the signer of the child key of the given index. The child
+// secret key is x + t where t is derived from the public key and the index,
+// so that the child public keys can be derived from the public key only, see
+// [Verifier.Child].
+func (s *Signer) Child(index uint32) (*Signer, error) {
+	t := s.tweak(index)
+	return s.curve.newSigner(t.Add(t, s.secret))
+}
+
+// Child returns the verifier of the child key of the given index, that is P +
+// [t]G where t is derived from the public key P and the index.
+func (v *Verifier) Child(index uint32) (*Verifier, error) {
+	tx, ty := v.curve.scalarMul(v.curve.params.Base[0], v.curve.params.Base[1], v.tweak(index))
+	x, y := v.curve.add(v.X, v.Y, tx, ty)
+	if x.Sign() == 0 && y.Cmp(big.NewInt(1)) == 0 {
+		return nil, errors.New("invalid child key")
+	}
+	return &Verifier{curve: v.curve, X: x, Y: y}, nil
+}
+
+// Secret returns the secret key.
+func (s *Signer) Secret() *big.Int {
+	return new(big.Int).Set(s.secret)
+}
+
+// Sign returns the signature (e, s) of msg, which must be an element of the
+// scalar field of the SNARK curve. The nonce is derived from the secret key
+// and the message, and the challenge is computed with h.
+func (s *Signer) Sign(msg *big.Int, h hash.Hash) (*big.Int, *big.Int, error) {
+	if msg.Sign() < 0 || msg.Cmp(s.curve.modulus) >= 0 {
+		return nil, nil, errors.New("message is not a field element")
+	}
+	order := s.curve.params.Order
+	k := s.curve.hashToScalar("schnorr/nonce", s.curve.bytes(s.secret), s.curve.bytes(msg))
+	if k.Sign() == 0 {
+		return nil, nil, errors.New("invalid nonce")
+	}
+	rx, ry := s.curve.scalarMul(s.curve.params.Base[0], s.curve.params.Base[1], k)
+	e := s.challenge(rx, ry, msg, h)
+
+	// S = k - e*x mod order
+	S := new(big.Int).Mul(e, s.secret)
+	S.Sub(k, S).Mod(S, order)
+	return e, S, nil
+}
+
+// Verify returns true if (e, s) is a valid signature of msg, the challenge
+// being computed with h.
+func (v *Verifier) Verify(msg, e, s *big.Int, h hash.Hash) bool {
+	if msg.Sign() < 0 || msg.Cmp(v.curve.modulus) >= 0 || e.Sign() < 0 || e.Cmp(v.curve.modulus) >= 0 {
+		return false
+	}
+	if s.Sign() < 0 || s.Cmp(v.curve.params.Order) >= 0 {
+		return false
+	}
+	sx, sy := v.curve.scalarMul(v.curve.params.Base[0], v.curve.params.Base[1], s)
+	ex, ey := v.curve.scalarMul(v.X, v.Y, e)
+	rx, ry := v.curve.add(sx, sy, ex, ey)
+	return v.challenge(rx, ry, msg, h).Cmp(e) == 0
+}
+
+// PublicKey returns the public key to be assigned in a circuit.
+func (v *Verifier) PublicKey() PublicKey {
+	return PublicKey{A: twistededwards.Point{X: v.X, Y: v.Y}}
+}
+
+// challenge returns H(R, P, msg).
+func (v *Verifier) challenge(rx, ry, msg *big.Int, h hash.Hash) *big.Int {
+	h.Reset()
+	for _, x := range []*big.Int{rx, ry, v.X, v.Y, msg} {
+		h.Write(v.curve.bytes(x))
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// tweak returns the scalar added to the key of the given index for the child
+// key derivation.
+func (v *Verifier) tweak(index uint32) *big.Int {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], index)
+	return v.curve.hashToScalar("schnorr/child", v.curve.bytes(v.X), v.curve.bytes(v.Y), buf[:])
+}
+
+// nativeCurve implements the arithmetic of a twisted Edwards curve with big
+// integers.
+type nativeCurve struct {
+	params  *twistededwards.CurveParams
+	modulus *big.Int
+}
+
+func newNativeCurve(id tedwards.ID) (*nativeCurve, error) {
+	snarkCurve, err := twistededwards.GetSnarkCurve(id)
+	if err != nil {
+		return nil, err
+	}
+	params, err := twistededwards.GetCurveParams(id)
+	if err != nil {
+		return nil, err
+	}
+	return &nativeCurve{params: params, modulus: snarkCurve.Info().Fr.Modulus()}, nil
+}
+
+func (c *nativeCurve) newSigner(secret *big.Int) (*Signer, error) {
+	x := new(big.Int).Mod(secret, c.params.Order)
+	if x.Sign() == 0 {
+		return nil, errors.New("invalid secret key")
+	}
+	px, py := c.scalarMul(c.params.Base[0], c.params.Base[1], x)
+	return &Signer{Verifier: Verifier{curve: c, X: px, Y: py}, secret: x}, nil
+}
+
+// hashToScalar returns SHA-512(tag || data) modulo the order of the subgroup.
+func (c *nativeCurve) hashToScalar(tag string, data ...[]byte) *big.Int {
+	h := sha512.New()
+	h.Write([]byte(tag))
+	for _, d := range data {
+		h.Write(d)
+	}
+	res := new(big.Int).SetBytes(h.Sum(nil))
+	return res.Mod(res, c.params.Order)
+}
+
+// bytes returns the big-endian encoding of x on the size of a field element.
+func (c *nativeCurve) bytes(x *big.Int) []byte {
+	buf := make([]byte, (c.modulus.BitLen()+7)/8)
+	return x.FillBytes(buf)
+}
+
+func (c *nativeCurve) isOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(c.modulus) >= 0 || y.Sign() < 0 || y.Cmp(c.modulus) >= 0 {
+		return false
+	}
+	// a*x^2 + y^2 = 1 + d*x^2*y^2
+	x2 := new(big.Int).Mul(x, x)
+	y2 := new(big.Int).Mul(y, y)
+	lhs := new(big.Int).Mul(c.params.A, x2)
+	lhs.Add(lhs, y2).Mod(lhs, c.modulus)
+	rhs := new(big.Int).Mul(c.params.D, x2)
+	rhs.Mul(rhs, y2).Add(rhs, big.NewInt(1)).Mod(rhs, c.modulus)
+	return lhs.Cmp(rhs) == 0
+}
+
+func (c *nativeCurve) add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := c.modulus
+	// x3 = (x1*y2 + y1*x2) / (1 + d*x1*x2*y1*y2)
+	// y3 = (y1*y2 - a*x1*x2) / (1 - d*x1*x2*y1*y2)
+	x1x2 := new(big.Int).Mul(x1, x2)
+	y1y2 := new(big.Int).Mul(y1, y2)
+	t := new(big.Int).Mul(x1x2, y1y2)
+	t.Mul(t, c.params.D).Mod(t, p)
+	x3 := new(big.Int).Mul(x1, y2)
+	x3.Add(x3, new(big.Int).Mul(y1, x2))
+	x3.Mul(x3, new(big.Int).ModInverse(new(big.Int).Add(t, big.NewInt(1)), p)).Mod(x3, p)
+	y3 := new(big.Int).Mul(c.params.A, x1x2)
+	y3.Sub(y1y2, y3)
+	y3.Mul(y3, new(big.Int).ModInverse(new(big.Int).Sub(big.NewInt(1), t), p)).Mod(y3, p)
+	return x3, y3
+}
+
+func (c *nativeCurve) scalarMul(x, y, s *big.Int) (*big.Int, *big.Int) {
+	rx, ry := big.NewInt(0), big.NewInt(1)
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		rx, ry = c.add(rx, ry, rx, ry)
+		if s.Bit(i) == 1 {
+			rx, ry = c.add(rx, ry, x, y)
+		}
+	}
+	return rx, ry
+}
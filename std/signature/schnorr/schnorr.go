@@ -0,0 +1,83 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schnorr provides ZKP-circuit functions to verify Schnorr signatures
+// on the twisted Edwards curves defined over the scalar fields of the SNARK
+// curves, and a native signer and verifier.
+//
+// As in BIP-340, the challenge commits to the public key. A signature of the
+// message M by the public key P = [x]G is a pair (E, S) of scalars such that
+//
+//	E = H(R, P, M) with R = [S]G + [E]P
+//
+// where H is a SNARK friendly hash function. The signer computes R = [k]G for
+// a deterministic nonce k and S = k - E*x modulo the order of G. S must be
+// less than the order of G, so that the signatures are not malleable.
+package schnorr
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// PublicKey stores a schnorr public key (to be used in gnark circuit)
+type PublicKey struct {
+	A twistededwards.Point
+}
+
+// Signature stores a schnorr signature (to be used in gnark circuit)
+type Signature struct {
+	E, S frontend.Variable
+}
+
+// Verify verifies a schnorr signature of msg by pubKey, using the given hash
+// function for the challenge.
+func Verify(curve twistededwards.Curve, sig Signature, msg frontend.Variable, pubKey PublicKey, hash hash.Hash) error {
+	api := curve.API()
+	curve.AssertIsOnCurve(pubKey.A)
+
+	// S < order
+	api.AssertIsLessOrEqual(sig.S, new(big.Int).Sub(curve.Params().Order, big.NewInt(1)))
+
+	// R = [S]G + [E]A
+	R := curve.Add(curve.FixedBaseScalarMul(base(curve), sig.S), curve.ScalarMul(pubKey.A, sig.E))
+
+	// E = H(R, A, M)
+	hash.Reset()
+	hash.Write(R.X, R.Y, pubKey.A.X, pubKey.A.Y, msg)
+	api.AssertIsEqual(hash.Sum(), sig.E)
+
+	return nil
+}
+
+// AssertKeyPair proves the knowledge of the secret key of pubKey, by checking
+// that pubKey is [secret]G.
+func AssertKeyPair(curve twistededwards.Curve, secret frontend.Variable, pubKey PublicKey) {
+	A := curve.FixedBaseScalarMul(base(curve), secret)
+	curve.API().AssertIsEqual(A.X, pubKey.A.X)
+	curve.API().AssertIsEqual(A.Y, pubKey.A.Y)
+}
+
+// base returns the base point of the curve.
+func base(curve twistededwards.Curve) twistededwards.Point {
+	return twistededwards.Point{
+		X: curve.Params().Base[0],
+		Y: curve.Params().Base[1],
+	}
+}
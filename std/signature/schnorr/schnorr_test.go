@@ -0,0 +1,191 @@
+/*
+Copyright © 2022 ConsenSys Software Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schnorr
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/test"
+)
+
+type schnorrCircuit struct {
+	curveID   tedwards.ID
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+}
+
+func (circuit *schnorrCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	mimc, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	return Verify(curve, circuit.Signature, circuit.Message, circuit.PublicKey, &mimc)
+}
+
+type keyPairCircuit struct {
+	curveID   tedwards.ID
+	PublicKey PublicKey `gnark:",public"`
+	Secret    frontend.Variable
+}
+
+func (circuit *keyPairCircuit) Define(api frontend.API) error {
+	curve, err := twistededwards.NewEdCurve(api, circuit.curveID)
+	if err != nil {
+		return err
+	}
+	AssertKeyPair(curve, circuit.Secret, circuit.PublicKey)
+	return nil
+}
+
+var confs = []struct {
+	hash  hash.Hash
+	curve tedwards.ID
+}{
+	{hash.MIMC_BN254, tedwards.BN254},
+	{hash.MIMC_BLS12_381, tedwards.BLS12_381},
+	{hash.MIMC_BLS12_377, tedwards.BLS12_377},
+	{hash.MIMC_BW6_761, tedwards.BW6_761},
+	{hash.MIMC_BLS24_315, tedwards.BLS24_315},
+	{hash.MIMC_BW6_633, tedwards.BW6_633},
+}
+
+func TestSignNative(t *testing.T) {
+	assert := test.NewAssert(t)
+	randomness := rand.New(rand.NewSource(1))
+	for _, conf := range confs {
+		snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+		assert.NoError(err)
+		signer, err := GenerateSigner(conf.curve, randomness)
+		assert.NoError(err)
+		msg := new(big.Int).Rand(randomness, snarkCurve.Info().Fr.Modulus())
+
+		e, s, err := signer.Sign(msg, conf.hash.New())
+		assert.NoError(err)
+		assert.True(signer.Verify(msg, e, s, conf.hash.New()))
+
+		// the nonce is deterministic
+		e2, s2, err := signer.Sign(msg, conf.hash.New())
+		assert.NoError(err)
+		assert.Equal(e, e2)
+		assert.Equal(s, s2)
+
+		// the verifier checks the public key
+		verifier, err := NewVerifier(conf.curve, signer.X, signer.Y)
+		assert.NoError(err)
+		assert.True(verifier.Verify(msg, e, s, conf.hash.New()))
+		_, err = NewVerifier(conf.curve, signer.X, new(big.Int).Add(signer.Y, big.NewInt(1)))
+		assert.Error(err)
+
+		// wrong message, wrong scalar, malleated scalar
+		assert.False(verifier.Verify(new(big.Int).Add(msg, big.NewInt(1)), e, s, conf.hash.New()))
+		assert.False(verifier.Verify(msg, e, new(big.Int).Add(s, big.NewInt(1)), conf.hash.New()))
+		params, err := twistededwards.GetCurveParams(conf.curve)
+		assert.NoError(err)
+		assert.False(verifier.Verify(msg, e, new(big.Int).Add(s, params.Order), conf.hash.New()))
+	}
+}
+
+func TestDeriveNative(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, conf := range confs {
+		signer, err := DeriveSigner(conf.curve, []byte("seed"))
+		assert.NoError(err)
+		other, err := DeriveSigner(conf.curve, []byte("seed"))
+		assert.NoError(err)
+		assert.Equal(signer.Secret(), other.Secret())
+		other, err = DeriveSigner(conf.curve, []byte("seed2"))
+		assert.NoError(err)
+		assert.NotEqual(signer.Secret(), other.Secret())
+
+		// the child public keys are derived from the parent public key
+		for _, index := range []uint32{0, 1, 1 << 31} {
+			child, err := signer.Child(index)
+			assert.NoError(err)
+			verifier, err := signer.Verifier.Child(index)
+			assert.NoError(err)
+			assert.Equal(child.X, verifier.X)
+			assert.Equal(child.Y, verifier.Y)
+
+			e, s, err := child.Sign(big.NewInt(42), conf.hash.New())
+			assert.NoError(err)
+			assert.True(verifier.Verify(big.NewInt(42), e, s, conf.hash.New()))
+			assert.False(signer.Verify(big.NewInt(42), e, s, conf.hash.New()))
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	randomness := rand.New(rand.NewSource(2))
+	for _, conf := range confs {
+		snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+		assert.NoError(err)
+		signer, err := GenerateSigner(conf.curve, randomness)
+		assert.NoError(err)
+		msg := new(big.Int).Rand(randomness, snarkCurve.Info().Fr.Modulus())
+		e, s, err := signer.Sign(msg, conf.hash.New())
+		assert.NoError(err)
+
+		circuit := schnorrCircuit{curveID: conf.curve}
+		witness := schnorrCircuit{
+			PublicKey: signer.PublicKey(),
+			Signature: Signature{E: e, S: s},
+			Message:   msg,
+		}
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		// wrong message
+		witness.Message = new(big.Int).Add(msg, big.NewInt(1))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		// malleated scalar
+		params, err := twistededwards.GetCurveParams(conf.curve)
+		assert.NoError(err)
+		witness.Message = msg
+		witness.Signature.S = new(big.Int).Add(s, params.Order)
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+	}
+}
+
+func TestAssertKeyPair(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, conf := range confs {
+		snarkCurve, err := twistededwards.GetSnarkCurve(conf.curve)
+		assert.NoError(err)
+		signer, err := DeriveSigner(conf.curve, []byte("key pair"))
+		assert.NoError(err)
+
+		circuit := keyPairCircuit{curveID: conf.curve}
+		witness := keyPairCircuit{PublicKey: signer.PublicKey(), Secret: signer.Secret()}
+		assert.SolvingSucceeded(&circuit, &witness, test.WithCurves(snarkCurve))
+
+		witness.Secret = new(big.Int).Add(signer.Secret(), big.NewInt(1))
+		assert.SolvingFailed(&circuit, &witness, test.WithCurves(snarkCurve))
+	}
+}
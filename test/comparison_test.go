@@ -0,0 +1,135 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+)
+
+const cmpBits = 8
+
+type isLessCircuit struct {
+	A, B     frontend.Variable
+	Less     frontend.Variable
+	Min, Max frontend.Variable
+}
+
+func (c *isLessCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.IsLess(c.A, c.B, cmpBits), c.Less)
+	api.AssertIsEqual(api.Min(c.A, c.B, cmpBits), c.Min)
+	api.AssertIsEqual(api.Max(c.A, c.B, cmpBits), c.Max)
+	return nil
+}
+
+type assertIsLessCircuit struct {
+	A, B frontend.Variable
+}
+
+func (c *assertIsLessCircuit) Define(api frontend.API) error {
+	api.AssertIsLess(c.A, c.B, cmpBits)
+	return nil
+}
+
+// TestBoundedComparisonSoundness checks that the compiled circuits accept the
+// same witnesses as the test engine, including operands which do not fit in
+// the declared number of bits and wrong results.
+func TestBoundedComparisonSoundness(t *testing.T) {
+	modulus := ecc.BN254.Info().Fr.Modulus()
+	values := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(100),
+		big.NewInt(1<<cmpBits - 2), big.NewInt(1<<cmpBits - 1),
+		big.NewInt(1 << cmpBits), big.NewInt(1<<cmpBits + 1), big.NewInt(1 << (cmpBits + 1)),
+		new(big.Int).Sub(modulus, big.NewInt(1)),
+		new(big.Int).Sub(modulus, big.NewInt(1<<cmpBits)),
+	}
+
+	var lessAssignments, assertAssignments []frontend.Circuit
+	for _, a := range values {
+		for _, b := range values {
+			less, min, max := 0, b, a
+			if a.Cmp(b) < 0 {
+				less, min, max = 1, a, b
+			}
+			// the correct result and every single wrong result
+			lessAssignments = append(lessAssignments,
+				&isLessCircuit{A: a, B: b, Less: less, Min: min, Max: max},
+				&isLessCircuit{A: a, B: b, Less: 1 - less, Min: min, Max: max},
+				&isLessCircuit{A: a, B: b, Less: less, Min: max, Max: max},
+				&isLessCircuit{A: a, B: b, Less: less, Min: min, Max: min},
+			)
+			assertAssignments = append(assertAssignments, &assertIsLessCircuit{A: a, B: b})
+		}
+	}
+	checkSameAsEngine(t, &isLessCircuit{}, lessAssignments)
+	checkSameAsEngine(t, &assertIsLessCircuit{}, assertAssignments)
+}
+
+// checkSameAsEngine checks that the circuit compiled with the R1CS and the
+// PLONK builders accepts exactly the assignments which the test engine
+// accepts.
+func checkSameAsEngine(t *testing.T, circuit frontend.Circuit, assignments []frontend.Circuit) {
+	const curve = ecc.BN254
+	builders := map[string]frontend.NewBuilder{"r1cs": r1cs.NewBuilder, "scs": scs.NewBuilder}
+
+	for name, newBuilder := range builders {
+		ccs, err := frontend.Compile(curve, newBuilder, circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, assignment := range assignments {
+			engineErr := IsSolved(circuit, assignment, curve, backend.UNKNOWN)
+			w, err := frontend.NewWitness(assignment, curve)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ccsErr := ccs.IsSolved(w)
+			if (engineErr == nil) != (ccsErr == nil) {
+				t.Fatalf("%s: engine error %v, constraint system error %v for %v", name, engineErr, ccsErr, assignment)
+			}
+		}
+	}
+}
+
+type constantCmpCircuit struct {
+	A frontend.Variable
+}
+
+func (c *constantCmpCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.IsLess(3, 5, cmpBits), 1)
+	api.AssertIsEqual(api.Min(c.A, 5, cmpBits), api.Min(5, c.A, cmpBits))
+	api.AssertIsLess(c.A, 1<<cmpBits-1, cmpBits)
+	api.AssertIsLess(4, 7, cmpBits)
+	return nil
+}
+
+func TestBoundedComparisonConstants(t *testing.T) {
+	assert := NewAssert(t)
+	for _, a := range []int{0, 5, 254} {
+		assert.SolvingSucceeded(&constantCmpCircuit{}, &constantCmpCircuit{A: a}, WithCurves(ecc.BN254))
+	}
+	for _, a := range []int{255, 256, -1} {
+		assert.SolvingFailed(&constantCmpCircuit{}, &constantCmpCircuit{A: a}, WithCurves(ecc.BN254))
+	}
+}
+
+type outOfRangeConstantCircuit struct {
+	A frontend.Variable
+}
+
+func (c *outOfRangeConstantCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.IsLess(1<<cmpBits, c.A, cmpBits), 0)
+	return nil
+}
+
+func TestBoundedComparisonOutOfRangeConstant(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		if _, err := frontend.Compile(ecc.BN254, newBuilder, &outOfRangeConstantCircuit{}); err == nil {
+			t.Fatal("expected compilation to fail")
+		}
+	}
+}
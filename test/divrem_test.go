@@ -0,0 +1,77 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+)
+
+const divRemBits = 8
+
+type divRemCircuit struct {
+	A, B    frontend.Variable
+	Q, R    frontend.Variable
+	Reduced frontend.Variable
+}
+
+func (c *divRemCircuit) Define(api frontend.API) error {
+	q, r := api.DivRem(c.A, c.B, divRemBits)
+	api.AssertIsEqual(q, c.Q)
+	api.AssertIsEqual(r, c.R)
+	api.AssertIsEqual(api.Mod(c.A, 7, divRemBits), c.Reduced)
+	return nil
+}
+
+// TestDivRemSoundness checks that the compiled circuits accept the same
+// witnesses as the test engine, including operands which do not fit in the
+// declared number of bits, division by zero and wrong results.
+func TestDivRemSoundness(t *testing.T) {
+	modulus := ecc.BN254.Info().Fr.Modulus()
+	values := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(3), big.NewInt(7), big.NewInt(200),
+		big.NewInt(1<<divRemBits - 1), big.NewInt(1 << divRemBits),
+		new(big.Int).Sub(modulus, big.NewInt(1)),
+	}
+
+	var assignments []frontend.Circuit
+	for _, a := range values {
+		for _, b := range values {
+			var q, r big.Int
+			if b.Sign() != 0 {
+				q.QuoRem(a, b, &r)
+			}
+			reduced := new(big.Int).Mod(a, big.NewInt(7))
+			assignments = append(assignments,
+				&divRemCircuit{A: a, B: b, Q: &q, R: &r, Reduced: reduced},
+				// a = (q-1)·b + (r+b) with a remainder too large
+				&divRemCircuit{A: a, B: b, Q: new(big.Int).Sub(&q, big.NewInt(1)), R: new(big.Int).Add(&r, b), Reduced: reduced},
+				&divRemCircuit{A: a, B: b, Q: &q, R: new(big.Int).Add(&r, big.NewInt(1)), Reduced: reduced},
+				&divRemCircuit{A: a, B: b, Q: &q, R: &r, Reduced: new(big.Int).Add(reduced, big.NewInt(7))},
+			)
+		}
+	}
+	checkSameAsEngine(t, &divRemCircuit{}, assignments)
+}
+
+type divRemConstantCircuit struct {
+	A frontend.Variable
+}
+
+func (c *divRemConstantCircuit) Define(api frontend.API) error {
+	q, r := api.DivRem(200, 7, divRemBits)
+	api.AssertIsEqual(q, 28)
+	api.AssertIsEqual(r, 4)
+	q, r = api.DivRem(c.A, 16, divRemBits)
+	api.AssertIsEqual(api.Add(api.Mul(q, 16), r), c.A)
+	api.AssertIsEqual(api.Mod(255, c.A, divRemBits), 255%10)
+	return nil
+}
+
+func TestDivRemConstants(t *testing.T) {
+	assert := NewAssert(t)
+	assert.SolvingSucceeded(&divRemConstantCircuit{}, &divRemConstantCircuit{A: 10}, WithCurves(ecc.BN254))
+	assert.SolvingFailed(&divRemConstantCircuit{}, &divRemConstantCircuit{A: 11}, WithCurves(ecc.BN254))
+	assert.SolvingFailed(&divRemConstantCircuit{}, &divRemConstantCircuit{A: 256}, WithCurves(ecc.BN254))
+}
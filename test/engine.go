@@ -17,6 +17,7 @@ limitations under the License.
 package test
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"math/big"
 	"path/filepath"
@@ -32,6 +33,8 @@ import (
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/circuitdefer"
+	"github.com/consensys/gnark/internal/kvstore"
 	"github.com/consensys/gnark/internal/utils"
 )
 
@@ -46,6 +49,7 @@ type engine struct {
 	curveID   ecc.ID
 	opt       backend.ProverConfig
 	// mHintsFunctions map[hint.ID]hintFunction
+	kvstore.Store
 }
 
 // IsSolved returns an error if the test execution engine failed to execute the given circuit
@@ -61,7 +65,7 @@ func IsSolved(circuit, witness frontend.Circuit, curveID ecc.ID, b backend.ID, o
 		return err
 	}
 
-	e := &engine{backendID: b, curveID: curveID, opt: opt}
+	e := &engine{backendID: b, curveID: curveID, opt: opt, Store: kvstore.New()}
 	if opt.Force {
 		panic("ignoring errors in test.Engine is not supported")
 	}
@@ -84,7 +88,14 @@ func IsSolved(circuit, witness frontend.Circuit, curveID ecc.ID, b backend.ID, o
 		}
 	}()
 
-	err = c.Define(e)
+	if err = c.Define(e); err != nil {
+		return err
+	}
+	for i := 0; i < len(circuitdefer.GetAll[func(frontend.API) error](e)); i++ {
+		if err = circuitdefer.GetAll[func(frontend.API) error](e)[i](e); err != nil {
+			return fmt.Errorf("deferred: %w", err)
+		}
+	}
 
 	return
 }
@@ -157,6 +168,26 @@ func (e *engine) Inverse(i1 frontend.Variable) frontend.Variable {
 	return b1
 }
 
+func (e *engine) DivRem(a, b frontend.Variable, nbBits int) (quotient, remainder frontend.Variable) {
+	if nbBits > (e.bitLen()-2)/2 {
+		panic(fmt.Sprintf("number of bits %d out of range [1, %d]", nbBits, (e.bitLen()-2)/2))
+	}
+	b1, b2 := e.toBigInt(a), e.toBigInt(b)
+	e.mustFitBits(&b1, nbBits)
+	e.mustFitBits(&b2, nbBits)
+	if b2.Sign() == 0 {
+		panic("[divRem] division by zero")
+	}
+	var q, r big.Int
+	q.QuoRem(&b1, &b2, &r)
+	return q, r
+}
+
+func (e *engine) Mod(a, m frontend.Variable, nbBits int) frontend.Variable {
+	_, r := e.DivRem(a, m, nbBits)
+	return r
+}
+
 func (e *engine) ToBinary(i1 frontend.Variable, n ...int) []frontend.Variable {
 	nbBits := e.bitLen()
 	if len(n) == 1 {
@@ -286,6 +317,35 @@ func (e *engine) Cmp(i1, i2 frontend.Variable) frontend.Variable {
 	return e.toBigInt(b1.Cmp(&b2))
 }
 
+func (e *engine) IsLess(a, b frontend.Variable, nbBits int) frontend.Variable {
+	if e.isLess(a, b, nbBits) {
+		return e.toBigInt(1)
+	}
+	return e.toBigInt(0)
+}
+
+func (e *engine) Min(a, b frontend.Variable, nbBits int) frontend.Variable {
+	if e.isLess(a, b, nbBits) {
+		return e.toBigInt(a)
+	}
+	return e.toBigInt(b)
+}
+
+func (e *engine) Max(a, b frontend.Variable, nbBits int) frontend.Variable {
+	if e.isLess(a, b, nbBits) {
+		return e.toBigInt(b)
+	}
+	return e.toBigInt(a)
+}
+
+// isLess returns a < b. It panics if a or b do not fit in nbBits bits.
+func (e *engine) isLess(a, b frontend.Variable, nbBits int) bool {
+	b1, b2 := e.toBigInt(a), e.toBigInt(b)
+	e.mustFitBits(&b1, nbBits)
+	e.mustFitBits(&b2, nbBits)
+	return b1.Cmp(&b2) < 0
+}
+
 func (e *engine) AssertIsEqual(i1, i2 frontend.Variable) {
 	b1, b2 := e.toBigInt(i1), e.toBigInt(i2)
 	if b1.Cmp(&b2) != 0 {
@@ -319,6 +379,47 @@ func (e *engine) AssertIsLessOrEqual(v frontend.Variable, bound frontend.Variabl
 	}
 }
 
+func (e *engine) AssertIsLess(a, b frontend.Variable, nbBits int) {
+	if !e.isLess(a, b, nbBits) {
+		b1, b2 := e.toBigInt(a), e.toBigInt(b)
+		panic(fmt.Sprintf("[assertIsLess] %s >= %s", b1.String(), b2.String()))
+	}
+}
+
+// mustFitBits panics if v does not fit in nbBits bits, or if nbBits is out of
+// the range supported by the bounded comparisons.
+func (e *engine) mustFitBits(v *big.Int, nbBits int) {
+	if nbBits <= 0 || nbBits > e.bitLen()-2 {
+		panic(fmt.Sprintf("number of bits %d out of range [1, %d]", nbBits, e.bitLen()-2))
+	}
+	if v.BitLen() > nbBits {
+		panic(fmt.Sprintf("%s does not fit in %d bits", v.String(), nbBits))
+	}
+}
+
+// Check asserts that v fits in nbBits bits. The engine checks the value directly
+// instead of batching the checks.
+func (e *engine) Check(v frontend.Variable, nbBits int) {
+	b := e.toBigInt(v)
+	if b.BitLen() > nbBits {
+		panic(fmt.Sprintf("[rangeCheck] %s does not fit in %d bits", b.String(), nbBits))
+	}
+}
+
+// Commit returns a hash of the values of the variables. The engine does not
+// need a binding commitment as the values are known.
+func (e *engine) Commit(v ...frontend.Variable) frontend.Variable {
+	h := sha256.New()
+	buf := make([]byte, (e.bitLen()+7)/8)
+	for _, vv := range v {
+		b := e.toBigInt(vv)
+		h.Write(b.FillBytes(buf)) // #nosec G104 -- does not err
+	}
+	var res big.Int
+	res.SetBytes(h.Sum(nil))
+	return res.Mod(&res, e.modulus())
+}
+
 func (e *engine) Println(a ...frontend.Variable) {
 	var sbb strings.Builder
 	sbb.WriteString("(test.engine) ")
@@ -405,6 +506,10 @@ func (e *engine) AddCounter(from, to frontend.Tag) {
 	// do nothing, we don't measure constraints with the test engine
 }
 
+func (e *engine) Defer(cb func(frontend.API) error) {
+	circuitdefer.Put(e, cb)
+}
+
 func (e *engine) toBigInt(i1 frontend.Variable) big.Int {
 	b := utils.FromInterface(i1)
 	b.Mod(&b, e.modulus())